@@ -46,9 +46,7 @@ func (a *TradeStatsAnalyzer) Analyze(trades []metrics.Trade, equityCurve []metri
 
 	stats.WinRate = decimal.New(float64(stats.WinningTrades)).Div(decimal.New(float64(stats.TotalTrades)))
 
-	if !grossLoss.IsZero() {
-		stats.ProfitFactor = grossProfit.Div(grossLoss)
-	}
+	stats.ProfitFactor = grossProfit.DivOrNaN(grossLoss)
 
 	if stats.WinningTrades > 0 {
 		stats.AverageWin = grossProfit.Div(decimal.New(float64(stats.WinningTrades)))
@@ -111,7 +109,8 @@ func (a *EquityCurveAnalyzer) Analyze(trades []metrics.Trade, equityCurve []metr
 	return equityCurve
 }
 
-// SharpeRatioAnalyzer calculates the Sharpe Ratio.
+// SharpeRatioAnalyzer calculates the Sharpe Ratio. The result is decimal.NaN
+// when fewer than two trades or zero volatility leave the ratio undefined.
 type SharpeRatioAnalyzer struct {
 	RiskFreeRate decimal.Decimal
 }
@@ -120,7 +119,7 @@ func (a *SharpeRatioAnalyzer) Name() string { return "SharpeRatio" }
 
 func (a *SharpeRatioAnalyzer) Analyze(trades []metrics.Trade, equityCurve []metrics.EquityPoint) interface{} {
 	if len(trades) < 2 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	// Calculate mean return
@@ -140,7 +139,7 @@ func (a *SharpeRatioAnalyzer) Analyze(trades []metrics.Trade, equityCurve []metr
 	stdDev := variance.Sqrt()
 
 	if stdDev.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	excessReturn := mean.Sub(a.RiskFreeRate.Div(decimal.New(365.0)))
@@ -3,6 +3,7 @@ package backtest
 import (
 	"github.com/irfndi/goflux/pkg/decimal"
 	"github.com/irfndi/goflux/pkg/metrics"
+	"github.com/irfndi/goflux/pkg/money"
 	"github.com/irfndi/goflux/pkg/series"
 	"github.com/irfndi/goflux/pkg/trading"
 )
@@ -56,6 +57,13 @@ type BacktestResult struct {
 	InitialCapital       decimal.Decimal
 	Trades               []Trade
 	Analysis             AnalysisResult
+	// EquityCurve is the equity marked to market at the close of each bar
+	EquityCurve []decimal.Decimal
+	// Base reports the result in BacktestConfig.BaseCurrency. It is nil when no
+	// base currency is configured or the conversion failed, such as for a
+	// missing exchange rate, and BaseError then holds the reason.
+	Base      *CurrencyReport
+	BaseError error
 }
 
 type BacktestConfig struct {
@@ -66,6 +74,11 @@ type BacktestConfig struct {
 	Slippage       decimal.Decimal
 	AllowShort     bool
 	AllowLong      bool
+	// Currency is the currency that prices, capital and commissions are quoted in.
+	Currency money.Currency
+	// BaseCurrency, when set, adds a Base report converted with FXRates.
+	BaseCurrency money.Currency
+	FXRates      money.RateProvider
 }
 
 type Backtester struct {
@@ -93,10 +106,22 @@ func (b *Backtester) AddAnalyzer(a Analyzer) {
 	b.analyzers.Add(a)
 }
 
+// Run executes the strategy over the series. A trading.Precomputable strategy
+// is first precomputed for the whole series so vectorized indicators are
+// evaluated in a single pass rather than once per bar.
 func (b *Backtester) Run(config BacktestConfig) BacktestResult {
 	if b == nil || b.series == nil || b.strategy == nil {
 		return BacktestResult{InitialCapital: config.InitialCapital, FinalEquity: config.InitialCapital}
 	}
+	if p, ok := b.strategy.(trading.Precomputable); ok {
+		run := *b
+		run.strategy = p.Precompute(len(b.series.Candles))
+		return run.run(config)
+	}
+	return b.run(config)
+}
+
+func (b *Backtester) run(config BacktestConfig) BacktestResult {
 	positions := make([]Position, 0)
 	trades := make([]Trade, 0)
 	equityCurve := make([]decimal.Decimal, len(b.series.Candles))
@@ -115,6 +140,9 @@ func (b *Backtester) Run(config BacktestConfig) BacktestResult {
 	}
 
 	result := b.calculateResults(trades, equityCurve, config.InitialCapital, equity)
+	if config.BaseCurrency != "" {
+		result.Base, result.BaseError = ConvertResult(result, b.series, config)
+	}
 
 	// Run analyzers
 	metricsTrades := make([]metrics.Trade, len(trades))
@@ -425,6 +453,7 @@ func (b *Backtester) calculateResults(trades []Trade, equityCurve []decimal.Deci
 		Trades:         trades,
 		InitialCapital: initialCapital,
 		FinalEquity:    finalEquity,
+		EquityCurve:    equityCurve,
 		GrossProfit:    decimal.ZERO,
 		GrossLoss:      decimal.ZERO,
 		TotalProfit:    decimal.ZERO,
@@ -432,6 +461,8 @@ func (b *Backtester) calculateResults(trades []Trade, equityCurve []decimal.Deci
 
 	if len(trades) == 0 {
 		result.NetProfit = decimal.ZERO
+		result.WinRate = decimal.NaN
+		result.ProfitFactor = decimal.NaN
 		return result
 	}
 
@@ -451,9 +482,7 @@ func (b *Backtester) calculateResults(trades []Trade, equityCurve []decimal.Deci
 		result.AverageTrade = result.TotalProfit.Div(decimal.New(float64(result.TotalTrades)))
 	}
 
-	if !result.GrossLoss.IsZero() {
-		result.ProfitFactor = result.GrossProfit.Div(result.GrossLoss)
-	}
+	result.ProfitFactor = result.GrossProfit.DivOrNaN(result.GrossLoss)
 
 	result.NetProfit = finalEquity.Sub(initialCapital)
 
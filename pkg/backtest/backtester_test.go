@@ -285,6 +285,25 @@ func TestBacktestExtendedAnalyzers(t *testing.T) {
 	assert.False(t, rExpectancy.IsZero())
 }
 
+func TestBacktestRatioAnalyzersUndefined(t *testing.T) {
+	wins := []metrics.Trade{
+		{Profit: decimal.New(100), IsWin: true},
+		{Profit: decimal.New(50), IsWin: true},
+	}
+	losses := []metrics.Trade{{Profit: decimal.New(-40)}}
+
+	pfa := &ProfitFactorAnalyzer{}
+	assert.True(t, pfa.Analyze(wins, nil).(decimal.Decimal).IsNaN(), "no losses")
+	assert.True(t, pfa.Analyze(nil, nil).(decimal.Decimal).IsNaN(), "no trades")
+	assert.True(t, pfa.Analyze(losses, nil).(decimal.Decimal).IsZero())
+
+	wlra := &WinLossRatioAnalyzer{}
+	assert.True(t, wlra.Analyze(wins, nil).(decimal.Decimal).IsNaN(), "no losses")
+	assert.True(t, wlra.Analyze(nil, nil).(decimal.Decimal).IsNaN(), "no trades")
+	assert.True(t, wlra.Analyze(losses, nil).(decimal.Decimal).IsZero())
+	assert.InDelta(t, 75.0/40, wlra.Analyze(append(wins, losses...), nil).(decimal.Decimal).Float(), 1e-9)
+}
+
 func TestBacktestSQNAnalyzer(t *testing.T) {
 	trades := make([]metrics.Trade, 0, 10)
 	for i := 0; i < 6; i++ {
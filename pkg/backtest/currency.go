@@ -0,0 +1,105 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/money"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// CurrencyReport is a backtest result expressed in a base currency.
+//
+// Each amount is converted at the close of the bar it was realized on: trade
+// P&L at its exit bar, commissions at their entry and exit bars, equity at
+// each bar, and capital at the first and last bars. NetProfit is therefore the
+// change in base-currency equity and includes the effect of exchange-rate
+// moves on the capital.
+type CurrencyReport struct {
+	Currency       money.Currency
+	InitialCapital money.Money
+	FinalEquity    money.Money
+	NetProfit      money.Money
+	GrossProfit    money.Money
+	GrossLoss      money.Money
+	Commissions    money.Money
+	TradeProfits   []money.Money
+	EquityCurve    []money.Money
+}
+
+// ConvertResult converts a result produced on s with config into
+// config.BaseCurrency using config.FXRates.
+func ConvertResult(result BacktestResult, s *series.TimeSeries, config BacktestConfig) (*CurrencyReport, error) {
+	if !config.BaseCurrency.Valid() {
+		return nil, fmt.Errorf("%w: base %q", money.ErrInvalidCurrency, config.BaseCurrency)
+	}
+	if !config.Currency.Valid() {
+		return nil, fmt.Errorf("%w: quote %q", money.ErrInvalidCurrency, config.Currency)
+	}
+	if s == nil || s.Length() == 0 {
+		return nil, fmt.Errorf("backtest: cannot convert a result without a time series")
+	}
+
+	base := config.BaseCurrency
+	convert := func(amount decimal.Decimal, index int) (money.Money, error) {
+		return money.New(amount, config.Currency).Convert(base, config.FXRates, barTime(s, index))
+	}
+
+	report := &CurrencyReport{
+		Currency:     base,
+		GrossProfit:  money.Zero(base),
+		GrossLoss:    money.Zero(base),
+		Commissions:  money.Zero(base),
+		TradeProfits: make([]money.Money, 0, len(result.Trades)),
+		EquityCurve:  make([]money.Money, len(result.EquityCurve)),
+	}
+
+	var err error
+	if report.InitialCapital, err = convert(result.InitialCapital, 0); err != nil {
+		return nil, err
+	}
+	if report.FinalEquity, err = convert(result.FinalEquity, s.Length()-1); err != nil {
+		return nil, err
+	}
+	report.NetProfit, _ = report.FinalEquity.Sub(report.InitialCapital)
+	for i, equity := range result.EquityCurve {
+		if report.EquityCurve[i], err = convert(equity, i); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, trade := range result.Trades {
+		profit, err := convert(trade.Profit, trade.ExitTime)
+		if err != nil {
+			return nil, err
+		}
+		report.TradeProfits = append(report.TradeProfits, profit)
+		if profit.IsPositive() {
+			report.GrossProfit, _ = report.GrossProfit.Add(profit)
+		} else if profit.IsNegative() {
+			report.GrossLoss, _ = report.GrossLoss.Add(profit.Abs())
+		}
+
+		for _, index := range []int{trade.EntryTime, trade.ExitTime} {
+			fee, err := convert(config.Commission, index)
+			if err != nil {
+				return nil, err
+			}
+			report.Commissions, _ = report.Commissions.Add(fee)
+		}
+	}
+
+	return report, nil
+}
+
+// barTime returns the time at which bar index closed.
+func barTime(s *series.TimeSeries, index int) time.Time {
+	if c := s.GetCandle(index); c != nil {
+		return c.Period.End
+	}
+	if last := s.LastCandle(); last != nil {
+		return last.Period.End
+	}
+	return time.Time{}
+}
@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/money"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+func TestBacktester_BaseCurrencyReport(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	config := defaultOptimizerBTConfig()
+	config.Commission = decimal.New(1)
+	config.Currency = money.USD
+	config.BaseCurrency = money.EUR
+	config.FXRates = money.NewStaticRates().Set(money.EUR, money.USD, decimal.New(1.25))
+
+	result := NewBacktester(ts, &thresholdStrategy{threshold: 10}).Run(config)
+	require.NoError(t, result.BaseError)
+	require.NotNil(t, result.Base)
+	require.NotEmpty(t, result.Trades)
+
+	base := result.Base
+	assert.Equal(t, money.EUR, base.Currency)
+	assert.InDelta(t, result.InitialCapital.Float()*0.8, base.InitialCapital.Amount.Float(), 1e-6)
+	assert.InDelta(t, result.FinalEquity.Float()*0.8, base.FinalEquity.Amount.Float(), 1e-6)
+	assert.InDelta(t, result.NetProfit.Float()*0.8, base.NetProfit.Amount.Float(), 1e-6)
+	assert.InDelta(t, float64(2*len(result.Trades))*0.8, base.Commissions.Amount.Float(), 1e-9)
+	assert.Len(t, base.TradeProfits, len(result.Trades))
+}
+
+func TestConvertResult_EquityCurveAtEachBar(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	fx := series.NewTimeSeries()
+	for i, c := range ts.Candles {
+		fx.AddCandle(&series.Candle{Period: c.Period, ClosePrice: decimal.New(0.8 + float64(i)/100)})
+	}
+	config := defaultOptimizerBTConfig()
+	config.Currency = money.USD
+	config.BaseCurrency = money.EUR
+	config.FXRates = money.NewSeriesRates().Add(money.USD, money.EUR, fx)
+
+	result := NewBacktester(ts, &thresholdStrategy{threshold: 10}).Run(config)
+	require.NoError(t, result.BaseError)
+	require.Len(t, result.EquityCurve, len(ts.Candles))
+	curve := result.Base.EquityCurve
+	require.Len(t, curve, len(result.EquityCurve))
+	for i, equity := range result.EquityCurve {
+		assert.Equal(t, money.EUR, curve[i].Currency)
+		assert.InDelta(t, equity.Float()*(0.8+float64(i)/100), curve[i].Amount.Float(), 1e-6, "bar %d", i)
+	}
+	assert.True(t, curve[len(curve)-1].Amount.EQ(result.Base.FinalEquity.Amount))
+	assert.False(t, curve[0].Amount.EQ(curve[len(curve)-1].Amount))
+}
+
+func TestBacktester_BaseCurrencyMissingRate(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	config := defaultOptimizerBTConfig()
+	config.Currency = money.USD
+	config.BaseCurrency = money.JPY
+	config.FXRates = money.NewStaticRates()
+
+	bt := NewBacktester(ts, &thresholdStrategy{threshold: 10})
+	result := bt.Run(config)
+	assert.Nil(t, result.Base)
+	assert.ErrorIs(t, result.BaseError, money.ErrRateNotFound)
+
+	_, err := ConvertResult(result, ts, config)
+	assert.ErrorIs(t, err, money.ErrRateNotFound)
+}
+
+func TestConvertResult_SameCurrencyNeedsNoRates(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	config := defaultOptimizerBTConfig()
+	config.Currency = money.USDT
+	config.BaseCurrency = money.USDT
+
+	result := NewBacktester(ts, &thresholdStrategy{threshold: 10}).Run(config)
+	require.NotNil(t, result.Base)
+	assert.True(t, result.Base.FinalEquity.Amount.EQ(result.FinalEquity))
+}
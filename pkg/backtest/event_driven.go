@@ -600,6 +600,7 @@ func (b *SimulatedBroker) calculateResults(trades []Trade, equityCurve []decimal
 		Trades:         trades,
 		InitialCapital: initialCapital,
 		FinalEquity:    finalEquity,
+		EquityCurve:    equityCurve,
 		GrossProfit:    decimal.ZERO,
 		GrossLoss:      decimal.ZERO,
 		TotalProfit:    decimal.ZERO,
@@ -607,6 +608,8 @@ func (b *SimulatedBroker) calculateResults(trades []Trade, equityCurve []decimal
 
 	if len(trades) == 0 {
 		result.NetProfit = decimal.ZERO
+		result.WinRate = decimal.NaN
+		result.ProfitFactor = decimal.NaN
 		return result
 	}
 
@@ -626,9 +629,7 @@ func (b *SimulatedBroker) calculateResults(trades []Trade, equityCurve []decimal
 		result.AverageTrade = result.TotalProfit.Div(decimal.New(float64(result.TotalTrades)))
 	}
 
-	if !result.GrossLoss.IsZero() {
-		result.ProfitFactor = result.GrossProfit.Div(result.GrossLoss)
-	}
+	result.ProfitFactor = result.GrossProfit.DivOrNaN(result.GrossLoss)
 
 	result.NetProfit = finalEquity.Sub(initialCapital)
 
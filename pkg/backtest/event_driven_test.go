@@ -645,3 +645,20 @@ func TestEventDrivenBacktester_ShortPositionPendingOrder(t *testing.T) {
 	assert.Equal(t, "short", result.Trades[0].Direction)
 	assert.True(t, result.Trades[0].ExitPrice.EQ(decimal.New(103)))
 }
+
+func TestSimulatedBroker_UndefinedProfitFactor(t *testing.T) {
+	broker := NewSimulatedBroker("TEST", decimal.New(10000))
+	capital := decimal.New(10000)
+
+	result := broker.calculateResults(nil, nil, capital, capital)
+	assert.True(t, result.ProfitFactor.IsNaN(), "no trades")
+	assert.True(t, result.WinRate.IsNaN(), "no trades")
+
+	wins := []Trade{{Profit: decimal.New(100)}, {Profit: decimal.New(50)}}
+	result = broker.calculateResults(wins, nil, capital, capital.Add(decimal.New(150)))
+	assert.True(t, result.ProfitFactor.IsNaN(), "no losses")
+	assert.Equal(t, 1.0, result.WinRate.Float())
+
+	result = broker.calculateResults(append(wins, Trade{Profit: decimal.New(-50)}), nil, capital, capital.Add(decimal.New(100)))
+	assert.Equal(t, 3.0, result.ProfitFactor.Float())
+}
@@ -64,14 +64,7 @@ func (pfa *ProfitFactorAnalyzer) Analyze(trades []metrics.Trade, equityCurve []m
 		}
 	}
 
-	if grossLoss.IsZero() {
-		if grossProfit.IsZero() {
-			return decimal.ZERO
-		}
-		return decimal.New(999)
-	}
-
-	return grossProfit.Div(grossLoss)
+	return grossProfit.DivOrNaN(grossLoss)
 }
 
 type AverageTradeDurationAnalyzer struct{}
@@ -210,21 +203,15 @@ func (wlra *WinLossRatioAnalyzer) Analyze(trades []metrics.Trade, equityCurve []
 		}
 	}
 
-	if totalWins == 0 || avgWin.IsZero() {
-		return decimal.ZERO
-	}
 	if totalLosses == 0 {
-		avgLoss = decimal.New(0.0001)
+		return decimal.NaN
 	}
-
-	avgWin = avgWin.Div(decimal.New(float64(totalWins)))
-	avgLoss = avgLoss.Div(decimal.New(float64(totalLosses)))
-
-	if avgLoss.IsZero() {
-		return decimal.New(999)
+	if totalWins > 0 {
+		avgWin = avgWin.Div(decimal.New(float64(totalWins)))
 	}
+	avgLoss = avgLoss.Div(decimal.New(float64(totalLosses)))
 
-	return avgWin.Div(avgLoss)
+	return avgWin.DivOrNaN(avgLoss)
 }
 
 type RExpectancyAnalyzer struct{}
@@ -7,10 +7,12 @@ import (
 	"math/rand"
 	"runtime"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/irfndi/goflux/pkg/indicators"
 	"github.com/irfndi/goflux/pkg/series"
 	"github.com/irfndi/goflux/pkg/trading"
 )
@@ -50,6 +52,47 @@ func (ps ParameterSpace) Validate() error {
 	return nil
 }
 
+// IndicatorParameterSpaces returns a parameter space for every parameter of
+// the indicator registered under key, searching its default grid. Names are
+// prefixed with prefix so several indicators can share one optimization. A
+// parameter without a grid spans its bounds, whole numbers stepping by 1 and
+// others by a twentieth of the range, and without an upper bound is an error.
+func IndicatorParameterSpaces(key, prefix string) ([]ParameterSpace, error) {
+	spec, ok := indicators.LookupIndicator(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", indicators.ErrUnknownIndicator, key)
+	}
+	spaces := make([]ParameterSpace, 0, len(spec.Params))
+	for _, p := range spec.Params {
+		if p.GridStep > 0 {
+			spaces = append(spaces, ParameterSpace{Name: prefix + p.Name, Min: p.GridMin, Max: p.GridMax, Step: p.GridStep})
+			continue
+		}
+		if p.Max == 0 {
+			return nil, fmt.Errorf("indicator %s parameter %q has no upper bound", spec.Key, p.Name)
+		}
+		step := 1.0
+		if p.Type != indicators.ParamInt {
+			step = (p.Max - p.Min) / 20
+		}
+		spaces = append(spaces, ParameterSpace{Name: prefix + p.Name, Min: p.Min, Max: p.Max, Step: step})
+	}
+	return spaces, nil
+}
+
+// IndicatorParams extracts the parameters starting with prefix from an
+// optimizer parameter set and strips the prefix, ready for
+// indicators.NewIndicatorByName.
+func IndicatorParams(params map[string]float64, prefix string) map[string]float64 {
+	out := make(map[string]float64)
+	for name, v := range params {
+		if strings.HasPrefix(name, prefix) {
+			out[strings.TrimPrefix(name, prefix)] = v
+		}
+	}
+	return out
+}
+
 // OptimizationConfig configures the optimization run.
 type OptimizationConfig struct {
 	Method          OptimizationMethod
@@ -187,7 +230,7 @@ func (o *Optimizer) Optimize(
 		// Sequential execution avoids goroutine overhead.
 		for i, params := range combinations {
 			result := o.runBacktest(ts, strategyFactory, btConfig, params)
-			score := o.config.ObjectiveFunc(result)
+			score := o.score(result)
 			results = append(results, ParameterSetResult{Params: params, Score: score, Result: result})
 			if i == 0 || score > bestScore {
 				bestScore = score
@@ -210,7 +253,7 @@ func (o *Optimizer) Optimize(
 				defer wg.Done()
 				for params := range jobs {
 					result := o.runBacktest(ts, strategyFactory, btConfig, params)
-					score := o.config.ObjectiveFunc(result)
+					score := o.score(result)
 					resCh <- ParameterSetResult{Params: params, Score: score, Result: result}
 					if o.config.ProgressFunc != nil {
 						c := int(completed.Add(1))
@@ -257,6 +300,17 @@ func (o *Optimizer) Optimize(
 	}, nil
 }
 
+// score evaluates the objective for a result. Undefined (NaN) scores, such as a
+// profit factor with no losing trades, rank below every defined score so they
+// never win the search or disturb the result ordering.
+func (o *Optimizer) score(result BacktestResult) float64 {
+	s := o.config.ObjectiveFunc(result)
+	if math.IsNaN(s) {
+		return math.Inf(-1)
+	}
+	return s
+}
+
 func (o *Optimizer) generateCombinations() []map[string]float64 {
 	switch o.config.Method {
 	case OptMethodRandomSearch:
@@ -333,6 +387,8 @@ func (o *Optimizer) generateRandomSamples() []map[string]float64 {
 	return combinations
 }
 
+// runBacktest builds a fresh strategy for params. Backtester.Run precomputes
+// it when possible, so every parameter set evaluates its indicators in one pass.
 func (o *Optimizer) runBacktest(
 	ts *series.TimeSeries,
 	strategyFactory func(params map[string]float64) trading.Strategy,
@@ -1,10 +1,12 @@
 package backtest
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
 	"github.com/irfndi/goflux/pkg/series"
 	"github.com/irfndi/goflux/pkg/trading"
 
@@ -387,3 +389,71 @@ func TestOptimizer_BestConfig(t *testing.T) {
 	}
 	assert.True(t, found, "best config must be one of the evaluated configs")
 }
+
+func TestOptimizer_UndefinedScoresRankLast(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	config := OptimizationConfig{
+		Method: OptMethodGridSearch,
+		ParameterSpaces: []ParameterSpace{
+			{Name: "threshold", Min: 0, Max: 20, Step: 10},
+		},
+		ObjectiveFunc: func(result BacktestResult) float64 {
+			if len(result.Trades) > 0 && result.Trades[0].EntryTime == 1 {
+				return math.NaN()
+			}
+			return float64(len(result.Trades))
+		},
+		MaxWorkers: 1,
+	}
+
+	opt, err := NewOptimizer(config)
+	require.NoError(t, err)
+	result, err := opt.Optimize(ts, thresholdStrategyFactory, defaultOptimizerBTConfig())
+	require.NoError(t, err)
+
+	assert.False(t, math.IsNaN(result.BestScore))
+	assert.NotEqual(t, 0.0, result.BestConfig["threshold"])
+	assert.True(t, math.IsInf(result.AllResults[len(result.AllResults)-1].Score, -1))
+}
+
+func TestBacktester_UndefinedRatiosAreNaN(t *testing.T) {
+	ts := createOptimizerTestSeries()
+	bt := NewBacktester(ts, &thresholdStrategy{threshold: 10})
+	result := bt.Run(defaultOptimizerBTConfig())
+
+	require.Greater(t, result.TotalTrades, 0)
+	assert.Zero(t, result.LosingTrades)
+	assert.True(t, result.ProfitFactor.IsNaN(), "profit factor without losses is undefined")
+	assert.True(t, result.WinRate.IsValid())
+}
+
+func TestIndicatorParameterSpaces(t *testing.T) {
+	spaces, err := IndicatorParameterSpaces("bbands", "bb_")
+	require.NoError(t, err)
+	require.Len(t, spaces, 2)
+	assert.Equal(t, ParameterSpace{Name: "bb_window", Min: 5, Max: 80, Step: 5}, spaces[0])
+	assert.Equal(t, ParameterSpace{Name: "bb_sigma", Min: 0.5, Max: 8, Step: 0.5}, spaces[1])
+
+	_, err = IndicatorParameterSpaces("nope", "")
+	assert.Error(t, err)
+
+	params := IndicatorParams(map[string]float64{"bb_window": 10, "bb_sigma": 1.5, "threshold": 3}, "bb_")
+	assert.Equal(t, map[string]float64{"window": 10, "sigma": 1.5}, params)
+
+	config := OptimizationConfig{
+		Method:          OptMethodGridSearch,
+		ParameterSpaces: spaces[:1],
+		ObjectiveFunc:   ObjectiveNetProfit,
+	}
+	opt, err := NewOptimizer(config)
+	require.NoError(t, err)
+	ts := createOptimizerTestSeries()
+	result, err := opt.Optimize(ts, func(params map[string]float64) trading.Strategy {
+		sma, err := indicators.NewIndicatorByName("bbands.middle", ts, IndicatorParams(params, "bb_"))
+		require.NoError(t, err)
+		require.NotNil(t, sma)
+		return &thresholdStrategy{threshold: int(params["bb_window"])}
+	}, defaultOptimizerBTConfig())
+	require.NoError(t, err)
+	assert.Equal(t, 16, result.TotalRuns)
+}
@@ -0,0 +1,557 @@
+// Package chartpatterns recognizes chart patterns spanning several swings,
+// such as double tops, head and shoulders, triangles and flags, from the swing
+// points of a series.
+package chartpatterns
+
+import (
+	"sort"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// Kind is the type of a chart pattern
+type Kind int
+
+const (
+	DoubleTop Kind = iota + 1
+	DoubleBottom
+	TripleTop
+	TripleBottom
+	HeadAndShoulders
+	InverseHeadAndShoulders
+	AscendingTriangle
+	DescendingTriangle
+	SymmetricTriangle
+	RisingWedge
+	FallingWedge
+	BullFlag
+	BearFlag
+	BullPennant
+	BearPennant
+)
+
+var kindNames = map[Kind]string{
+	DoubleTop:               "Double Top",
+	DoubleBottom:            "Double Bottom",
+	TripleTop:               "Triple Top",
+	TripleBottom:            "Triple Bottom",
+	HeadAndShoulders:        "Head and Shoulders",
+	InverseHeadAndShoulders: "Inverse Head and Shoulders",
+	AscendingTriangle:       "Ascending Triangle",
+	DescendingTriangle:      "Descending Triangle",
+	SymmetricTriangle:       "Symmetric Triangle",
+	RisingWedge:             "Rising Wedge",
+	FallingWedge:            "Falling Wedge",
+	BullFlag:                "Bull Flag",
+	BearFlag:                "Bear Flag",
+	BullPennant:             "Bull Pennant",
+	BearPennant:             "Bear Pennant",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return "None"
+}
+
+// mirrored maps a top to the matching bottom and back
+var mirrored = map[Kind]Kind{
+	DoubleTop:               DoubleBottom,
+	DoubleBottom:            DoubleTop,
+	TripleTop:               TripleBottom,
+	TripleBottom:            TripleTop,
+	HeadAndShoulders:        InverseHeadAndShoulders,
+	InverseHeadAndShoulders: HeadAndShoulders,
+}
+
+// Status is the progress of a pattern after it is detected
+type Status int
+
+const (
+	// Forming patterns are complete but price has not broken out
+	Forming Status = iota
+	// Confirmed patterns have closed beyond their breakout line
+	Confirmed
+	// Completed patterns have reached their target after the breakout
+	Completed
+	// Failed patterns closed beyond the opposite boundary, or expired,
+	// before breaking out
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Forming:
+		return "Forming"
+	case Confirmed:
+		return "Confirmed"
+	case Completed:
+		return "Completed"
+	case Failed:
+		return "Failed"
+	}
+	return "Unknown"
+}
+
+// Point is a key point of a pattern
+type Point struct {
+	Index int
+	Price decimal.Decimal
+}
+
+// Line is a straight price line through From with Slope in price per candle
+type Line struct {
+	From  Point
+	Slope decimal.Decimal
+}
+
+// HorizontalLine returns the flat line at price p.Price
+func HorizontalLine(p Point) Line {
+	return Line{From: p, Slope: decimal.ZERO}
+}
+
+func lineThrough(a, b Point) Line {
+	if a.Index == b.Index {
+		return HorizontalLine(a)
+	}
+	return Line{From: a, Slope: b.Price.Sub(a.Price).Div(decimal.New(float64(b.Index - a.Index)))}
+}
+
+// At returns the price of the line at index
+func (l Line) At(index int) decimal.Decimal {
+	return l.From.Price.Add(l.Slope.Mul(decimal.New(float64(index - l.From.Index))))
+}
+
+func (l Line) neg() Line {
+	return Line{From: Point{l.From.Index, l.From.Price.Neg()}, Slope: l.Slope.Neg()}
+}
+
+// Pattern is a recognized chart pattern
+type Pattern struct {
+	Kind Kind
+	// Points are the swings making up the pattern, in index order
+	Points []Point
+	// Detected is the index at which the last swing was confirmed, the first
+	// index at which the pattern is known
+	Detected int
+	// Upper and Lower bound the pattern. For tops the neckline is Lower and
+	// Upper runs through the highest peak; for bottoms the reverse. For
+	// triangles, wedges, flags and pennants they are the trendlines through
+	// the highs and the lows.
+	Upper Line
+	Lower Line
+	// Bias is 1 for patterns that break out upwards and -1 for those that
+	// break down. A symmetric triangle has bias 0 until it breaks out.
+	Bias int
+	// Height is the measured move: the depth of the pattern, or the pole of a
+	// flag or pennant
+	Height decimal.Decimal
+	// Target is the breakout level plus (or minus) Height, projected from the
+	// breakout once there is one
+	Target        decimal.Decimal
+	Status        Status
+	BreakoutIndex int
+}
+
+// Neckline returns the breakout line of the pattern: Upper for a bullish bias
+// and Lower otherwise
+func (p Pattern) Neckline() Line {
+	if p.Bias > 0 {
+		return p.Upper
+	}
+	return p.Lower
+}
+
+// Bullish reports whether the pattern breaks, or is expected to break, upwards
+func (p Pattern) Bullish() bool { return p.Bias > 0 }
+
+// Start returns the index of the first key point
+func (p Pattern) Start() int { return p.Points[0].Index }
+
+// End returns the index of the last key point
+func (p Pattern) End() int { return p.Points[len(p.Points)-1].Index }
+
+// Config configures pattern detection
+type Config struct {
+	// Strength is the number of candles on each side of a swing when swings
+	// are N-bar pivots, 3 if zero
+	Strength int
+	// Deviation switches to ZigZag swings reversing by at least this fraction,
+	// with legs of at least Depth candles
+	Deviation float64
+	Depth     int
+	// Tolerance is how far apart, relative to price, prices still count as
+	// level, 0.02 if zero
+	Tolerance float64
+	// BreakoutMargin is how far beyond the breakout line, relative to price,
+	// a close must be to confirm the pattern
+	BreakoutMargin float64
+	// Expiry fails a pattern that has not broken out this many candles after
+	// its last point; zero uses the width of the pattern
+	Expiry int
+	// RatioTolerance widens the Fibonacci ratio ranges of harmonic patterns
+	// by this fraction on each side, 0.05 if zero
+	RatioTolerance float64
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Strength <= 0 {
+		cfg.Strength = 3
+	}
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = 0.02
+	}
+	if cfg.RatioTolerance <= 0 {
+		cfg.RatioTolerance = 0.05
+	}
+	return cfg
+}
+
+// Lookback is the first index at which a swing can be confirmed
+func (cfg Config) Lookback() int {
+	if cfg.Deviation > 0 {
+		return 1
+	}
+	return 2 * cfg.withDefaults().Strength
+}
+
+// poleRatio is how many times the height of a flag or pennant its pole must
+// be, and convergence how much narrower a triangle, wedge or pennant must end
+// than it starts
+var (
+	poleRatio   = decimal.New(2)
+	convergence = decimal.New(0.8)
+)
+
+// Detect returns the patterns of s with their status at the last candle
+func Detect(s *series.TimeSeries, cfg Config) []Pattern {
+	if s == nil {
+		return nil
+	}
+	return DetectAt(s, len(s.Candles)-1, cfg)
+}
+
+// DetectAt returns the patterns known at index, from the swings confirmed by
+// then, with their status at index. It never looks past index. Patterns are
+// ordered by the index of their first point.
+func DetectAt(s *series.TimeSeries, index int, cfg Config) []Pattern {
+	if s == nil || index < 0 || index >= len(s.Candles) {
+		return nil
+	}
+	cfg = cfg.withDefaults()
+	known := knownSwings(s, index, cfg)
+
+	tol := decimal.New(cfg.Tolerance)
+	patterns := append(findReversals(known, tol), findConsolidations(known, tol)...)
+	sort.SliceStable(patterns, func(i, j int) bool { return patterns[i].Start() < patterns[j].Start() })
+	for i := range patterns {
+		track(&patterns[i], s, index, cfg)
+	}
+	return patterns
+}
+
+// knownSwings returns the alternating swings of s confirmed by index
+func knownSwings(s *series.TimeSeries, index int, cfg Config) []indicators.SwingPoint {
+	var known []indicators.SwingPoint
+	for _, sw := range swingScanner(s, cfg).Scan(index) {
+		known, _ = alternate(known, sw)
+	}
+	return known
+}
+
+// swingScanner returns the scanner of the swings patterns are made of
+func swingScanner(s *series.TimeSeries, cfg Config) *indicators.SwingScanner {
+	if cfg.Deviation > 0 {
+		return indicators.NewZigZagScanner(s, cfg.Depth, cfg.Deviation)
+	}
+	return indicators.NewPivotScanner(s, cfg.Strength, cfg.Strength)
+}
+
+// alternate adds sw to the alternating highs and lows of out, keeping the
+// more extreme of consecutive swings of one side, and reports whether out
+// changed. Only the last swing of out is ever replaced.
+func alternate(out []indicators.SwingPoint, sw indicators.SwingPoint) ([]indicators.SwingPoint, bool) {
+	if len(out) == 0 {
+		return append(out, sw), true
+	}
+	last := &out[len(out)-1]
+	switch {
+	case last.Index == sw.Index:
+		// An outside candle is both; keep the first
+	case last.High != sw.High:
+		return append(out, sw), true
+	case sw.High && sw.Price.GT(last.Price), !sw.High && sw.Price.LT(last.Price):
+		*last = sw
+		return out, true
+	}
+	return out, false
+}
+
+func point(sw indicators.SwingPoint) Point { return Point{Index: sw.Index, Price: sw.Price} }
+
+// near reports whether a and b are within tol of each other relative to the
+// larger magnitude
+func near(a, b, tol decimal.Decimal) bool {
+	return a.Sub(b).Abs().LTE(a.Abs().Max(b.Abs()).Mul(tol))
+}
+
+// above reports whether a is above b by more than tol relative to b
+func above(a, b, tol decimal.Decimal) bool {
+	return a.Sub(b).GT(b.Abs().Mul(tol))
+}
+
+// findReversals finds double and triple tops and bottoms and head and
+// shoulders. Bottoms are found as tops of the negated prices.
+func findReversals(swings []indicators.SwingPoint, tol decimal.Decimal) []Pattern {
+	var patterns []Pattern
+	for _, bottoms := range []bool{false, true} {
+		found, _ := reversalsFrom(swings, bottoms, 0, len(swings), tol)
+		patterns = append(patterns, found...)
+	}
+	return patterns
+}
+
+// reversalSpan is the most swings a reversal is matched against
+const reversalSpan = 5
+
+// reversalsFrom finds the tops, or bottoms, starting at swing k or later.
+// Only the first final swings can no longer change, all of them when final
+// is len(swings). It stops at the first swing whose match reads past them and
+// returns the patterns with the swing to resume from.
+func reversalsFrom(swings []indicators.SwingPoint, bottoms bool, k, final int, tol decimal.Decimal) ([]Pattern, int) {
+	var patterns []Pattern
+	for ; k+2 < len(swings) && (k+reversalSpan <= final || final == len(swings)); k++ {
+		if swings[k].High == bottoms {
+			continue
+		}
+		window := swings[k:min(k+reversalSpan, len(swings))]
+		points := make([]Point, len(window))
+		for i, sw := range window {
+			points[i] = point(sw)
+			if bottoms {
+				points[i].Price = sw.Price.Neg()
+			}
+		}
+		p, n, ok := matchTop(points, tol)
+		if !ok {
+			continue
+		}
+		p.Detected = swings[k+n-1].Confirmed
+		if bottoms {
+			p = p.mirror()
+		}
+		patterns = append(patterns, p)
+		k += n - 2
+	}
+	return patterns, k
+}
+
+// matchTop matches a top starting at the swing high points[0] and returns it
+// with the number of points it uses
+func matchTop(points []Point, tol decimal.Decimal) (Pattern, int, bool) {
+	if len(points) >= 5 {
+		ls, l1, head, l2, rs := points[0], points[1], points[2], points[3], points[4]
+		shoulders := ls.Price.Max(rs.Price)
+		neckTop := l1.Price.Max(l2.Price)
+		if near(ls.Price, rs.Price, tol) && near(ls.Price, head.Price, tol) && near(head.Price, rs.Price, tol) &&
+			above(ls.Price.Min(head.Price).Min(rs.Price), neckTop, tol) {
+			peak := ls
+			for _, p := range []Point{head, rs} {
+				if p.Price.GT(peak.Price) {
+					peak = p
+				}
+			}
+			neck := l1
+			if l2.Price.LT(neck.Price) {
+				neck = l2
+			}
+			return newReversal(TripleTop, points[:5], HorizontalLine(neck), peak), 5, true
+		}
+		if above(head.Price, shoulders, tol) && near(ls.Price, rs.Price, tol) && above(ls.Price.Min(rs.Price), neckTop, tol) {
+			return newReversal(HeadAndShoulders, points[:5], lineThrough(l1, l2), head), 5, true
+		}
+	}
+
+	h1, trough, h2 := points[0], points[1], points[2]
+	if near(h1.Price, h2.Price, tol) && above(h1.Price.Min(h2.Price), trough.Price, tol) {
+		peak := h1
+		if h2.Price.GT(peak.Price) {
+			peak = h2
+		}
+		return newReversal(DoubleTop, points[:3], HorizontalLine(trough), peak), 3, true
+	}
+	return Pattern{}, 0, false
+}
+
+func newReversal(kind Kind, points []Point, neck Line, peak Point) Pattern {
+	last := points[len(points)-1].Index
+	height := peak.Price.Sub(neck.At(peak.Index))
+	return Pattern{
+		Kind:          kind,
+		Points:        append([]Point(nil), points...),
+		Upper:         HorizontalLine(peak),
+		Lower:         neck,
+		Bias:          -1,
+		Height:        height,
+		Target:        neck.At(last).Sub(height),
+		BreakoutIndex: -1,
+	}
+}
+
+// mirror turns a top found in negated prices into the matching bottom
+func (p Pattern) mirror() Pattern {
+	for i := range p.Points {
+		p.Points[i].Price = p.Points[i].Price.Neg()
+	}
+	p.Kind = mirrored[p.Kind]
+	p.Upper, p.Lower = p.Lower.neg(), p.Upper.neg()
+	p.Bias = -p.Bias
+	p.Target = p.Target.Neg()
+	return p
+}
+
+// findConsolidations finds triangles, wedges, flags and pennants in each run
+// of four alternating swings
+func findConsolidations(swings []indicators.SwingPoint, tol decimal.Decimal) []Pattern {
+	patterns, _ := consolidationsFrom(swings, 0, len(swings), tol)
+	return patterns
+}
+
+// consolidationsFrom finds the consolidations starting at swing k or later,
+// stopping, like reversalsFrom, at the first run that reads past the first
+// final swings
+func consolidationsFrom(swings []indicators.SwingPoint, k, final int, tol decimal.Decimal) ([]Pattern, int) {
+	var patterns []Pattern
+	for ; k+3 < len(swings) && k+4 <= final; k++ {
+		window := swings[k : k+4]
+		var highs, lows []Point
+		for _, sw := range window {
+			if sw.High {
+				highs = append(highs, point(sw))
+			} else {
+				lows = append(lows, point(sw))
+			}
+		}
+		upper, lower := lineThrough(highs[0], highs[1]), lineThrough(lows[0], lows[1])
+		start, end := window[0].Index, window[3].Index
+		startHeight := upper.At(start).Sub(lower.At(start))
+		endHeight := upper.At(end).Sub(lower.At(end))
+		if !startHeight.IsPositive() || !endHeight.IsPositive() {
+			continue
+		}
+
+		slope := func(l Line) int {
+			change := l.At(end).Sub(l.At(start))
+			if change.Abs().LTE(l.At(start).Abs().Mul(tol)) {
+				return 0
+			}
+			return change.Sign()
+		}
+		up, down := slope(upper), slope(lower)
+		converging := endHeight.LT(startHeight.Mul(convergence))
+
+		// A pole is the move into the first swing from the one before
+		var pole decimal.Decimal
+		if k > 0 {
+			pole = window[0].Price.Sub(swings[k-1].Price)
+		}
+		strongPole := k > 0 && pole.Abs().GTE(startHeight.Mul(poleRatio))
+
+		p := Pattern{
+			Points:        make([]Point, len(window)),
+			Detected:      window[3].Confirmed,
+			Upper:         upper,
+			Lower:         lower,
+			Height:        startHeight,
+			BreakoutIndex: -1,
+		}
+		for i, sw := range window {
+			p.Points[i] = point(sw)
+		}
+		switch {
+		case strongPole && window[0].High && pole.IsPositive() && converging:
+			p.Kind, p.Bias, p.Height = BullPennant, 1, pole
+		case strongPole && window[0].High && pole.IsPositive() && up <= 0 && down <= 0:
+			p.Kind, p.Bias, p.Height = BullFlag, 1, pole
+		case strongPole && !window[0].High && pole.IsNegative() && converging:
+			p.Kind, p.Bias, p.Height = BearPennant, -1, pole.Neg()
+		case strongPole && !window[0].High && pole.IsNegative() && up >= 0 && down >= 0:
+			p.Kind, p.Bias, p.Height = BearFlag, -1, pole.Neg()
+		case up == 0 && down > 0:
+			p.Kind, p.Bias = AscendingTriangle, 1
+		case up < 0 && down == 0:
+			p.Kind, p.Bias = DescendingTriangle, -1
+		case up < 0 && down > 0:
+			p.Kind, p.Bias = SymmetricTriangle, 0
+		case up > 0 && down > 0 && converging:
+			p.Kind, p.Bias = RisingWedge, -1
+		case up < 0 && down < 0 && converging:
+			p.Kind, p.Bias = FallingWedge, 1
+		default:
+			continue
+		}
+		p.Target = projectTarget(p, end)
+		patterns = append(patterns, p)
+		k += 2
+	}
+	return patterns, k
+}
+
+// projectTarget returns the measured-move target from the breakout line at
+// index. A pattern without a bias projects from the upper line.
+func projectTarget(p Pattern, index int) decimal.Decimal {
+	if p.Bias < 0 {
+		return p.Lower.At(index).Sub(p.Height)
+	}
+	return p.Upper.At(index).Add(p.Height)
+}
+
+// track follows the pattern from its detection up to index and sets its
+// status
+func track(p *Pattern, s *series.TimeSeries, index int, cfg Config) {
+	trackFrom(p, s, p.Detected+1, index, cfg)
+}
+
+// trackFrom follows the pattern over the candles from from to index,
+// continuing from its status at from-1
+func trackFrom(p *Pattern, s *series.TimeSeries, from, index int, cfg Config) {
+	expiry := cfg.Expiry
+	if expiry <= 0 {
+		expiry = max(p.End()-p.Start(), 1)
+	}
+	margin := decimal.New(cfg.BreakoutMargin)
+
+	for i := from; i <= index; i++ {
+		c := s.Candles[i]
+		switch p.Status {
+		case Forming:
+			upper, lower := p.Upper.At(i), p.Lower.At(i)
+			breaksUp := c.ClosePrice.GT(upper.Add(upper.Abs().Mul(margin)))
+			breaksDown := c.ClosePrice.LT(lower.Sub(lower.Abs().Mul(margin)))
+			switch {
+			case breaksUp && p.Bias >= 0, breaksDown && p.Bias <= 0:
+				if p.Bias == 0 {
+					p.Bias = 1
+					if breaksDown {
+						p.Bias = -1
+					}
+				}
+				p.Status, p.BreakoutIndex = Confirmed, i
+				p.Target = projectTarget(*p, i)
+			case breaksUp || breaksDown || i-p.End() > expiry:
+				p.Status = Failed
+				return
+			}
+		case Confirmed:
+			if p.Bias > 0 && c.MaxPrice.GTE(p.Target) || p.Bias < 0 && c.MinPrice.LTE(p.Target) {
+				p.Status = Completed
+				return
+			}
+		default:
+			return
+		}
+	}
+}
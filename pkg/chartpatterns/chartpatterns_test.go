@@ -0,0 +1,145 @@
+package chartpatterns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// pathSeries joins waypoints with straight lines of step candles each
+func pathSeries(step int, waypoints ...float64) *series.TimeSeries {
+	ts := series.NewTimeSeries()
+	add := func(price float64) {
+		c := series.NewCandle(series.NewTimePeriod(time.Unix(int64(len(ts.Candles))*60, 0), time.Minute))
+		c.OpenPrice, c.ClosePrice = decimal.New(price), decimal.New(price)
+		c.MaxPrice, c.MinPrice = decimal.New(price), decimal.New(price)
+		c.Volume = decimal.ONE
+		ts.AddCandle(c)
+	}
+	add(waypoints[0])
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1], waypoints[i]
+		for j := 1; j <= step; j++ {
+			add(from + (to-from)*float64(j)/float64(step))
+		}
+	}
+	return ts
+}
+
+func findKind(patterns []Pattern, kind Kind) (Pattern, bool) {
+	for _, p := range patterns {
+		if p.Kind == kind {
+			return p, true
+		}
+	}
+	return Pattern{}, false
+}
+
+func TestDoubleTop(t *testing.T) {
+	ts := pathSeries(5, 100, 120, 105, 120.5, 95, 80)
+
+	_, ok := findKind(DetectAt(ts, 17, Config{}), DoubleTop)
+	assert.False(t, ok, "the second peak is confirmed at 18")
+
+	p, ok := findKind(DetectAt(ts, 18, Config{}), DoubleTop)
+	require.True(t, ok)
+	assert.Equal(t, []int{5, 10, 15}, []int{p.Points[0].Index, p.Points[1].Index, p.Points[2].Index})
+	assert.Equal(t, 18, p.Detected)
+	assert.Equal(t, Forming, p.Status)
+	assert.Equal(t, -1, p.Bias)
+	assert.False(t, p.Bullish())
+	assert.Equal(t, "105", p.Neckline().At(30).String())
+	assert.Equal(t, "15.5", p.Height.String())
+	assert.Equal(t, "89.5", p.Target.String())
+
+	p, _ = findKind(DetectAt(ts, 19, Config{}), DoubleTop)
+	assert.Equal(t, Confirmed, p.Status)
+	assert.Equal(t, 19, p.BreakoutIndex)
+
+	p, _ = findKind(Detect(ts, Config{}), DoubleTop)
+	assert.Equal(t, Completed, p.Status)
+}
+
+func TestDoubleTop_Fails(t *testing.T) {
+	ts := pathSeries(5, 100, 120, 105, 120.5, 110, 130)
+	p, ok := findKind(Detect(ts, Config{}), DoubleTop)
+	require.True(t, ok)
+	assert.Equal(t, Failed, p.Status)
+	assert.Equal(t, -1, p.BreakoutIndex)
+}
+
+func TestHeadAndShoulders(t *testing.T) {
+	waypoints := []float64{100, 115, 105, 125, 106, 116, 90}
+	p, ok := findKind(Detect(pathSeries(5, waypoints...), Config{}), HeadAndShoulders)
+	require.True(t, ok)
+	require.Len(t, p.Points, 5)
+	assert.Equal(t, "125", p.Points[2].Price.String())
+	assert.Equal(t, "105.5", p.Lower.At(15).String(), "the neckline joins the troughs")
+	assert.Equal(t, Confirmed, p.Status)
+
+	inverted := make([]float64, len(waypoints))
+	for i, w := range waypoints {
+		inverted[i] = 200 - w
+	}
+	inv, ok := findKind(Detect(pathSeries(5, inverted...), Config{}), InverseHeadAndShoulders)
+	require.True(t, ok)
+	assert.Equal(t, 1, inv.Bias)
+	assert.Equal(t, "75", inv.Points[2].Price.String())
+	assert.Equal(t, "94.5", inv.Neckline().At(15).String())
+	assert.Equal(t, "75", inv.Lower.At(0).String())
+	assert.Equal(t, Confirmed, inv.Status)
+}
+
+func TestTripleBottom(t *testing.T) {
+	p, ok := findKind(Detect(pathSeries(5, 110, 90, 100, 90.5, 101, 90.2, 120), Config{}), TripleBottom)
+	require.True(t, ok)
+	assert.Len(t, p.Points, 5)
+	assert.Equal(t, "101", p.Neckline().At(0).String(), "the higher of the two peaks")
+	assert.Equal(t, "112", p.Target.String())
+	assert.Equal(t, Completed, p.Status)
+}
+
+func TestAscendingTriangle(t *testing.T) {
+	ts := pathSeries(5, 100, 90, 120, 100, 120.4, 112, 135)
+	p, ok := findKind(Detect(ts, Config{}), AscendingTriangle)
+	require.True(t, ok)
+	assert.Equal(t, 1, p.Bias)
+	assert.Equal(t, 5, p.Start())
+	assert.Equal(t, 20, p.End())
+	assert.Equal(t, Confirmed, p.Status)
+	assert.True(t, p.Target.GT(p.Upper.At(p.BreakoutIndex)))
+}
+
+func TestBullFlag(t *testing.T) {
+	ts := pathSeries(5, 100, 95, 130, 124, 128, 122, 140)
+	p, ok := findKind(Detect(ts, Config{}), BullFlag)
+	require.True(t, ok)
+	assert.Equal(t, "35", p.Height.String(), "the pole")
+	assert.Equal(t, 10, p.Start())
+	assert.Equal(t, Confirmed, p.Status)
+	assertApprox(t, p.Upper.At(p.BreakoutIndex).Float()+35, p.Target.Float())
+}
+
+func TestSymmetricTriangle_TakesBreakoutDirection(t *testing.T) {
+	ts := pathSeries(5, 100, 130, 100, 124, 106, 115, 90)
+	p, ok := findKind(Detect(ts, Config{}), SymmetricTriangle)
+	require.True(t, ok)
+	assert.Equal(t, Confirmed, p.Status)
+	assert.Equal(t, -1, p.Bias)
+}
+
+func TestKindString(t *testing.T) {
+	assert.Equal(t, "Inverse Head and Shoulders", InverseHeadAndShoulders.String())
+	assert.Equal(t, "None", Kind(0).String())
+	assert.Equal(t, "Completed", Completed.String())
+}
+
+func assertApprox(t *testing.T, want, got float64) {
+	t.Helper()
+	assert.InDelta(t, want, got, 1e-9)
+}
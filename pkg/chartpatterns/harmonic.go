@@ -0,0 +1,286 @@
+package chartpatterns
+
+import (
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// HarmonicKind is the type of a harmonic XABCD pattern
+type HarmonicKind int
+
+const (
+	Gartley HarmonicKind = iota + 1
+	Bat
+	Butterfly
+	Crab
+	Shark
+	Cypher
+)
+
+var harmonicNames = map[HarmonicKind]string{
+	Gartley:   "Gartley",
+	Bat:       "Bat",
+	Butterfly: "Butterfly",
+	Crab:      "Crab",
+	Shark:     "Shark",
+	Cypher:    "Cypher",
+}
+
+func (k HarmonicKind) String() string {
+	if s, ok := harmonicNames[k]; ok {
+		return s
+	}
+	return "None"
+}
+
+// harmonicSpec holds the Fibonacci ratio ranges of a harmonic pattern. ab is
+// AB as a fraction of XA, bc BC of AB and cd CD of BC. xd places D as a
+// fraction of XA measured from A, or of XC measured from C when fromC is set.
+type harmonicSpec struct {
+	kind  HarmonicKind
+	ab    [2]float64
+	bc    [2]float64
+	cd    [2]float64
+	xd    [2]float64
+	fromC bool
+}
+
+var harmonicSpecs = []harmonicSpec{
+	{kind: Gartley, ab: [2]float64{0.618, 0.618}, bc: [2]float64{0.382, 0.886}, cd: [2]float64{1.272, 1.618}, xd: [2]float64{0.786, 0.786}},
+	{kind: Bat, ab: [2]float64{0.382, 0.5}, bc: [2]float64{0.382, 0.886}, cd: [2]float64{1.618, 2.618}, xd: [2]float64{0.886, 0.886}},
+	{kind: Butterfly, ab: [2]float64{0.786, 0.786}, bc: [2]float64{0.382, 0.886}, cd: [2]float64{1.618, 2.24}, xd: [2]float64{1.272, 1.618}},
+	{kind: Crab, ab: [2]float64{0.382, 0.618}, bc: [2]float64{0.382, 0.886}, cd: [2]float64{2.24, 3.618}, xd: [2]float64{1.618, 1.618}},
+	{kind: Shark, ab: [2]float64{0.382, 0.886}, bc: [2]float64{1.13, 1.618}, cd: [2]float64{1.618, 2.24}, xd: [2]float64{0.886, 1.13}, fromC: true},
+	{kind: Cypher, ab: [2]float64{0.382, 0.618}, bc: [2]float64{1.13, 1.414}, cd: [2]float64{1.272, 2}, xd: [2]float64{0.786, 0.786}, fromC: true},
+}
+
+// Zone is a price band
+type Zone struct {
+	Low  decimal.Decimal
+	High decimal.Decimal
+}
+
+// Contains reports whether price lies in the zone, edges included
+func (z Zone) Contains(price decimal.Decimal) bool {
+	return price.GTE(z.Low) && price.LTE(z.High)
+}
+
+// HarmonicRatios are the measured ratios of a harmonic pattern. CD and XD are
+// zero until D is known.
+type HarmonicRatios struct {
+	AB decimal.Decimal
+	BC decimal.Decimal
+	CD decimal.Decimal
+	XD decimal.Decimal
+}
+
+// HarmonicPattern is a harmonic XABCD pattern
+type HarmonicPattern struct {
+	Kind HarmonicKind
+	// Points are X, A, B, C and, once the pattern is complete, D
+	Points []Point
+	// Bias is 1 for patterns completing at a low, where D is a buy, and -1
+	// for those completing at a high
+	Bias int
+	// PRZ is the potential reversal zone where the ratio ranges of CD and XD
+	// overlap, and where D must lie
+	PRZ    Zone
+	Ratios HarmonicRatios
+	// Detected is the index at which the last point was confirmed
+	Detected int
+	// Status is Forming while D is awaited, Completed once a swing is
+	// confirmed in the PRZ, and Failed when price has run through the PRZ or
+	// back beyond C without one
+	Status Status
+}
+
+// Bullish reports whether the pattern completes at a low
+func (p HarmonicPattern) Bullish() bool { return p.Bias > 0 }
+
+// DetectHarmonics returns the harmonic patterns of s with their status at the
+// last candle
+func DetectHarmonics(s *series.TimeSeries, cfg Config) []HarmonicPattern {
+	if s == nil {
+		return nil
+	}
+	return DetectHarmonicsAt(s, len(s.Candles)-1, cfg)
+}
+
+// DetectHarmonicsAt returns the harmonic patterns known at index, from the
+// swings confirmed by then. Every completed pattern is returned, along with
+// the patterns still awaiting D after the latest swing. It never looks past
+// index. Patterns are ordered by the index of X.
+func DetectHarmonicsAt(s *series.TimeSeries, index int, cfg Config) []HarmonicPattern {
+	if s == nil || index < 0 || index >= len(s.Candles) {
+		return nil
+	}
+	cfg = cfg.withDefaults()
+	known := knownSwings(s, index, cfg)
+	tol := cfg.RatioTolerance
+
+	var patterns []HarmonicPattern
+	for i := 0; i+3 < len(known); i++ {
+		xabc := known[i : i+4]
+		for _, spec := range harmonicSpecs {
+			p, ok := spec.match(xabc, tol)
+			if !ok {
+				continue
+			}
+			if i+4 < len(known) {
+				d := known[i+4]
+				if !p.PRZ.Contains(d.Price) {
+					continue
+				}
+				p.complete(spec, d)
+			} else {
+				trackHarmonic(&p, s, index)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// within reports whether r lies in rng widened by tol on each side
+func within(r decimal.Decimal, rng [2]float64, tol float64) bool {
+	return r.GTE(decimal.New(rng[0]*(1-tol))) && r.LTE(decimal.New(rng[1]*(1+tol)))
+}
+
+// projectZone returns the prices base + leg*r for r in rng widened by tol
+func projectZone(base, leg decimal.Decimal, rng [2]float64, tol float64) Zone {
+	a := base.Add(leg.Mul(decimal.New(rng[0] * (1 - tol))))
+	b := base.Add(leg.Mul(decimal.New(rng[1] * (1 + tol))))
+	return Zone{Low: a.Min(b), High: a.Max(b)}
+}
+
+// match checks the XA, AB and BC legs of the swings against the spec and
+// projects the PRZ for D
+func (spec harmonicSpec) match(xabc []indicators.SwingPoint, tol float64) (HarmonicPattern, bool) {
+	x, a, b, c := xabc[0].Price, xabc[1].Price, xabc[2].Price, xabc[3].Price
+	xa, ab := a.Sub(x), b.Sub(a)
+	if xa.IsZero() || ab.IsZero() {
+		return HarmonicPattern{}, false
+	}
+	ratios := HarmonicRatios{AB: ab.Div(xa).Abs(), BC: c.Sub(b).Div(ab).Abs()}
+	if !within(ratios.AB, spec.ab, tol) || !within(ratios.BC, spec.bc, tol) {
+		return HarmonicPattern{}, false
+	}
+
+	base := a
+	if spec.fromC {
+		base = c
+	}
+	xd := projectZone(base, x.Sub(base), spec.xd, tol)
+	cd := projectZone(c, b.Sub(c), spec.cd, tol)
+	prz := Zone{Low: xd.Low.Max(cd.Low), High: xd.High.Min(cd.High)}
+	if prz.Low.GT(prz.High) {
+		return HarmonicPattern{}, false
+	}
+
+	p := HarmonicPattern{
+		Kind:     spec.kind,
+		Bias:     1,
+		PRZ:      prz,
+		Ratios:   ratios,
+		Detected: xabc[3].Confirmed,
+	}
+	if xabc[0].High {
+		p.Bias = -1
+	}
+	for _, sw := range xabc {
+		p.Points = append(p.Points, point(sw))
+	}
+	return p, true
+}
+
+// complete adds D to the pattern
+func (p *HarmonicPattern) complete(spec harmonicSpec, d indicators.SwingPoint) {
+	x, b, c := p.Points[0].Price, p.Points[2].Price, p.Points[3].Price
+	base := p.Points[1].Price
+	if spec.fromC {
+		base = c
+	}
+	p.Ratios.CD = c.Sub(d.Price).Div(c.Sub(b)).Abs()
+	p.Ratios.XD = base.Sub(d.Price).Div(base.Sub(x)).Abs()
+	p.Points = append(p.Points, point(d))
+	p.Detected = d.Confirmed
+	p.Status = Completed
+}
+
+// trackHarmonic fails a pattern awaiting D once price after C runs through
+// the far side of the PRZ or back beyond C
+func trackHarmonic(p *HarmonicPattern, s *series.TimeSeries, index int) {
+	trackHarmonicFrom(p, s, p.Points[3].Index+1, index)
+}
+
+// trackHarmonicFrom follows a pattern awaiting D over the candles from from to
+// index
+func trackHarmonicFrom(p *HarmonicPattern, s *series.TimeSeries, from, index int) {
+	c := p.Points[3]
+	for i := from; i <= index; i++ {
+		candle := s.Candles[i]
+		if p.Bias > 0 && (candle.MinPrice.LT(p.PRZ.Low) || candle.MaxPrice.GT(c.Price)) ||
+			p.Bias < 0 && (candle.MaxPrice.GT(p.PRZ.High) || candle.MinPrice.LT(c.Price)) {
+			p.Status = Failed
+			return
+		}
+	}
+}
+
+var harmonicOutputs = []string{"signal", "prz_low", "prz_high"}
+
+type harmonicIndicator struct {
+	scanner *HarmonicScanner
+	cfg     Config
+}
+
+type harmonicLine struct {
+	h    *harmonicIndicator
+	line int
+}
+
+func (l harmonicLine) Calculate(index int) decimal.Decimal { return l.h.values(index)[l.line] }
+
+func (l harmonicLine) Lookback() int { return l.h.cfg.Lookback() }
+
+// NewHarmonicIndicator returns a MultiOutputIndicator over the harmonic
+// patterns of the given kinds, or all kinds if none are given, known at each
+// index. The signal output is 1 (or -1) at the index where a bullish (or
+// bearish) pattern completes and 0 elsewhere; Calculate returns it. prz_low
+// and prz_high are the PRZ of the newest pattern that is forming or complete,
+// NaN when there is none. Panics if s is nil.
+func NewHarmonicIndicator(s *series.TimeSeries, cfg Config, kinds ...HarmonicKind) indicators.MultiOutputIndicator {
+	if s == nil {
+		panic("goflux: HarmonicIndicator series cannot be nil")
+	}
+	h := &harmonicIndicator{scanner: NewHarmonicScanner(s, cfg, kinds...), cfg: cfg}
+	return indicators.NewMultiOutputIndicator(harmonicOutputs, harmonicLine{h, 0}, harmonicLine{h, 1}, harmonicLine{h, 2})
+}
+
+// harmonicKindSet returns the set of kinds, nil for any kind
+func harmonicKindSet(kinds []HarmonicKind) map[HarmonicKind]bool {
+	if len(kinds) == 0 {
+		return nil
+	}
+	set := make(map[HarmonicKind]bool, len(kinds))
+	for _, k := range kinds {
+		set[k] = true
+	}
+	return set
+}
+
+func (h *harmonicIndicator) values(index int) [3]decimal.Decimal {
+	if index < 0 || index >= len(h.scanner.series.Candles) {
+		return [3]decimal.Decimal{decimal.ZERO, decimal.ZERO, decimal.ZERO}
+	}
+	step := h.scanner.at(index)
+	v := [3]decimal.Decimal{decimal.ZERO, decimal.NaN, decimal.NaN}
+	for _, p := range step.completed {
+		v[0] = decimal.New(float64(p.Bias))
+	}
+	if step.newest != nil {
+		v[1], v[2] = step.newest.PRZ.Low, step.newest.PRZ.High
+	}
+	return v
+}
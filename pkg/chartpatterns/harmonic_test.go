@@ -0,0 +1,102 @@
+package chartpatterns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+func findHarmonic(patterns []HarmonicPattern, kind HarmonicKind) (HarmonicPattern, bool) {
+	for _, p := range patterns {
+		if p.Kind == kind {
+			return p, true
+		}
+	}
+	return HarmonicPattern{}, false
+}
+
+// gartley is a bullish Gartley: AB 0.618 of XA, BC 0.618 of AB, CD 1.44 of
+// BC and D at 0.786 of XA. X is at 5, A 10, B 15, C 20 and D 25.
+var gartley = []float64{120, 100, 200, 138.2, 176.4, 121.4, 160}
+
+func TestDetectHarmonics_Gartley(t *testing.T) {
+	ts := pathSeries(5, gartley...)
+
+	p, ok := findHarmonic(DetectHarmonicsAt(ts, 23, Config{}), Gartley)
+	require.True(t, ok, "XABC is known once C is confirmed")
+	assert.Equal(t, Forming, p.Status)
+	assert.Len(t, p.Points, 4)
+	assert.Equal(t, 23, p.Detected)
+	assert.True(t, p.Bullish())
+	assert.True(t, p.PRZ.Contains(decimal.New(121.4)))
+	assert.False(t, p.PRZ.Contains(decimal.New(140)))
+	assert.InDelta(t, 0.618, p.Ratios.AB.Float(), 1e-9)
+	assert.InDelta(t, 0.618, p.Ratios.BC.Float(), 0.001)
+
+	p, ok = findHarmonic(DetectHarmonics(ts, Config{}), Gartley)
+	require.True(t, ok)
+	assert.Equal(t, Completed, p.Status)
+	require.Len(t, p.Points, 5)
+	assert.Equal(t, 25, p.Points[4].Index)
+	assert.Equal(t, 28, p.Detected)
+	assert.InDelta(t, 0.786, p.Ratios.XD.Float(), 1e-9)
+	assert.InDelta(t, 1.44, p.Ratios.CD.Float(), 0.001)
+
+	_, ok = findHarmonic(DetectHarmonics(ts, Config{}), Bat)
+	assert.False(t, ok)
+}
+
+func TestDetectHarmonics_Bearish(t *testing.T) {
+	inverted := make([]float64, len(gartley))
+	for i, w := range gartley {
+		inverted[i] = 300 - w
+	}
+	p, ok := findHarmonic(DetectHarmonics(pathSeries(5, inverted...), Config{}), Gartley)
+	require.True(t, ok)
+	assert.Equal(t, -1, p.Bias)
+	assert.Equal(t, Completed, p.Status)
+	assert.Equal(t, "178.6", p.Points[4].Price.String())
+}
+
+func TestDetectHarmonics_FailsThroughPRZ(t *testing.T) {
+	ts := pathSeries(5, 120, 100, 200, 138.2, 176.4, 60)
+	p, ok := findHarmonic(DetectHarmonics(ts, Config{}), Gartley)
+	require.True(t, ok)
+	assert.Equal(t, Failed, p.Status)
+}
+
+func TestDetectHarmonics_Tolerance(t *testing.T) {
+	// AB is 0.65 of XA, outside a 2% tolerance around 0.618
+	ts := pathSeries(5, 120, 100, 200, 135, 175, 120, 160)
+	_, ok := findHarmonic(DetectHarmonics(ts, Config{RatioTolerance: 0.02}), Gartley)
+	assert.False(t, ok)
+	_, ok = findHarmonic(DetectHarmonics(ts, Config{RatioTolerance: 0.1}), Gartley)
+	assert.True(t, ok)
+}
+
+func TestHarmonicIndicator(t *testing.T) {
+	ts := pathSeries(5, gartley...)
+	ind := NewHarmonicIndicator(ts, Config{}, Gartley)
+
+	assert.Equal(t, []string{"signal", "prz_low", "prz_high"}, ind.Outputs())
+	assert.True(t, ind.Output("prz_low").Calculate(20).IsNaN())
+	assert.False(t, ind.Output("prz_low").Calculate(23).IsNaN())
+	for i := range ts.Candles {
+		want := 0.0
+		if i == 28 {
+			want = 1
+		}
+		assert.Equal(t, want, ind.Calculate(i).Float(), "index %d", i)
+	}
+
+	none := NewHarmonicIndicator(ts, Config{}, Crab)
+	assert.True(t, none.Output("prz_high").Calculate(28).IsNaN())
+}
+
+func TestHarmonicKind_String(t *testing.T) {
+	assert.Equal(t, "Cypher", Cypher.String())
+	assert.Equal(t, "None", HarmonicKind(0).String())
+}
@@ -0,0 +1,371 @@
+package chartpatterns
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// swingLog follows the alternating swings known at each index of a series,
+// one index after another
+type swingLog struct {
+	scanner *indicators.SwingScanner
+	used    int
+	known   []indicators.SwingPoint
+}
+
+// advance adds the swings confirmed by index and reports whether the known
+// swings changed
+func (l *swingLog) advance(index int) bool {
+	swings := l.scanner.Scan(index)
+	changed := false
+	for ; l.used < len(swings); l.used++ {
+		var added bool
+		l.known, added = alternate(l.known, swings[l.used])
+		changed = changed || added
+	}
+	return changed
+}
+
+// final returns the number of known swings that can no longer change: all
+// but the last, which a more extreme swing of its side may still replace
+func (l *swingLog) final() int { return max(len(l.known)-1, 0) }
+
+// pending returns the known swings from the one at from on, with the swings
+// confirmed on the candle at index added without recording them, where the
+// first returned swing is among the known ones, and whether they changed. The
+// log must have advanced to the candle before index.
+func (l *swingLog) pending(index, from int) ([]indicators.SwingPoint, int, bool) {
+	base := max(min(from, len(l.known)-1), 0)
+	known := append([]indicators.SwingPoint(nil), l.known[base:]...)
+	changed := false
+	for _, sw := range l.scanner.Pending(index) {
+		var added bool
+		known, added = alternate(known, sw)
+		changed = changed || added
+	}
+	return known, base, changed
+}
+
+// trackedPattern is a pattern with the last index its status was tracked to
+type trackedPattern struct {
+	Pattern
+	at int
+}
+
+// resumePoints are the swings matching resumes from: one for tops, one for
+// bottoms and one for consolidations
+type resumePoints [3]int
+
+// first returns the earliest of the points
+func (r resumePoints) first() int { return min(r[0], r[1], r[2]) }
+
+// shift returns the points counted from swing base
+func (r resumePoints) shift(base int) resumePoints {
+	return resumePoints{r[0] - base, r[1] - base, r[2] - base}
+}
+
+// BreakoutScanner finds the patterns breaking out on each candle of a series,
+// as DetectAt reports them, for callers asking about every index in turn.
+// Each swing is confirmed and each pattern tracked once, where calling
+// DetectAt at every index redoes both. Closed candles are scanned once; the
+// last candle, which may still be changing, is applied to what is known of
+// the candle before on each call and not recorded. It is safe for concurrent
+// use.
+type BreakoutScanner struct {
+	series *series.TimeSeries
+	cfg    Config
+	tol    decimal.Decimal
+
+	mu     sync.Mutex
+	swings swingLog
+	resume resumePoints
+	// active are the patterns of final swings still forming, and tail those
+	// reading swings that may change, found again whenever the swings do
+	active    []trackedPattern
+	tail      []trackedPattern
+	breakouts [][]Pattern
+}
+
+// NewBreakoutScanner returns a BreakoutScanner over the patterns of s. Panics
+// if s is nil.
+func NewBreakoutScanner(s *series.TimeSeries, cfg Config) *BreakoutScanner {
+	if s == nil {
+		panic("goflux: BreakoutScanner series cannot be nil")
+	}
+	cfg = cfg.withDefaults()
+	return &BreakoutScanner{
+		series: s,
+		cfg:    cfg,
+		tol:    decimal.New(cfg.Tolerance),
+		swings: swingLog{scanner: swingScanner(s, cfg)},
+	}
+}
+
+// Breakouts returns the patterns known at index that broke out on the candle
+// at index, ordered by the index of their first point. The patterns are
+// shared and must not be modified.
+func (sc *BreakoutScanner) Breakouts(index int) []Pattern {
+	if index < 0 || index >= len(sc.series.Candles) {
+		return nil
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for len(sc.breakouts) < index {
+		sc.step(len(sc.breakouts))
+	}
+	if index < len(sc.breakouts) {
+		return sc.breakouts[index]
+	}
+	if index < len(sc.series.Candles)-1 {
+		sc.step(index)
+		return sc.breakouts[index]
+	}
+
+	// The last candle is followed on copies of the patterns
+	active := append([]trackedPattern(nil), sc.active...)
+	tail := append([]trackedPattern(nil), sc.tail...)
+	if known, base, changed := sc.swings.pending(index, sc.resume.first()-1); changed {
+		found, next := sc.match(known, len(known)-1, sc.resume.shift(base))
+		active = append(active, found...)
+		tail, _ = sc.match(known, len(known), next)
+	}
+	return sc.follow(index, &active, tail)
+}
+
+// step adds the swings confirmed at index, then tracks the patterns over the
+// candle at index and records those breaking out on it
+func (sc *BreakoutScanner) step(index int) {
+	if sc.swings.advance(index) {
+		known, final := sc.swings.known, sc.swings.final()
+		var found []trackedPattern
+		found, sc.resume = sc.match(known, final, sc.resume)
+		sc.active = append(sc.active, found...)
+		sc.tail, _ = sc.match(known, len(known), sc.resume)
+	}
+	sc.breakouts = append(sc.breakouts, sc.follow(index, &sc.active, sc.tail))
+}
+
+// match finds the patterns from the resume points on, reading only the first
+// final swings, and returns them with the points to resume from next
+func (sc *BreakoutScanner) match(known []indicators.SwingPoint, final int, from resumePoints) ([]trackedPattern, resumePoints) {
+	var found []Pattern
+	next := from
+	for side, bottoms := range []bool{false, true} {
+		var patterns []Pattern
+		patterns, next[side] = reversalsFrom(known, bottoms, from[side], final, sc.tol)
+		found = append(found, patterns...)
+	}
+	var patterns []Pattern
+	patterns, next[2] = consolidationsFrom(known, from[2], final, sc.tol)
+	found = append(found, patterns...)
+
+	tracked := make([]trackedPattern, len(found))
+	for i, p := range found {
+		tracked[i] = trackedPattern{Pattern: p, at: p.Detected}
+	}
+	return tracked, next
+}
+
+// follow tracks the active and tail patterns over the candle at index,
+// dropping active patterns no longer forming, and returns those breaking out
+// on it
+func (sc *BreakoutScanner) follow(index int, active *[]trackedPattern, tail []trackedPattern) []Pattern {
+	var breakouts []Pattern
+	forming := (*active)[:0]
+	for _, p := range *active {
+		if sc.track(&p, index) {
+			breakouts = append(breakouts, p.Pattern)
+		}
+		if p.Status == Forming {
+			forming = append(forming, p)
+		}
+	}
+	*active = forming
+	for i := range tail {
+		if sc.track(&tail[i], index) {
+			breakouts = append(breakouts, tail[i].Pattern)
+		}
+	}
+	sort.SliceStable(breakouts, func(i, j int) bool { return breakouts[i].Start() < breakouts[j].Start() })
+	return breakouts
+}
+
+// track follows a forming pattern up to index and reports whether it broke
+// out on the candle at index
+func (sc *BreakoutScanner) track(p *trackedPattern, index int) bool {
+	if p.Status == Forming && p.at < index {
+		trackFrom(&p.Pattern, sc.series, p.at+1, index, sc.cfg)
+		p.at = index
+	}
+	return p.BreakoutIndex == index
+}
+
+// HarmonicScanner finds the harmonic patterns of the given kinds, or all
+// kinds if none are given, completing on each candle of a series as
+// DetectHarmonicsAt reports them, for callers asking about every index in
+// turn. It shares the way BreakoutScanner confirms each swing once, matching
+// each run of final swings once and tracking patterns awaiting D as candles
+// close. The last candle is applied to what is known of the candle before on
+// each call and not recorded. It is safe for concurrent use.
+type HarmonicScanner struct {
+	series *series.TimeSeries
+	cfg    Config
+	kinds  map[HarmonicKind]bool
+
+	mu     sync.Mutex
+	swings swingLog
+	state  harmonicState
+	steps  []harmonicStep
+}
+
+// harmonicState is what is known of the harmonic patterns from the swings
+// confirmed so far
+type harmonicState struct {
+	// next is the first XABCD run not yet matched among the final swings,
+	// and latest the newest pattern completed by them
+	next   int
+	latest *HarmonicPattern
+	// tail are the patterns completed by the newest swing and forming those
+	// awaiting D after it, found again whenever the swings change
+	tail    []HarmonicPattern
+	forming []trackedHarmonic
+}
+
+// trackedHarmonic is a pattern awaiting D with the last index it was
+// tracked to
+type trackedHarmonic struct {
+	HarmonicPattern
+	at int
+}
+
+// harmonicStep is what is known of the harmonic patterns at an index: those
+// completing there, and the newest pattern completed or still forming, nil
+// if there is none
+type harmonicStep struct {
+	completed []HarmonicPattern
+	newest    *HarmonicPattern
+}
+
+// NewHarmonicScanner returns a HarmonicScanner over the harmonic patterns of
+// s. Panics if s is nil.
+func NewHarmonicScanner(s *series.TimeSeries, cfg Config, kinds ...HarmonicKind) *HarmonicScanner {
+	if s == nil {
+		panic("goflux: HarmonicScanner series cannot be nil")
+	}
+	cfg = cfg.withDefaults()
+	return &HarmonicScanner{
+		series: s,
+		cfg:    cfg,
+		kinds:  harmonicKindSet(kinds),
+		swings: swingLog{scanner: swingScanner(s, cfg)},
+	}
+}
+
+// Completed returns the patterns known at index whose D was confirmed on the
+// candle at index. The patterns are shared and must not be modified.
+func (sc *HarmonicScanner) Completed(index int) []HarmonicPattern {
+	if index < 0 || index >= len(sc.series.Candles) {
+		return nil
+	}
+	return sc.at(index).completed
+}
+
+// at returns the step at index, which must be a candle of the series
+func (sc *HarmonicScanner) at(index int) harmonicStep {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for len(sc.steps) < index {
+		sc.step(len(sc.steps))
+	}
+	if index < len(sc.steps) {
+		return sc.steps[index]
+	}
+	if index < len(sc.series.Candles)-1 {
+		sc.step(index)
+		return sc.steps[index]
+	}
+
+	// The last candle is followed on a copy of the state
+	state := sc.state
+	state.forming = append([]trackedHarmonic(nil), sc.state.forming...)
+	from := min(state.next, len(sc.swings.known)-4)
+	if known, base, changed := sc.swings.pending(index, from); changed {
+		state.next -= base
+		sc.rematch(&state, known, len(known)-1)
+	}
+	return sc.follow(&state, index)
+}
+
+// step adds the swings confirmed at index, then tracks the patterns awaiting
+// D over the candle at index and records the step
+func (sc *HarmonicScanner) step(index int) {
+	if sc.swings.advance(index) {
+		sc.rematch(&sc.state, sc.swings.known, sc.swings.final())
+	}
+	sc.steps = append(sc.steps, sc.follow(&sc.state, index))
+}
+
+// rematch completes the runs of final swings from state.next on, and finds
+// the tail and forming patterns of the swings known
+func (sc *HarmonicScanner) rematch(state *harmonicState, known []indicators.SwingPoint, final int) {
+	for ; state.next+5 <= final; state.next++ {
+		if completed := sc.complete(known, state.next); len(completed) > 0 {
+			state.latest = &completed[len(completed)-1]
+		}
+	}
+	state.tail = nil
+	for i := state.next; i+4 < len(known); i++ {
+		state.tail = append(state.tail, sc.complete(known, i)...)
+	}
+	state.forming = nil
+	if i := len(known) - 4; i >= 0 {
+		for _, spec := range harmonicSpecs {
+			if p, ok := spec.match(known[i:], sc.cfg.RatioTolerance); ok && (sc.kinds == nil || sc.kinds[p.Kind]) {
+				state.forming = append(state.forming, trackedHarmonic{HarmonicPattern: p, at: p.Points[3].Index})
+			}
+		}
+	}
+}
+
+// follow tracks the patterns awaiting D over the candle at index and returns
+// the step there
+func (sc *HarmonicScanner) follow(state *harmonicState, index int) harmonicStep {
+	step := harmonicStep{newest: state.latest}
+	for i := range state.tail {
+		if state.tail[i].Detected == index {
+			step.completed = append(step.completed, state.tail[i])
+		}
+		step.newest = &state.tail[i]
+	}
+	for i := range state.forming {
+		p := &state.forming[i]
+		if p.Status != Failed && p.at < index {
+			trackHarmonicFrom(&p.HarmonicPattern, sc.series, p.at+1, index)
+			p.at = index
+		}
+		if p.Status != Failed {
+			newest := p.HarmonicPattern
+			step.newest = &newest
+		}
+	}
+	return step
+}
+
+// complete returns the patterns of the kinds scanned for whose XABC are the
+// four swings from i and whose D is the swing after them
+func (sc *HarmonicScanner) complete(known []indicators.SwingPoint, i int) []HarmonicPattern {
+	var completed []HarmonicPattern
+	for _, spec := range harmonicSpecs {
+		p, ok := spec.match(known[i:i+4], sc.cfg.RatioTolerance)
+		if !ok || !p.PRZ.Contains(known[i+4].Price) || sc.kinds != nil && !sc.kinds[p.Kind] {
+			continue
+		}
+		p.complete(spec, known[i+4])
+		completed = append(completed, p)
+	}
+	return completed
+}
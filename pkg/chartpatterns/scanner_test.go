@@ -0,0 +1,191 @@
+package chartpatterns
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// walkSeries returns n candles of a seeded random walk
+func walkSeries(n int, seed int64) *series.TimeSeries {
+	rng := rand.New(rand.NewSource(seed)) //nolint:gosec // deterministic test data
+	ts := series.NewTimeSeries()
+	price := 100.0
+	for i := 0; i < n; i++ {
+		open := price
+		price += rng.NormFloat64() * 2
+		c := series.NewCandle(series.NewTimePeriod(time.Unix(int64(i)*60, 0), time.Minute))
+		c.OpenPrice, c.ClosePrice = decimal.New(open), decimal.New(price)
+		c.MaxPrice = decimal.New(max(open, price) + rng.Float64())
+		c.MinPrice = decimal.New(min(open, price) - rng.Float64())
+		c.Volume = decimal.ONE
+		ts.AddCandle(c)
+	}
+	return ts
+}
+
+// breakoutsAt returns the patterns DetectAt reports breaking out at index
+func breakoutsAt(s *series.TimeSeries, index int, cfg Config) []Pattern {
+	var breakouts []Pattern
+	for _, p := range DetectAt(s, index, cfg) {
+		if p.BreakoutIndex == index {
+			breakouts = append(breakouts, p)
+		}
+	}
+	return breakouts
+}
+
+func TestBreakoutScanner_MatchesDetectAt(t *testing.T) {
+	for _, cfg := range []Config{{}, {Strength: 2, Tolerance: 0.03}, {Deviation: 0.03, Depth: 2}} {
+		s := walkSeries(400, 7)
+		scanner := NewBreakoutScanner(s, cfg)
+		total := 0
+		for i := range s.Candles {
+			want := breakoutsAt(s, i, cfg)
+			got := scanner.Breakouts(i)
+			require.Equal(t, len(want), len(got), "breakouts at %d with %+v", i, cfg)
+			for k := range want {
+				assert.Equal(t, want[k].Kind, got[k].Kind)
+				assert.Equal(t, want[k].Points, got[k].Points)
+				assert.Equal(t, want[k].Bias, got[k].Bias)
+			}
+			total += len(got)
+		}
+		assert.Positive(t, total, "no breakouts with %+v", cfg)
+	}
+}
+
+// growSeries adds the candles of full to an empty series one at a time,
+// calling check after each with the candle still open: first spiking beyond
+// its final range, then on its final prices
+func growSeries(full *series.TimeSeries, check func(ts *series.TimeSeries, last int)) {
+	ts := series.NewTimeSeries()
+	for i, c := range full.Candles {
+		open := *c
+		open.ClosePrice = c.ClosePrice.Mul(decimal.New(1.04))
+		open.MaxPrice = c.MaxPrice.Mul(decimal.New(1.05))
+		open.MinPrice = c.MinPrice.Mul(decimal.New(0.95))
+		ts.AddCandle(&open)
+		check(ts, i)
+		open.ClosePrice, open.MaxPrice, open.MinPrice = c.ClosePrice, c.MaxPrice, c.MinPrice
+		check(ts, i)
+	}
+}
+
+func TestBreakoutScanner_GrowingSeries(t *testing.T) {
+	for _, cfg := range []Config{{}, {Deviation: 0.03, Depth: 2}} {
+		full := walkSeries(300, 11)
+		var scanner *BreakoutScanner
+		total := 0
+		growSeries(full, func(ts *series.TimeSeries, last int) {
+			if scanner == nil {
+				scanner = NewBreakoutScanner(ts, cfg)
+			}
+			// The open last candle is applied afresh and the one before it scanned
+			for _, index := range []int{last, last - 1} {
+				want := breakoutsAt(ts, index, cfg)
+				got := scanner.Breakouts(index)
+				require.Equal(t, len(want), len(got), "breakouts at %d of %d with %+v", index, last, cfg)
+				for k := range want {
+					assert.Equal(t, want[k].Kind, got[k].Kind)
+					assert.Equal(t, want[k].Points, got[k].Points)
+				}
+				total += len(want)
+			}
+		})
+		assert.Positive(t, total, "no breakouts with %+v", cfg)
+		assert.Nil(t, scanner.Breakouts(len(full.Candles)))
+	}
+	assert.Panics(t, func() { NewBreakoutScanner(nil, Config{}) })
+}
+
+// harmonicValuesAt returns the outputs of NewHarmonicIndicator at index from
+// DetectHarmonicsAt
+func harmonicValuesAt(s *series.TimeSeries, index int, cfg Config, kinds ...HarmonicKind) [3]decimal.Decimal {
+	set := harmonicKindSet(kinds)
+	v := [3]decimal.Decimal{decimal.ZERO, decimal.NaN, decimal.NaN}
+	newest := -1
+	for _, p := range DetectHarmonicsAt(s, index, cfg) {
+		if set != nil && !set[p.Kind] || p.Status == Failed {
+			continue
+		}
+		if p.Status == Completed && p.Detected == index {
+			v[0] = decimal.New(float64(p.Bias))
+		}
+		if p.Detected >= newest {
+			newest = p.Detected
+			v[1], v[2] = p.PRZ.Low, p.PRZ.High
+		}
+	}
+	return v
+}
+
+func TestHarmonicScanner_MatchesDetectHarmonicsAt(t *testing.T) {
+	for _, kinds := range [][]HarmonicKind{nil, {Bat, Crab}} {
+		for _, cfg := range []Config{{Strength: 2, RatioTolerance: 0.15}, {Deviation: 0.02, RatioTolerance: 0.15}} {
+			s := walkSeries(400, 5)
+			scanner := NewHarmonicScanner(s, cfg, kinds...)
+			h := NewHarmonicIndicator(s, cfg, kinds...)
+			completed := 0
+			for i := range s.Candles {
+				want := harmonicValuesAt(s, i, cfg, kinds...)
+				for line, name := range h.Outputs() {
+					got := h.Output(name).Calculate(i)
+					require.True(t, want[line].Cmp(got) == 0, "%s at %d with %+v: want %s, got %s", name, i, cfg, want[line], got)
+				}
+				var wantCompleted int
+				for _, p := range DetectHarmonicsAt(s, i, cfg) {
+					if p.Status == Completed && p.Detected == i && (kinds == nil || harmonicKindSet(kinds)[p.Kind]) {
+						wantCompleted++
+					}
+				}
+				require.Equal(t, wantCompleted, len(scanner.Completed(i)), "completed at %d", i)
+				completed += wantCompleted
+			}
+			assert.Positive(t, completed, "no harmonics with %+v", cfg)
+		}
+	}
+	assert.Panics(t, func() { NewHarmonicScanner(nil, Config{}) })
+}
+
+func TestHarmonicScanner_GrowingSeries(t *testing.T) {
+	for _, cfg := range []Config{{Strength: 2, RatioTolerance: 0.15}, {Deviation: 0.02, RatioTolerance: 0.15}} {
+		full := walkSeries(400, 5)
+		var h indicators.MultiOutputIndicator
+		var scanner *HarmonicScanner
+		completed := 0
+		growSeries(full, func(ts *series.TimeSeries, last int) {
+			if scanner == nil {
+				scanner = NewHarmonicScanner(ts, cfg)
+				h = NewHarmonicIndicator(ts, cfg)
+			}
+			// The open last candle is applied afresh and the one before it scanned
+			for _, index := range []int{last, last - 1} {
+				if index < 0 {
+					continue
+				}
+				want := harmonicValuesAt(ts, index, cfg)
+				for line, name := range h.Outputs() {
+					got := h.Output(name).Calculate(index)
+					require.True(t, want[line].Cmp(got) == 0, "%s at %d of %d with %+v: want %s, got %s", name, index, last, cfg, want[line], got)
+				}
+				var wantCompleted int
+				for _, p := range DetectHarmonicsAt(ts, index, cfg) {
+					if p.Status == Completed && p.Detected == index {
+						wantCompleted++
+					}
+				}
+				require.Equal(t, wantCompleted, len(scanner.Completed(index)), "completed at %d of %d", index, last)
+				completed += wantCompleted
+			}
+		})
+		assert.Positive(t, completed, "no harmonics with %+v", cfg)
+	}
+}
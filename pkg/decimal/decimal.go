@@ -1,6 +1,7 @@
 package decimal
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -8,23 +9,43 @@ import (
 
 // Decimal represents a high-precision decimal number.
 // It wraps math/big.Float to provide convenient methods for financial calculations.
+//
+// A Decimal may also be NaN, meaning "undefined" (for example a ratio whose
+// denominator is zero). NaN propagates through arithmetic, compares unequal to
+// everything including itself, and is detectable with IsValid or IsNaN.
 type Decimal struct {
 	val *big.Float
+	nan bool
 }
 
+var (
+	// ErrDivisionByZero is returned by checked operations when the divisor is zero
+	ErrDivisionByZero = errors.New("decimal: division by zero")
+	// ErrNaN is returned by checked operations when an operand or the result is undefined
+	ErrNaN = errors.New("decimal: undefined (NaN) value")
+	// ErrNegativeSqrt is returned by SqrtE for negative inputs
+	ErrNegativeSqrt = errors.New("decimal: square root of negative number")
+)
+
 var (
 	// ZERO is a Decimal with value 0
 	ZERO = New(0)
 	// ONE is a Decimal with value 1
 	ONE = New(1)
+	// NaN is an undefined Decimal. Use IsNaN or IsValid to test for it, since
+	// NaN never compares equal to any value.
+	NaN = Decimal{nan: true}
 	// zeroFloat is used for comparisons with a zero-value Decimal. big.Float's
 	// zero value is a valid immutable zero, so this avoids allocating a new
 	// big.Float on every comparison while preserving nil-safe semantics.
 	zeroFloat big.Float
 )
 
-// New creates a new Decimal from a float64
+// New creates a new Decimal from a float64. A NaN input yields NaN.
 func New(f float64) Decimal {
+	if math.IsNaN(f) {
+		return NaN
+	}
 	return Decimal{val: new(big.Float).SetFloat64(f)}
 }
 
@@ -36,16 +57,24 @@ func NewFromInt(i int64) Decimal {
 // NewFromString creates a new Decimal from a string.
 // It panics if string is not a valid number.
 func NewFromString(s string) Decimal {
-	val, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
+	d, err := parse(s)
 	if err != nil {
 		panic(fmt.Sprintf("invalid decimal string: %s", s))
 	}
-	return Decimal{val: val}
+	return d
 }
 
 // NewFromStringWithError creates a new Decimal from a string.
 // Returns error if string is not a valid number.
 func NewFromStringWithError(s string) (Decimal, error) {
+	return parse(s)
+}
+
+// parse accepts anything big.ParseFloat does plus the literal "NaN".
+func parse(s string) (Decimal, error) {
+	if s == "NaN" {
+		return NaN, nil
+	}
 	val, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven)
 	if err != nil {
 		return Decimal{}, err
@@ -61,8 +90,22 @@ func NewFromBigFloat(f *big.Float) Decimal {
 	return Decimal{val: new(big.Float).Copy(f)}
 }
 
+// IsNaN returns true if d is undefined
+func (d Decimal) IsNaN() bool {
+	return d.nan
+}
+
+// IsValid returns true if d holds a defined value. The zero value Decimal{}
+// is valid and equal to 0.
+func (d Decimal) IsValid() bool {
+	return !d.nan
+}
+
 // Add returns d + d2
 func (d Decimal) Add(d2 Decimal) Decimal {
+	if d.nan || d2.nan || infOpposed(d, d2) {
+		return NaN
+	}
 	if d2.val == nil {
 		return d
 	}
@@ -74,6 +117,9 @@ func (d Decimal) Add(d2 Decimal) Decimal {
 
 // Sub returns d - d2
 func (d Decimal) Sub(d2 Decimal) Decimal {
+	if d.nan || d2.nan || infSame(d, d2) {
+		return NaN
+	}
 	if d2.val == nil {
 		return d
 	}
@@ -85,25 +131,74 @@ func (d Decimal) Sub(d2 Decimal) Decimal {
 
 // Mul returns d * d2
 func (d Decimal) Mul(d2 Decimal) Decimal {
+	if d.nan || d2.nan {
+		return NaN
+	}
 	if d.val == nil || d2.val == nil {
 		return Decimal{}
 	}
+	if (d.val.IsInf() && d2.val.Sign() == 0) || (d2.val.IsInf() && d.val.Sign() == 0) {
+		return NaN
+	}
 	return Decimal{val: new(big.Float).Mul(d.val, d2.val)}
 }
 
-// Div returns d / d2
+// Div returns d / d2. Division by zero returns 0 for backwards compatibility;
+// use DivE or DivOrNaN when an undefined quotient must be detectable.
 func (d Decimal) Div(d2 Decimal) Decimal {
+	if d.nan || d2.nan {
+		return NaN
+	}
 	if d2.Zero() {
 		return Decimal{val: big.NewFloat(0)}
 	}
 	if d.val == nil || d2.val == nil {
 		return Decimal{}
 	}
+	if d.val.IsInf() && d2.val.IsInf() {
+		return NaN
+	}
 	return Decimal{val: new(big.Float).Quo(d.val, d2.val)}
 }
 
+// DivE returns d / d2, or an error if d2 is zero or either operand is NaN.
+func (d Decimal) DivE(d2 Decimal) (Decimal, error) {
+	if d.nan || d2.nan {
+		return NaN, ErrNaN
+	}
+	if d2.Zero() {
+		return NaN, ErrDivisionByZero
+	}
+	q := d.Div(d2)
+	if q.nan {
+		return NaN, ErrNaN
+	}
+	return q, nil
+}
+
+// DivOrNaN returns d / d2, or NaN if d2 is zero.
+func (d Decimal) DivOrNaN(d2 Decimal) Decimal {
+	if d2.Zero() {
+		return NaN
+	}
+	return d.Div(d2)
+}
+
+// infOpposed reports whether a + b is ∞ + (-∞), which has no defined value.
+func infOpposed(a, b Decimal) bool {
+	return a.val != nil && b.val != nil && a.val.IsInf() && b.val.IsInf() && a.val.Sign() != b.val.Sign()
+}
+
+// infSame reports whether a - b is ∞ - ∞, which has no defined value.
+func infSame(a, b Decimal) bool {
+	return a.val != nil && b.val != nil && a.val.IsInf() && b.val.IsInf() && a.val.Sign() == b.val.Sign()
+}
+
 // GT returns true if d > d2
 func (d Decimal) GT(d2 Decimal) bool {
+	if d.nan || d2.nan {
+		return false
+	}
 	if d.val != nil && d2.val != nil {
 		return d.val.Cmp(d2.val) > 0
 	}
@@ -112,6 +207,9 @@ func (d Decimal) GT(d2 Decimal) bool {
 
 // GTE returns true if d >= d2
 func (d Decimal) GTE(d2 Decimal) bool {
+	if d.nan || d2.nan {
+		return false
+	}
 	if d.val != nil && d2.val != nil {
 		return d.val.Cmp(d2.val) >= 0
 	}
@@ -120,6 +218,9 @@ func (d Decimal) GTE(d2 Decimal) bool {
 
 // LT returns true if d < d2
 func (d Decimal) LT(d2 Decimal) bool {
+	if d.nan || d2.nan {
+		return false
+	}
 	if d.val != nil && d2.val != nil {
 		return d.val.Cmp(d2.val) < 0
 	}
@@ -128,6 +229,9 @@ func (d Decimal) LT(d2 Decimal) bool {
 
 // LTE returns true if d <= d2
 func (d Decimal) LTE(d2 Decimal) bool {
+	if d.nan || d2.nan {
+		return false
+	}
 	if d.val != nil && d2.val != nil {
 		return d.val.Cmp(d2.val) <= 0
 	}
@@ -136,19 +240,25 @@ func (d Decimal) LTE(d2 Decimal) bool {
 
 // EQ returns true if d == d2
 func (d Decimal) EQ(d2 Decimal) bool {
+	if d.nan || d2.nan {
+		return false
+	}
 	if d.val != nil && d2.val != nil {
 		return d.val.Cmp(d2.val) == 0
 	}
 	return d.Cmp(d2) == 0
 }
 
-// Zero returns true if d == 0
+// Zero returns true if d == 0. NaN is not zero.
 func (d Decimal) Zero() bool {
-	return d.Sign() == 0
+	return !d.nan && d.Sign() == 0
 }
 
-// Float returns float64 representation of d
+// Float returns float64 representation of d. NaN maps to math.NaN().
 func (d Decimal) Float() float64 {
+	if d.nan {
+		return math.NaN()
+	}
 	if d.val == nil {
 		return 0
 	}
@@ -158,6 +268,9 @@ func (d Decimal) Float() float64 {
 
 // String returns string representation of d
 func (d Decimal) String() string {
+	if d.nan {
+		return "NaN"
+	}
 	if d.val == nil {
 		return "0"
 	}
@@ -166,6 +279,9 @@ func (d Decimal) String() string {
 
 // FormattedString returns string representation of d with fixed precision
 func (d Decimal) FormattedString(precision int) string {
+	if d.nan {
+		return "NaN"
+	}
 	if d.val == nil {
 		return "0"
 	}
@@ -174,6 +290,9 @@ func (d Decimal) FormattedString(precision int) string {
 
 // Abs returns absolute value of d
 func (d Decimal) Abs() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
@@ -182,22 +301,31 @@ func (d Decimal) Abs() Decimal {
 
 // Neg returns -d
 func (d Decimal) Neg() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
 	return Decimal{val: new(big.Float).Neg(d.val)}
 }
 
-// Max returns larger of d and d2
+// Max returns larger of d and d2, or NaN if either is NaN
 func (d Decimal) Max(d2 Decimal) Decimal {
+	if d.nan || d2.nan {
+		return NaN
+	}
 	if d.GT(d2) {
 		return d
 	}
 	return d2
 }
 
-// Min returns smaller of d and d2
+// Min returns smaller of d and d2, or NaN if either is NaN
 func (d Decimal) Min(d2 Decimal) Decimal {
+	if d.nan || d2.nan {
+		return NaN
+	}
 	if d.LT(d2) {
 		return d
 	}
@@ -206,14 +334,31 @@ func (d Decimal) Min(d2 Decimal) Decimal {
 
 // Sqrt returns square root of d
 func (d Decimal) Sqrt() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil || d.IsNegative() {
 		return ZERO
 	}
 	return Decimal{val: new(big.Float).Sqrt(d.val)}
 }
 
+// SqrtE returns the square root of d, or an error if d is negative or NaN.
+func (d Decimal) SqrtE() (Decimal, error) {
+	if d.nan {
+		return NaN, ErrNaN
+	}
+	if d.IsNegative() {
+		return NaN, ErrNegativeSqrt
+	}
+	return d.Sqrt(), nil
+}
+
 // Pow returns d^y where y is an integer
 func (d Decimal) Pow(y int) Decimal {
+	if d.nan {
+		return NaN
+	}
 	if y == 0 {
 		return ONE
 	}
@@ -244,7 +389,9 @@ func (d Decimal) Pow(y int) Decimal {
 	return result
 }
 
-// PowFloat returns d^y where y is a float64 using math.Pow
+// PowFloat returns d^y where y is a float64 using math.Pow. Results that
+// math.Pow reports as NaN (e.g. a negative base with a fractional exponent)
+// are returned as NaN.
 func (d Decimal) PowFloat(y float64) Decimal {
 	f := math.Pow(d.Float(), y)
 	return New(f)
@@ -255,8 +402,17 @@ func (d Decimal) PowFloat(y float64) Decimal {
 //	-1 if d <  d2
 //	 0 if d == d2
 //	+1 if d >  d2
+//
+// For ordering purposes NaN is considered less than any defined value and
+// equal to another NaN, matching cmp.Compare for floats.
 func (d Decimal) Cmp(d2 Decimal) int {
 	switch {
+	case d.nan && d2.nan:
+		return 0
+	case d.nan:
+		return -1
+	case d2.nan:
+		return 1
 	case d.val == nil && d2.val == nil:
 		return 0
 	case d.val == nil:
@@ -268,17 +424,17 @@ func (d Decimal) Cmp(d2 Decimal) int {
 	}
 }
 
-// Sign returns -1 if d < 0, 0 if d == 0, +1 if d > 0
+// Sign returns -1 if d < 0, 0 if d == 0, +1 if d > 0. NaN has sign 0.
 func (d Decimal) Sign() int {
-	if d.val == nil {
+	if d.nan || d.val == nil {
 		return 0
 	}
 	return d.val.Sign()
 }
 
-// IsZero returns true if d == 0
+// IsZero returns true if d == 0. NaN is not zero.
 func (d Decimal) IsZero() bool {
-	return d.Sign() == 0
+	return !d.nan && d.Sign() == 0
 }
 
 // IsNegative returns true if d < 0
@@ -293,7 +449,7 @@ func (d Decimal) IsPositive() bool {
 
 // Round returns d rounded to the nearest integer, with ties rounding away from zero
 func (d Decimal) Round() Decimal {
-	if d.val == nil || d.IsZero() {
+	if d.nan || d.val == nil || d.IsZero() {
 		return d
 	}
 
@@ -313,6 +469,9 @@ func (d Decimal) Round() Decimal {
 
 // Floor returns the greatest integer value less than or equal to d
 func (d Decimal) Floor() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
@@ -328,6 +487,9 @@ func (d Decimal) Floor() Decimal {
 
 // Ceil returns the least integer value greater than or equal to d
 func (d Decimal) Ceil() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
@@ -343,6 +505,9 @@ func (d Decimal) Ceil() Decimal {
 
 // Truncate returns the integer part of d, dropping any fractional part
 func (d Decimal) Truncate() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
@@ -353,6 +518,9 @@ func (d Decimal) Truncate() Decimal {
 
 // Frac returns the fractional part of d
 func (d Decimal) Frac() Decimal {
+	if d.nan {
+		return NaN
+	}
 	if d.val == nil {
 		return ZERO
 	}
@@ -373,10 +541,10 @@ func (d *Decimal) UnmarshalJSON(data []byte) error {
 	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
 		str = str[1 : len(str)-1]
 	}
-	val, _, err := big.ParseFloat(str, 10, 256, big.ToNearestEven)
+	val, err := parse(str)
 	if err != nil {
 		return err
 	}
-	d.val = val
+	*d = val
 	return nil
 }
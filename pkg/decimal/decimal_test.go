@@ -2,6 +2,7 @@ package decimal
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
 	"testing"
 )
@@ -742,3 +743,120 @@ func TestDecimal_Frac(t *testing.T) {
 		})
 	}
 }
+
+func TestDecimal_NaN(t *testing.T) {
+	if !NaN.IsNaN() || NaN.IsValid() {
+		t.Fatal("NaN should report IsNaN and not IsValid")
+	}
+	if !(Decimal{}).IsValid() || !ZERO.IsValid() {
+		t.Error("zero values should be valid")
+	}
+	if !New(math.NaN()).IsNaN() {
+		t.Error("New(math.NaN()) should be NaN")
+	}
+
+	ops := map[string]Decimal{
+		"add":   ONE.Add(NaN),
+		"sub":   NaN.Sub(ONE),
+		"mul":   ONE.Mul(NaN),
+		"div":   NaN.Div(ONE),
+		"abs":   NaN.Abs(),
+		"neg":   NaN.Neg(),
+		"sqrt":  NaN.Sqrt(),
+		"pow":   NaN.Pow(2),
+		"max":   ONE.Max(NaN),
+		"min":   NaN.Min(ONE),
+		"floor": NaN.Floor(),
+		"round": NaN.Round(),
+	}
+	for name, got := range ops {
+		if !got.IsNaN() {
+			t.Errorf("%s: expected NaN to propagate, got %v", name, got)
+		}
+	}
+
+	if NaN.EQ(NaN) || NaN.GT(ZERO) || NaN.LT(ZERO) || NaN.GTE(NaN) || ZERO.LTE(NaN) {
+		t.Error("comparisons involving NaN should be false")
+	}
+	if NaN.IsZero() || NaN.Zero() {
+		t.Error("NaN should not be zero")
+	}
+	if NaN.Cmp(ONE) != -1 || ONE.Cmp(NaN) != 1 || NaN.Cmp(NaN) != 0 {
+		t.Error("NaN should order before defined values")
+	}
+	if NaN.String() != "NaN" || !math.IsNaN(NaN.Float()) {
+		t.Errorf("unexpected NaN representation %q / %v", NaN.String(), NaN.Float())
+	}
+}
+
+func TestDecimal_InfinityArithmeticIsNaN(t *testing.T) {
+	inf := New(math.Inf(1))
+	negInf := New(math.Inf(-1))
+
+	if !inf.Sub(inf).IsNaN() {
+		t.Error("inf - inf should be NaN")
+	}
+	if !inf.Add(negInf).IsNaN() {
+		t.Error("inf + -inf should be NaN")
+	}
+	if !inf.Mul(ZERO).IsNaN() {
+		t.Error("inf * 0 should be NaN")
+	}
+	if !inf.Div(inf).IsNaN() {
+		t.Error("inf / inf should be NaN")
+	}
+	if !inf.Add(inf).GT(ONE) {
+		t.Error("inf + inf should be inf")
+	}
+}
+
+func TestDecimal_DivE(t *testing.T) {
+	q, err := New(10).DivE(New(4))
+	if err != nil || q.Float() != 2.5 {
+		t.Errorf("DivE(10, 4) = %v, %v", q, err)
+	}
+
+	q, err = ONE.DivE(ZERO)
+	if !errors.Is(err, ErrDivisionByZero) || !q.IsNaN() {
+		t.Errorf("DivE by zero = %v, %v", q, err)
+	}
+
+	if _, err = NaN.DivE(ONE); !errors.Is(err, ErrNaN) {
+		t.Errorf("DivE with NaN operand should return ErrNaN, got %v", err)
+	}
+
+	if !ONE.DivOrNaN(ZERO).IsNaN() {
+		t.Error("DivOrNaN by zero should be NaN")
+	}
+	if !ONE.Div(ZERO).IsZero() {
+		t.Error("Div by zero should keep returning zero")
+	}
+}
+
+func TestDecimal_SqrtE(t *testing.T) {
+	r, err := New(9).SqrtE()
+	if err != nil || r.Float() != 3 {
+		t.Errorf("SqrtE(9) = %v, %v", r, err)
+	}
+	if _, err = New(-1).SqrtE(); !errors.Is(err, ErrNegativeSqrt) {
+		t.Errorf("SqrtE(-1) should return ErrNegativeSqrt, got %v", err)
+	}
+}
+
+func TestDecimal_NaNJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(NaN)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if string(data) != `"NaN"` {
+		t.Errorf("expected \"NaN\", got %s", data)
+	}
+
+	var decoded Decimal
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if !decoded.IsNaN() {
+		t.Errorf("expected NaN after round-trip, got %v", decoded)
+	}
+}
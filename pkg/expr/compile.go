@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"strings"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/trading"
+)
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (c *Compiler) compile(node Node, env Env) (Value, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		return NumberValue(n.Value), nil
+	case *CallNode:
+		return c.compileCall(n, env)
+	case *UnaryNode:
+		return c.compileUnary(n, env)
+	case *BinaryNode:
+		return c.compileBinary(n, env)
+	}
+	return Value{}, errorAt(node.Pos(), "unsupported expression %s", node)
+}
+
+func (c *Compiler) compileCall(n *CallNode, env Env) (Value, error) {
+	fn, ok := c.Lookup(n.Name)
+	if !ok {
+		return Value{}, errorAt(n.At, "unknown function %q", n.Name)
+	}
+	if len(n.Args) != len(fn.Params) {
+		return Value{}, errorAt(n.At, "%s takes %d argument(s), got %d", n.Name, len(fn.Params), len(n.Args))
+	}
+
+	args := make([]Value, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := c.compile(argNode, env)
+		if err != nil {
+			return Value{}, err
+		}
+		want := fn.Params[i]
+		if arg.Kind != want && !(want == KindSeries && arg.Kind == KindNumber) {
+			return Value{}, errorAt(argNode.Pos(), "argument %d of %s must be a %s, got a %s", i+1, n.Name, want, arg.Kind)
+		}
+		args[i] = arg
+	}
+
+	val, err := fn.Build(env, args)
+	if err != nil {
+		return Value{}, errorAt(n.At, "%s: %v", n.Name, err)
+	}
+	return val, nil
+}
+
+func (c *Compiler) compileUnary(n *UnaryNode, env Env) (Value, error) {
+	x, err := c.compile(n.X, env)
+	if err != nil {
+		return Value{}, err
+	}
+
+	if n.Op == "not" {
+		if x.Kind != KindRule {
+			return Value{}, errorAt(n.At, "not needs a rule, got a %s", x.Kind)
+		}
+		return RuleValue(trading.Not(x.Rule)), nil
+	}
+
+	switch x.Kind {
+	case KindNumber:
+		return NumberValue(-x.Number), nil
+	case KindSeries:
+		return SeriesValue(indicators.NewProductIndicator(x.Series, indicators.NewConstantIndicator(-1))), nil
+	}
+	return Value{}, errorAt(n.At, "cannot negate a rule")
+}
+
+func (c *Compiler) compileBinary(n *BinaryNode, env Env) (Value, error) {
+	x, err := c.compile(n.X, env)
+	if err != nil {
+		return Value{}, err
+	}
+	y, err := c.compile(n.Y, env)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch n.Op {
+	case "and", "or":
+		if x.Kind != KindRule || y.Kind != KindRule {
+			return Value{}, errorAt(n.At, "%s needs rules on both sides, got a %s and a %s", n.Op, x.Kind, y.Kind)
+		}
+		if n.Op == "and" {
+			return RuleValue(trading.And(x.Rule, y.Rule)), nil
+		}
+		return RuleValue(trading.Or(x.Rule, y.Rule)), nil
+	}
+
+	if x.Kind == KindRule || y.Kind == KindRule {
+		return Value{}, errorAt(n.At, "%s needs numbers or series, got a %s and a %s", n.Op, x.Kind, y.Kind)
+	}
+
+	switch n.Op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		return RuleValue(newCompareRule(n.Op, x.Indicator(), y.Indicator())), nil
+	}
+
+	if x.Kind == KindNumber && y.Kind == KindNumber {
+		switch n.Op {
+		case "+":
+			return NumberValue(x.Number + y.Number), nil
+		case "-":
+			return NumberValue(x.Number - y.Number), nil
+		case "*":
+			return NumberValue(x.Number * y.Number), nil
+		case "/":
+			if y.Number == 0 {
+				return Value{}, errorAt(n.At, "division by zero")
+			}
+			return NumberValue(x.Number / y.Number), nil
+		}
+	}
+
+	a, b := x.Indicator(), y.Indicator()
+	switch n.Op {
+	case "+":
+		return SeriesValue(indicators.NewSumIndicator(a, b)), nil
+	case "-":
+		return SeriesValue(indicators.NewDifferenceIndicator(a, b)), nil
+	case "*":
+		return SeriesValue(indicators.NewProductIndicator(a, b)), nil
+	case "/":
+		return SeriesValue(indicators.NewQuotientIndicator(a, b)), nil
+	}
+	return Value{}, errorAt(n.At, "unknown operator %q", n.Op)
+}
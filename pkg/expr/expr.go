@@ -0,0 +1,223 @@
+// Package expr compiles text expressions into indicators and trading rules.
+//
+// An expression combines price series, indicator functions, numbers and
+// operators:
+//
+//	crossup(ema(close, 12), ema(close, 26)) and rsi(close, 14) < 70
+//
+// Every expression has one of three kinds. Numbers are constants, series are
+// indicators.Indicator values, and rules are trading.Rule values produced by
+// comparisons, logical operators and rule functions such as crossup. Numbers
+// are promoted to constant series wherever a series is expected.
+//
+// The function table of a Compiler starts with the built-in indicators and
+// every entry of the indicators registry, and can be extended with Register,
+// so strategies can be kept in configuration files.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/trading"
+)
+
+// Error is a parse or compile error at a position in the source
+type Error struct {
+	Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("expr: %s: %s", e.Position, e.Msg)
+}
+
+func errorAt(pos Position, format string, args ...interface{}) error {
+	return &Error{Position: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Kind is the type of an expression value
+type Kind int
+
+// Value kinds
+const (
+	KindNumber Kind = iota
+	KindSeries
+	KindRule
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNumber:
+		return "number"
+	case KindSeries:
+		return "series"
+	case KindRule:
+		return "rule"
+	}
+	return "unknown"
+}
+
+// Value is the result of evaluating an expression or function
+type Value struct {
+	Kind   Kind
+	Number float64
+	Series indicators.Indicator
+	Rule   trading.Rule
+}
+
+// NumberValue returns a constant number value
+func NumberValue(n float64) Value {
+	return Value{Kind: KindNumber, Number: n}
+}
+
+// SeriesValue returns a series value
+func SeriesValue(ind indicators.Indicator) Value {
+	return Value{Kind: KindSeries, Series: ind}
+}
+
+// RuleValue returns a rule value
+func RuleValue(r trading.Rule) Value {
+	return Value{Kind: KindRule, Rule: r}
+}
+
+// Indicator returns the value as a series, promoting a number to a constant indicator
+func (v Value) Indicator() indicators.Indicator {
+	if v.Kind == KindNumber {
+		return indicators.NewConstantIndicator(v.Number)
+	}
+	return v.Series
+}
+
+// Int returns a number value as an int, or an error if it is not a whole number
+func (v Value) Int() (int, error) {
+	if v.Kind != KindNumber {
+		return 0, fmt.Errorf("expected a number, got a %s", v.Kind)
+	}
+	if v.Number != math.Trunc(v.Number) || math.Abs(v.Number) > math.MaxInt32 {
+		return 0, fmt.Errorf("expected a whole number, got %g", v.Number)
+	}
+	return int(v.Number), nil
+}
+
+// Env is the context functions are built in
+type Env struct {
+	Series *series.TimeSeries
+}
+
+// Function is an entry of the function table. Args are checked against
+// Params before Build is called: a KindSeries parameter also accepts a number,
+// which is passed through as a KindNumber value so Build can promote it with
+// Value.Indicator. An error returned by Build is reported at the call site.
+type Function struct {
+	Params []Kind
+	Doc    string
+	Build  func(env Env, args []Value) (Value, error)
+}
+
+// ErrInvalidFunction is returned when registering a malformed function
+var ErrInvalidFunction = errors.New("expr: invalid function")
+
+// Compiler compiles expressions against a function table
+type Compiler struct {
+	funcs map[string]Function
+}
+
+// NewCompiler returns a Compiler with the built-in function table and a
+// function for every indicator in the indicators registry
+func NewCompiler() *Compiler {
+	c := &Compiler{funcs: make(map[string]Function, len(builtins))}
+	for name, fn := range builtins {
+		c.funcs[name] = fn
+	}
+	addRegistryFunctions(c.funcs)
+	return c
+}
+
+// Register adds or replaces a function. Names are case-insensitive and must be
+// identifiers that are not keywords.
+func (c *Compiler) Register(name string, fn Function) error {
+	key := normalizeName(name)
+	tokens, err := lex(name)
+	if err != nil || len(tokens) != 2 || tokens[0].kind != tokenIdent {
+		return fmt.Errorf("%w: %q is not an identifier", ErrInvalidFunction, name)
+	}
+	if fn.Build == nil {
+		return fmt.Errorf("%w: %s has no Build", ErrInvalidFunction, name)
+	}
+	c.funcs[key] = fn
+	return nil
+}
+
+// Lookup returns the function registered under name
+func (c *Compiler) Lookup(name string) (Function, bool) {
+	fn, ok := c.funcs[normalizeName(name)]
+	return fn, ok
+}
+
+// Compile parses and compiles src against s
+func (c *Compiler) Compile(src string, s *series.TimeSeries) (Value, error) {
+	node, err := Parse(src)
+	if err != nil {
+		return Value{}, err
+	}
+	return c.compile(node, Env{Series: s})
+}
+
+// CompileIndicator compiles src into an indicator. A number compiles into a constant indicator.
+func (c *Compiler) CompileIndicator(src string, s *series.TimeSeries) (indicators.Indicator, error) {
+	node, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	val, err := c.compile(node, Env{Series: s})
+	if err != nil {
+		return nil, err
+	}
+	if val.Kind == KindRule {
+		return nil, errorAt(node.Pos(), "expression is a rule, expected a series")
+	}
+	return val.Indicator(), nil
+}
+
+// CompileRule compiles src into a trading rule
+func (c *Compiler) CompileRule(src string, s *series.TimeSeries) (trading.Rule, error) {
+	node, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	val, err := c.compile(node, Env{Series: s})
+	if err != nil {
+		return nil, err
+	}
+	if val.Kind != KindRule {
+		return nil, errorAt(node.Pos(), "expression is a %s, expected a rule such as a comparison", val.Kind)
+	}
+	return val.Rule, nil
+}
+
+// CompileStrategy compiles entry and exit rules into a RuleStrategy
+func (c *Compiler) CompileStrategy(entry, exit string, unstablePeriod int, s *series.TimeSeries) (trading.RuleStrategy, error) {
+	entryRule, err := c.CompileRule(entry, s)
+	if err != nil {
+		return trading.RuleStrategy{}, fmt.Errorf("entry: %w", err)
+	}
+	exitRule, err := c.CompileRule(exit, s)
+	if err != nil {
+		return trading.RuleStrategy{}, fmt.Errorf("exit: %w", err)
+	}
+	return trading.NewRuleStrategy(entryRule, exitRule, unstablePeriod)
+}
+
+// CompileIndicator compiles src with the built-in function table
+func CompileIndicator(src string, s *series.TimeSeries) (indicators.Indicator, error) {
+	return NewCompiler().CompileIndicator(src, s)
+}
+
+// CompileRule compiles src with the built-in function table
+func CompileRule(src string, s *series.TimeSeries) (trading.Rule, error) {
+	return NewCompiler().CompileRule(src, s)
+}
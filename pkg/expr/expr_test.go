@@ -0,0 +1,156 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+	"github.com/irfndi/goflux/pkg/trading"
+)
+
+func TestCompileIndicator_MatchesConstructors(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(10, 11, 12, 11, 13, 15, 14, 16, 18, 17, 19, 21)
+	closes := indicators.NewClosePriceIndicator(ts)
+
+	cases := map[string]indicators.Indicator{
+		"close":         closes,
+		"sma(close, 3)": indicators.NewSimpleMovingAverage(closes, 3),
+		"ema(close, 4) - sma(close, 4)": indicators.NewDifferenceIndicator(
+			indicators.NewEMAIndicator(closes, 4), indicators.NewSimpleMovingAverage(closes, 4)),
+		"macd(close, 3, 6)":    indicators.NewMACDIndicator(closes, 3, 6),
+		"bbupper(close, 5, 2)": indicators.NewBollingerUpperBandIndicator(closes, 5, 2),
+		"(high + low) / 2":     indicators.NewMedianPriceIndicator(ts),
+		"atr(3) * 2":           indicators.NewProductIndicator(indicators.NewAverageTrueRangeIndicator(ts, 3), indicators.NewConstantIndicator(2)),
+		"-mom(2)":              indicators.NewProductIndicator(indicators.NewMomentumIndicator(ts, 2), indicators.NewConstantIndicator(-1)),
+		"2 * 3 + 1":            indicators.NewConstantIndicator(7),
+		"RSI(Close, 5)":        indicators.NewRelativeStrengthIndexIndicator(closes, 5),
+
+		"bbands_lower(close, 5, 2)":   indicators.NewBollingerLowerBandIndicator(closes, 5, 2),
+		"macd_signal(close, 3, 6, 2)": indicators.NewEMAIndicator(indicators.NewMACDIndicator(closes, 3, 6), 2),
+		"ultosc(2, 3, 4)":             indicators.NewUltimateOscillatorIndicator(ts, 2, 3, 4),
+	}
+
+	for src, want := range cases {
+		got, err := CompileIndicator(src, ts)
+		require.NoError(t, err, src)
+		for i := 0; i < ts.Length(); i++ {
+			assert.Equal(t, want.Calculate(i).String(), got.Calculate(i).String(), "%s at %d", src, i)
+		}
+	}
+}
+
+func TestCompileRule(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(10, 9, 8, 9, 11, 13, 12, 10, 8, 7)
+	record := trading.NewTradingRecord()
+
+	eval := func(src string) []bool {
+		rule, err := CompileRule(src, ts)
+		require.NoError(t, err, src)
+		out := make([]bool, ts.Length())
+		for i := range out {
+			out[i] = rule.IsSatisfied(i, record)
+		}
+		return out
+	}
+
+	assert.Equal(t, []bool{false, false, false, false, true, true, true, false, false, false}, eval("close > 10"))
+	assert.Equal(t, []bool{true, false, false, false, true, true, true, true, false, false}, eval("close >= 10"))
+	assert.Equal(t, []bool{true, false, false, false, false, false, false, true, false, false}, eval("close == 10"))
+	assert.Equal(t, []bool{false, true, true, true, true, true, true, false, true, true}, eval("close != 10"))
+	assert.Equal(t, []bool{false, false, false, true, true, true, false, false, false, false}, eval("rising(close)"))
+	assert.Equal(t, []bool{false, false, false, false, true, false, true, false, false, false}, eval("close > 10 and not close > 12"))
+	assert.Equal(t, []bool{false, false, true, false, false, true, false, false, true, true}, eval("close < 9 || close >= 13"))
+
+	closes := indicators.NewClosePriceIndicator(ts)
+	ten := indicators.NewConstantIndicator(10)
+	crossUp := trading.NewCrossUpIndicatorRule(ten, closes)
+	crossDown := trading.NewCrossDownIndicatorRule(closes, ten)
+	up, down := eval("crossup(close, 10)"), eval("crossdown(close, 10)")
+	for i := 0; i < ts.Length(); i++ {
+		assert.Equal(t, crossUp.IsSatisfied(i, record), up[i], "crossup at %d", i)
+		assert.Equal(t, crossDown.IsSatisfied(i, record), down[i], "crossdown at %d", i)
+	}
+}
+
+func TestCompileRule_NaNComparisonsAreFalse(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(10, 10, 10)
+	rule, err := CompileRule("close / 0 != 1 or close / 0 <= 1", ts)
+	require.NoError(t, err)
+	assert.False(t, rule.IsSatisfied(1, nil))
+}
+
+func TestCompile_Errors(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(1, 2, 3)
+	cases := []struct {
+		src    string
+		rule   bool
+		column int
+		msg    string
+	}{
+		{"smma(close, 3)", false, 1, `unknown function "smma"`},
+		{"sma(close)", false, 1, "sma takes 2 argument(s), got 1"},
+		{"sma(3, close)", false, 8, "argument 2 of sma must be a number, got a series"},
+		{"sma(close, 2.5)", false, 1, "sma: expected a whole number, got 2.5"},
+		{"atr(1)", false, 1, "atr: window must be at least 2, got 1"},
+		{"close > 1", false, 7, "expression is a rule, expected a series"},
+		{"sma(close, 3)", true, 1, "expression is a series, expected a rule such as a comparison"},
+		{"close and close > 1", true, 7, "and needs rules on both sides, got a series and a rule"},
+		{"rising(close) + 1", false, 15, "+ needs numbers or series, got a rule and a number"},
+		{"not close", true, 1, "not needs a rule, got a series"},
+		{"1 / (2 - 2)", false, 3, "division by zero"},
+		{"ultosc(0, 3, 4)", false, 1, "ultosc: invalid indicator parameter: period1 must be at least 1, got 0"},
+	}
+	for _, tc := range cases {
+		var err error
+		if tc.rule {
+			_, err = CompileRule(tc.src, ts)
+		} else {
+			_, err = CompileIndicator(tc.src, ts)
+		}
+		var exprErr *Error
+		require.True(t, errors.As(err, &exprErr), tc.src)
+		assert.Equal(t, tc.column, exprErr.Column, tc.src)
+		assert.Equal(t, tc.msg, exprErr.Msg, tc.src)
+	}
+}
+
+func TestCompiler_Register(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(1, 2, 3, 4)
+	c := NewCompiler()
+
+	err := c.Register("double", Function{
+		Params: []Kind{KindSeries},
+		Build: func(_ Env, args []Value) (Value, error) {
+			return SeriesValue(indicators.NewProductIndicator(args[0].Indicator(), indicators.NewConstantIndicator(2))), nil
+		},
+	})
+	require.NoError(t, err)
+
+	ind, err := c.CompileIndicator("double(close) + double(1)", ts)
+	require.NoError(t, err)
+	assert.True(t, ind.Calculate(3).EQ(decimal.New(10)))
+
+	_, ok := NewCompiler().Lookup("double")
+	assert.False(t, ok, "registration must not leak into other compilers")
+
+	assert.ErrorIs(t, c.Register("and", Function{Build: func(Env, []Value) (Value, error) { return Value{}, nil }}), ErrInvalidFunction)
+	assert.ErrorIs(t, c.Register("two words", Function{Build: func(Env, []Value) (Value, error) { return Value{}, nil }}), ErrInvalidFunction)
+	assert.ErrorIs(t, c.Register("nobuild", Function{}), ErrInvalidFunction)
+}
+
+func TestCompiler_CompileStrategy(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(10, 9, 8, 9, 11, 13, 12, 10, 8, 7)
+	c := NewCompiler()
+
+	strategy, err := c.CompileStrategy("crossup(close, sma(close, 3))", "crossdown(close, sma(close, 3))", 2, ts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strategy.UnstablePeriod)
+
+	_, err = c.CompileStrategy("close >", "close < 1", 0, ts)
+	assert.ErrorContains(t, err, "entry: expr: 1:8")
+}
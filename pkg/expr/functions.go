@@ -0,0 +1,268 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/trading"
+)
+
+var (
+	seriesParam       = []Kind{KindSeries}
+	seriesWindowParam = []Kind{KindSeries, KindNumber}
+	windowParam       = []Kind{KindNumber}
+	pairParam         = []Kind{KindSeries, KindSeries}
+)
+
+// builtins is the function table every Compiler starts with
+var builtins = map[string]Function{
+	"open":   priceFunction(indicators.NewOpenPriceIndicator, "open price"),
+	"high":   priceFunction(indicators.NewHighPriceIndicator, "high price"),
+	"low":    priceFunction(indicators.NewLowPriceIndicator, "low price"),
+	"close":  priceFunction(indicators.NewClosePriceIndicator, "close price"),
+	"volume": priceFunction(indicators.NewVolumeIndicator, "volume"),
+	"hl2":    priceFunction(indicators.NewMedianPriceIndicator, "(high + low) / 2"),
+	"hlc3":   priceFunction(indicators.NewTypicalPriceIndicator, "typical price (high + low + close) / 3"),
+	"ohlc4":  priceFunction(indicators.NewAveragePriceIndicator, "(open + high + low + close) / 4"),
+	"hlcc4":  priceFunction(indicators.NewWeightedCloseIndicator, "weighted close (high + low + 2*close) / 4"),
+	"tr":     priceFunction(indicators.NewTrueRangeIndicator, "true range"),
+	"obv":    priceFunction(indicators.NewOBVIndicator, "on-balance volume"),
+	"vwap":   priceFunction(indicators.NewVWAPIndicator, "cumulative volume weighted average price"),
+	"sar":    priceFunction(indicators.NewParabolicSARIndicator, "parabolic SAR"),
+	"adline": priceFunction(indicators.NewADLineIndicator, "accumulation/distribution line"),
+
+	"sma":       seriesWindowFunction(indicators.NewSimpleMovingAverage, 1, "sma(series, n): simple moving average"),
+	"ema":       seriesWindowFunction(indicators.NewEMAIndicator, 1, "ema(series, n): exponential moving average"),
+	"mma":       seriesWindowFunction(indicators.NewMMAIndicator, 1, "mma(series, n): modified (Wilder) moving average"),
+	"rma":       seriesWindowFunction(indicators.NewRMAIndicator, 1, "rma(series, n): running moving average"),
+	"wma":       seriesWindowFunction(indicators.NewWMAIndicator, 1, "wma(series, n): weighted moving average"),
+	"hma":       seriesWindowFunction(indicators.NewHMAIndicator, 1, "hma(series, n): Hull moving average"),
+	"trima":     seriesWindowFunction(indicators.NewTRIMAIndicator, 1, "trima(series, n): triangular moving average"),
+	"rsi":       seriesWindowFunction(indicators.NewRelativeStrengthIndexIndicator, 1, "rsi(series, n): relative strength index"),
+	"cmo":       seriesWindowFunction(indicators.NewChandeMomentumOscillatorIndicator, 1, "cmo(series, n): Chande momentum oscillator"),
+	"trix":      seriesWindowFunction(indicators.NewTRIXIndicator, 1, "trix(series, n): triple smoothed EMA rate of change"),
+	"stdev":     seriesWindowFunction(indicators.NewWindowedStandardDeviationIndicator, 1, "stdev(series, n): standard deviation"),
+	"highest":   seriesWindowFunction(indicators.NewMaximumValueIndicator, 1, "highest(series, n): maximum over n bars"),
+	"lowest":    seriesWindowFunction(indicators.NewMinimumValueIndicator, 1, "lowest(series, n): minimum over n bars"),
+	"linreg":    seriesWindowFunction(indicators.NewLinearRegressionIndicator, 2, "linreg(series, n): linear regression value"),
+	"slope":     seriesWindowFunction(indicators.NewLinearRegressionSlopeIndicator, 2, "slope(series, n): linear regression slope"),
+	"aroonup":   seriesWindowFunction(indicators.NewAroonUpIndicator, 1, "aroonup(series, n): Aroon up"),
+	"aroondown": seriesWindowFunction(indicators.NewAroonDownIndicator, 1, "aroondown(series, n): Aroon down"),
+
+	"atr":      candleWindowFunction(indicators.NewAverageTrueRangeIndicator, 2, "atr(n): average true range"),
+	"adx":      candleWindowFunction(indicators.NewADXIndicator, 1, "adx(n): average directional index"),
+	"cci":      candleWindowFunction(indicators.NewCCIIndicator, 1, "cci(n): commodity channel index"),
+	"roc":      candleWindowFunction(indicators.NewROCIndicator, 1, "roc(n): close rate of change in percent"),
+	"mom":      candleWindowFunction(indicators.NewMomentumIndicator, 1, "mom(n): close momentum"),
+	"willr":    candleWindowFunction(indicators.NewWilliamsRIndicator, 1, "willr(n): Williams %R"),
+	"stoch":    candleWindowFunction(indicators.NewFastStochasticIndicator, 1, "stoch(n): fast stochastic %K"),
+	"mfi":      candleWindowFunction(indicators.NewMFIIndicator, 1, "mfi(n): money flow index"),
+	"cmf":      candleWindowFunction(indicators.NewChaikinMoneyFlowIndicator, 1, "cmf(n): Chaikin money flow"),
+	"kama":     candleWindowFunction(indicators.NewKAMAIndicator, 1, "kama(n): Kaufman adaptive moving average of close"),
+	"dcupper":  candleWindowFunction(indicators.NewDonchianUpperBandIndicator, 1, "dcupper(n): Donchian upper band"),
+	"dclower":  candleWindowFunction(indicators.NewDonchianLowerBandIndicator, 1, "dclower(n): Donchian lower band"),
+	"vortex":   candleWindowFunction(indicators.NewVortexIndicator, 1, "vortex(n): vortex indicator"),
+	"kcupper":  candleWindowFunction(indicators.NewKeltnerChannelUpperIndicator, 1, "kcupper(n): Keltner channel upper band"),
+	"kclower":  candleWindowFunction(indicators.NewKeltnerChannelLowerIndicator, 1, "kclower(n): Keltner channel lower band"),
+	"aroonosc": candleWindowFunction(indicators.NewAroonOscillatorFromSeries, 1, "aroonosc(n): Aroon oscillator"),
+
+	"macd": {
+		Params: []Kind{KindSeries, KindNumber, KindNumber},
+		Doc:    "macd(series, fast, slow): MACD line",
+		Build: func(_ Env, args []Value) (Value, error) {
+			fast, slow, err := windowPair(args[1], args[2])
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(indicators.NewMACDIndicator(args[0].Indicator(), fast, slow)), nil
+		},
+	},
+	"macdhist": {
+		Params: []Kind{KindSeries, KindNumber, KindNumber, KindNumber},
+		Doc:    "macdhist(series, fast, slow, signal): MACD histogram",
+		Build: func(_ Env, args []Value) (Value, error) {
+			fast, slow, err := windowPair(args[1], args[2])
+			if err != nil {
+				return Value{}, err
+			}
+			signal, err := window(args[3], 1)
+			if err != nil {
+				return Value{}, err
+			}
+			macd := indicators.NewMACDIndicator(args[0].Indicator(), fast, slow)
+			return SeriesValue(indicators.NewMACDHistogramIndicator(macd, signal)), nil
+		},
+	},
+	"bbupper": bollingerFunction(indicators.NewBollingerUpperBandIndicator, "bbupper(series, n, k): upper Bollinger band"),
+	"bblower": bollingerFunction(indicators.NewBollingerLowerBandIndicator, "bblower(series, n, k): lower Bollinger band"),
+	"supertrend": {
+		Params: []Kind{KindNumber, KindNumber},
+		Doc:    "supertrend(n, multiplier): SuperTrend line",
+		Build: func(env Env, args []Value) (Value, error) {
+			n, err := window(args[0], 2)
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(indicators.NewSuperTrendIndicator(env.Series, n, args[1].Number)), nil
+		},
+	},
+
+	"crossup": {
+		Params: pairParam,
+		Doc:    "crossup(a, b): a has crossed above b, as trading.NewCrossUpIndicatorRule",
+		Build: func(_ Env, args []Value) (Value, error) {
+			return RuleValue(trading.NewCrossUpIndicatorRule(args[1].Indicator(), args[0].Indicator())), nil
+		},
+	},
+	"crossdown": {
+		Params: pairParam,
+		Doc:    "crossdown(a, b): a has crossed below b, as trading.NewCrossDownIndicatorRule",
+		Build: func(_ Env, args []Value) (Value, error) {
+			return RuleValue(trading.NewCrossDownIndicatorRule(args[0].Indicator(), args[1].Indicator())), nil
+		},
+	},
+	"rising": {
+		Params: seriesParam,
+		Doc:    "rising(series): series is above its previous value",
+		Build: func(_ Env, args []Value) (Value, error) {
+			return RuleValue(trading.IncreaseRule{Indicator: args[0].Indicator()}), nil
+		},
+	},
+	"falling": {
+		Params: seriesParam,
+		Doc:    "falling(series): series is below its previous value",
+		Build: func(_ Env, args []Value) (Value, error) {
+			return RuleValue(trading.DecreaseRule{Indicator: args[0].Indicator()}), nil
+		},
+	},
+}
+
+func window(v Value, minimum int) (int, error) {
+	n, err := v.Int()
+	if err != nil {
+		return 0, err
+	}
+	if n < minimum {
+		return 0, fmt.Errorf("window must be at least %d, got %d", minimum, n)
+	}
+	return n, nil
+}
+
+func windowPair(fast, slow Value) (int, int, error) {
+	f, err := window(fast, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	s, err := window(slow, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return f, s, nil
+}
+
+func priceFunction(ctor func(*series.TimeSeries) indicators.Indicator, doc string) Function {
+	return Function{
+		Doc: doc,
+		Build: func(env Env, _ []Value) (Value, error) {
+			return SeriesValue(ctor(env.Series)), nil
+		},
+	}
+}
+
+func seriesWindowFunction(ctor func(indicators.Indicator, int) indicators.Indicator, minimum int, doc string) Function {
+	return Function{
+		Params: seriesWindowParam,
+		Doc:    doc,
+		Build: func(_ Env, args []Value) (Value, error) {
+			n, err := window(args[1], minimum)
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(ctor(args[0].Indicator(), n)), nil
+		},
+	}
+}
+
+func candleWindowFunction(ctor func(*series.TimeSeries, int) indicators.Indicator, minimum int, doc string) Function {
+	return Function{
+		Params: windowParam,
+		Doc:    doc,
+		Build: func(env Env, args []Value) (Value, error) {
+			n, err := window(args[0], minimum)
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(ctor(env.Series, n)), nil
+		},
+	}
+}
+
+func bollingerFunction(ctor func(indicators.Indicator, int, float64) indicators.Indicator, doc string) Function {
+	return Function{
+		Params: []Kind{KindSeries, KindNumber, KindNumber},
+		Doc:    doc,
+		Build: func(_ Env, args []Value) (Value, error) {
+			n, err := window(args[1], 1)
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(ctor(args[0].Indicator(), n, args[2].Number)), nil
+		},
+	}
+}
+
+// addRegistryFunctions adds a function for every entry of the indicator
+// registry that has no function of the same name yet. The first output of an
+// indicator is available under its key and every output under key_output,
+// for example bbands and bbands_lower.
+func addRegistryFunctions(funcs map[string]Function) {
+	for _, spec := range indicators.RegisteredIndicators() {
+		for i, output := range spec.Outputs {
+			names := []string{spec.Key + "_" + output}
+			if i == 0 {
+				names = append(names, spec.Key)
+			}
+			for _, name := range names {
+				if _, ok := funcs[name]; !ok {
+					funcs[name] = registryFunction(spec, output)
+				}
+			}
+		}
+	}
+}
+
+func registryFunction(spec indicators.IndicatorSpec, output string) Function {
+	usesSource := spec.UsesSource()
+	params := make([]Kind, 0, len(spec.Params)+1)
+	args := make([]string, 0, len(spec.Params)+1)
+	if usesSource {
+		params = append(params, KindSeries)
+		args = append(args, "series")
+	}
+	for _, ps := range spec.Params {
+		params = append(params, KindNumber)
+		args = append(args, ps.Name)
+	}
+
+	return Function{
+		Params: params,
+		Doc:    fmt.Sprintf("%s(%s): %s %s", spec.Key, strings.Join(args, ", "), spec.Name, output),
+		Build: func(env Env, args []Value) (Value, error) {
+			var src indicators.Indicator
+			if usesSource {
+				src, args = args[0].Indicator(), args[1:]
+			}
+			values := make(map[string]float64, len(args))
+			for i, ps := range spec.Params {
+				values[ps.Name] = args[i].Number
+			}
+			outputs, err := spec.Build(env.Series, src, values)
+			if err != nil {
+				return Value{}, err
+			}
+			return SeriesValue(outputs[output]), nil
+		},
+	}
+}
@@ -0,0 +1,198 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenPlus
+	tokenMinus
+	tokenStar
+	tokenSlash
+	tokenLT
+	tokenLE
+	tokenGT
+	tokenGE
+	tokenEQ
+	tokenNE
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:    "end of input",
+	tokenNumber: "number",
+	tokenIdent:  "identifier",
+	tokenLParen: `"("`,
+	tokenRParen: `")"`,
+	tokenComma:  `","`,
+}
+
+func (k tokenKind) String() string {
+	if name, ok := tokenNames[k]; ok {
+		return name
+	}
+	return "operator"
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Position
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenNumber, tokenIdent:
+		return fmt.Sprintf("%s %q", t.kind, t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+var keywords = map[string]tokenKind{
+	"and": tokenAnd,
+	"or":  tokenOr,
+	"not": tokenNot,
+}
+
+// lex splits src into tokens. Whitespace and comments running from # to the
+// end of the line are skipped.
+func lex(src string) ([]token, error) {
+	runes := []rune(src)
+	tokens := make([]token, 0, len(runes)/2)
+	line, col := 1, 1
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := Position{Line: line, Column: col}
+
+		switch {
+		case r == '\n':
+			line++
+			col = 1
+			i++
+			continue
+		case unicode.IsSpace(r):
+			col++
+			i++
+			continue
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			continue
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					i = j
+					for i < len(runes) && unicode.IsDigit(runes[i]) {
+						i++
+					}
+				}
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokenNumber, text: text, pos: pos})
+			col += i - start
+			continue
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			text := string(runes[start:i])
+			kind := tokenIdent
+			if kw, ok := keywords[strings.ToLower(text)]; ok {
+				kind = kw
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: pos})
+			col += i - start
+			continue
+		}
+
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		kind, width := operator(r, next)
+		if width == 0 {
+			return nil, errorAt(pos, "unexpected character %q", r)
+		}
+		tokens = append(tokens, token{kind: kind, text: string(runes[i : i+width]), pos: pos})
+		col += width
+		i += width
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: Position{Line: line, Column: col}})
+	return tokens, nil
+}
+
+// operator returns the token starting with r and its width in runes, or a zero
+// width if r does not start an operator.
+func operator(r, next rune) (tokenKind, int) {
+	switch r {
+	case '(':
+		return tokenLParen, 1
+	case ')':
+		return tokenRParen, 1
+	case ',':
+		return tokenComma, 1
+	case '+':
+		return tokenPlus, 1
+	case '-':
+		return tokenMinus, 1
+	case '*':
+		return tokenStar, 1
+	case '/':
+		return tokenSlash, 1
+	case '<':
+		if next == '=' {
+			return tokenLE, 2
+		}
+		return tokenLT, 1
+	case '>':
+		if next == '=' {
+			return tokenGE, 2
+		}
+		return tokenGT, 1
+	case '=':
+		if next == '=' {
+			return tokenEQ, 2
+		}
+	case '!':
+		if next == '=' {
+			return tokenNE, 2
+		}
+		return tokenNot, 1
+	case '&':
+		if next == '&' {
+			return tokenAnd, 2
+		}
+	case '|':
+		if next == '|' {
+			return tokenOr, 2
+		}
+	}
+	return tokenEOF, 0
+}
@@ -0,0 +1,301 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Position is a 1-based line and column in the expression source
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Node is a node of a parsed expression
+type Node interface {
+	Pos() Position
+	String() string
+}
+
+// NumberNode is a numeric literal
+type NumberNode struct {
+	At    Position
+	Value float64
+	Text  string
+}
+
+// CallNode is a function call. A bare identifier such as close is a call
+// without arguments and with Parens false.
+type CallNode struct {
+	At     Position
+	Name   string
+	Args   []Node
+	Parens bool
+}
+
+// UnaryNode is a prefix operator applied to X: "-" or "not"
+type UnaryNode struct {
+	At Position
+	Op string
+	X  Node
+}
+
+// BinaryNode is an infix operator applied to X and Y
+type BinaryNode struct {
+	At Position
+	Op string
+	X  Node
+	Y  Node
+}
+
+// Pos returns the position of the literal
+func (n *NumberNode) Pos() Position { return n.At }
+
+// Pos returns the position of the function name
+func (n *CallNode) Pos() Position { return n.At }
+
+// Pos returns the position of the operator
+func (n *UnaryNode) Pos() Position { return n.At }
+
+// Pos returns the position of the operator
+func (n *BinaryNode) Pos() Position { return n.At }
+
+func (n *NumberNode) String() string { return n.Text }
+
+func (n *CallNode) String() string {
+	if !n.Parens {
+		return n.Name
+	}
+	args := make([]string, len(n.Args))
+	for i, arg := range n.Args {
+		args[i] = arg.String()
+	}
+	return n.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (n *UnaryNode) String() string {
+	if n.Op == "not" {
+		return "(not " + n.X.String() + ")"
+	}
+	return "(" + n.Op + n.X.String() + ")"
+}
+
+func (n *BinaryNode) String() string {
+	return "(" + n.X.String() + " " + n.Op + " " + n.Y.String() + ")"
+}
+
+// Parse parses src into an expression tree.
+//
+// Operators from lowest to highest precedence are: or (||); and (&&);
+// not (!); the comparisons < <= > >= == !=; + and -; * and /; unary minus.
+// Comparisons do not chain, so a < b < c is an error.
+func Parse(src string) (Node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, errorAt(tok.pos, "unexpected %s after complete expression", tok.describe())
+	}
+	return node, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (Node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		op := p.next()
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryNode{At: op.pos, Op: "or", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		op := p.next()
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryNode{At: op.pos, Op: "and", X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.peek().kind == tokenNot {
+		op := p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryNode{At: op.pos, Op: "not", X: x}, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOps = map[tokenKind]string{
+	tokenLT: "<",
+	tokenLE: "<=",
+	tokenGT: ">",
+	tokenGE: ">=",
+	tokenEQ: "==",
+	tokenNE: "!=",
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := comparisonOps[p.peek().kind]
+	if !ok {
+		return x, nil
+	}
+	tok := p.next()
+	y, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); comparisonOps[next.kind] != "" {
+		return nil, errorAt(next.pos, "comparisons cannot be chained; combine them with and")
+	}
+	return &BinaryNode{At: tok.pos, Op: op, X: x, Y: y}, nil
+}
+
+func (p *parser) parseAdditive() (Node, error) {
+	x, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for kind := p.peek().kind; kind == tokenPlus || kind == tokenMinus; kind = p.peek().kind {
+		op := p.next()
+		y, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryNode{At: op.pos, Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseMultiplicative() (Node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for kind := p.peek().kind; kind == tokenStar || kind == tokenSlash; kind = p.peek().kind {
+		op := p.next()
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryNode{At: op.pos, Op: op.text, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokenMinus {
+		op := p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryNode{At: op.pos, Op: "-", X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		val, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, errorAt(tok.pos, "invalid number %q", tok.text)
+		}
+		return &NumberNode{At: tok.pos, Value: val, Text: tok.text}, nil
+	case tokenIdent:
+		return p.parseCall(tok)
+	case tokenLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, errorAt(closing.pos, `expected ")" to close "(" at %s, found %s`, tok.pos, closing.describe())
+		}
+		return x, nil
+	case tokenEOF:
+		return nil, errorAt(tok.pos, "unexpected end of input, expected a value")
+	default:
+		return nil, errorAt(tok.pos, "unexpected %s, expected a value", tok.describe())
+	}
+}
+
+func (p *parser) parseCall(name token) (Node, error) {
+	call := &CallNode{At: name.pos, Name: name.text}
+	if p.peek().kind != tokenLParen {
+		return call, nil
+	}
+	open := p.next()
+	call.Parens = true
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		switch tok := p.next(); tok.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return call, nil
+		default:
+			return nil, errorAt(tok.pos, `expected "," or ")" in call to %s opened at %s, found %s`, name.text, open.pos, tok.describe())
+		}
+	}
+}
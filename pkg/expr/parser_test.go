@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Precedence(t *testing.T) {
+	cases := map[string]string{
+		"1 + 2 * 3":                        "(1 + (2 * 3))",
+		"(1 + 2) * 3":                      "((1 + 2) * 3)",
+		"-close / 2":                       "((-close) / 2)",
+		"a < b and c > d or not e == f":    "(((a < b) and (c > d)) or (not (e == f)))",
+		"a && b || !c":                     "((a and b) or (not c))",
+		"sma(close, 20) >= ema(hlc3, 1e1)": "(sma(close, 20) >= ema(hlc3, 1e1))",
+		"f()":                              "f()",
+		"x - y - z":                        "((x - y) - z)",
+		"rsi(close,14)<70 # trailing note": "(rsi(close, 14) < 70)",
+		"AND_not(.5)":                      "AND_not(.5)",
+	}
+	for src, want := range cases {
+		node, err := Parse(src)
+		require.NoError(t, err, src)
+		assert.Equal(t, want, node.String(), src)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		src    string
+		line   int
+		column int
+		msg    string
+	}{
+		{"sma(close, 20", 1, 14, `expected "," or ")" in call to sma opened at 1:4, found end of input`},
+		{"close >", 1, 8, "unexpected end of input, expected a value"},
+		{"close $ 1", 1, 7, `unexpected character '$'`},
+		{"(close + 1", 1, 11, `expected ")" to close "(" at 1:1, found end of input`},
+		{"a < b < c", 1, 7, "comparisons cannot be chained; combine them with and"},
+		{"close\n  and\n  ) ", 3, 3, `unexpected ")", expected a value`},
+		{"close 1", 1, 7, `unexpected number "1" after complete expression`},
+		{"1.2.3", 1, 1, `invalid number "1.2.3"`},
+		{"a = b", 1, 3, `unexpected character '='`},
+	}
+	for _, tc := range cases {
+		_, err := Parse(tc.src)
+		var exprErr *Error
+		require.True(t, errors.As(err, &exprErr), tc.src)
+		assert.Equal(t, tc.line, exprErr.Line, tc.src)
+		assert.Equal(t, tc.column, exprErr.Column, tc.src)
+		assert.Equal(t, tc.msg, exprErr.Msg, tc.src)
+	}
+}
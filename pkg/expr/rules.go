@@ -0,0 +1,47 @@
+package expr
+
+import (
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/trading"
+)
+
+// newCompareRule returns a rule comparing x and y with op. Strict comparisons
+// use the trading package rules so they can be precomputed. All comparisons
+// are false when either side is NaN, including !=.
+func newCompareRule(op string, x, y indicators.Indicator) trading.Rule {
+	switch op {
+	case "<":
+		return trading.NewUnderIndicatorRule(x, y)
+	case ">":
+		return trading.NewOverIndicatorRule(x, y)
+	}
+	return compareRule{op: op, x: x, y: y}
+}
+
+type compareRule struct {
+	op string
+	x  indicators.Indicator
+	y  indicators.Indicator
+}
+
+func (cr compareRule) IsSatisfied(index int, record *trading.TradingRecord) bool {
+	if !indicators.IsReady(index, cr.x, cr.y) {
+		return false
+	}
+	a, b := cr.x.Calculate(index), cr.y.Calculate(index)
+	switch cr.op {
+	case "<=":
+		return a.LTE(b)
+	case ">=":
+		return a.GTE(b)
+	case "==":
+		return a.EQ(b)
+	case "!=":
+		return a.IsValid() && b.IsValid() && !a.EQ(b)
+	}
+	return false
+}
+
+func (cr compareRule) Lookback() int {
+	return indicators.MaxLookback(cr.x, cr.y)
+}
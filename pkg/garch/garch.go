@@ -0,0 +1,338 @@
+// Package garch fits GARCH family volatility models to a return series by
+// maximum likelihood, tracks their conditional variance as an indicator and
+// forecasts volatility ahead, for sizing positions and measuring value at risk
+// on forecast rather than historical volatility.
+package garch
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+)
+
+// Kind is the type of a GARCH model
+type Kind int
+
+const (
+	// GARCH is Bollerslev's GARCH(1,1):
+	// var[t] = omega + alpha*e[t-1]^2 + beta*var[t-1]
+	GARCH Kind = iota + 1
+	// GJR is the GJR-GARCH(1,1) of Glosten, Jagannathan and Runkle, which adds
+	// gamma*e[t-1]^2 after a negative return to let losses raise volatility
+	// more than gains
+	GJR
+	// EGARCH is Nelson's EGARCH(1,1), which models the log variance:
+	// ln var[t] = omega + alpha*(|z[t-1]| - sqrt(2/pi)) + gamma*z[t-1] + beta*ln var[t-1]
+	// where z is the standardized return. A negative gamma makes losses raise
+	// volatility more than gains.
+	EGARCH
+)
+
+var kindNames = map[Kind]string{
+	GARCH:  "GARCH",
+	GJR:    "GJR-GARCH",
+	EGARCH: "EGARCH",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return "None"
+}
+
+var (
+	// ErrUnknownKind is returned when fitting a model of an unknown kind
+	ErrUnknownKind = errors.New("garch: unknown model kind")
+	// ErrTooFewReturns is returned when fitting fewer than MinReturns returns
+	ErrTooFewReturns = errors.New("garch: too few returns to fit")
+	// ErrFlatReturns is returned when fitting returns without variance
+	ErrFlatReturns = errors.New("garch: returns have no variance")
+	// ErrFitFailed is returned when the returns are not finite or no
+	// parameters give a finite likelihood
+	ErrFitFailed = errors.New("garch: likelihood maximization failed")
+)
+
+// MinReturns is the fewest returns Fit accepts
+const MinReturns = 30
+
+// Model is a fitted GARCH family model
+type Model struct {
+	Kind Kind
+	// Mu is the mean return, the sample mean of the fitted returns. The model
+	// describes the variance of the residuals, the returns less Mu.
+	Mu    float64
+	Omega float64
+	Alpha float64
+	Beta  float64
+	// Gamma is the asymmetry term of GJR and EGARCH models, zero for GARCH
+	Gamma float64
+	// LogLikelihood is the Gaussian log likelihood of the fitted returns
+	LogLikelihood float64
+	// N is the number of fitted returns
+	N int
+
+	// next is the variance forecast for the return after the fitted ones
+	next float64
+}
+
+// Fit fits a model of the given kind to returns, such as log returns, by
+// maximizing the Gaussian likelihood. Parameters are kept stationary: the
+// persistence of GARCH and GJR models stays below 1, with alpha, beta and
+// gamma non-negative, and |beta| of EGARCH models stays below 1.
+func Fit(returns []decimal.Decimal, kind Kind) (*Model, error) {
+	if _, ok := kindNames[kind]; !ok {
+		return nil, ErrUnknownKind
+	}
+	if len(returns) < MinReturns {
+		return nil, ErrTooFewReturns
+	}
+
+	values := make([]float64, len(returns))
+	mu, flat := 0.0, true
+	for i, r := range returns {
+		values[i] = r.Float()
+		mu += values[i]
+		flat = flat && values[i] == values[0]
+	}
+	if flat {
+		return nil, ErrFlatReturns
+	}
+	mu /= float64(len(values))
+	residuals := make([]float64, len(values))
+	variance := 0.0
+	for i, v := range values {
+		residuals[i] = v - mu
+		variance += residuals[i] * residuals[i]
+	}
+	variance /= float64(len(values))
+	if math.IsNaN(variance) || math.IsInf(variance, 0) {
+		return nil, ErrFitFailed
+	}
+
+	negLogLikelihood := func(x []float64) float64 {
+		m := kind.model(x)
+		ll, _ := m.logLikelihood(residuals, variance)
+		if math.IsNaN(ll) || math.IsInf(ll, 0) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	best, value := minimize(negLogLikelihood, kind.start(variance))
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, ErrFitFailed
+	}
+
+	m := kind.model(best)
+	m.Mu, m.N = mu, len(values)
+	m.LogLikelihood, m.next = m.logLikelihood(residuals, variance)
+	return &m, nil
+}
+
+// start returns the unconstrained parameters the fit starts from: a
+// persistence of 0.95 with alpha of 0.05, and the sample variance as the
+// long run variance
+func (k Kind) start(variance float64) []float64 {
+	switch k {
+	case GJR:
+		return []float64{math.Log(0.05 * variance), logit(0.95), logit(0.05 / 0.95), 0}
+	case EGARCH:
+		return []float64{0.05 * math.Log(variance), 0.1, 0, math.Atanh(0.95)}
+	default:
+		return []float64{math.Log(0.05 * variance), logit(0.95), logit(0.05 / 0.95)}
+	}
+}
+
+// model maps unconstrained parameters to a model. GARCH and GJR models take
+// the log of omega, then logits of the persistence, of the share of it due to
+// returns, and for GJR of the share of that due to alpha rather than gamma/2.
+// EGARCH models take omega, alpha, gamma and the inverse tanh of beta.
+func (k Kind) model(x []float64) Model {
+	m := Model{Kind: k}
+	switch k {
+	case EGARCH:
+		m.Omega, m.Alpha, m.Gamma, m.Beta = x[0], x[1], x[2], math.Tanh(x[3])
+	default:
+		persistence, share := logistic(x[1]), logistic(x[2])
+		m.Omega = math.Exp(x[0])
+		m.Alpha, m.Beta = persistence*share, persistence*(1-share)
+		if k == GJR {
+			a := logistic(x[3])
+			m.Alpha, m.Gamma = persistence*share*a, 2*persistence*share*(1-a)
+		}
+	}
+	return m
+}
+
+func logistic(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
+
+func logit(p float64) float64 { return math.Log(p / (1 - p)) }
+
+// logLikelihood returns the Gaussian log likelihood of the residuals, the
+// variance of the first being the given one, and the variance forecast for
+// the next residual
+func (m *Model) logLikelihood(residuals []float64, variance float64) (ll, next float64) {
+	for _, e := range residuals {
+		if variance <= 0 || math.IsNaN(variance) || math.IsInf(variance, 0) {
+			return math.Inf(-1), variance
+		}
+		ll -= 0.5 * (math.Log(2*math.Pi) + math.Log(variance) + e*e/variance)
+		variance = m.step(variance, e)
+	}
+	return ll, variance
+}
+
+// step returns the variance of the next residual from the variance of the
+// residual e
+func (m *Model) step(variance, e float64) float64 {
+	switch m.Kind {
+	case EGARCH:
+		z := e / math.Sqrt(variance)
+		return math.Exp(m.Omega + m.Alpha*(math.Abs(z)-math.Sqrt(2/math.Pi)) + m.Gamma*z + m.Beta*math.Log(variance))
+	case GJR:
+		v := m.Omega + m.Alpha*e*e + m.Beta*variance
+		if e < 0 {
+			v += m.Gamma * e * e
+		}
+		return v
+	default:
+		return m.Omega + m.Alpha*e*e + m.Beta*variance
+	}
+}
+
+// Persistence returns how much of a variance shock carries to the next
+// return: alpha + beta for GARCH, alpha + gamma/2 + beta for GJR and beta for
+// EGARCH
+func (m *Model) Persistence() float64 {
+	switch m.Kind {
+	case EGARCH:
+		return m.Beta
+	case GJR:
+		return m.Alpha + m.Gamma/2 + m.Beta
+	default:
+		return m.Alpha + m.Beta
+	}
+}
+
+// LongRunVariance returns the variance that forecasts revert to, NaN for a
+// model that is not stationary
+func (m *Model) LongRunVariance() float64 {
+	p := m.Persistence()
+	if p >= 1 || p <= -1 {
+		return math.NaN()
+	}
+	if m.Kind == EGARCH {
+		return math.Exp(m.Omega / (1 - m.Beta))
+	}
+	return m.Omega / (1 - p)
+}
+
+// forecast advances a variance forecast one step when the residual is unknown
+func (m *Model) forecast(variance float64) float64 {
+	if m.Kind == EGARCH {
+		// The expected log variance, as E[|z|] = sqrt(2/pi) and E[z] = 0
+		return math.Exp(m.Omega + m.Beta*math.Log(variance))
+	}
+	return m.Omega + m.Persistence()*variance
+}
+
+// Forecast returns the variance forecasts for each of the next h returns after
+// the fitted ones. EGARCH forecasts are the exponential of the expected log
+// variance.
+func (m *Model) Forecast(h int) []decimal.Decimal {
+	return m.ForecastFrom(decimal.New(m.next), h)
+}
+
+// ForecastFrom returns the variance forecasts for each of the next h returns
+// given next, the variance of the first of them, such as a value of
+// NewVarianceIndicator
+func (m *Model) ForecastFrom(next decimal.Decimal, h int) []decimal.Decimal {
+	forecasts := make([]decimal.Decimal, 0, max(h, 0))
+	variance := next.Float()
+	for k := 0; k < h; k++ {
+		if k > 0 {
+			variance = m.forecast(variance)
+		}
+		forecasts = append(forecasts, finite(variance))
+	}
+	return forecasts
+}
+
+// Volatility returns the forecast volatility of the sum of the next h returns,
+// the square root of their summed variance forecasts: the h-bar volatility to
+// size positions or measure value at risk over h bars
+func (m *Model) Volatility(h int) decimal.Decimal {
+	sum := decimal.ZERO
+	for _, v := range m.Forecast(h) {
+		sum = sum.Add(v)
+	}
+	return sum.Sqrt()
+}
+
+func finite(v float64) decimal.Decimal {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return decimal.NaN
+	}
+	return decimal.New(v)
+}
+
+// varianceIndicator runs a model over the returns of an indicator
+type varianceIndicator struct {
+	model   *Model
+	returns indicators.Indicator
+	start   int
+	sqrt    bool
+
+	mu        sync.Mutex
+	variances []float64
+}
+
+// NewVarianceIndicator returns the conditional variance of a fitted model over
+// returns: at each index, the variance forecast for the next return from the
+// returns up to and including the index. The model starts from its long run
+// variance at the first ready return, which may lie outside the fitted ones,
+// so the values before that start are zero. Panics if m or returns is nil.
+func NewVarianceIndicator(m *Model, returns indicators.Indicator) indicators.Indicator {
+	return newVarianceIndicator(m, returns, false)
+}
+
+// NewVolatilityIndicator returns the square root of NewVarianceIndicator: the
+// forecast volatility of the next return. Panics if m or returns is nil.
+func NewVolatilityIndicator(m *Model, returns indicators.Indicator) indicators.Indicator {
+	return newVarianceIndicator(m, returns, true)
+}
+
+func newVarianceIndicator(m *Model, returns indicators.Indicator, sqrt bool) indicators.Indicator {
+	if m == nil || returns == nil {
+		panic("goflux: GARCH variance model and returns cannot be nil")
+	}
+	return &varianceIndicator{model: m, returns: returns, start: indicators.Lookback(returns), sqrt: sqrt}
+}
+
+func (v *varianceIndicator) Calculate(index int) decimal.Decimal {
+	if index < v.start {
+		return decimal.ZERO
+	}
+
+	v.mu.Lock()
+	for i := v.start + len(v.variances); i <= index; i++ {
+		variance := v.model.LongRunVariance()
+		if k := len(v.variances); k > 0 {
+			variance = v.variances[k-1]
+		}
+		e := v.returns.Calculate(i).Float() - v.model.Mu
+		v.variances = append(v.variances, v.model.step(variance, e))
+	}
+	variance := v.variances[index-v.start]
+	v.mu.Unlock()
+
+	if v.sqrt {
+		variance = math.Sqrt(variance)
+	}
+	return finite(variance)
+}
+
+func (v *varianceIndicator) Lookback() int { return v.start }
@@ -0,0 +1,143 @@
+package garch
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+// simulate returns n seeded returns of the model around its mean, starting at
+// its long run variance
+func simulate(m Model, seed int64, n int) []decimal.Decimal {
+	r := rand.New(rand.NewSource(seed))
+	returns := make([]decimal.Decimal, n)
+	variance := m.LongRunVariance()
+	for i := range returns {
+		e := math.Sqrt(variance) * r.NormFloat64()
+		returns[i] = decimal.New(m.Mu + e)
+		variance = m.step(variance, e)
+	}
+	return returns
+}
+
+func TestFitGARCH(t *testing.T) {
+	truth := Model{Kind: GARCH, Mu: 0.0005, Omega: 2e-6, Alpha: 0.08, Beta: 0.9}
+	returns := simulate(truth, 1, 4000)
+
+	m, err := Fit(returns, GARCH)
+	require.NoError(t, err)
+	assert.Equal(t, 4000, m.N)
+	assert.InDelta(t, truth.Alpha, m.Alpha, 0.03)
+	assert.InDelta(t, truth.Beta, m.Beta, 0.04)
+	assert.InDelta(t, truth.Persistence(), m.Persistence(), 0.02)
+	assert.InDelta(t, truth.Mu, m.Mu, 0.0005)
+	assert.Zero(t, m.Gamma)
+
+	// The fit is at least as likely as the true parameters
+	truthAtMean := truth
+	truthAtMean.Mu = m.Mu
+	residuals := make([]float64, len(returns))
+	for i, r := range returns {
+		residuals[i] = r.Float() - m.Mu
+	}
+	ll, _ := truthAtMean.logLikelihood(residuals, sampleVar(residuals))
+	assert.GreaterOrEqual(t, m.LogLikelihood, ll-1e-6)
+}
+
+func sampleVar(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x * x
+	}
+	return sum / float64(len(xs))
+}
+
+func TestFitAsymmetricModels(t *testing.T) {
+	gjr := Model{Kind: GJR, Omega: 2e-6, Alpha: 0.03, Gamma: 0.1, Beta: 0.88}
+	m, err := Fit(simulate(gjr, 2, 4000), GJR)
+	require.NoError(t, err)
+	assert.InDelta(t, gjr.Gamma, m.Gamma, 0.05)
+	assert.InDelta(t, gjr.Persistence(), m.Persistence(), 0.03)
+	assert.Less(t, m.Persistence(), 1.0)
+
+	egarch := Model{Kind: EGARCH, Omega: -0.5, Alpha: 0.15, Gamma: -0.08, Beta: 0.95}
+	m, err = Fit(simulate(egarch, 3, 4000), EGARCH)
+	require.NoError(t, err)
+	assert.InDelta(t, egarch.Beta, m.Beta, 0.03)
+	assert.InDelta(t, egarch.Gamma, m.Gamma, 0.04)
+	assert.InDelta(t, egarch.Alpha, m.Alpha, 0.05)
+}
+
+func TestFitErrors(t *testing.T) {
+	_, err := Fit(make([]decimal.Decimal, 10), GARCH)
+	assert.ErrorIs(t, err, ErrTooFewReturns)
+
+	flat := make([]decimal.Decimal, MinReturns)
+	for i := range flat {
+		flat[i] = decimal.New(0.01)
+	}
+	_, err = Fit(flat, GARCH)
+	assert.ErrorIs(t, err, ErrFlatReturns)
+
+	_, err = Fit(flat, Kind(0))
+	assert.ErrorIs(t, err, ErrUnknownKind)
+	assert.Equal(t, "None", Kind(0).String())
+	assert.Equal(t, "GJR-GARCH", GJR.String())
+}
+
+func TestForecast(t *testing.T) {
+	m := &Model{Kind: GARCH, Omega: 1e-5, Alpha: 0.1, Beta: 0.8, next: 4e-4}
+	assert.InDelta(t, 1e-4, m.LongRunVariance(), 1e-12)
+
+	forecasts := m.Forecast(3)
+	require.Len(t, forecasts, 3)
+	assert.InDelta(t, 4e-4, forecasts[0].Float(), 1e-12)
+	assert.InDelta(t, 1e-5+0.9*4e-4, forecasts[1].Float(), 1e-12)
+	assert.InDelta(t, 1e-5+0.9*(1e-5+0.9*4e-4), forecasts[2].Float(), 1e-12)
+
+	// Forecasts revert to the long run variance
+	far := m.Forecast(500)
+	assert.InDelta(t, 1e-4, far[499].Float(), 1e-9)
+
+	sum := 0.0
+	for _, f := range forecasts {
+		sum += f.Float()
+	}
+	assert.InDelta(t, math.Sqrt(sum), m.Volatility(3).Float(), 1e-9)
+	assert.Empty(t, m.Forecast(0))
+
+	egarch := &Model{Kind: EGARCH, Omega: -0.5, Beta: 0.95}
+	long := egarch.ForecastFrom(decimal.New(1e-3), 1000)
+	assert.InDelta(t, egarch.LongRunVariance(), long[999].Float(), 1e-9)
+}
+
+func TestVarianceIndicator(t *testing.T) {
+	m := &Model{Kind: GJR, Mu: 0, Omega: 1e-5, Alpha: 0.1, Gamma: 0.2, Beta: 0.7}
+	returns := indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(0.01, -0.01, 0.02))
+
+	variance := NewVarianceIndicator(m, returns)
+	longRun := m.LongRunVariance()
+	first := 1e-5 + 0.1*1e-4 + 0.7*longRun
+	second := 1e-5 + 0.3*1e-4 + 0.7*first
+	assert.InDelta(t, first, variance.Calculate(0).Float(), 1e-12)
+	assert.InDelta(t, second, variance.Calculate(1).Float(), 1e-12)
+	assert.InDelta(t, math.Sqrt(second), NewVolatilityIndicator(m, returns).Calculate(1).Float(), 1e-12)
+	assert.Equal(t, 0, indicators.Lookback(variance))
+
+	// The forecasts after an index continue from the indicator
+	ahead := m.ForecastFrom(variance.Calculate(2), 2)
+	assert.InDelta(t, 1e-5+0.9*variance.Calculate(2).Float(), ahead[1].Float(), 1e-12)
+
+	delayed := NewVarianceIndicator(m, indicators.NewPercentChangeIndicator(returns))
+	assert.True(t, delayed.Calculate(0).IsZero())
+	assert.Equal(t, 1, indicators.Lookback(delayed))
+
+	assert.Panics(t, func() { NewVarianceIndicator(nil, returns) })
+}
@@ -0,0 +1,95 @@
+package garch
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	minimizeIterations = 2000
+	minimizeRestarts   = 3
+	minimizeTolerance  = 1e-10
+)
+
+// minimize finds a local minimum of f by the Nelder-Mead simplex method from
+// x0, restarting from each result so the simplex cannot stall, and returns it
+// with the value of f there
+func minimize(f func([]float64) float64, x0 []float64) ([]float64, float64) {
+	best, value := x0, f(x0)
+	for r := 0; r < minimizeRestarts; r++ {
+		x, v := nelderMead(f, best)
+		if v >= value && r > 0 {
+			break
+		}
+		if v <= value {
+			best, value = x, v
+		}
+	}
+	return best, value
+}
+
+func nelderMead(f func([]float64) float64, x0 []float64) ([]float64, float64) {
+	n := len(x0)
+	type vertex struct {
+		x []float64
+		v float64
+	}
+	simplex := make([]vertex, n+1)
+	simplex[0] = vertex{append([]float64(nil), x0...), f(x0)}
+	for i := 0; i < n; i++ {
+		x := append([]float64(nil), x0...)
+		x[i] += 0.5
+		simplex[i+1] = vertex{x, f(x)}
+	}
+
+	// along returns centroid + t*(x - centroid)
+	along := func(centroid, x []float64, t float64) vertex {
+		p := make([]float64, n)
+		for j := range p {
+			p[j] = centroid[j] + t*(x[j]-centroid[j])
+		}
+		return vertex{p, f(p)}
+	}
+
+	for iter := 0; iter < minimizeIterations; iter++ {
+		sort.Slice(simplex, func(a, b int) bool { return simplex[a].v < simplex[b].v })
+		best, worst := simplex[0], simplex[n]
+		if math.Abs(worst.v-best.v) <= minimizeTolerance*(1+math.Abs(best.v)) {
+			break
+		}
+
+		centroid := make([]float64, n)
+		for _, vx := range simplex[:n] {
+			for j := range centroid {
+				centroid[j] += vx.x[j] / float64(n)
+			}
+		}
+
+		reflected := along(centroid, worst.x, -1)
+		switch {
+		case reflected.v < best.v:
+			if expanded := along(centroid, worst.x, -2); expanded.v < reflected.v {
+				simplex[n] = expanded
+			} else {
+				simplex[n] = reflected
+			}
+		case reflected.v < simplex[n-1].v:
+			simplex[n] = reflected
+		default:
+			contracted := along(centroid, worst.x, 0.5)
+			if reflected.v < worst.v {
+				contracted = along(centroid, worst.x, -0.5)
+			}
+			if contracted.v < math.Min(worst.v, reflected.v) {
+				simplex[n] = contracted
+				continue
+			}
+			// Shrink towards the best vertex
+			for i := 1; i <= n; i++ {
+				simplex[i] = along(best.x, simplex[i].x, 0.5)
+			}
+		}
+	}
+	sort.Slice(simplex, func(a, b int) bool { return simplex[a].v < simplex[b].v })
+	return simplex[0].x, simplex[0].v
+}
@@ -63,3 +63,5 @@ func (adl *adLineIndicator) Calculate(index int) decimal.Decimal {
 
 	return adl.cache[index]
 }
+
+func (adl *adLineIndicator) Lookback() int { return 0 }
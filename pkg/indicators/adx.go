@@ -186,3 +186,5 @@ func (a *adxIndicator) calculateTrueRange(index int) decimal.Decimal {
 
 	return tr
 }
+
+func (a *adxIndicator) Lookback() int { return 2*a.period - 1 }
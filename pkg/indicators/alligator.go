@@ -69,6 +69,34 @@ func NewGatorOscillatorIndicatorsFromAlligator(jaw, teeth, lips Indicator) (uppe
 		gatorLower{teeth: teeth, lips: lips}
 }
 
+// NewAlligatorIndicator returns the default Alligator lines as a
+// MultiOutputIndicator with the outputs jaw, teeth and lips.
+// Panics if s is nil.
+func NewAlligatorIndicator(s *series.TimeSeries) MultiOutputIndicator {
+	jaw, teeth, lips := NewAlligatorIndicators(s)
+	return NewMultiOutputIndicator(alligatorOutputs, jaw, teeth, lips)
+}
+
+// NewAlligatorIndicatorCustom is NewAlligatorIndicatorsCustom as a
+// MultiOutputIndicator with the outputs jaw, teeth and lips.
+func NewAlligatorIndicatorCustom(
+	s *series.TimeSeries,
+	jawPeriod, jawShift int,
+	teethPeriod, teethShift int,
+	lipsPeriod, lipsShift int,
+) MultiOutputIndicator {
+	jaw, teeth, lips := NewAlligatorIndicatorsCustom(s, jawPeriod, jawShift, teethPeriod, teethShift, lipsPeriod, lipsShift)
+	return NewMultiOutputIndicator(alligatorOutputs, jaw, teeth, lips)
+}
+
+// NewGatorOscillatorIndicator returns the Gator Oscillator bars as a
+// MultiOutputIndicator with the outputs upper and lower.
+// Panics if s is nil.
+func NewGatorOscillatorIndicator(s *series.TimeSeries) MultiOutputIndicator {
+	upper, lower := NewGatorOscillatorIndicators(s)
+	return NewMultiOutputIndicator(gatorOutputs, upper, lower)
+}
+
 // --- Alligator lines ---
 
 type shiftedSMMA struct {
@@ -125,3 +153,9 @@ func (g gatorUpper) Calculate(index int) decimal.Decimal {
 func (g gatorLower) Calculate(index int) decimal.Decimal {
 	return g.teeth.Calculate(index).Sub(g.lips.Calculate(index)).Abs().Neg()
 }
+
+func (s shiftedSMMA) Lookback() int { return Lookback(s.smma) + s.shift }
+
+func (g gatorUpper) Lookback() int { return MaxLookback(g.jaw, g.teeth) }
+
+func (g gatorLower) Lookback() int { return MaxLookback(g.teeth, g.lips) }
@@ -0,0 +1,238 @@
+package indicators
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+var anchoredVWAPOutputs = []string{"vwap", "upper1", "lower1", "upper2", "lower2", "upper3", "lower3"}
+
+// VWAPAnchor decides where an anchored VWAP starts. Anchor returns the index
+// the VWAP at index is accumulated from, or -1 before the first anchor. An
+// anchor may move forward as new events occur, but may only look at candles up
+// to index.
+type VWAPAnchor interface {
+	Anchor(s *series.TimeSeries, index int) int
+}
+
+type indexAnchor int
+
+// AnchorAtIndex anchors at a fixed candle index
+func AnchorAtIndex(index int) VWAPAnchor { return indexAnchor(index) }
+
+func (a indexAnchor) Anchor(_ *series.TimeSeries, index int) int {
+	if index < int(a) {
+		return -1
+	}
+	return int(a)
+}
+
+func (a indexAnchor) Lookback() int { return max(int(a), 0) }
+
+type timeAnchor time.Time
+
+// AnchorAtTime anchors at the candle containing t, or the first one after it
+func AnchorAtTime(t time.Time) VWAPAnchor { return timeAnchor(t) }
+
+func (a timeAnchor) Anchor(s *series.TimeSeries, index int) int {
+	at := time.Time(a)
+	first := sort.Search(index+1, func(i int) bool { return s.Candles[i].Period.End.After(at) })
+	if first > index {
+		return -1
+	}
+	return first
+}
+
+type sessionAnchor struct{ key SessionKey }
+
+// AnchorAtSession anchors at the first candle of each session, giving a
+// session VWAP
+func AnchorAtSession(key SessionKey) VWAPAnchor { return sessionAnchor{key} }
+
+func (a sessionAnchor) Anchor(s *series.TimeSeries, index int) int {
+	return sessionStart(s, a.key, index)
+}
+
+// swingAnchor anchors at the latest confirmed swing. Swings of each series are
+// memoized by index.
+type swingAnchor struct {
+	strength int
+	high     bool
+
+	mu     sync.Mutex
+	latest map[*series.TimeSeries][]int
+}
+
+// AnchorAtSwingHigh anchors at the latest swing high: a candle whose high is
+// above the highs of the strength candles on each side. A swing is only known
+// strength candles after it, so the anchor moves then.
+func AnchorAtSwingHigh(strength int) VWAPAnchor {
+	return &swingAnchor{strength: safeWindow(strength), high: true, latest: make(map[*series.TimeSeries][]int)}
+}
+
+// AnchorAtSwingLow anchors at the latest swing low, a candle whose low is
+// below the lows of the strength candles on each side
+func AnchorAtSwingLow(strength int) VWAPAnchor {
+	return &swingAnchor{strength: safeWindow(strength), high: false, latest: make(map[*series.TimeSeries][]int)}
+}
+
+func (a *swingAnchor) Anchor(s *series.TimeSeries, index int) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	latest := a.latest[s]
+	for i := len(latest); i <= index; i++ {
+		anchor := -1
+		if i > 0 {
+			anchor = latest[i-1]
+		}
+		if pivot := i - a.strength; pivot >= a.strength && isPivot(s, pivot, a.strength, a.strength, a.high) {
+			anchor = pivot
+		}
+		latest = append(latest, anchor)
+	}
+	a.latest[s] = latest
+	return latest[index]
+}
+
+func (a *swingAnchor) Lookback() int { return 2 * a.strength }
+
+// vwapInputs memoizes the volume, price-volume and squared price-volume of the
+// typical price of each candle, shared by the anchored VWAPs of a series
+type vwapInputs struct {
+	series *series.TimeSeries
+	mu     sync.Mutex
+	v      []decimal.Decimal
+	pv     []decimal.Decimal
+	p2v    []decimal.Decimal
+}
+
+func (in *vwapInputs) at(index int) (v, pv, p2v decimal.Decimal) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for i := len(in.v); i <= index; i++ {
+		c := in.series.Candles[i]
+		tp := c.MaxPrice.Add(c.MinPrice).Add(c.ClosePrice).Div(decThree)
+		pv := tp.Mul(c.Volume)
+		in.v = append(in.v, c.Volume)
+		in.pv = append(in.pv, pv)
+		in.p2v = append(in.p2v, pv.Mul(tp))
+	}
+	return in.v[index], in.pv[index], in.p2v[index]
+}
+
+type anchoredVWAPValue struct {
+	vwap  decimal.Decimal
+	stdev decimal.Decimal
+}
+
+type anchoredVWAPIndicator struct {
+	series *series.TimeSeries
+	anchor VWAPAnchor
+	inputs *vwapInputs
+
+	mu      sync.Mutex
+	results map[int]anchoredVWAPValue
+	// Sums from runAnchor through runLast, extended while the anchor holds
+	runAnchor int
+	runLast   int
+	sumV      decimal.Decimal
+	sumPV     decimal.Decimal
+	sumP2V    decimal.Decimal
+}
+
+// NewAnchoredVWAPIndicator returns the VWAP of the typical price accumulated
+// from the index given by anchor, with volume weighted standard deviation
+// bands at 1, 2 and 3 sigma, as a MultiOutputIndicator. Calculate returns the
+// VWAP. Before the first anchor, and while there is no volume, every line is
+// NaN. Panics if s or anchor is nil.
+func NewAnchoredVWAPIndicator(s *series.TimeSeries, anchor VWAPAnchor) MultiOutputIndicator {
+	return NewAnchoredVWAPs(s, anchor)[0]
+}
+
+// NewAnchoredVWAPs returns an anchored VWAP for each anchor, in order. The
+// VWAPs share the per-candle inputs, so several anchors on one series cost
+// little more than one. Panics if s or any anchor is nil.
+func NewAnchoredVWAPs(s *series.TimeSeries, anchors ...VWAPAnchor) []MultiOutputIndicator {
+	if s == nil {
+		panic("goflux: AnchoredVWAP series cannot be nil")
+	}
+	inputs := &vwapInputs{series: s}
+	vwaps := make([]MultiOutputIndicator, len(anchors))
+	for i, anchor := range anchors {
+		if anchor == nil {
+			panic("goflux: AnchoredVWAP anchor cannot be nil")
+		}
+		a := &anchoredVWAPIndicator{
+			series:    s,
+			anchor:    anchor,
+			inputs:    inputs,
+			results:   make(map[int]anchoredVWAPValue),
+			runAnchor: -1,
+		}
+		vwaps[i] = a.multiOutput()
+	}
+	return vwaps
+}
+
+func (a *anchoredVWAPIndicator) multiOutput() MultiOutputIndicator {
+	lines := []Indicator{withLookback{indicatorFunc(func(index int) decimal.Decimal { return a.value(index).vwap }), a.Lookback()}}
+	for sigma := 1; sigma <= 3; sigma++ {
+		for _, sign := range []float64{1, -1} {
+			k := decimal.New(sign * float64(sigma))
+			lines = append(lines, withLookback{indicatorFunc(func(index int) decimal.Decimal {
+				v := a.value(index)
+				return v.vwap.Add(v.stdev.Mul(k))
+			}), a.Lookback()})
+		}
+	}
+	return NewMultiOutputIndicator(anchoredVWAPOutputs, lines...)
+}
+
+func (a *anchoredVWAPIndicator) value(index int) anchoredVWAPValue {
+	if index < 0 || index >= len(a.series.Candles) {
+		return anchoredVWAPValue{decimal.ZERO, decimal.ZERO}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if v, ok := a.results[index]; ok {
+		return v
+	}
+
+	undefined := anchoredVWAPValue{decimal.NaN, decimal.NaN}
+	anchor := a.anchor.Anchor(a.series, index)
+	if anchor < 0 || anchor > index {
+		a.results[index] = undefined
+		return undefined
+	}
+	if anchor != a.runAnchor || index < a.runLast {
+		a.runAnchor, a.runLast = anchor, anchor-1
+		a.sumV, a.sumPV, a.sumP2V = decimal.ZERO, decimal.ZERO, decimal.ZERO
+	}
+	for i := a.runLast + 1; i <= index; i++ {
+		v, pv, p2v := a.inputs.at(i)
+		a.sumV, a.sumPV, a.sumP2V = a.sumV.Add(v), a.sumPV.Add(pv), a.sumP2V.Add(p2v)
+	}
+	a.runLast = index
+
+	result := undefined
+	if !a.sumV.IsZero() {
+		vwap := a.sumPV.Div(a.sumV)
+		variance := a.sumP2V.Div(a.sumV).Sub(vwap.Mul(vwap))
+		result = anchoredVWAPValue{vwap: vwap, stdev: variance.Max(decimal.ZERO).Sqrt()}
+	}
+	a.results[index] = result
+	return result
+}
+
+// Lookback is that of the anchor if it reports one, such as AnchorAtIndex
+func (a *anchoredVWAPIndicator) Lookback() int {
+	if l, ok := a.anchor.(interface{ Lookback() int }); ok {
+		return l.Lookback()
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+func TestAnchoredVWAP(t *testing.T) {
+	ts := streamingTestSeries(80)
+	vwap := indicators.NewVWAPIndicator(ts)
+
+	anchored := indicators.NewAnchoredVWAPIndicator(ts, indicators.AnchorAtIndex(0))
+	assert.Equal(t, []string{"vwap", "upper1", "lower1", "upper2", "lower2", "upper3", "lower3"}, anchored.Outputs())
+	for i := 0; i < 80; i++ {
+		assert.InDelta(t, vwap.Calculate(i).Float(), anchored.Calculate(i).Float(), 1e-9, "index %d", i)
+	}
+
+	late := indicators.NewAnchoredVWAPIndicator(ts, indicators.AnchorAtIndex(30))
+	assert.True(t, late.Calculate(29).IsNaN())
+	assert.Equal(t, 30, indicators.Lookback(late.Output("upper2")))
+
+	// The VWAP and bands from index 30 worked out directly
+	for _, i := range []int{30, 31, 55, 79} {
+		var sumV, sumPV float64
+		prices := make([]float64, 0, i-29)
+		for j := 30; j <= i; j++ {
+			c := ts.Candles[j]
+			tp := (c.MaxPrice.Float() + c.MinPrice.Float() + c.ClosePrice.Float()) / 3
+			prices = append(prices, tp)
+			sumV += c.Volume.Float()
+			sumPV += tp * c.Volume.Float()
+		}
+		mean := sumPV / sumV
+		var sumSq float64
+		for j, tp := range prices {
+			sumSq += ts.Candles[30+j].Volume.Float() * (tp - mean) * (tp - mean)
+		}
+		stdev := math.Sqrt(sumSq / sumV)
+
+		values := late.CalculateAll(i)
+		assert.InDelta(t, mean, values["vwap"].Float(), 1e-6, "index %d", i)
+		assert.InDelta(t, mean+stdev, values["upper1"].Float(), 1e-6, "index %d", i)
+		assert.InDelta(t, mean-2*stdev, values["lower2"].Float(), 1e-6, "index %d", i)
+		assert.InDelta(t, mean+3*stdev, values["upper3"].Float(), 1e-6, "index %d", i)
+	}
+}
+
+func TestVWAPAnchors(t *testing.T) {
+	start := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+	ts := rangeSeries(start, time.Hour,
+		[3]float64{10, 11, 1},
+		[3]float64{11, 14, 1}, // swing high at index 1
+		[3]float64{10, 12, 1},
+		[3]float64{8, 9, 1},  // swing low at index 3
+		[3]float64{9, 13, 1}, // swing high at index 4, confirmed at 5
+		[3]float64{10, 12, 1},
+	)
+
+	at := func(a indicators.VWAPAnchor) []int {
+		out := make([]int, len(ts.Candles))
+		for i := range out {
+			out[i] = a.Anchor(ts, i)
+		}
+		return out
+	}
+	assert.Equal(t, []int{-1, -1, 2, 2, 2, 2}, at(indicators.AnchorAtIndex(2)))
+	assert.Equal(t, []int{-1, -1, 2, 2, 2, 2}, at(indicators.AnchorAtTime(start.Add(150*time.Minute))))
+	assert.Equal(t, []int{0, 0, 2, 2, 2, 2}, at(indicators.AnchorAtSession(indicators.DailySessions(nil))))
+	assert.Equal(t, []int{-1, -1, 1, 1, 1, 4}, at(indicators.AnchorAtSwingHigh(1)))
+	assert.Equal(t, []int{-1, -1, -1, -1, 3, 3}, at(indicators.AnchorAtSwingLow(1)))
+}
+
+func TestNewAnchoredVWAPs(t *testing.T) {
+	ts := testutils.RandomTimeSeries(60)
+	anchors := []indicators.VWAPAnchor{
+		indicators.AnchorAtIndex(0),
+		indicators.AnchorAtIndex(20),
+		indicators.AnchorAtSwingLow(3),
+	}
+	vwaps := indicators.NewAnchoredVWAPs(ts, anchors...)
+	require.Len(t, vwaps, 3)
+	for k, anchor := range anchors {
+		alone := indicators.NewAnchoredVWAPIndicator(ts, anchor)
+		for i := 59; i >= 0; i-- {
+			assertSameValue(t, "anchored", i, alone.Calculate(i), vwaps[k].Calculate(i))
+		}
+	}
+
+	assert.Panics(t, func() { indicators.NewAnchoredVWAPs(nil, indicators.AnchorAtIndex(0)) })
+	assert.Panics(t, func() { indicators.NewAnchoredVWAPs(ts, nil) })
+	assert.True(t, indicators.NewAnchoredVWAPIndicator(series.NewTimeSeries(), indicators.AnchorAtIndex(0)).Calculate(0).IsZero())
+}
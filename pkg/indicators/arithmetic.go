@@ -0,0 +1,76 @@
+package indicators
+
+import "github.com/irfndi/goflux/pkg/decimal"
+
+type sumIndicator struct {
+	augend Indicator
+	addend Indicator
+}
+
+// NewSumIndicator returns an indicator which returns the sum of two indicators
+func NewSumIndicator(augend, addend Indicator) Indicator {
+	return sumIndicator{augend: augend, addend: addend}
+}
+
+func (si sumIndicator) Calculate(index int) decimal.Decimal {
+	return si.augend.Calculate(index).Add(si.addend.Calculate(index))
+}
+
+func (si sumIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(si.augend, dst)
+	addend := ComputeAll(si.addend, len(dst))
+	for i := range dst {
+		dst[i] = dst[i].Add(addend[i])
+	}
+}
+
+type productIndicator struct {
+	multiplicand Indicator
+	multiplier   Indicator
+}
+
+// NewProductIndicator returns an indicator which returns the product of two indicators
+func NewProductIndicator(multiplicand, multiplier Indicator) Indicator {
+	return productIndicator{multiplicand: multiplicand, multiplier: multiplier}
+}
+
+func (pi productIndicator) Calculate(index int) decimal.Decimal {
+	return pi.multiplicand.Calculate(index).Mul(pi.multiplier.Calculate(index))
+}
+
+func (pi productIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(pi.multiplicand, dst)
+	multiplier := ComputeAll(pi.multiplier, len(dst))
+	for i := range dst {
+		dst[i] = dst[i].Mul(multiplier[i])
+	}
+}
+
+type quotientIndicator struct {
+	dividend Indicator
+	divisor  Indicator
+}
+
+// NewQuotientIndicator returns an indicator which returns one indicator divided by another. The result is
+// decimal.NaN where the divisor is zero.
+func NewQuotientIndicator(dividend, divisor Indicator) Indicator {
+	return quotientIndicator{dividend: dividend, divisor: divisor}
+}
+
+func (qi quotientIndicator) Calculate(index int) decimal.Decimal {
+	return qi.dividend.Calculate(index).DivOrNaN(qi.divisor.Calculate(index))
+}
+
+func (qi quotientIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(qi.dividend, dst)
+	divisor := ComputeAll(qi.divisor, len(dst))
+	for i := range dst {
+		dst[i] = dst[i].DivOrNaN(divisor[i])
+	}
+}
+
+func (si sumIndicator) Lookback() int { return MaxLookback(si.augend, si.addend) }
+
+func (pi productIndicator) Lookback() int { return MaxLookback(pi.multiplicand, pi.multiplier) }
+
+func (qi quotientIndicator) Lookback() int { return MaxLookback(qi.dividend, qi.divisor) }
@@ -0,0 +1,30 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+func TestArithmeticIndicators(t *testing.T) {
+	a := indicators.NewFixedIndicator(10, 9, 8)
+	b := indicators.NewFixedIndicator(2, 3, 0)
+
+	sum := indicators.NewSumIndicator(a, b)
+	testutils.DecimalEquals(t, 12, sum.Calculate(0))
+	testutils.DecimalEquals(t, 8, sum.Calculate(2))
+
+	product := indicators.NewProductIndicator(a, b)
+	testutils.DecimalEquals(t, 27, product.Calculate(1))
+
+	quotient := indicators.NewQuotientIndicator(a, b)
+	testutils.DecimalEquals(t, 5, quotient.Calculate(0))
+	assert.True(t, quotient.Calculate(2).IsNaN())
+
+	all := indicators.ComputeAll(quotient, 3)
+	testutils.DecimalEquals(t, 3, all[1])
+	assert.True(t, all[2].IsNaN())
+}
@@ -80,3 +80,5 @@ func NewAroonDownIndicator(indicator Indicator, window int) Indicator {
 		lowIndex:  -1,
 	}
 }
+
+func (ai *aroonIndicator) Lookback() int { return Lookback(ai.indicator) + ai.window - 1 }
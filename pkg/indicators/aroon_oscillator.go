@@ -48,3 +48,5 @@ func NewAroonOscillatorFromSeries(s *series.TimeSeries, window int) Indicator {
 func (ao aroonOscillator) Calculate(index int) decimal.Decimal {
 	return ao.aroonUp.Calculate(index).Sub(ao.aroonDown.Calculate(index))
 }
+
+func (ao aroonOscillator) Lookback() int { return MaxLookback(ao.aroonUp, ao.aroonDown) }
@@ -31,3 +31,5 @@ func NewAverageLossesIndicator(indicator Indicator, window int) Indicator {
 func (ai averageIndicator) Calculate(index int) decimal.Decimal {
 	return ai.Indicator.Calculate(index).Div(decimal.New(float64(math.Min(index+1, ai.window))))
 }
+
+func (ai averageIndicator) Lookback() int { return Lookback(ai.Indicator) }
@@ -40,3 +40,22 @@ func (atr averageTrueRangeIndicator) Calculate(index int) decimal.Decimal {
 
 	return sum.Div(decimal.New(float64(atr.window)))
 }
+
+func (atr averageTrueRangeIndicator) ComputeInto(dst []decimal.Decimal) {
+	n := len(dst)
+	if atr.series != nil && n > atr.series.Length() {
+		n = atr.series.Length()
+	}
+	NewTrueRangeIndicator(atr.series).(trueRangeIndicator).ComputeInto(dst[:n])
+	computeWindowSum(dst[:n], atr.window, dst[:n])
+	windowDec := decimal.New(float64(atr.window))
+	for i := range dst {
+		if i < atr.window-1 || i >= n {
+			dst[i] = decimal.ZERO
+			continue
+		}
+		dst[i] = dst[i].Div(windowDec)
+	}
+}
+
+func (atr averageTrueRangeIndicator) Lookback() int { return atr.window - 1 }
@@ -61,3 +61,5 @@ func (ao *awesomeOscillatorIndicator) calculateSMA(index int, window int) decima
 
 	return sum.Div(decimal.New(float64(window)))
 }
+
+func (ao *awesomeOscillatorIndicator) Lookback() int { return ao.windowSlow - 1 }
@@ -1,6 +1,8 @@
 package indicators
 
 import (
+	"reflect"
+
 	"github.com/irfndi/goflux/pkg/decimal"
 	"github.com/irfndi/goflux/pkg/series"
 )
@@ -22,6 +24,10 @@ func (vi volumeIndicator) Calculate(index int) decimal.Decimal {
 	return candle.Volume
 }
 
+func (vi volumeIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(vi.series, dst, func(c *series.Candle) decimal.Decimal { return c.Volume })
+}
+
 type closePriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -39,6 +45,10 @@ func (cpi closePriceIndicator) Calculate(index int) decimal.Decimal {
 	return candle.ClosePrice
 }
 
+func (cpi closePriceIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(cpi.series, dst, func(c *series.Candle) decimal.Decimal { return c.ClosePrice })
+}
+
 type highPriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -58,6 +68,10 @@ func (hpi highPriceIndicator) Calculate(index int) decimal.Decimal {
 	return candle.MaxPrice
 }
 
+func (hpi highPriceIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(hpi.series, dst, func(c *series.Candle) decimal.Decimal { return c.MaxPrice })
+}
+
 type lowPriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -77,6 +91,10 @@ func (lpi lowPriceIndicator) Calculate(index int) decimal.Decimal {
 	return candle.MinPrice
 }
 
+func (lpi lowPriceIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(lpi.series, dst, func(c *series.Candle) decimal.Decimal { return c.MinPrice })
+}
+
 type openPriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -96,6 +114,10 @@ func (opi openPriceIndicator) Calculate(index int) decimal.Decimal {
 	return candle.OpenPrice
 }
 
+func (opi openPriceIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(opi.series, dst, func(c *series.Candle) decimal.Decimal { return c.OpenPrice })
+}
+
 type typicalPriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -115,6 +137,13 @@ func (tpi typicalPriceIndicator) Calculate(index int) decimal.Decimal {
 	return numerator.Div(decimal.NewFromString("3"))
 }
 
+func (tpi typicalPriceIndicator) ComputeInto(dst []decimal.Decimal) {
+	three := decimal.NewFromString("3")
+	computeCandles(tpi.series, dst, func(c *series.Candle) decimal.Decimal {
+		return c.MaxPrice.Add(c.MinPrice).Add(c.ClosePrice).Div(three)
+	})
+}
+
 type averagePriceIndicator struct {
 	series *series.TimeSeries
 }
@@ -169,3 +198,67 @@ func candleAt(s *series.TimeSeries, index int) *series.Candle {
 	}
 	return s.GetCandle(index)
 }
+
+// seriesIndicator is implemented by indicators that read the candles of a
+// series directly, and so have no values past its end
+type seriesIndicator interface {
+	timeSeries() *series.TimeSeries
+}
+
+var seriesType = reflect.TypeOf((*series.TimeSeries)(nil))
+
+// seriesOf returns the series ind is computed from: the one it reads directly,
+// or else the only one reachable through its fields, such as the series of the
+// close prices an SMA averages. It returns nil if there is none, or several.
+func seriesOf(ind Indicator) *series.TimeSeries {
+	if si, ok := ind.(seriesIndicator); ok {
+		return si.timeSeries()
+	}
+	var found *series.TimeSeries
+	several := false
+	reachable(reflect.ValueOf(ind), seriesType, func(v reflect.Value) {
+		s := (*series.TimeSeries)(v.UnsafePointer())
+		several = several || found != nil && found != s
+		found = s
+	}, make(map[uintptr]bool))
+	if several {
+		return nil
+	}
+	return found
+}
+
+func (vi volumeIndicator) Lookback() int { return 0 }
+
+func (cpi closePriceIndicator) Lookback() int { return 0 }
+
+func (hpi highPriceIndicator) Lookback() int { return 0 }
+
+func (lpi lowPriceIndicator) Lookback() int { return 0 }
+
+func (opi openPriceIndicator) Lookback() int { return 0 }
+
+func (tpi typicalPriceIndicator) Lookback() int { return 0 }
+
+func (api averagePriceIndicator) Lookback() int { return 0 }
+
+func (mpi medianPriceIndicator) Lookback() int { return 0 }
+
+func (wci weightedCloseIndicator) Lookback() int { return 0 }
+
+func (vi volumeIndicator) timeSeries() *series.TimeSeries { return vi.series }
+
+func (cpi closePriceIndicator) timeSeries() *series.TimeSeries { return cpi.series }
+
+func (hpi highPriceIndicator) timeSeries() *series.TimeSeries { return hpi.series }
+
+func (lpi lowPriceIndicator) timeSeries() *series.TimeSeries { return lpi.series }
+
+func (opi openPriceIndicator) timeSeries() *series.TimeSeries { return opi.series }
+
+func (tpi typicalPriceIndicator) timeSeries() *series.TimeSeries { return tpi.series }
+
+func (api averagePriceIndicator) timeSeries() *series.TimeSeries { return api.series }
+
+func (mpi medianPriceIndicator) timeSeries() *series.TimeSeries { return mpi.series }
+
+func (wci weightedCloseIndicator) timeSeries() *series.TimeSeries { return wci.series }
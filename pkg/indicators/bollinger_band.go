@@ -35,6 +35,25 @@ func NewBollingerLowerBandIndicator(indicator Indicator, window int, sigma float
 	}
 }
 
+// NewBollingerBandsIndicator returns the middle, upper and lower Bollinger bands as a MultiOutputIndicator.
+// Calculate returns the middle band, the SMA of the underlying indicator.
+func NewBollingerBandsIndicator(indicator Indicator, window int, sigma float64) MultiOutputIndicator {
+	return NewMultiOutputIndicator(bandOutputs,
+		NewSimpleMovingAverage(indicator, window),
+		NewBollingerUpperBandIndicator(indicator, window, sigma),
+		NewBollingerLowerBandIndicator(indicator, window, sigma))
+}
+
 func (bbi bbandIndicator) Calculate(index int) decimal.Decimal {
 	return bbi.ma.Calculate(index).Add(bbi.stdev.Calculate(index).Mul(bbi.muladd))
 }
+
+func (bbi bbandIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(bbi.ma, dst)
+	stdev := ComputeAll(bbi.stdev, len(dst))
+	for i := range dst {
+		dst[i] = dst[i].Add(stdev[i].Mul(bbi.muladd))
+	}
+}
+
+func (bbi bbandIndicator) Lookback() int { return MaxLookback(bbi.ma, bbi.stdev) }
@@ -0,0 +1,325 @@
+package indicators
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+// Universe is a set of series measured for market breadth on the timeline of
+// a base series, such as the index they make up or the series being traded.
+// At each base index a member counts only if it has a candle ending at the
+// same time, so members that did not trade that bar, or are not listed yet,
+// are left out rather than carried forward.
+type Universe struct {
+	base    *series.TimeSeries
+	members []*series.TimeSeries
+}
+
+// NewUniverse returns the universe of members on the timeline of base.
+// Panics if base is nil, there are no members or a member is nil.
+func NewUniverse(base *series.TimeSeries, members ...*series.TimeSeries) *Universe {
+	if base == nil || len(members) == 0 {
+		panic("goflux: Universe needs a base series and members")
+	}
+	for _, m := range members {
+		if m == nil {
+			panic("goflux: Universe members cannot be nil")
+		}
+	}
+	telemetry.ReportUsage("Universe", map[string]string{"members": strconv.Itoa(len(members))})
+	return &Universe{base: base, members: members}
+}
+
+// Len returns the number of members
+func (u *Universe) Len() int { return len(u.members) }
+
+// candleIndex returns the index of the candle of member ending with the base
+// candle at index, or -1 when there is none
+func (u *Universe) candleIndex(member *series.TimeSeries, index int) int {
+	if index < 0 || index >= len(u.base.Candles) {
+		return -1
+	}
+	end := u.base.Candles[index].Period.End
+	k := sort.Search(len(member.Candles), func(k int) bool { return !member.Candles[k].Period.End.Before(end) })
+	if k == len(member.Candles) || !member.Candles[k].Period.End.Equal(end) {
+		return -1
+	}
+	return k
+}
+
+// breadthCounts are the advancing and declining members at one bar
+type breadthCounts struct {
+	advances, declines, unchanged int
+	upVolume, downVolume          decimal.Decimal
+}
+
+// counts compares the close of each member trading at index with its close
+// on the candle before
+func (u *Universe) counts(index int) breadthCounts {
+	c := breadthCounts{upVolume: decimal.ZERO, downVolume: decimal.ZERO}
+	for _, m := range u.members {
+		k := u.candleIndex(m, index)
+		if k < 1 {
+			continue
+		}
+		candle := m.Candles[k]
+		switch change := candle.ClosePrice.Cmp(m.Candles[k-1].ClosePrice); {
+		case change > 0:
+			c.advances++
+			c.upVolume = c.upVolume.Add(candle.Volume)
+		case change < 0:
+			c.declines++
+			c.downVolume = c.downVolume.Add(candle.Volume)
+		default:
+			c.unchanged++
+		}
+	}
+	return c
+}
+
+// settled returns the number of base indices whose values can no longer
+// change: all but the last, whose members may still be arriving
+func (u *Universe) settled() int { return max(len(u.base.Candles)-1, 0) }
+
+// breadthIndicator is a per-bar breadth measure, NaN outside the base series
+type breadthIndicator struct {
+	universe *Universe
+	value    func(index int) decimal.Decimal
+}
+
+func newBreadth(u *Universe, value func(index int) decimal.Decimal) Indicator {
+	if u == nil {
+		panic("goflux: breadth universe cannot be nil")
+	}
+	return breadthIndicator{universe: u, value: value}
+}
+
+func (b breadthIndicator) Calculate(index int) decimal.Decimal {
+	if index < 0 || index >= len(b.universe.base.Candles) {
+		return decimal.NaN
+	}
+	return b.value(index)
+}
+
+func (b breadthIndicator) Lookback() int { return 0 }
+
+// settledRecurrence is a first-order recurrence over an indicator from index
+// start, memoizing its values below settled, the number of bars whose inputs
+// can no longer change. Later bars are recomputed from the last settled value.
+type settledRecurrence struct {
+	start   int
+	settled func() int
+	first   func(index int) decimal.Decimal
+	next    func(prev decimal.Decimal, index int) decimal.Decimal
+
+	mu     sync.Mutex
+	values []decimal.Decimal
+}
+
+func (r *settledRecurrence) Calculate(index int) decimal.Decimal {
+	if index < r.start {
+		return decimal.ZERO
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if k := index - r.start; k < len(r.values) {
+		return r.values[k]
+	}
+	var value decimal.Decimal
+	if n := len(r.values); n > 0 {
+		value = r.values[n-1]
+	}
+	settled := r.settled()
+	for i := r.start + len(r.values); i <= index; i++ {
+		if i == r.start {
+			value = r.first(i)
+		} else {
+			value = r.next(value, i)
+		}
+		if i < settled && i-r.start == len(r.values) {
+			r.values = append(r.values, value)
+		}
+	}
+	return value
+}
+
+func (r *settledRecurrence) Lookback() int { return r.start }
+
+// newRunningSum returns the running total of indicator from its lookback
+func newRunningSum(indicator Indicator, settled func() int) *settledRecurrence {
+	return &settledRecurrence{
+		start:   Lookback(indicator),
+		settled: settled,
+		first:   indicator.Calculate,
+		next: func(prev decimal.Decimal, index int) decimal.Decimal {
+			return prev.Add(indicator.Calculate(index))
+		},
+	}
+}
+
+// newSettledEMA returns the EMA of indicator over window as NewEMAIndicator
+// computes it, seeded with the simple average of the first window values
+func newSettledEMA(indicator Indicator, window int, settled func() int) *settledRecurrence {
+	alpha := decimal.New(2).Div(decimal.NewFromInt(int64(window + 1)))
+	keep := decimal.ONE.Sub(alpha)
+	return &settledRecurrence{
+		start:   Lookback(indicator) + window - 1,
+		settled: settled,
+		first: func(index int) decimal.Decimal {
+			return NewSimpleMovingAverage(indicator, window).Calculate(index)
+		},
+		next: func(prev decimal.Decimal, index int) decimal.Decimal {
+			return indicator.Calculate(index).Mul(alpha).Add(prev.Mul(keep))
+		},
+	}
+}
+
+// NewAdvanceDeclineIndicator returns the net advances of the universe at each
+// bar: the members closing up less those closing down
+func NewAdvanceDeclineIndicator(u *Universe) Indicator {
+	return newBreadth(u, func(index int) decimal.Decimal {
+		c := u.counts(index)
+		return decimal.NewFromInt(int64(c.advances - c.declines))
+	})
+}
+
+// NewAdvanceDeclineLineIndicator returns the advance/decline line, the
+// running total of net advances from the first base index. Its trend, rather
+// than its level, shows whether a move is broadly supported.
+func NewAdvanceDeclineLineIndicator(u *Universe) Indicator {
+	return newRunningSum(NewAdvanceDeclineIndicator(u), u.settled)
+}
+
+// NewAdvanceDeclineRatioIndicator returns the members closing up divided by
+// those closing down, NaN when none closed down
+func NewAdvanceDeclineRatioIndicator(u *Universe) Indicator {
+	return newBreadth(u, func(index int) decimal.Decimal {
+		c := u.counts(index)
+		return decimal.NewFromInt(int64(c.advances)).DivOrNaN(decimal.NewFromInt(int64(c.declines)))
+	})
+}
+
+// NewMcClellanOscillatorIndicator returns the McClellan oscillator: the 19 bar
+// EMA of net advances less their 39 bar EMA, the 10% and 5% trends of the
+// original. The last bar is recounted on each call while members may still be
+// arriving.
+func NewMcClellanOscillatorIndicator(u *Universe) Indicator {
+	net := NewAdvanceDeclineIndicator(u)
+	return NewDifferenceIndicator(newSettledEMA(net, 19, u.settled), newSettledEMA(net, 39, u.settled))
+}
+
+// NewMcClellanSummationIndexIndicator returns the McClellan summation index,
+// the running total of the McClellan oscillator from its lookback
+func NewMcClellanSummationIndexIndicator(u *Universe) Indicator {
+	return newRunningSum(NewMcClellanOscillatorIndicator(u), u.settled)
+}
+
+// NewTRINIndicator returns the Arms index: the advance/decline ratio divided
+// by the ratio of up volume to down volume. Below 1 volume favours the
+// advancing members, above 1 the declining ones. NaN when any of the four is
+// zero.
+func NewTRINIndicator(u *Universe) Indicator {
+	return newBreadth(u, func(index int) decimal.Decimal {
+		c := u.counts(index)
+		if c.advances == 0 || c.declines == 0 || c.upVolume.IsZero() || c.downVolume.IsZero() {
+			return decimal.NaN
+		}
+		breadth := decimal.NewFromInt(int64(c.advances)).Div(decimal.NewFromInt(int64(c.declines)))
+		return breadth.Div(c.upVolume.Div(c.downVolume))
+	})
+}
+
+var (
+	upDownVolumeOutputs = []string{"up", "down", "ratio"}
+	highsLowsOutputs    = []string{"net", "highs", "lows"}
+)
+
+// NewUpDownVolumeIndicator returns the volume of the members closing up and
+// down, as a MultiOutputIndicator with outputs up, down and ratio, up over
+// down and NaN without down volume. Calculate returns up.
+func NewUpDownVolumeIndicator(u *Universe) MultiOutputIndicator {
+	up := newBreadth(u, func(index int) decimal.Decimal { return u.counts(index).upVolume })
+	down := newBreadth(u, func(index int) decimal.Decimal { return u.counts(index).downVolume })
+	ratio := newBreadth(u, func(index int) decimal.Decimal {
+		c := u.counts(index)
+		return c.upVolume.DivOrNaN(c.downVolume)
+	})
+	return NewMultiOutputIndicator(upDownVolumeOutputs, up, down, ratio)
+}
+
+// NewHighsLowsIndicator returns the members making new highs and lows, as a
+// MultiOutputIndicator with outputs net, highs less lows, highs and lows. A
+// member makes a new high when the high of its candle is above the highs of
+// its previous window-1 candles, and a new low likewise; members with less
+// history are left out. Calculate returns net. Panics if window < 2.
+func NewHighsLowsIndicator(u *Universe, window int) MultiOutputIndicator {
+	if window < 2 {
+		panic("goflux: HighsLows window must be >= 2")
+	}
+	count := func(index int) (highs, lows int) {
+		for _, m := range u.members {
+			k := u.candleIndex(m, index)
+			if k < window-1 {
+				continue
+			}
+			candle := m.Candles[k]
+			high, low := true, true
+			for _, prev := range m.Candles[k-window+1 : k] {
+				high = high && candle.MaxPrice.GT(prev.MaxPrice)
+				low = low && candle.MinPrice.LT(prev.MinPrice)
+			}
+			if high {
+				highs++
+			}
+			if low {
+				lows++
+			}
+		}
+		return highs, lows
+	}
+	line := func(f func(highs, lows int) int) Indicator {
+		return newBreadth(u, func(index int) decimal.Decimal {
+			return decimal.NewFromInt(int64(f(count(index))))
+		})
+	}
+	return NewMultiOutputIndicator(highsLowsOutputs,
+		line(func(highs, lows int) int { return highs - lows }),
+		line(func(highs, _ int) int { return highs }),
+		line(func(_, lows int) int { return lows }))
+}
+
+// NewPercentAboveMovingAverageIndicator returns the percentage of the members
+// trading at each bar whose close is above its simple moving average over
+// window, from 0 to 100. Members with fewer than window candles are left out;
+// NaN when none are left. Panics if window < 1.
+func NewPercentAboveMovingAverageIndicator(u *Universe, window int) Indicator {
+	if window < 1 {
+		panic("goflux: PercentAboveMovingAverage window must be >= 1")
+	}
+	return newBreadth(u, func(index int) decimal.Decimal {
+		above, count := 0, 0
+		for _, m := range u.members {
+			k := u.candleIndex(m, index)
+			if k < window-1 {
+				continue
+			}
+			sum := 0.0
+			for _, c := range m.Candles[k-window+1 : k+1] {
+				sum += c.ClosePrice.Float()
+			}
+			count++
+			if m.Candles[k].ClosePrice.Float() > sum/float64(window) {
+				above++
+			}
+		}
+		if count == 0 {
+			return decimal.NaN
+		}
+		return decimal.New(100 * float64(above) / float64(count))
+	})
+}
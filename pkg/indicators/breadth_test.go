@@ -0,0 +1,144 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// withVolume sets the volume of every candle of s
+func withVolume(s *series.TimeSeries, volume float64) *series.TimeSeries {
+	for _, c := range s.Candles {
+		c.Volume = decimal.New(volume)
+	}
+	return s
+}
+
+// breadthUniverse returns a universe of three members over five days, the
+// third not trading on the last
+func breadthUniverse() (*indicators.Universe, *series.TimeSeries) {
+	a := withVolume(dailyCandles(10, 11, 12, 11, 11), 100)
+	b := withVolume(dailyCandles(20, 19, 18, 19, 19), 200)
+	c := withVolume(dailyCandles(30, 31, 32, 33), 300)
+	return indicators.NewUniverse(dailyCandles(1, 1, 1, 1, 1), a, b, c), c
+}
+
+func TestAdvanceDeclineIndicators(t *testing.T) {
+	u, _ := breadthUniverse()
+	assert.Equal(t, 3, u.Len())
+
+	net := indicators.NewAdvanceDeclineIndicator(u)
+	line := indicators.NewAdvanceDeclineLineIndicator(u)
+	for i, want := range []float64{0, 1, 1, 1, 0} {
+		assert.Equal(t, want, net.Calculate(i).Float(), "net at %d", i)
+	}
+	for i, want := range []float64{0, 1, 2, 3, 3} {
+		assert.Equal(t, want, line.Calculate(i).Float(), "line at %d", i)
+	}
+	assert.True(t, net.Calculate(5).IsNaN())
+
+	ratio := indicators.NewAdvanceDeclineRatioIndicator(u)
+	assert.Equal(t, 2.0, ratio.Calculate(1).Float())
+	assert.True(t, ratio.Calculate(4).IsNaN(), "no declines")
+
+	assert.Panics(t, func() { indicators.NewAdvanceDeclineIndicator(nil) })
+	assert.Panics(t, func() { indicators.NewUniverse(dailyCandles(1), nil) })
+}
+
+func TestAdvanceDeclineLineLateMember(t *testing.T) {
+	u, c := breadthUniverse()
+	line := indicators.NewAdvanceDeclineLineIndicator(u)
+	assert.Equal(t, 3.0, line.Calculate(4).Float())
+
+	// The last bar is recounted when a member's candle arrives late
+	late := series.NewCandle(series.NewTimePeriod(c.Candles[3].Period.Start.AddDate(0, 0, 1), 24*time.Hour))
+	late.OpenPrice, late.ClosePrice, late.MaxPrice, late.MinPrice = decimal.New(34), decimal.New(34), decimal.New(34), decimal.New(34)
+	require.True(t, c.AddCandle(late))
+	assert.Equal(t, 4.0, line.Calculate(4).Float())
+}
+
+func TestVolumeBreadthIndicators(t *testing.T) {
+	u, _ := breadthUniverse()
+
+	volume := indicators.NewUpDownVolumeIndicator(u)
+	require.Equal(t, []string{"up", "down", "ratio"}, volume.Outputs())
+	assert.Equal(t, 400.0, volume.Calculate(1).Float())
+	assert.Equal(t, 200.0, volume.Output("down").Calculate(1).Float())
+	assert.Equal(t, 2.0, volume.Output("ratio").Calculate(1).Float())
+
+	trin := indicators.NewTRINIndicator(u)
+	assert.InDelta(t, 1, trin.Calculate(1).Float(), 1e-9)
+	// Two up on 500 against one down on 100
+	assert.InDelta(t, 0.4, trin.Calculate(3).Float(), 1e-9)
+	assert.True(t, trin.Calculate(4).IsNaN())
+}
+
+func TestHighsLowsAndPercentAbove(t *testing.T) {
+	u, _ := breadthUniverse()
+
+	highsLows := indicators.NewHighsLowsIndicator(u, 3)
+	assert.Equal(t, 0.0, highsLows.Calculate(1).Float(), "not enough history")
+	assert.Equal(t, 2.0, highsLows.Output("highs").Calculate(2).Float())
+	assert.Equal(t, 1.0, highsLows.Output("lows").Calculate(2).Float())
+	assert.Equal(t, 1.0, highsLows.Calculate(2).Float())
+	assert.Panics(t, func() { indicators.NewHighsLowsIndicator(u, 1) })
+
+	above := indicators.NewPercentAboveMovingAverageIndicator(u, 3)
+	assert.True(t, above.Calculate(1).IsNaN(), "no member has the history")
+	assert.InDelta(t, 200.0/3, above.Calculate(2).Float(), 1e-9)
+	assert.InDelta(t, 50, above.Calculate(4).Float(), 1e-9)
+}
+
+func TestMcClellanIndicators(t *testing.T) {
+	n := 120
+	rising, falling := make([]float64, n), make([]float64, n)
+	for i := range rising {
+		rising[i] = 100 + float64(i)
+		falling[i] = 500 - float64(i)
+	}
+	u := indicators.NewUniverse(dailyCandles(rising...), dailyCandles(rising...), dailyCandles(rising...), dailyCandles(falling...))
+
+	oscillator := indicators.NewMcClellanOscillatorIndicator(u)
+	summation := indicators.NewMcClellanSummationIndexIndicator(u)
+	assert.Equal(t, 38, indicators.Lookback(oscillator))
+	assert.Equal(t, 38, indicators.Lookback(summation))
+	assert.True(t, summation.Calculate(37).IsZero())
+
+	// Steady breadth leaves both averages equal
+	assert.InDelta(t, 0, oscillator.Calculate(n-1).Float(), 1e-3)
+	for i := 39; i < n; i++ {
+		assert.InDelta(t, oscillator.Calculate(i).Float(), summation.Calculate(i).Sub(summation.Calculate(i-1)).Float(), 1e-9)
+	}
+}
+
+func TestMcClellanLateMember(t *testing.T) {
+	n := 60
+	rising, falling := make([]float64, n), make([]float64, n)
+	for i := range rising {
+		rising[i] = 100 + float64(i%7)
+		falling[i] = 500 - float64(i)
+	}
+	late := dailyCandles(falling[:n-1]...)
+	u := indicators.NewUniverse(dailyCandles(rising...), dailyCandles(rising...), dailyCandles(falling...), late)
+
+	oscillator := indicators.NewMcClellanOscillatorIndicator(u)
+	summation := indicators.NewMcClellanSummationIndexIndicator(u)
+	net := indicators.NewAdvanceDeclineIndicator(u)
+	want := indicators.NewDifferenceIndicator(indicators.NewEMAIndicator(net, 19), indicators.NewEMAIndicator(net, 39))
+	assertSameValue(t, "oscillator", n-1, want.Calculate(n-1), oscillator.Calculate(n-1))
+	before := summation.Calculate(n - 1)
+
+	// The last bar is recounted when a member's candle arrives late
+	require.True(t, late.AddCandle(dailyCandles(falling...).LastCandle()))
+	fresh := indicators.NewMcClellanOscillatorIndicator(u)
+	assertSameValue(t, "late oscillator", n-1, fresh.Calculate(n-1), oscillator.Calculate(n-1))
+	assert.False(t, fresh.Calculate(n-1).EQ(want.Calculate(n-1)), "the late member moves the oscillator")
+	assertSameValue(t, "late summation", n-1, indicators.NewMcClellanSummationIndexIndicator(u).Calculate(n-1), summation.Calculate(n-1))
+	assert.False(t, before.EQ(summation.Calculate(n-1)))
+}
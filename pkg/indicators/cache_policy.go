@@ -0,0 +1,241 @@
+package indicators
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+// ResultStore holds cached indicator results by index. Stores are only used
+// under their indicator's cache lock, so they need not be safe for concurrent
+// use.
+type ResultStore interface {
+	Get(index int) (decimal.Decimal, bool)
+	Set(index int, value decimal.Decimal)
+	Len() int
+	Clear()
+}
+
+// CachePolicy decides which results a recursive indicator such as EMA, MMA or
+// RMA keeps. Indicators take the default policy when they are constructed;
+// SetCachePolicy changes it for one indicator.
+type CachePolicy interface {
+	NewStore() ResultStore
+}
+
+// UnboundedCache keeps every result. Memory grows with the highest index
+// calculated.
+type UnboundedCache struct{}
+
+func (UnboundedCache) NewStore() ResultStore { return &unboundedStore{} }
+
+// SlidingWindowCache keeps the results for the Size highest indexes
+// calculated so far, which is all a recursion over the latest bars needs.
+// Results older than the window are recalculated on demand.
+type SlidingWindowCache struct {
+	Size int
+}
+
+func (p SlidingWindowCache) NewStore() ResultStore {
+	size := safeWindow(p.Size)
+	return &slidingWindowStore{values: make([]*decimal.Decimal, size), last: -1}
+}
+
+// LRUCache keeps the Size most recently used results, for access patterns that
+// revisit scattered indexes.
+type LRUCache struct {
+	Size int
+}
+
+func (p LRUCache) NewStore() ResultStore {
+	return &lruStore{size: safeWindow(p.Size), entries: make(map[int]*list.Element), order: list.New()}
+}
+
+var (
+	defaultCachePolicyMu sync.RWMutex
+	defaultCachePolicy   CachePolicy = SlidingWindowCache{Size: defaultMaxCacheSize}
+)
+
+// DefaultCachePolicy returns the policy given to newly constructed indicators,
+// initially a SlidingWindowCache of 10,000 results
+func DefaultCachePolicy() CachePolicy {
+	defaultCachePolicyMu.RLock()
+	defer defaultCachePolicyMu.RUnlock()
+	return defaultCachePolicy
+}
+
+// SetDefaultCachePolicy sets the policy given to indicators constructed from
+// now on. Existing indicators keep their caches. A nil policy restores the
+// initial default.
+func SetDefaultCachePolicy(policy CachePolicy) {
+	if policy == nil {
+		policy = SlidingWindowCache{Size: defaultMaxCacheSize}
+	}
+	defaultCachePolicyMu.Lock()
+	defer defaultCachePolicyMu.Unlock()
+	defaultCachePolicy = policy
+}
+
+// SetCachePolicy replaces the result cache of ind with an empty one following
+// policy. It returns false if ind does not cache its own results.
+func SetCachePolicy(ind Indicator, policy CachePolicy) bool {
+	cached, ok := ind.(cachedIndicator)
+	if !ok || policy == nil {
+		return false
+	}
+	cached.indicatorCache().reset(policy)
+	return true
+}
+
+// CacheStats are the result cache counters of an indicator. Lookups in the
+// warm-up, which never reach the cache, are not counted.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// HitRate returns the fraction of lookups served from the cache, or 0 before
+// the first lookup
+func (s CacheStats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// GetCacheStats returns the cache statistics of ind and whether it caches its
+// own results
+func GetCacheStats(ind Indicator) (CacheStats, bool) {
+	cached, ok := ind.(cachedIndicator)
+	if !ok {
+		return CacheStats{}, false
+	}
+	return cached.indicatorCache().stats(), true
+}
+
+type unboundedStore struct {
+	values []*decimal.Decimal
+	size   int
+}
+
+func (s *unboundedStore) Get(index int) (decimal.Decimal, bool) {
+	if index < 0 || index >= len(s.values) || s.values[index] == nil {
+		return decimal.Decimal{}, false
+	}
+	return *s.values[index], true
+}
+
+func (s *unboundedStore) Set(index int, value decimal.Decimal) {
+	if index < 0 {
+		return
+	}
+	if index >= len(s.values) {
+		s.values = append(s.values, make([]*decimal.Decimal, index+1-len(s.values))...)
+	}
+	if s.values[index] == nil {
+		s.size++
+	}
+	s.values[index] = &value
+}
+
+func (s *unboundedStore) Len() int { return s.size }
+
+func (s *unboundedStore) Clear() { s.values, s.size = nil, 0 }
+
+// slidingWindowStore is a ring buffer over the indexes (last-len(values), last]
+type slidingWindowStore struct {
+	values []*decimal.Decimal
+	last   int
+	size   int
+}
+
+func (s *slidingWindowStore) inWindow(index int) bool {
+	return index >= 0 && index <= s.last && index > s.last-len(s.values)
+}
+
+func (s *slidingWindowStore) Get(index int) (decimal.Decimal, bool) {
+	if !s.inWindow(index) {
+		return decimal.Decimal{}, false
+	}
+	if v := s.values[index%len(s.values)]; v != nil {
+		return *v, true
+	}
+	return decimal.Decimal{}, false
+}
+
+func (s *slidingWindowStore) Set(index int, value decimal.Decimal) {
+	if index < 0 {
+		return
+	}
+	if index > s.last {
+		// Slots between the old and new end belong to indexes that slide out
+		for i := max(s.last+1, index-len(s.values)+1); i <= index; i++ {
+			s.drop(i % len(s.values))
+		}
+		s.last = index
+	} else if !s.inWindow(index) {
+		return
+	}
+	slot := index % len(s.values)
+	if s.values[slot] == nil {
+		s.size++
+	}
+	s.values[slot] = &value
+}
+
+func (s *slidingWindowStore) drop(slot int) {
+	if s.values[slot] != nil {
+		s.values[slot] = nil
+		s.size--
+	}
+}
+
+func (s *slidingWindowStore) Len() int { return s.size }
+
+func (s *slidingWindowStore) Clear() {
+	clear(s.values)
+	s.last, s.size = -1, 0
+}
+
+type lruEntry struct {
+	index int
+	value decimal.Decimal
+}
+
+type lruStore struct {
+	size    int
+	entries map[int]*list.Element
+	order   *list.List
+}
+
+func (s *lruStore) Get(index int) (decimal.Decimal, bool) {
+	e, ok := s.entries[index]
+	if !ok {
+		return decimal.Decimal{}, false
+	}
+	s.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+func (s *lruStore) Set(index int, value decimal.Decimal) {
+	if e, ok := s.entries[index]; ok {
+		e.Value.(*lruEntry).value = value
+		s.order.MoveToFront(e)
+		return
+	}
+	s.entries[index] = s.order.PushFront(&lruEntry{index, value})
+	if s.order.Len() > s.size {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*lruEntry).index)
+	}
+}
+
+func (s *lruStore) Len() int { return s.order.Len() }
+
+func (s *lruStore) Clear() {
+	s.entries = make(map[int]*list.Element)
+	s.order.Init()
+}
@@ -0,0 +1,107 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachePolicies(t *testing.T) {
+	t.Run("unbounded keeps everything", func(t *testing.T) {
+		store := indicators.UnboundedCache{}.NewStore()
+		for i := 0; i < 100; i++ {
+			store.Set(i, decimal.New(float64(i)))
+		}
+		assert.Equal(t, 100, store.Len())
+		v, ok := store.Get(0)
+		assert.True(t, ok)
+		assert.Equal(t, "0", v.String())
+	})
+
+	t.Run("sliding window keeps the highest indexes", func(t *testing.T) {
+		store := indicators.SlidingWindowCache{Size: 3}.NewStore()
+		for i := 0; i < 5; i++ {
+			store.Set(i, decimal.New(float64(i)))
+		}
+		assert.Equal(t, 3, store.Len())
+		_, ok := store.Get(1)
+		assert.False(t, ok)
+		v, ok := store.Get(2)
+		assert.True(t, ok)
+		assert.Equal(t, "2", v.String())
+
+		store.Set(0, decimal.ONE)
+		_, ok = store.Get(0)
+		assert.False(t, ok, "indexes behind the window are not stored")
+
+		store.Set(10, decimal.ONE)
+		assert.Equal(t, 1, store.Len(), "a jump past the window drops it all")
+	})
+
+	t.Run("LRU evicts the least recently used", func(t *testing.T) {
+		store := indicators.LRUCache{Size: 2}.NewStore()
+		store.Set(1, decimal.ONE)
+		store.Set(2, decimal.ONE)
+		store.Get(1)
+		store.Set(3, decimal.ONE)
+		_, ok := store.Get(2)
+		assert.False(t, ok)
+		_, ok = store.Get(1)
+		assert.True(t, ok)
+		assert.Equal(t, 2, store.Len())
+
+		store.Clear()
+		assert.Equal(t, 0, store.Len())
+	})
+}
+
+func TestCachePolicy_LongSeries(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(testutils.RandomTimeSeries(12000))
+	reference := indicators.ComputeAll(indicators.NewEMAIndicator(closePrice, 10), 12000)
+
+	for name, policy := range map[string]indicators.CachePolicy{
+		"sliding": indicators.SlidingWindowCache{Size: 500},
+		"lru":     indicators.LRUCache{Size: 500},
+		"all":     indicators.UnboundedCache{},
+	} {
+		ema := indicators.NewEMAIndicator(closePrice, 10)
+		assert.True(t, indicators.SetCachePolicy(ema, policy), name)
+		for i := 0; i < 12000; i++ {
+			assertSameValue(t, name, i, reference[i], ema.Calculate(i))
+		}
+
+		stats, ok := indicators.GetCacheStats(ema)
+		assert.True(t, ok)
+		assert.Positive(t, stats.Hits, name)
+		assert.Greater(t, stats.HitRate(), 0.4, name)
+		if name == "all" {
+			assert.Equal(t, 12000-9, stats.Size)
+		} else {
+			assert.Equal(t, 500, stats.Size, name)
+		}
+	}
+}
+
+func TestSetDefaultCachePolicy(t *testing.T) {
+	defer indicators.SetDefaultCachePolicy(nil)
+
+	indicators.SetDefaultCachePolicy(indicators.LRUCache{Size: 16})
+	assert.Equal(t, indicators.LRUCache{Size: 16}, indicators.DefaultCachePolicy())
+
+	closePrice := indicators.NewClosePriceIndicator(testutils.RandomTimeSeries(100))
+	mma := indicators.NewMMAIndicator(closePrice, 5)
+	mma.Calculate(99)
+	stats, ok := indicators.GetCacheStats(mma)
+	assert.True(t, ok)
+	assert.Equal(t, 16, stats.Size)
+
+	indicators.SetDefaultCachePolicy(nil)
+	assert.Equal(t, indicators.SlidingWindowCache{Size: 10000}, indicators.DefaultCachePolicy())
+
+	_, ok = indicators.GetCacheStats(closePrice)
+	assert.False(t, ok)
+	assert.False(t, indicators.SetCachePolicy(closePrice, indicators.UnboundedCache{}))
+}
@@ -13,72 +13,74 @@ const (
 
 type resultCache []*decimal.Decimal
 
+// cachedIndicator is a recursive indicator that caches its own results
 type cachedIndicator interface {
 	Indicator
-	cache() resultCache
-	setCache(cache resultCache)
+	indicatorCache() *indicatorCache
 	windowSize() int
-	cacheMutex() *sync.RWMutex
-	maxCacheSize() int
 }
 
-func cacheResult(indicator cachedIndicator, index int, val decimal.Decimal) {
-	cacheMutex := indicator.cacheMutex()
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
-
-	c := indicator.cache()
-	if index < len(c) {
-		c[index] = &val
-	} else if index == len(c) {
-		if len(c) >= indicator.maxCacheSize() {
-			return
-		}
-		indicator.setCache(append(c, &val))
+// indicatorCache is the result cache of a cachedIndicator: a ResultStore made
+// by its CachePolicy, with hit and miss counters
+type indicatorCache struct {
+	mu     sync.Mutex
+	policy CachePolicy
+	store  ResultStore
+	hits   uint64
+	misses uint64
+}
+
+// newIndicatorCache returns an empty cache following DefaultCachePolicy
+func newIndicatorCache() *indicatorCache {
+	policy := DefaultCachePolicy()
+	return &indicatorCache{policy: policy, store: policy.NewStore()}
+}
+
+func (c *indicatorCache) get(index int) (decimal.Decimal, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.store.Get(index)
+	if ok {
+		c.hits++
 	} else {
-		if index >= indicator.maxCacheSize() {
-			return
-		}
-		expandResultCache(indicator, index+1)
-		indicator.cache()[index] = &val
+		c.misses++
 	}
+	return val, ok
 }
 
-func expandResultCache(indicator cachedIndicator, newSize int) {
-	c := indicator.cache()
-	sizeDiff := newSize - len(c)
-	if sizeDiff <= 0 {
-		return
-	}
+func (c *indicatorCache) set(index int, val decimal.Decimal) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store.Set(index, val)
+}
 
-	if newSize > indicator.maxCacheSize() {
-		newSize = indicator.maxCacheSize()
-		sizeDiff = newSize - len(c)
-		if sizeDiff <= 0 {
-			return
-		}
-	}
+func (c *indicatorCache) reset(policy CachePolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.policy, c.store = policy, policy.NewStore()
+	c.hits, c.misses = 0, 0
+}
 
-	expansion := make([]*decimal.Decimal, sizeDiff)
-	indicator.setCache(append(c, expansion...))
+func (c *indicatorCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Size: c.store.Len()}
 }
 
-func returnIfCached(indicator cachedIndicator, index int, firstValueFallback func(int) decimal.Decimal) *decimal.Decimal {
-	cacheMutex := indicator.cacheMutex()
-	cacheMutex.RLock()
-	c := indicator.cache()
-	if index < len(c) && index >= indicator.windowSize()-1 {
-		if val := c[index]; val != nil {
-			cacheMutex.RUnlock()
-			return val
-		}
-	}
-	cacheMutex.RUnlock()
+func cacheResult(indicator cachedIndicator, index int, val decimal.Decimal) {
+	indicator.indicatorCache().set(index, val)
+}
 
+func returnIfCached(indicator cachedIndicator, index int, firstValueFallback func(int) decimal.Decimal) *decimal.Decimal {
 	if index < indicator.windowSize()-1 {
 		return &decimal.ZERO
 	}
 
+	if val, ok := indicator.indicatorCache().get(index); ok {
+		return &val
+	}
+
 	if index == indicator.windowSize()-1 {
 		value := firstValueFallback(index)
 		cacheResult(indicator, index, value)
@@ -152,32 +154,39 @@ func (c *cache) Clear() {
 	c.items = make(resultCache, defaultCacheSize)
 }
 
+// ClearCache empties the result cache of indicator, keeping its policy
 func ClearCache(indicator cachedIndicator) {
 	if indicator == nil {
 		return
 	}
-	mutex := indicator.cacheMutex()
-	mutex.Lock()
-	defer mutex.Unlock()
-	indicator.setCache(make([]*decimal.Decimal, 0, indicator.windowSize()))
+	c := indicator.indicatorCache()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store.Clear()
 }
 
+// GetCacheSize returns the number of results cached by indicator
 func GetCacheSize(indicator cachedIndicator) int {
 	if indicator == nil {
 		return 0
 	}
-	mutex := indicator.cacheMutex()
-	mutex.RLock()
-	defer mutex.RUnlock()
-	return len(indicator.cache())
+	return indicator.indicatorCache().stats().Size
 }
 
+// GetCacheCapacity returns the most results indicator will cache, or -1 if its
+// policy is unbounded or not one of the built-in policies
 func GetCacheCapacity(indicator cachedIndicator) int {
 	if indicator == nil {
 		return 0
 	}
-	mutex := indicator.cacheMutex()
-	mutex.RLock()
-	defer mutex.RUnlock()
-	return cap(indicator.cache())
+	c := indicator.indicatorCache()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch p := c.policy.(type) {
+	case SlidingWindowCache:
+		return safeWindow(p.Size)
+	case LRUCache:
+		return safeWindow(p.Size)
+	}
+	return -1
 }
@@ -8,35 +8,27 @@ import (
 )
 
 type mockCachedIndicator struct {
-	store         *cache
+	results       *indicatorCache
 	calculateFunc func(int) decimal.Decimal
 	window        int
 }
 
-func (m *mockCachedIndicator) Calculate(index int) decimal.Decimal {
-	return m.calculateFunc(index)
+func newMockCachedIndicator(window int, calculate func(int) decimal.Decimal) *mockCachedIndicator {
+	return &mockCachedIndicator{results: newIndicatorCache(), calculateFunc: calculate, window: window}
 }
 
-func (m *mockCachedIndicator) cache() resultCache {
-	return m.store.items
+func (m *mockCachedIndicator) Calculate(index int) decimal.Decimal {
+	return m.calculateFunc(index)
 }
 
-func (m *mockCachedIndicator) setCache(c resultCache) {
-	m.store.items = c
+func (m *mockCachedIndicator) indicatorCache() *indicatorCache {
+	return m.results
 }
 
 func (m *mockCachedIndicator) windowSize() int {
 	return m.window
 }
 
-func (m *mockCachedIndicator) cacheMutex() *sync.RWMutex {
-	return &m.store.mu
-}
-
-func (m *mockCachedIndicator) maxCacheSize() int {
-	return m.store.maxSize
-}
-
 func TestNewCache(t *testing.T) {
 	c := NewCache(5)
 	if len(c.items) != 5 {
@@ -156,49 +148,42 @@ func TestCacheThreadSafety(t *testing.T) {
 }
 
 func TestCacheResult(t *testing.T) {
-	store := NewCache(5)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(idx int) decimal.Decimal {
-			return decimal.New(float64(idx))
-		},
-		window: 5,
-	}
+	ind := newMockCachedIndicator(5, func(idx int) decimal.Decimal {
+		return decimal.New(float64(idx))
+	})
 
 	cacheResult(ind, 0, decimal.New(10))
 	cacheResult(ind, 1, decimal.New(20))
 	cacheResult(ind, 2, decimal.New(30))
 
-	if result := store.Get(1); result == nil || result.String() != "20" {
+	if result, ok := ind.results.get(1); !ok || result.String() != "20" {
 		t.Errorf("Expected 20, got %v", result)
 	}
 }
 
-func TestExpandResultCache(t *testing.T) {
-	store := NewCache(5)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(int) decimal.Decimal {
-			return decimal.ZERO
-		},
-		window: 5,
-	}
+func TestCacheResultPastDefaultMaxSize(t *testing.T) {
+	ind := newMockCachedIndicator(5, func(int) decimal.Decimal {
+		return decimal.ZERO
+	})
 
-	expandResultCache(ind, 10)
-	if len(ind.cache()) != 10 {
-		t.Errorf("Expected size 10, got %d", len(ind.cache()))
+	for i := 0; i < defaultMaxCacheSize+500; i++ {
+		cacheResult(ind, i, decimal.New(float64(i)))
+	}
+	if result, ok := ind.results.get(defaultMaxCacheSize + 499); !ok || result.String() != "10499" {
+		t.Errorf("Expected the latest result to be cached, got %v", result)
+	}
+	if _, ok := ind.results.get(0); ok {
+		t.Error("Expected the oldest result to have slid out of the window")
+	}
+	if size := GetCacheSize(ind); size != defaultMaxCacheSize {
+		t.Errorf("Expected size %d, got %d", defaultMaxCacheSize, size)
 	}
 }
 
 func TestReturnIfCached(t *testing.T) {
-	store := NewCache(5)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(idx int) decimal.Decimal {
-			return decimal.New(float64(idx * 10))
-		},
-		window: 5,
-	}
+	ind := newMockCachedIndicator(5, func(idx int) decimal.Decimal {
+		return decimal.New(float64(idx * 10))
+	})
 
 	fallbackCalled := 0
 	fallback := func(idx int) decimal.Decimal {
@@ -214,11 +199,9 @@ func TestReturnIfCached(t *testing.T) {
 		t.Error("Fallback should not be called for index below window size")
 	}
 
-	store.Set(0, decimal.New(1))
-	store.Set(1, decimal.New(2))
-	store.Set(2, decimal.New(3))
-	store.Set(3, decimal.New(4))
-	store.Set(4, decimal.New(5))
+	for i := 0; i < 5; i++ {
+		cacheResult(ind, i, decimal.New(float64(i+1)))
+	}
 
 	result = returnIfCached(ind, 4, fallback)
 	if result == nil || result.String() != "5" {
@@ -235,25 +218,24 @@ func TestReturnIfCached(t *testing.T) {
 	if fallbackCalled != 0 {
 		t.Errorf("Expected fallback not to be called, called %d times", fallbackCalled)
 	}
+
+	if stats := ind.results.stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
 }
 
 func TestClearCache(t *testing.T) {
-	store := NewCache(5)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(int) decimal.Decimal {
-			return decimal.ZERO
-		},
-		window: 5,
-	}
+	ind := newMockCachedIndicator(5, func(int) decimal.Decimal {
+		return decimal.ZERO
+	})
 
 	cacheResult(ind, 10, decimal.New(1))
 	ClearCache(ind)
 	if GetCacheSize(ind) != 0 {
 		t.Errorf("Expected size 0 after ClearCache, got %d", GetCacheSize(ind))
 	}
-	if GetCacheCapacity(ind) != 5 {
-		t.Errorf("Expected capacity 5 after ClearCache, got %d", GetCacheCapacity(ind))
+	if GetCacheCapacity(ind) != defaultMaxCacheSize {
+		t.Errorf("Expected capacity %d after ClearCache, got %d", defaultMaxCacheSize, GetCacheCapacity(ind))
 	}
 }
 
@@ -277,14 +259,9 @@ func BenchmarkCacheGet(b *testing.B) {
 }
 
 func BenchmarkCacheResult(b *testing.B) {
-	store := NewCache(1000)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(idx int) decimal.Decimal {
-			return decimal.New(float64(idx))
-		},
-		window: 5,
-	}
+	ind := newMockCachedIndicator(5, func(idx int) decimal.Decimal {
+		return decimal.New(float64(idx))
+	})
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -293,16 +270,11 @@ func BenchmarkCacheResult(b *testing.B) {
 }
 
 func BenchmarkReturnIfCached(b *testing.B) {
-	store := NewCache(1000)
-	ind := &mockCachedIndicator{
-		store: store,
-		calculateFunc: func(idx int) decimal.Decimal {
-			return decimal.New(float64(idx))
-		},
-		window: 5,
-	}
+	ind := newMockCachedIndicator(5, func(idx int) decimal.Decimal {
+		return decimal.New(float64(idx))
+	})
 	for i := 0; i < 1000; i++ {
-		store.Set(i, decimal.New(float64(i)))
+		cacheResult(ind, i, decimal.New(float64(i)))
 	}
 
 	b.ResetTimer()
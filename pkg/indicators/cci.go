@@ -28,5 +28,7 @@ func (ccii commidityChannelIndexIndicator) Calculate(index int) decimal.Decimal
 	typicalPriceSma := NewSimpleMovingAverage(typicalPrice, ccii.window)
 	meanDeviation := NewMeanDeviationIndicator(NewClosePriceIndicator(ccii.series), ccii.window)
 
-	return typicalPrice.Calculate(index).Sub(typicalPriceSma.Calculate(index)).Div(meanDeviation.Calculate(index).Mul(decimal.NewFromString("0.015")))
+	return typicalPrice.Calculate(index).Sub(typicalPriceSma.Calculate(index)).DivOrNaN(meanDeviation.Calculate(index).Mul(decimal.NewFromString("0.015")))
 }
+
+func (ccii commidityChannelIndexIndicator) Lookback() int { return safeWindow(ccii.window) - 1 }
@@ -71,8 +71,10 @@ func (cmf chaikinMoneyFlow) Calculate(index int) decimal.Decimal {
 	}
 
 	if sumVolume.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return sumMFV.Div(sumVolume)
 }
+
+func (cmf chaikinMoneyFlow) Lookback() int { return cmf.window - 1 }
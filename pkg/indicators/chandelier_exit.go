@@ -133,3 +133,7 @@ func lowestLow(s *series.TimeSeries, index int, period int) decimal.Decimal {
 	}
 	return minPrice
 }
+
+func (ce chandelierExitLongIndicator) Lookback() int { return max(ce.period, ce.atrWindow) - 1 }
+
+func (ce chandelierExitShortIndicator) Lookback() int { return max(ce.period, ce.atrWindow) - 1 }
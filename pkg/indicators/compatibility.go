@@ -0,0 +1,561 @@
+package indicators
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// Compatibility selects the seeding and warm-up conventions an indicator
+// follows, so its values can be matched against another charting package.
+// Under CompatTALib and CompatTradingView an indicator is decimal.NaN wherever
+// that package produces no value, instead of the zeros and partial-window
+// values the goflux constructors return during warm-up. They are also NaN past
+// the end of the series their source is read from; see
+// NewSeriesBoundedIndicator for sources other than price indicators.
+type Compatibility int
+
+// Compatibility profiles
+const (
+	// CompatGoFlux is the behaviour of the plain goflux constructors
+	CompatGoFlux Compatibility = iota
+	// CompatTALib follows the TA-Lib C functions with their default settings
+	// (no unstable period, SMA-seeded EMAs, Wilder smoothing seeded from the
+	// first price change).
+	CompatTALib
+	// CompatTradingView follows the Pine Script ta.* built-ins
+	CompatTradingView
+)
+
+var compatibilityNames = []string{"goflux", "talib", "tradingview"}
+
+func (c Compatibility) String() string {
+	if c < 0 || int(c) >= len(compatibilityNames) {
+		return fmt.Sprintf("Compatibility(%d)", int(c))
+	}
+	return compatibilityNames[c]
+}
+
+// ParseCompatibility returns the profile named name. Names are
+// case-insensitive and ignore dashes, so "TA-Lib" is CompatTALib.
+func ParseCompatibility(name string) (Compatibility, error) {
+	key := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "")
+	for i, n := range compatibilityNames {
+		if n == key {
+			return Compatibility(i), nil
+		}
+	}
+	return CompatGoFlux, fmt.Errorf("unknown compatibility profile %q (profiles: %s)", name, strings.Join(compatibilityNames, ", "))
+}
+
+func (c Compatibility) check() {
+	if c < CompatGoFlux || c > CompatTradingView {
+		panic(fmt.Sprintf("goflux: unknown compatibility profile %d", int(c)))
+	}
+}
+
+// NewEMAIndicatorCompat returns the EMA of indicator under the given profile.
+// TA-Lib and TradingView both seed the EMA with the SMA of the first window
+// values and have no value before it.
+func NewEMAIndicatorCompat(indicator Indicator, window int, c Compatibility) Indicator {
+	c.check()
+	if c == CompatGoFlux {
+		return NewEMAIndicator(indicator, window)
+	}
+	window = safeWindow(window)
+	return newSeededAverage(indicator, seriesOf(indicator), window, 0, emaAlpha(window))
+}
+
+// NewRelativeStrengthIndexIndicatorCompat returns the RSI of indicator under
+// the given profile. TA-Lib and TradingView seed Wilder's average gain and
+// loss with the mean of the first window price changes, so the first RSI is
+// at index window. A window without any change is 0 under TA-Lib and 100
+// under TradingView.
+func NewRelativeStrengthIndexIndicatorCompat(indicator Indicator, window int, c Compatibility) Indicator {
+	c.check()
+	if c == CompatGoFlux {
+		return NewRelativeStrengthIndexIndicator(indicator, window)
+	}
+	window = safeWindow(window)
+	s := seriesOf(indicator)
+	gain := newSeededAverage(NewGainIndicator(indicator), s, window, 1, wilderAlpha(window))
+	loss := newSeededAverage(NewLossIndicator(indicator), s, window, 1, wilderAlpha(window))
+	hundred := decimal.New(100)
+	return indicatorFunc(func(index int) decimal.Decimal {
+		g, l := gain.Calculate(index), loss.Calculate(index)
+		switch {
+		case g.IsNaN() || l.IsNaN():
+			return decimal.NaN
+		case c == CompatTALib && g.Add(l).Zero():
+			return decimal.ZERO
+		case c == CompatTradingView && l.Zero():
+			return hundred
+		}
+		return hundred.Mul(g).Div(g.Add(l))
+	})
+}
+
+// NewMACDLinesIndicatorCompat returns the MACD, signal and histogram lines
+// under the given profile. The signal line is seeded with the SMA of the
+// first signalWindow MACD values. TA-Lib also seeds the short EMA at the
+// first long EMA value and reports all three lines from the first signal
+// value on; TradingView reports the MACD line from the first long EMA value.
+func NewMACDLinesIndicatorCompat(baseIndicator Indicator, shortwindow, longwindow, signalWindow int, c Compatibility) MultiOutputIndicator {
+	c.check()
+	if c == CompatGoFlux {
+		return NewMACDLinesIndicator(baseIndicator, shortwindow, longwindow, signalWindow)
+	}
+	shortwindow, longwindow, signalWindow = safeWindow(shortwindow), safeWindow(longwindow), safeWindow(signalWindow)
+
+	shortStart := 0
+	if c == CompatTALib && longwindow > shortwindow {
+		shortStart = longwindow - shortwindow
+	}
+	s := seriesOf(baseIndicator)
+	long := newSeededAverage(baseIndicator, s, longwindow, 0, emaAlpha(longwindow))
+	var macd Indicator = NewDifferenceIndicator(newSeededAverage(baseIndicator, s, shortwindow, shortStart, emaAlpha(shortwindow)), long)
+	signal := newSeededAverage(macd, s, signalWindow, longwindow-1, emaAlpha(signalWindow))
+	if c == CompatTALib {
+		macd = warmUpIndicator{macd, longwindow + signalWindow - 2}
+	}
+	return NewMultiOutputIndicator(macdOutputs, macd, signal, NewDifferenceIndicator(macd, signal))
+}
+
+// NewBollingerBandsIndicatorCompat returns the middle, upper and lower
+// Bollinger bands under the given profile. TA-Lib and TradingView use the
+// population standard deviation, as goflux does, and have no value before
+// the first full window.
+func NewBollingerBandsIndicatorCompat(indicator Indicator, window int, sigma float64, c Compatibility) MultiOutputIndicator {
+	c.check()
+	bands := NewBollingerBandsIndicator(indicator, window, sigma)
+	if c == CompatGoFlux {
+		return bands
+	}
+	return warmUpLines(bands, safeWindow(window)-1)
+}
+
+// NewStochasticIndicatorCompat returns the full stochastic oscillator under
+// the given profile: k is the kSmooth SMA of the raw %K over kWindow and d is
+// the dWindow SMA of k. A flat high-low range gives a raw %K of 50 under
+// goflux, 0 under TA-Lib and no value under TradingView. TA-Lib reports k
+// from the first d value on.
+func NewStochasticIndicatorCompat(s *series.TimeSeries, kWindow, kSmooth, dWindow int, c Compatibility) MultiOutputIndicator {
+	c.check()
+	kWindow, kSmooth, dWindow = safeWindow(kWindow), safeWindow(kSmooth), safeWindow(dWindow)
+	var raw Indicator = NewFastStochasticIndicator(s, kWindow)
+	if c != CompatGoFlux {
+		raw = warmUpIndicator{newRawStochastic(s, kWindow, c), kWindow - 1}
+	}
+
+	k := NewSimpleMovingAverage(raw, kSmooth)
+	d := NewSimpleMovingAverage(k, dWindow)
+	if c == CompatGoFlux {
+		return NewMultiOutputIndicator(stochasticOutputs, k, d)
+	}
+
+	kFirst, dFirst := kWindow+kSmooth-2, kWindow+kSmooth+dWindow-3
+	if c == CompatTALib {
+		kFirst = dFirst
+	}
+	return NewMultiOutputIndicator(stochasticOutputs, warmUpIndicator{k, kFirst}, warmUpIndicator{d, dFirst})
+}
+
+func newRawStochastic(s *series.TimeSeries, window int, c Compatibility) Indicator {
+	hundred := decimal.New(100)
+	return indicatorFunc(func(index int) decimal.Decimal {
+		if s == nil || index < 0 || index >= s.Length() {
+			return decimal.NaN
+		}
+		highest, lowest, closePrice, ok := s.HighLowClose(max(index-window+1, 0), index+1)
+		if !ok {
+			return decimal.NaN
+		}
+		if highest.EQ(lowest) {
+			if c == CompatTALib {
+				return decimal.ZERO
+			}
+			return decimal.NaN
+		}
+		return closePrice.Sub(lowest).Div(highest.Sub(lowest)).Mul(hundred)
+	})
+}
+
+// NewAverageTrueRangeIndicatorCompat returns the ATR under the given profile.
+// TA-Lib and TradingView use Wilder smoothing instead of the simple average
+// of goflux. TA-Lib ignores the first candle, which has no previous close, so
+// its first ATR is at index window; TradingView counts the high-low range of
+// the first candle and starts at index window-1.
+func NewAverageTrueRangeIndicatorCompat(s *series.TimeSeries, window int, c Compatibility) Indicator {
+	c.check()
+	if c == CompatGoFlux {
+		return NewAverageTrueRangeIndicator(s, window)
+	}
+	if window < 1 {
+		panic("goflux: ATR window must be >= 1")
+	}
+	start := 0
+	if c == CompatTALib {
+		start = 1
+	}
+	return newSeededAverage(NewTrueRangeIndicator(s), s, window, start, wilderAlpha(window))
+}
+
+// NewADXIndicatorCompat returns the ADX under the given profile. TradingView
+// matches goflux apart from having no value before index 2*window-1. TA-Lib
+// seeds its Wilder sums with window-1 values before the first smoothing step,
+// which changes every value.
+func NewADXIndicatorCompat(s *series.TimeSeries, window int, c Compatibility) Indicator {
+	c.check()
+	switch c {
+	case CompatTALib:
+		return newTALibADX(s, safeWindow(window))
+	case CompatTradingView:
+		return warmUpIndicator{NewADXIndicator(s, window), 2*window - 1}
+	}
+	return NewADXIndicator(s, window)
+}
+
+func newTALibADX(s *series.TimeSeries, window int) Indicator {
+	period := decimal.NewFromInt(int64(window))
+	periodLessOne := decimal.NewFromInt(int64(window - 1))
+	hundred := decimal.New(100)
+	var smTR, smPlus, smMinus, sumDX decimal.Decimal
+
+	return newSequenceIndicator(s, func(index int, values []decimal.Decimal) decimal.Decimal {
+		if index == 0 {
+			smTR, smPlus, smMinus, sumDX = decimal.ZERO, decimal.ZERO, decimal.ZERO, decimal.ZERO
+			return decimal.NaN
+		}
+		candle, previous := s.GetCandlePair(index)
+		plusDM, minusDM := directionalMovement(candle, previous)
+		tr := candle.MaxPrice.Max(previous.ClosePrice).Sub(candle.MinPrice.Min(previous.ClosePrice))
+
+		if index < window {
+			smTR, smPlus, smMinus = smTR.Add(tr), smPlus.Add(plusDM), smMinus.Add(minusDM)
+			return decimal.NaN
+		}
+		smTR = smTR.Sub(smTR.Div(period)).Add(tr)
+		smPlus = smPlus.Sub(smPlus.Div(period)).Add(plusDM)
+		smMinus = smMinus.Sub(smMinus.Div(period)).Add(minusDM)
+
+		dx := decimal.NaN
+		if !smTR.Zero() {
+			plusDI, minusDI := hundred.Mul(smPlus).Div(smTR), hundred.Mul(smMinus).Div(smTR)
+			if sum := plusDI.Add(minusDI); !sum.Zero() {
+				dx = hundred.Mul(plusDI.Sub(minusDI).Abs()).Div(sum)
+			}
+		}
+
+		first := 2*window - 1
+		switch {
+		case index < first:
+			if dx.IsValid() {
+				sumDX = sumDX.Add(dx)
+			}
+			return decimal.NaN
+		case index == first:
+			if dx.IsValid() {
+				sumDX = sumDX.Add(dx)
+			}
+			return sumDX.Div(period)
+		case dx.IsNaN():
+			return values[index-1]
+		}
+		return values[index-1].Mul(periodLessOne).Add(dx).Div(period)
+	})
+}
+
+// directionalMovement returns the +DM and -DM of candle over previous
+func directionalMovement(candle, previous *series.Candle) (plusDM, minusDM decimal.Decimal) {
+	up := candle.MaxPrice.Sub(previous.MaxPrice)
+	down := previous.MinPrice.Sub(candle.MinPrice)
+	switch {
+	case up.GT(down) && up.IsPositive():
+		return up, decimal.ZERO
+	case down.GT(up) && down.IsPositive():
+		return decimal.ZERO, down
+	}
+	return decimal.ZERO, decimal.ZERO
+}
+
+// NewCCIIndicatorCompat returns the CCI under the given profile. TA-Lib and
+// TradingView take the mean deviation of the typical price, where goflux
+// takes it of the close price. A zero mean deviation gives 0 under TA-Lib and
+// no value under TradingView.
+func NewCCIIndicatorCompat(s *series.TimeSeries, window int, c Compatibility) Indicator {
+	c.check()
+	if c == CompatGoFlux {
+		return NewCCIIndicator(s, window)
+	}
+	window = safeWindow(window)
+	typicalPrice := NewTypicalPriceIndicator(s)
+	sma := NewSimpleMovingAverage(typicalPrice, window)
+	meanDeviation := NewMeanDeviationIndicator(typicalPrice, window)
+	factor := decimal.NewFromString("0.015")
+	return warmUpIndicator{seriesBounded{indicatorFunc(func(index int) decimal.Decimal {
+		divisor := meanDeviation.Calculate(index).Mul(factor)
+		if c == CompatTALib && divisor.Zero() {
+			return decimal.ZERO
+		}
+		return typicalPrice.Calculate(index).Sub(sma.Calculate(index)).DivOrNaN(divisor)
+	}), s}, window - 1}
+}
+
+// NewOBVIndicatorCompat returns the on-balance volume under the given
+// profile. goflux and TA-Lib start from the volume of the first candle;
+// TradingView starts from zero.
+func NewOBVIndicatorCompat(s *series.TimeSeries, c Compatibility) Indicator {
+	c.check()
+	if c != CompatTradingView {
+		return NewOBVIndicator(s)
+	}
+	return newSequenceIndicator(s, func(index int, values []decimal.Decimal) decimal.Decimal {
+		if index == 0 {
+			return decimal.ZERO
+		}
+		candle, previous := s.GetCandlePair(index)
+		switch {
+		case candle.ClosePrice.GT(previous.ClosePrice):
+			return values[index-1].Add(candle.Volume)
+		case candle.ClosePrice.LT(previous.ClosePrice):
+			return values[index-1].Sub(candle.Volume)
+		}
+		return values[index-1]
+	})
+}
+
+// NewParabolicSARIndicatorCompat returns the parabolic SAR with acceleration
+// factor start, raised by increment on every new extreme up to maximum, under
+// the given profile. The goflux profile always uses 0.02, 0.02 and 0.2. TA-Lib
+// starts short when the second candle has a -DM and long otherwise;
+// TradingView starts long when the second close is above the first. Neither
+// has a value at index 0.
+func NewParabolicSARIndicatorCompat(s *series.TimeSeries, start, increment, maximum float64, c Compatibility) Indicator {
+	c.check()
+	switch c {
+	case CompatTALib:
+		return newTALibSAR(s, decimal.New(start), decimal.New(increment), decimal.New(maximum))
+	case CompatTradingView:
+		return newTradingViewSAR(s, decimal.New(start), decimal.New(increment), decimal.New(maximum))
+	}
+	return NewParabolicSARIndicator(s)
+}
+
+func newTALibSAR(s *series.TimeSeries, start, increment, maximum decimal.Decimal) Indicator {
+	var isLong bool
+	var af, ep, sar decimal.Decimal
+
+	return newSequenceIndicator(s, func(index int, _ []decimal.Decimal) decimal.Decimal {
+		if index == 0 {
+			return decimal.NaN
+		}
+		candle, previous := s.GetCandlePair(index)
+		prevHigh, prevLow := previous.MaxPrice, previous.MinPrice
+		if index == 1 {
+			_, minusDM := directionalMovement(candle, previous)
+			isLong, af = !minusDM.IsPositive(), start
+			if isLong {
+				ep, sar = candle.MaxPrice, previous.MinPrice
+			} else {
+				ep, sar = candle.MinPrice, previous.MaxPrice
+			}
+			prevHigh, prevLow = candle.MaxPrice, candle.MinPrice
+		}
+		high, low := candle.MaxPrice, candle.MinPrice
+
+		var out decimal.Decimal
+		switch {
+		case isLong && low.LTE(sar):
+			isLong, af = false, start
+			sar = ep.Max(prevHigh).Max(high)
+			out = sar
+			ep = low
+			sar = sar.Add(af.Mul(ep.Sub(sar))).Max(prevHigh).Max(high)
+		case isLong:
+			out = sar
+			if high.GT(ep) {
+				ep, af = high, af.Add(increment).Min(maximum)
+			}
+			sar = sar.Add(af.Mul(ep.Sub(sar))).Min(prevLow).Min(low)
+		case high.GTE(sar):
+			isLong, af = true, start
+			sar = ep.Min(prevLow).Min(low)
+			out = sar
+			ep = high
+			sar = sar.Add(af.Mul(ep.Sub(sar))).Min(prevLow).Min(low)
+		default:
+			out = sar
+			if low.LT(ep) {
+				ep, af = low, af.Add(increment).Min(maximum)
+			}
+			sar = sar.Add(af.Mul(ep.Sub(sar))).Max(prevHigh).Max(high)
+		}
+		return out
+	})
+}
+
+func newTradingViewSAR(s *series.TimeSeries, start, increment, maximum decimal.Decimal) Indicator {
+	var isBelow bool
+	var acceleration, extreme decimal.Decimal
+
+	return newSequenceIndicator(s, func(index int, values []decimal.Decimal) decimal.Decimal {
+		if index == 0 {
+			return decimal.NaN
+		}
+		candle, previous := s.GetCandlePair(index)
+		high, low := candle.MaxPrice, candle.MinPrice
+
+		result := values[index-1]
+		firstTrendBar := index == 1
+		if firstTrendBar {
+			isBelow, acceleration = candle.ClosePrice.GT(previous.ClosePrice), start
+			if isBelow {
+				extreme, result = high, previous.MinPrice
+			} else {
+				extreme, result = low, previous.MaxPrice
+			}
+		}
+
+		result = result.Add(acceleration.Mul(extreme.Sub(result)))
+		switch {
+		case isBelow && result.GT(low):
+			firstTrendBar, isBelow = true, false
+			result, extreme, acceleration = high.Max(extreme), low, start
+		case !isBelow && result.LT(high):
+			firstTrendBar, isBelow = true, true
+			result, extreme, acceleration = low.Min(extreme), high, start
+		}
+
+		if !firstTrendBar {
+			if isBelow && high.GT(extreme) {
+				extreme, acceleration = high, acceleration.Add(increment).Min(maximum)
+			} else if !isBelow && low.LT(extreme) {
+				extreme, acceleration = low, acceleration.Add(increment).Min(maximum)
+			}
+		}
+
+		if isBelow {
+			result = result.Min(previous.MinPrice)
+			if index > 1 {
+				result = result.Min(s.GetCandle(index - 2).MinPrice)
+			}
+		} else {
+			result = result.Max(previous.MaxPrice)
+			if index > 1 {
+				result = result.Max(s.GetCandle(index - 2).MaxPrice)
+			}
+		}
+		return result
+	})
+}
+
+func emaAlpha(window int) decimal.Decimal {
+	return decimal.New(2).Div(decimal.NewFromInt(int64(window + 1)))
+}
+
+func wilderAlpha(window int) decimal.Decimal {
+	return decimal.ONE.Div(decimal.NewFromInt(int64(window)))
+}
+
+// newSeededAverage returns an exponential average of src that has no value
+// before index start+window-1, is seeded there with the simple average of the
+// window values from start, and then moves alpha of the way towards each new
+// value of src. It is NaN past the end of s, the series src is computed from;
+// when s is nil its values are recomputed on every call.
+func newSeededAverage(src Indicator, s *series.TimeSeries, window, start int, alpha decimal.Decimal) Indicator {
+	first := start + window - 1
+	return withLookback{newSequenceIndicator(s, func(index int, values []decimal.Decimal) decimal.Decimal {
+		if index < first {
+			return decimal.NaN
+		}
+		if index == first {
+			sum := decimal.ZERO
+			for i := start; i <= first; i++ {
+				sum = sum.Add(src.Calculate(i))
+			}
+			return sum.Div(decimal.NewFromInt(int64(window)))
+		}
+		prev := values[index-1]
+		return prev.Add(alpha.Mul(src.Calculate(index).Sub(prev)))
+	}), first}
+}
+
+// sequenceIndicator caches a recurrence that is computed in index order, so
+// next may keep state between calls and read every earlier value. Indexes past
+// the end of series are NaN and are not cached. Without a series every index
+// asked for is cached, as the cached indicators do.
+type sequenceIndicator struct {
+	series *series.TimeSeries
+	next   func(index int, values []decimal.Decimal) decimal.Decimal
+
+	mu     sync.Mutex
+	values []decimal.Decimal
+}
+
+func newSequenceIndicator(s *series.TimeSeries, next func(index int, values []decimal.Decimal) decimal.Decimal) *sequenceIndicator {
+	return &sequenceIndicator{series: s, next: next}
+}
+
+func (si *sequenceIndicator) Calculate(index int) decimal.Decimal {
+	if index < 0 || (si.series != nil && index >= si.series.Length()) {
+		return decimal.NaN
+	}
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	for i := len(si.values); i <= index; i++ {
+		si.values = append(si.values, si.next(i, si.values))
+	}
+	return si.values[index]
+}
+
+// warmUpIndicator is NaN before index first
+type warmUpIndicator struct {
+	Indicator
+	first int
+}
+
+func (w warmUpIndicator) Calculate(index int) decimal.Decimal {
+	if index < w.first {
+		return decimal.NaN
+	}
+	return w.Indicator.Calculate(index)
+}
+
+func (w warmUpIndicator) Lookback() int { return max(w.first, Lookback(w.Indicator)) }
+
+// warmUpLines wraps every output of m in a warmUpIndicator
+func warmUpLines(m MultiOutputIndicator, first int) MultiOutputIndicator {
+	lines := outputIndicators(m)
+	for i, line := range lines {
+		lines[i] = warmUpIndicator{line, first}
+	}
+	return NewMultiOutputIndicator(m.Outputs(), lines...)
+}
+
+// NewSeriesBoundedIndicator returns indicator as computed from the candles of
+// s, NaN outside them. The Compat constructors bound their caches by the
+// series of a price indicator; wrap a derived source with this to give them
+// the series it is computed from.
+func NewSeriesBoundedIndicator(indicator Indicator, s *series.TimeSeries) Indicator {
+	return seriesBounded{indicator, s}
+}
+
+// seriesBounded is NaN past the end of series
+type seriesBounded struct {
+	Indicator
+	series *series.TimeSeries
+}
+
+func (sb seriesBounded) Calculate(index int) decimal.Decimal {
+	if sb.series == nil || index < 0 || index >= sb.series.Length() {
+		return decimal.NaN
+	}
+	return sb.Indicator.Calculate(index)
+}
+
+func (sb seriesBounded) Lookback() int { return Lookback(sb.Indicator) }
+
+func (sb seriesBounded) timeSeries() *series.TimeSeries { return sb.series }
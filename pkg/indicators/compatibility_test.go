@@ -0,0 +1,125 @@
+package indicators_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompatibility(t *testing.T) {
+	for name, want := range map[string]indicators.Compatibility{
+		"goflux":      indicators.CompatGoFlux,
+		"TA-Lib":      indicators.CompatTALib,
+		" talib ":     indicators.CompatTALib,
+		"TradingView": indicators.CompatTradingView,
+	} {
+		got, err := indicators.ParseCompatibility(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+	_, err := indicators.ParseCompatibility("metastock")
+	assert.Error(t, err)
+
+	assert.Equal(t, "talib", indicators.CompatTALib.String())
+	assert.Equal(t, "Compatibility(9)", indicators.Compatibility(9).String())
+}
+
+func TestCompatGoFluxMatchesPlainConstructors(t *testing.T) {
+	ts := streamingTestSeries(60)
+	closes := indicators.NewClosePriceIndicator(ts)
+	pairs := map[string][2]indicators.Indicator{
+		"ema": {indicators.NewEMAIndicator(closes, 10), indicators.NewEMAIndicatorCompat(closes, 10, indicators.CompatGoFlux)},
+		"rsi": {indicators.NewRelativeStrengthIndexIndicator(closes, 14), indicators.NewRelativeStrengthIndexIndicatorCompat(closes, 14, indicators.CompatGoFlux)},
+		"atr": {indicators.NewAverageTrueRangeIndicator(ts, 14), indicators.NewAverageTrueRangeIndicatorCompat(ts, 14, indicators.CompatGoFlux)},
+		"adx": {indicators.NewADXIndicator(ts, 14), indicators.NewADXIndicatorCompat(ts, 14, indicators.CompatGoFlux)},
+		"cci": {indicators.NewCCIIndicator(ts, 20), indicators.NewCCIIndicatorCompat(ts, 20, indicators.CompatGoFlux)},
+		"obv": {indicators.NewOBVIndicator(ts), indicators.NewOBVIndicatorCompat(ts, indicators.CompatGoFlux)},
+		"sar": {indicators.NewParabolicSARIndicator(ts), indicators.NewParabolicSARIndicatorCompat(ts, 0.02, 0.02, 0.2, indicators.CompatGoFlux)},
+	}
+	for name, pair := range pairs {
+		for i := 0; i < ts.Length(); i++ {
+			assertSameValue(t, name, i, pair[0].Calculate(i), pair[1].Calculate(i))
+		}
+	}
+}
+
+func TestCompatIndicatorsFollowGrowingSeries(t *testing.T) {
+	full := streamingTestSeries(40)
+	ts := streamingTestSeries(0)
+	for _, c := range full.Candles[:30] {
+		ts.AddCandle(c)
+	}
+	adx := indicators.NewADXIndicatorCompat(ts, 5, indicators.CompatTALib)
+	sar := indicators.NewParabolicSARIndicatorCompat(ts, 0.02, 0.02, 0.2, indicators.CompatTradingView)
+	assert.True(t, adx.Calculate(35).IsNaN())
+	assert.True(t, sar.Calculate(35).IsNaN())
+
+	for _, c := range full.Candles[30:] {
+		ts.AddCandle(c)
+	}
+	wantADX := indicators.NewADXIndicatorCompat(full, 5, indicators.CompatTALib)
+	wantSAR := indicators.NewParabolicSARIndicatorCompat(full, 0.02, 0.02, 0.2, indicators.CompatTradingView)
+	for i := 0; i < full.Length(); i++ {
+		assertSameValue(t, "adx", i, wantADX.Calculate(i), adx.Calculate(i))
+		assertSameValue(t, "sar", i, wantSAR.Calculate(i), sar.Calculate(i))
+	}
+}
+
+func TestCompatAveragesFollowGrowingSeries(t *testing.T) {
+	full := streamingTestSeries(40)
+	ts := streamingTestSeries(0)
+	for _, c := range full.Candles[:30] {
+		ts.AddCandle(c)
+	}
+	build := func(s *series.TimeSeries) map[string]indicators.Indicator {
+		closes := indicators.NewClosePriceIndicator(s)
+		sma := indicators.NewSimpleMovingAverage(closes, 3)
+		return map[string]indicators.Indicator{
+			"ema":         indicators.NewEMAIndicatorCompat(closes, 5, indicators.CompatTALib),
+			"rsi":         indicators.NewRelativeStrengthIndexIndicatorCompat(closes, 5, indicators.CompatTradingView),
+			"macd":        indicators.NewMACDLinesIndicatorCompat(closes, 3, 6, 4, indicators.CompatTALib).Output("signal"),
+			"atr":         indicators.NewAverageTrueRangeIndicatorCompat(s, 5, indicators.CompatTALib),
+			"derived":     indicators.NewEMAIndicatorCompat(sma, 5, indicators.CompatTradingView),
+			"bounded sma": indicators.NewEMAIndicatorCompat(indicators.NewSeriesBoundedIndicator(sma, s), 5, indicators.CompatTradingView),
+		}
+	}
+	growing := build(ts)
+	for name, ind := range growing {
+		assert.True(t, ind.Calculate(35).IsNaN(), name)
+		assert.True(t, ind.Calculate(1_000_000).IsNaN(), name)
+	}
+
+	for _, c := range full.Candles[30:] {
+		ts.AddCandle(c)
+	}
+	want := build(full)
+	for name, ind := range growing {
+		for i := 0; i < full.Length(); i++ {
+			assertSameValue(t, name, i, want[name].Calculate(i), ind.Calculate(i))
+		}
+	}
+}
+
+func TestCompatAverageWithoutSeriesCachesValues(t *testing.T) {
+	calls := new(atomic.Int64)
+	src := countingIndicator{indicators.NewConstantIndicator(2), calls}
+	ema := indicators.NewEMAIndicatorCompat(src, 5, indicators.CompatTALib)
+	for i := 0; i < 500; i++ {
+		want := decimal.New(2)
+		if i < 4 {
+			want = decimal.NaN
+		}
+		assertSameValue(t, "EMA", i, want, ema.Calculate(i))
+	}
+	assert.LessOrEqual(t, calls.Load(), int64(2*500), "each value is computed once")
+}
+
+func TestCompatUnknownProfilePanics(t *testing.T) {
+	ts := streamingTestSeries(5)
+	assert.Panics(t, func() { indicators.NewOBVIndicatorCompat(ts, indicators.Compatibility(7)) })
+}
@@ -13,3 +13,12 @@ func NewConstantIndicator(constant float64) Indicator {
 func (ci constantIndicator) Calculate(index int) decimal.Decimal {
 	return decimal.New(float64(ci))
 }
+
+func (ci constantIndicator) ComputeInto(dst []decimal.Decimal) {
+	val := decimal.New(float64(ci))
+	for i := range dst {
+		dst[i] = val
+	}
+}
+
+func (ci constantIndicator) Lookback() int { return 0 }
@@ -11,6 +11,11 @@ type dominantCyclePeriod struct {
 	indicator Indicator
 }
 
+// NewDominantCyclePeriod returns the lag between 7 and 50 bars at which the
+// input best correlates with itself.
+//
+// Deprecated: use NewHTDCPeriodIndicator, which follows TA-Lib's HT_DCPERIOD,
+// or NewAutocorrelationPeriodogramIndicator.
 func NewDominantCyclePeriod(indicator Indicator) Indicator {
 	return dominantCyclePeriod{indicator}
 }
@@ -94,6 +99,10 @@ type htTrendline struct {
 	indicator Indicator
 }
 
+// NewHTTrendline returns a 4-bar WMA of the input.
+//
+// Deprecated: use NewHTTrendlineIndicator, which follows TA-Lib's
+// HT_TRENDLINE.
 func NewHTTrendline(indicator Indicator) Indicator {
 	return htTrendline{indicator}
 }
@@ -112,3 +121,9 @@ func (htt htTrendline) Calculate(index int) decimal.Decimal {
 
 	return p0.Mul(decimal.New(0.4)).Add(p1.Mul(decimal.New(0.3))).Add(p2.Mul(decimal.New(0.2))).Add(p3.Mul(decimal.New(0.1)))
 }
+
+func (dcp dominantCyclePeriod) Lookback() int { return Lookback(dcp.indicator) + 7 }
+
+func (ht hilbertTransform) Lookback() int { return Lookback(ht.indicator) + 7 }
+
+func (htt htTrendline) Lookback() int { return Lookback(htt.indicator) + 12 }
@@ -22,3 +22,6 @@ func (di DerivativeIndicator) Calculate(index int) decimal.Decimal {
 
 	return di.Indicator.Calculate(index).Sub(di.Indicator.Calculate(index - 1))
 }
+
+// Lookback is one past the lookback of the underlying indicator
+func (di DerivativeIndicator) Lookback() int { return Lookback(di.Indicator) + 1 }
@@ -19,3 +19,13 @@ func NewDifferenceIndicator(minuend, subtrahend Indicator) Indicator {
 func (di differenceIndicator) Calculate(index int) decimal.Decimal {
 	return di.minuend.Calculate(index).Sub(di.subtrahend.Calculate(index))
 }
+
+func (di differenceIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(di.minuend, dst)
+	subtrahend := ComputeAll(di.subtrahend, len(dst))
+	for i := range dst {
+		dst[i] = dst[i].Sub(subtrahend[i])
+	}
+}
+
+func (di differenceIndicator) Lookback() int { return MaxLookback(di.minuend, di.subtrahend) }
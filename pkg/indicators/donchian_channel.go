@@ -76,8 +76,22 @@ func NewDonchianMiddleBandIndicator(s *series.TimeSeries, window int) Indicator
 	}
 }
 
+// NewDonchianChannelIndicator returns the middle, upper and lower Donchian bands
+// as a MultiOutputIndicator. Panics if window < 1.
+func NewDonchianChannelIndicator(s *series.TimeSeries, window int) MultiOutputIndicator {
+	upper := NewDonchianUpperBandIndicator(s, window)
+	lower := NewDonchianLowerBandIndicator(s, window)
+	return NewMultiOutputIndicator(bandOutputs, donchianMiddleBand{upper: upper, lower: lower}, upper, lower)
+}
+
 func (d donchianMiddleBand) Calculate(index int) decimal.Decimal {
 	up := d.upper.Calculate(index)
 	lo := d.lower.Calculate(index)
 	return up.Add(lo).Div(decimal.New(2))
 }
+
+func (d donchianUpperBand) Lookback() int { return d.window - 1 }
+
+func (d donchianLowerBand) Lookback() int { return d.window - 1 }
+
+func (d donchianMiddleBand) Lookback() int { return MaxLookback(d.upper, d.lower) }
@@ -0,0 +1,430 @@
+package indicators
+
+import (
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+// recursiveFilter memoizes one of John Ehlers' recursive filters, whose lines
+// at each bar depend on the input and on the lines of earlier bars. It runs
+// from the first ready value of its input; step returns the lines of bar i and
+// reads earlier bars with past.
+type recursiveFilter struct {
+	input Indicator
+	start int
+	step  func(i int) []float64
+
+	mu     sync.Mutex
+	values [][]float64
+}
+
+func newRecursiveFilter(input Indicator) *recursiveFilter {
+	return &recursiveFilter{input: input, start: Lookback(input)}
+}
+
+// price returns the input at i, or its first ready value before the start
+func (f *recursiveFilter) price(i int) float64 {
+	return f.input.Calculate(max(i, f.start)).Float()
+}
+
+// bar returns how many bars i is after the start
+func (f *recursiveFilter) bar(i int) int { return i - f.start }
+
+// past returns line at an earlier bar i, zero before the start
+func (f *recursiveFilter) past(line, i int) float64 {
+	if k := i - f.start; k >= 0 && k < len(f.values) {
+		return f.values[k][line]
+	}
+	return 0
+}
+
+func (f *recursiveFilter) at(index int) []float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := f.start + len(f.values); i <= index; i++ {
+		f.values = append(f.values, f.step(i))
+	}
+	return f.values[index-f.start]
+}
+
+// filterLine is one output of a recursiveFilter, zero before its start
+type filterLine struct {
+	filter   *recursiveFilter
+	line     int
+	lookback int
+}
+
+func (l filterLine) Calculate(index int) decimal.Decimal {
+	if index < l.filter.start {
+		return decimal.ZERO
+	}
+	return decimal.New(l.filter.at(index)[l.line])
+}
+
+func (l filterLine) Lookback() int { return l.filter.start + l.lookback }
+
+// superSmootherCoefficients returns the coefficients of Ehlers' two-pole
+// Butterworth SuperSmoother with the given critical period
+func superSmootherCoefficients(period float64) (c1, c2, c3 float64) {
+	a1 := math.Exp(-math.Sqrt2 * math.Pi / period)
+	c2 = 2 * a1 * math.Cos(math.Sqrt2*math.Pi/period)
+	c3 = -a1 * a1
+	return 1 - c2 - c3, c2, c3
+}
+
+// highPassAlpha returns alpha of Ehlers' two-pole high-pass filter with the
+// given critical period
+func highPassAlpha(period float64) float64 {
+	angle := 0.707 * 2 * math.Pi / period
+	return (math.Cos(angle) + math.Sin(angle) - 1) / math.Cos(angle)
+}
+
+// highPass returns the next value of a two-pole high-pass filter of x from
+// its inputs x0 (newest) to x2 and its previous values h1 and h2
+func highPass(alpha, x0, x1, x2, h1, h2 float64) float64 {
+	return (1-alpha/2)*(1-alpha/2)*(x0-2*x1+x2) + 2*(1-alpha)*h1 - (1-alpha)*(1-alpha)*h2
+}
+
+// NewSuperSmootherIndicator returns Ehlers' SuperSmoother, a two-pole
+// Butterworth low-pass filter that removes cycles shorter than period with
+// less lag than a moving average of the same smoothing. Panics if indicator
+// is nil or period < 2.
+func NewSuperSmootherIndicator(indicator Indicator, period int) Indicator {
+	if indicator == nil {
+		panic("goflux: SuperSmoother indicator cannot be nil")
+	}
+	if period < 2 {
+		panic("goflux: SuperSmoother period must be >= 2")
+	}
+	telemetry.ReportUsage("SuperSmoother", map[string]string{"period": strconv.Itoa(period)})
+
+	c1, c2, c3 := superSmootherCoefficients(float64(period))
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		x := f.price(i)
+		if f.bar(i) < 2 {
+			return []float64{x}
+		}
+		return []float64{c1*(x+f.price(i-1))/2 + c2*f.past(0, i-1) + c3*f.past(0, i-2)}
+	}
+	return filterLine{filter: f, lookback: period}
+}
+
+// NewRoofingFilterIndicator returns Ehlers' Roofing Filter: a two-pole
+// high-pass filter removing cycles longer than highPass, smoothed by a
+// SuperSmoother removing those shorter than lowPass. It leaves the cycles
+// between them as a zero-mean oscillator. Panics if indicator is nil,
+// lowPass < 2 or highPass <= lowPass.
+func NewRoofingFilterIndicator(indicator Indicator, highPass, lowPass int) Indicator {
+	if indicator == nil {
+		panic("goflux: RoofingFilter indicator cannot be nil")
+	}
+	if lowPass < 2 || highPass <= lowPass {
+		panic("goflux: RoofingFilter requires 2 <= lowPass < highPass")
+	}
+	telemetry.ReportUsage("RoofingFilter", map[string]string{
+		"high_pass": strconv.Itoa(highPass),
+		"low_pass":  strconv.Itoa(lowPass),
+	})
+	return filterLine{filter: newRoofingFilter(indicator, highPass, lowPass), line: 1, lookback: highPass}
+}
+
+// newRoofingFilter returns a filter with lines high-pass and roofing
+func newRoofingFilter(indicator Indicator, highPassPeriod, lowPass int) *recursiveFilter {
+	alpha := highPassAlpha(float64(highPassPeriod))
+	c1, c2, c3 := superSmootherCoefficients(float64(lowPass))
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		if f.bar(i) < 2 {
+			return []float64{0, 0}
+		}
+		hp := highPass(alpha, f.price(i), f.price(i-1), f.price(i-2), f.past(0, i-1), f.past(0, i-2))
+		return []float64{hp, c1*(hp+f.past(0, i-1))/2 + c2*f.past(1, i-1) + c3*f.past(1, i-2)}
+	}
+	return f
+}
+
+// NewDecyclerIndicator returns Ehlers' Simple Decycler: the input less a
+// one-pole high-pass filter with the given critical period, which leaves the
+// trend with the cycles shorter than period removed at very little lag.
+// Panics if indicator is nil or period < 5.
+func NewDecyclerIndicator(indicator Indicator, period int) Indicator {
+	if indicator == nil {
+		panic("goflux: Decycler indicator cannot be nil")
+	}
+	if period < 5 {
+		panic("goflux: Decycler period must be >= 5")
+	}
+	telemetry.ReportUsage("Decycler", map[string]string{"period": strconv.Itoa(period)})
+
+	angle := 2 * math.Pi / float64(period)
+	alpha := (math.Cos(angle) + math.Sin(angle) - 1) / math.Cos(angle)
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		x := f.price(i)
+		hp := 0.0
+		if f.bar(i) > 0 {
+			hp = (1-alpha/2)*(x-f.price(i-1)) + (1-alpha)*f.past(0, i-1)
+		}
+		return []float64{hp, x - hp}
+	}
+	return filterLine{filter: f, line: 1, lookback: period}
+}
+
+var (
+	instantaneousTrendlineOutputs = []string{"itrend", "trigger"}
+	cyberCycleOutputs             = []string{"cycle", "trigger"}
+	fisherTransformOutputs        = []string{"fisher", "trigger"}
+	centerOfGravityOutputs        = []string{"cg", "trigger"}
+)
+
+// NewInstantaneousTrendlineIndicator returns Ehlers' Instantaneous Trendline
+// from Cybernetic Analysis, a low-lag trend filter with smoothing factor alpha
+// (0.07 in the book), as a MultiOutputIndicator with outputs itrend and
+// trigger, 2*itrend less itrend two bars ago. Trigger crossing above itrend
+// is a buy. Panics if indicator is nil or alpha is not in (0, 1).
+func NewInstantaneousTrendlineIndicator(indicator Indicator, alpha float64) MultiOutputIndicator {
+	if indicator == nil {
+		panic("goflux: InstantaneousTrendline indicator cannot be nil")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		panic("goflux: InstantaneousTrendline alpha must be in (0, 1)")
+	}
+	telemetry.ReportUsage("InstantaneousTrendline", map[string]string{"alpha": strconv.FormatFloat(alpha, 'f', -1, 64)})
+
+	a2 := alpha * alpha
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		x0, x1, x2 := f.price(i), f.price(i-1), f.price(i-2)
+		it := (x0 + 2*x1 + x2) / 4
+		if f.bar(i) >= 7 {
+			it = (alpha-a2/4)*x0 + 0.5*a2*x1 - (alpha-0.75*a2)*x2 +
+				2*(1-alpha)*f.past(0, i-1) - (1-alpha)*(1-alpha)*f.past(0, i-2)
+		}
+		trigger := it
+		if f.bar(i) >= 2 {
+			trigger = 2*it - f.past(0, i-2)
+		}
+		return []float64{it, trigger}
+	}
+	return NewMultiOutputIndicator(instantaneousTrendlineOutputs,
+		filterLine{filter: f, line: 0, lookback: 7}, filterLine{filter: f, line: 1, lookback: 7})
+}
+
+// NewCyberCycleIndicator returns Ehlers' Cyber Cycle from Cybernetic
+// Analysis, a high-pass filter of the smoothed input isolating its cycle
+// component, with smoothing factor alpha (0.07 in the book). It is a
+// MultiOutputIndicator with outputs cycle and trigger, the cycle one bar ago.
+// Panics if indicator is nil or alpha is not in (0, 1).
+func NewCyberCycleIndicator(indicator Indicator, alpha float64) MultiOutputIndicator {
+	if indicator == nil {
+		panic("goflux: CyberCycle indicator cannot be nil")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		panic("goflux: CyberCycle alpha must be in (0, 1)")
+	}
+	telemetry.ReportUsage("CyberCycle", map[string]string{"alpha": strconv.FormatFloat(alpha, 'f', -1, 64)})
+
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		x0, x1, x2, x3 := f.price(i), f.price(i-1), f.price(i-2), f.price(i-3)
+		smooth := (x0 + 2*x1 + 2*x2 + x3) / 6
+		cycle := (x0 - 2*x1 + x2) / 4
+		if f.bar(i) >= 7 {
+			cycle = (1-alpha/2)*(1-alpha/2)*(smooth-2*f.past(2, i-1)+f.past(2, i-2)) +
+				2*(1-alpha)*f.past(0, i-1) - (1-alpha)*(1-alpha)*f.past(0, i-2)
+		}
+		return []float64{cycle, f.past(0, i-1), smooth}
+	}
+	return NewMultiOutputIndicator(cyberCycleOutputs,
+		filterLine{filter: f, line: 0, lookback: 7}, filterLine{filter: f, line: 1, lookback: 7})
+}
+
+// NewFisherTransformIndicator returns Ehlers' Fisher Transform of indicator,
+// usually the median price, normalized to its range over window. It turns the
+// position in the range into a nearly Gaussian oscillator with sharp turning
+// points. It is a MultiOutputIndicator with outputs fisher and trigger, the
+// fisher one bar ago. Panics if indicator is nil or window <= 0.
+func NewFisherTransformIndicator(indicator Indicator, window int) MultiOutputIndicator {
+	if indicator == nil {
+		panic("goflux: FisherTransform indicator cannot be nil")
+	}
+	if window <= 0 {
+		panic("goflux: FisherTransform window must be > 0")
+	}
+	telemetry.ReportUsage("FisherTransform", map[string]string{"window": strconv.Itoa(window)})
+
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		x := f.price(i)
+		highest, lowest := x, x
+		for k := 1; k < window; k++ {
+			highest, lowest = math.Max(highest, f.price(i-k)), math.Min(lowest, f.price(i-k))
+		}
+		position := 0.0
+		if highest > lowest {
+			position = (x-lowest)/(highest-lowest) - 0.5
+		}
+		value := math.Max(math.Min(0.66*position+0.67*f.past(2, i-1), 0.999), -0.999)
+		fisher := 0.5*math.Log((1+value)/(1-value)) + 0.5*f.past(0, i-1)
+		return []float64{fisher, f.past(0, i-1), value}
+	}
+	return NewMultiOutputIndicator(fisherTransformOutputs,
+		filterLine{filter: f, line: 0, lookback: window - 1}, filterLine{filter: f, line: 1, lookback: window})
+}
+
+type inverseFisherTransformIndicator struct {
+	indicator Indicator
+}
+
+// NewInverseFisherTransformIndicator returns the inverse Fisher transform,
+// tanh, of indicator. It compresses an oscillator centred on zero into
+// (-1, 1), so scale the input first: Ehlers applies it to 0.1*(RSI-50)
+// smoothed by a 9-bar WMA. Panics if indicator is nil.
+func NewInverseFisherTransformIndicator(indicator Indicator) Indicator {
+	if indicator == nil {
+		panic("goflux: InverseFisherTransform indicator cannot be nil")
+	}
+	return inverseFisherTransformIndicator{indicator}
+}
+
+func (ift inverseFisherTransformIndicator) Calculate(index int) decimal.Decimal {
+	x := ift.indicator.Calculate(index)
+	if x.IsNaN() {
+		return x
+	}
+	return decimal.New(math.Tanh(x.Float()))
+}
+
+func (ift inverseFisherTransformIndicator) Lookback() int { return Lookback(ift.indicator) }
+
+// NewCenterOfGravityIndicator returns Ehlers' Center of Gravity oscillator:
+// the balance point of the input over window, weighting each value by its
+// age, centred on zero. It turns with little lag at cycle extremes. It is a
+// MultiOutputIndicator with outputs cg and trigger, the cg one bar ago; both
+// are zero before the window is full or when the window sums to zero. Panics
+// if indicator is nil or window <= 0.
+func NewCenterOfGravityIndicator(indicator Indicator, window int) MultiOutputIndicator {
+	if indicator == nil {
+		panic("goflux: CenterOfGravity indicator cannot be nil")
+	}
+	if window <= 0 {
+		panic("goflux: CenterOfGravity window must be > 0")
+	}
+	telemetry.ReportUsage("CenterOfGravity", map[string]string{"window": strconv.Itoa(window)})
+
+	lookback := Lookback(indicator) + window - 1
+	cg := func(index int) decimal.Decimal {
+		if index < lookback {
+			return decimal.ZERO
+		}
+		num, den := 0.0, 0.0
+		for k := 0; k < window; k++ {
+			x := indicator.Calculate(index - k).Float()
+			num += float64(k+1) * x
+			den += x
+		}
+		if den == 0 {
+			return decimal.ZERO
+		}
+		return decimal.New(-num/den + float64(window+1)/2)
+	}
+	return NewMultiOutputIndicator(centerOfGravityOutputs,
+		withLookback{indicatorFunc(cg), lookback},
+		withLookback{indicatorFunc(func(index int) decimal.Decimal { return cg(index - 1) }), lookback + 1})
+}
+
+// NewAutocorrelationPeriodogramIndicator returns the dominant cycle period
+// measured by Ehlers' Autocorrelation Periodogram from Cycle Analytics for
+// Traders. The input is band limited by a Roofing Filter passing minPeriod to
+// maxPeriod; the autocorrelation of the result at lags up to maxPeriod, each
+// averaged over avgLength bars (the lag itself if zero), is transformed to a
+// power spectrum over the periods minPeriod to maxPeriod. The spectrum is
+// smoothed, normalized by its decaying peak, and the dominant cycle is the
+// power weighted mean of the periods with at least half the peak power. The
+// book uses 10, 48 and 3. Panics if indicator is nil, minPeriod < 3,
+// maxPeriod <= minPeriod or avgLength < 0.
+func NewAutocorrelationPeriodogramIndicator(indicator Indicator, minPeriod, maxPeriod, avgLength int) Indicator {
+	if indicator == nil {
+		panic("goflux: AutocorrelationPeriodogram indicator cannot be nil")
+	}
+	if minPeriod < 3 || maxPeriod <= minPeriod {
+		panic("goflux: AutocorrelationPeriodogram requires 3 <= minPeriod < maxPeriod")
+	}
+	if avgLength < 0 {
+		panic("goflux: AutocorrelationPeriodogram avgLength must be >= 0")
+	}
+	telemetry.ReportUsage("AutocorrelationPeriodogram", map[string]string{
+		"min_period": strconv.Itoa(minPeriod),
+		"max_period": strconv.Itoa(maxPeriod),
+		"avg_length": strconv.Itoa(avgLength),
+	})
+
+	roof := newRoofingFilter(indicator, maxPeriod, minPeriod)
+	periods := maxPeriod - minPeriod + 1
+	cosines, sines := make([][]float64, periods), make([][]float64, periods)
+	for p := range cosines {
+		cosines[p], sines[p] = make([]float64, maxPeriod+1), make([]float64, maxPeriod+1)
+		for lag := 3; lag <= maxPeriod; lag++ {
+			angle := 2 * math.Pi * float64(lag) / float64(minPeriod+p)
+			cosines[p][lag], sines[p][lag] = math.Cos(angle), math.Sin(angle)
+		}
+	}
+
+	// Lines are the dominant cycle, the peak power and the smoothed power of
+	// each period
+	f := newRecursiveFilter(indicator)
+	f.step = func(i int) []float64 {
+		roof.at(i)
+		filt := func(k int) float64 { return roof.past(1, k) }
+
+		corr := make([]float64, maxPeriod+1)
+		for lag := range corr {
+			m := avgLength
+			if m == 0 {
+				m = lag
+			}
+			var sx, sy, sxx, syy, sxy float64
+			for n := 0; n < m; n++ {
+				x, y := filt(i-n), filt(i-lag-n)
+				sx, sy, sxx, syy, sxy = sx+x, sy+y, sxx+x*x, syy+y*y, sxy+x*y
+			}
+			mf := float64(m)
+			if d := (mf*sxx - sx*sx) * (mf*syy - sy*sy); d > 0 {
+				corr[lag] = (mf*sxy - sx*sy) / math.Sqrt(d)
+			}
+		}
+
+		values := make([]float64, 2+periods)
+		peak := 0.995 * f.past(1, i-1)
+		for p := 0; p < periods; p++ {
+			var cosPart, sinPart float64
+			for lag := 3; lag <= maxPeriod; lag++ {
+				cosPart += corr[lag] * cosines[p][lag]
+				sinPart += corr[lag] * sines[p][lag]
+			}
+			sq := cosPart*cosPart + sinPart*sinPart
+			values[2+p] = 0.2*sq*sq + 0.8*f.past(2+p, i-1)
+			peak = math.Max(peak, values[2+p])
+		}
+		values[1] = peak
+
+		var spx, sp float64
+		for p := 0; p < periods && peak > 0; p++ {
+			if pwr := values[2+p] / peak; pwr >= 0.5 {
+				spx += float64(minPeriod+p) * pwr
+				sp += pwr
+			}
+		}
+		values[0] = f.past(0, i-1)
+		if sp > 0 {
+			values[0] = spx / sp
+		}
+		return values
+	}
+	return filterLine{filter: f, lookback: maxPeriod + avgLength}
+}
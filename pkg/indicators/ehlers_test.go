@@ -0,0 +1,178 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+// sineSeries returns n closes of base plus a sine wave with the given period
+// and amplitude
+func sineSeries(n int, base, amplitude, period float64) indicators.Indicator {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = base + amplitude*math.Sin(2*math.Pi*float64(i)/period)
+	}
+	return indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(values...))
+}
+
+// amplitude returns the largest absolute value of ind over [from, to)
+func amplitude(ind indicators.Indicator, from, to int, center float64) float64 {
+	peak := 0.0
+	for i := from; i < to; i++ {
+		peak = math.Max(peak, math.Abs(ind.Calculate(i).Float()-center))
+	}
+	return peak
+}
+
+func TestSuperSmoother(t *testing.T) {
+	flat := indicators.NewSuperSmootherIndicator(sineSeries(50, 100, 0, 10), 10)
+	assert.InDelta(t, 100, flat.Calculate(49).Float(), 1e-9)
+
+	// Cycles well above the period pass, those well below are removed
+	assert.Greater(t, amplitude(indicators.NewSuperSmootherIndicator(sineSeries(200, 100, 5, 60), 10), 100, 200, 100), 4.5)
+	assert.Less(t, amplitude(indicators.NewSuperSmootherIndicator(sineSeries(200, 100, 5, 4), 10), 100, 200, 100), 1.0)
+	assert.Equal(t, 10, indicators.Lookback(flat))
+
+	assert.Panics(t, func() { indicators.NewSuperSmootherIndicator(sineSeries(1, 1, 0, 1), 1) })
+}
+
+func TestRoofingFilter(t *testing.T) {
+	flat := indicators.NewRoofingFilterIndicator(sineSeries(100, 100, 0, 10), 48, 10)
+	assert.InDelta(t, 0, flat.Calculate(99).Float(), 1e-9)
+
+	// A 20 bar cycle is inside the pass band, trend and noise are outside it
+	roof := indicators.NewRoofingFilterIndicator(sineSeries(300, 100, 5, 20), 48, 10)
+	assert.Greater(t, amplitude(roof, 150, 300, 0), 3.0)
+	trend := make([]float64, 300)
+	for i := range trend {
+		trend[i] = 100 + float64(i)
+	}
+	roofTrend := indicators.NewRoofingFilterIndicator(indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(trend...)), 48, 10)
+	assert.Less(t, amplitude(roofTrend, 150, 300, 0), 0.01)
+
+	assert.Panics(t, func() { indicators.NewRoofingFilterIndicator(flat, 10, 10) })
+}
+
+func TestDecycler(t *testing.T) {
+	trend, wave := make([]float64, 300), make([]float64, 300)
+	for i := range trend {
+		trend[i] = 100 + float64(i)
+		wave[i] = trend[i] + 3*math.Sin(2*math.Pi*float64(i)/10)
+	}
+	plain := indicators.NewDecyclerIndicator(indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(trend...)), 60)
+	decycled := indicators.NewDecyclerIndicator(indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(wave...)), 60)
+	for i := 200; i < 300; i++ {
+		assert.InDelta(t, plain.Calculate(i).Float(), decycled.Calculate(i).Float(), 0.5, "the 10 bar cycle is removed at %d", i)
+	}
+
+	flat := indicators.NewDecyclerIndicator(sineSeries(50, 100, 0, 10), 60)
+	assert.InDelta(t, 100, flat.Calculate(49).Float(), 1e-9)
+	assert.Panics(t, func() { indicators.NewDecyclerIndicator(flat, 4) })
+}
+
+func TestInstantaneousTrendline(t *testing.T) {
+	itrend := indicators.NewInstantaneousTrendlineIndicator(sineSeries(60, 50, 0, 10), 0.07)
+	assert.Equal(t, []string{"itrend", "trigger"}, itrend.Outputs())
+	assert.InDelta(t, 50, itrend.Calculate(59).Float(), 1e-9)
+	assert.InDelta(t, 50, itrend.Output("trigger").Calculate(59).Float(), 1e-9)
+
+	wave := indicators.NewInstantaneousTrendlineIndicator(sineSeries(300, 50, 5, 20), 0.07)
+	for i := 100; i < 300; i += 7 {
+		want := 2*wave.Calculate(i).Float() - wave.Calculate(i-2).Float()
+		assert.InDelta(t, want, wave.Output("trigger").Calculate(i).Float(), 1e-9)
+	}
+	assert.Less(t, amplitude(wave, 100, 300, 50), 2.5, "the trendline damps the cycle")
+}
+
+func TestCyberCycle(t *testing.T) {
+	flat := indicators.NewCyberCycleIndicator(sineSeries(60, 50, 0, 10), 0.07)
+	assert.InDelta(t, 0, flat.Calculate(59).Float(), 1e-9)
+
+	cycle := indicators.NewCyberCycleIndicator(sineSeries(300, 50, 5, 20), 0.07)
+	assert.Greater(t, amplitude(cycle, 100, 300, 0), 2.0)
+	assert.Equal(t, cycle.Calculate(150), cycle.Output("trigger").Calculate(151))
+}
+
+func TestFisherTransform(t *testing.T) {
+	fisher := indicators.NewFisherTransformIndicator(sineSeries(200, 50, 5, 20), 10)
+	assert.Equal(t, fisher.Calculate(150), fisher.Output("trigger").Calculate(151))
+	peak := amplitude(fisher, 50, 200, 0)
+	assert.Greater(t, peak, 1.0)
+	assert.Less(t, peak, 4.0)
+
+	flat := indicators.NewFisherTransformIndicator(sineSeries(20, 50, 0, 20), 10)
+	assert.Equal(t, "0", flat.Calculate(19).String())
+}
+
+func TestInverseFisherTransform(t *testing.T) {
+	ift := indicators.NewInverseFisherTransformIndicator(indicators.NewConstantIndicator(0.5))
+	assert.InDelta(t, math.Tanh(0.5), ift.Calculate(0).Float(), 1e-12)
+	assert.True(t, indicators.NewInverseFisherTransformIndicator(indicators.NewConstantIndicator(math.NaN())).Calculate(0).IsNaN())
+}
+
+func TestCenterOfGravity(t *testing.T) {
+	flat := indicators.NewCenterOfGravityIndicator(sineSeries(20, 50, 0, 10), 10)
+	assert.InDelta(t, 0, flat.Calculate(19).Float(), 1e-9)
+	assert.Equal(t, "0", flat.Calculate(8).String())
+	assert.Equal(t, 9, indicators.Lookback(flat))
+
+	// Rising prices weigh the window towards its newest end
+	rising := indicators.NewCenterOfGravityIndicator(indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(1, 2, 3, 4)), 4)
+	assert.InDelta(t, -(1*4+2*3+3*2+4*1)/10.0+2.5, rising.Calculate(3).Float(), 1e-9)
+	assert.Equal(t, rising.Calculate(3), rising.Output("trigger").Calculate(4))
+}
+
+func TestAutocorrelationPeriodogram(t *testing.T) {
+	for _, period := range []float64{15, 25, 35} {
+		acp := indicators.NewAutocorrelationPeriodogramIndicator(sineSeries(400, 100, 5, period), 10, 48, 3)
+		assert.InDelta(t, period, acp.Calculate(399).Float(), 2, "period %v", period)
+	}
+	assert.Panics(t, func() { indicators.NewAutocorrelationPeriodogramIndicator(sineSeries(1, 1, 0, 1), 10, 10, 3) })
+}
+
+func TestHilbertTransformIndicators(t *testing.T) {
+	closes := sineSeries(300, 100, 5, 20)
+	period := indicators.NewHTDCPeriodIndicator(closes)
+	assert.True(t, period.Calculate(31).IsNaN())
+	assert.InDelta(t, 20, period.Calculate(299).Float(), 2)
+	assert.Equal(t, 32, indicators.Lookback(period))
+
+	sine := indicators.NewHTSineIndicator(closes)
+	assert.Equal(t, []string{"sine", "leadsine"}, sine.Outputs())
+	assert.True(t, sine.Calculate(62).IsNaN())
+	for i := 63; i < 300; i++ {
+		s, lead := sine.Calculate(i).Float(), sine.Output("leadsine").Calculate(i).Float()
+		require.LessOrEqual(t, math.Abs(s), 1.0)
+		require.LessOrEqual(t, math.Abs(lead), 1.0)
+	}
+
+	phase := indicators.NewHTDCPhaseIndicator(closes)
+	for i := 63; i < 300; i++ {
+		p := phase.Calculate(i).Float()
+		require.True(t, p >= -45 && p <= 315, "phase %v at %d", p, i)
+	}
+
+	// Within 1.5% of the trendline a pure sine wave is mostly in cycle mode
+	mode := indicators.NewHTTrendModeIndicator(sineSeries(300, 100, 1, 20))
+	cycling := 0
+	for i := 200; i < 300; i++ {
+		if mode.Calculate(i).IsZero() {
+			cycling++
+		}
+	}
+	assert.Greater(t, cycling, 50)
+
+	trendline := indicators.NewHTTrendlineIndicator(closes)
+	assert.InDelta(t, 100, trendline.Calculate(299).Float(), 1)
+
+	phasor := indicators.NewHTPhasorIndicator(closes)
+	assert.Equal(t, []string{"inphase", "quadrature"}, phasor.Outputs())
+	assert.False(t, phasor.Output("quadrature").Calculate(32).IsNaN())
+	assert.True(t, phasor.Calculate(31).IsNaN())
+}
@@ -106,3 +106,7 @@ func (fi *rawForceIndexIndicator) Calculate(index int) decimal.Decimal {
 	closeDiff := currCandle.ClosePrice.Sub(prevCandle.ClosePrice)
 	return closeDiff.Mul(currCandle.Volume)
 }
+
+func (eom *rawEaseOfMovementIndicator) Lookback() int { return 1 }
+
+func (fi *rawForceIndexIndicator) Lookback() int { return 1 }
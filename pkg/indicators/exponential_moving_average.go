@@ -2,7 +2,6 @@ package indicators
 
 import (
 	"strconv"
-	"sync"
 
 	"github.com/irfndi/goflux/pkg/decimal"
 	"github.com/irfndi/goflux/pkg/telemetry"
@@ -12,8 +11,7 @@ type emaIndicator struct {
 	indicator   Indicator
 	window      int
 	alpha       decimal.Decimal
-	resultCache resultCache
-	cacheMu     sync.RWMutex
+	resultCache *indicatorCache
 }
 
 // NewEMAIndicator returns a derivative indicator which returns the average of the current and preceding values in
@@ -26,7 +24,7 @@ func NewEMAIndicator(indicator Indicator, window int) Indicator {
 		indicator:   indicator,
 		window:      window,
 		alpha:       decimal.New(2).Div(decimal.NewFromInt(int64(window + 1))),
-		resultCache: make([]*decimal.Decimal, 0, defaultCacheSize),
+		resultCache: newIndicatorCache(),
 	}
 }
 
@@ -48,18 +46,20 @@ func (ema *emaIndicator) Calculate(index int) decimal.Decimal {
 	return result
 }
 
-func (ema *emaIndicator) cache() resultCache { return ema.resultCache }
-
-func (ema *emaIndicator) setCache(newCache resultCache) {
-	ema.resultCache = newCache
+func (ema *emaIndicator) ComputeInto(dst []decimal.Decimal) {
+	NewSimpleMovingAverage(ema.indicator, ema.window).(smaIndicator).ComputeInto(dst[:min(ema.window, len(dst))])
+	if len(dst) <= ema.window {
+		return
+	}
+	src := ComputeAll(ema.indicator, len(dst))
+	keep := decimal.ONE.Sub(ema.alpha)
+	for i := ema.window; i < len(dst); i++ {
+		dst[i] = src[i].Mul(ema.alpha).Add(dst[i-1].Mul(keep))
+	}
 }
 
-func (ema *emaIndicator) windowSize() int { return ema.window }
+func (ema *emaIndicator) indicatorCache() *indicatorCache { return ema.resultCache }
 
-func (ema *emaIndicator) cacheMutex() *sync.RWMutex {
-	return &ema.cacheMu
-}
+func (ema *emaIndicator) windowSize() int { return ema.window }
 
-func (ema *emaIndicator) maxCacheSize() int {
-	return defaultMaxCacheSize
-}
+func (ema *emaIndicator) Lookback() int { return Lookback(ema.indicator) + ema.window - 1 }
@@ -37,7 +37,7 @@ func TestExponentialMovingAverage(t *testing.T) {
 
 		emaStruct, ok := ema.(cachedIndicator)
 		assert.True(t, ok)
-		assert.EqualValues(t, 1001, len(emaStruct.cache()))
+		assert.EqualValues(t, 1001-19, GetCacheSize(emaStruct), "every index from the seed at 19 is cached")
 	})
 }
 
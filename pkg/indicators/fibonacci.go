@@ -170,3 +170,5 @@ func (f *FibonacciExtension) LevelsDown() FibonacciExtensionResult {
 		Level4236: f.Low.Sub(range_.Mul(decimal.New(3.236))),
 	}
 }
+
+func (f fibonacciRetracementIndicator) Lookback() int { return f.lookback - 1 }
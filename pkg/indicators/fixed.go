@@ -28,3 +28,7 @@ func (fi fixedDecimalIndicator) Calculate(index int) decimal.Decimal {
 	}
 	return fi[index]
 }
+
+func (fi fixedIndicator) Lookback() int { return 0 }
+
+func (fi fixedDecimalIndicator) Lookback() int { return 0 }
@@ -41,6 +41,19 @@ func (gli gainLossIndicator) Calculate(index int) decimal.Decimal {
 	return decimal.ZERO
 }
 
+func (gli gainLossIndicator) ComputeInto(dst []decimal.Decimal) {
+	src := ComputeAll(gli.Indicator, len(dst))
+	for i := range dst {
+		dst[i] = decimal.ZERO
+		if i == 0 {
+			continue
+		}
+		if delta := src[i].Sub(src[i-1]).Mul(gli.coefficient); delta.GT(decimal.ZERO) {
+			dst[i] = delta
+		}
+	}
+}
+
 type cumulativeIndicator struct {
 	Indicator
 	window int
@@ -99,3 +112,10 @@ func (pgi percentChangeIndicator) Calculate(index int) decimal.Decimal {
 	cplast := pgi.Indicator.Calculate(index - 1)
 	return cp.Div(cplast).Sub(decimal.ONE)
 }
+
+func (gli gainLossIndicator) Lookback() int { return Lookback(gli.Indicator) + 1 }
+
+// Lookback is the first index with a full window of changes
+func (ci cumulativeIndicator) Lookback() int { return Lookback(ci.Indicator) + safeWindow(ci.window) }
+
+func (pgi percentChangeIndicator) Lookback() int { return Lookback(pgi.Indicator) + 1 }
@@ -0,0 +1,294 @@
+package indicators
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// Graph builds indicators as a dependency graph. A node is identified by its
+// kind, parameters and input nodes, so asking for SMA(close, 20) from the
+// Bollinger bands, a standard deviation and a rule returns the same node, and
+// its value is calculated once per bar for all of them.
+//
+// Nodes must be added before they are used as inputs, which keeps the graph
+// acyclic. A Graph is safe for concurrent use, and so must be the indicators
+// given to Source where they share state, as the built-in indicators are. A
+// GraphBuilder must build only from its inputs.
+type Graph struct {
+	mu    sync.Mutex
+	nodes []*GraphNode
+	keys  map[string]*GraphNode
+}
+
+// NewGraph returns an empty indicator graph
+func NewGraph() *Graph {
+	return &Graph{keys: make(map[string]*GraphNode)}
+}
+
+// GraphNode is an indicator owned by a Graph. It memoizes every value it
+// calculates and serializes the calculations of the indicator it wraps, so
+// sibling nodes can read it concurrently whatever that indicator's own cache
+// does.
+type GraphNode struct {
+	id     int
+	key    string
+	depth  int
+	inputs []*GraphNode
+	ind    Indicator
+
+	mu     *sync.Mutex
+	values *indicatorCache
+}
+
+// GraphBuilder builds the indicator of a node from its input nodes
+type GraphBuilder func(inputs ...Indicator) Indicator
+
+// Node returns the node of kind with params over inputs, calling build the
+// first time that combination is asked for.
+func (g *Graph) Node(kind string, params []float64, inputs []*GraphNode, build GraphBuilder) *GraphNode {
+	var key strings.Builder
+	key.WriteString(kind)
+	if len(params) > 0 {
+		fmt.Fprintf(&key, "%v", params)
+	}
+	if len(inputs) > 0 {
+		ids := make([]string, len(inputs))
+		for i, in := range inputs {
+			ids[i] = fmt.Sprint(in.id)
+		}
+		fmt.Fprintf(&key, "(%s)", strings.Join(ids, ","))
+	}
+	return g.add(key.String(), inputs, build)
+}
+
+// add returns the node of key, building it over inputs if it is new
+func (g *Graph) add(key string, inputs []*GraphNode, build GraphBuilder) *GraphNode {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if node, ok := g.keys[key]; ok {
+		return node
+	}
+
+	args := make([]Indicator, len(inputs))
+	depth := 0
+	for i, in := range inputs {
+		args[i] = in
+		depth = max(depth, in.depth+1)
+	}
+	node := &GraphNode{
+		id:     len(g.nodes),
+		key:    key,
+		depth:  depth,
+		inputs: inputs,
+		ind:    build(args...),
+		mu:     new(sync.Mutex),
+		values: newIndicatorCache(),
+	}
+	g.nodes = append(g.nodes, node)
+	g.keys[node.key] = node
+	return node
+}
+
+// Source returns the node called name, adding ind under that name if it is
+// new. Later calls with the same name return the first node. A node is
+// already in a graph and is returned as it is. The nodes of the graph held
+// among the fields of ind, such as the input of NewSimpleMovingAverage(node,
+// 5), become the inputs of the new node, which is calculated after them.
+func (g *Graph) Source(name string, ind Indicator) *GraphNode {
+	if node, ok := ind.(*GraphNode); ok {
+		return node
+	}
+	return g.add(name, g.heldNodes(ind), func(...Indicator) Indicator { return ind })
+}
+
+var graphNodeType = reflect.TypeOf((*GraphNode)(nil))
+
+// heldNodes returns the nodes of the graph reachable through the fields of
+// ind, in the order they were added
+func (g *Graph) heldNodes(ind Indicator) []*GraphNode {
+	nodes := g.Nodes()
+	ids := make(map[uintptr]int, len(nodes))
+	for _, node := range nodes {
+		ids[reflect.ValueOf(node).Pointer()] = node.id
+	}
+	held := make([]bool, len(nodes))
+	reachable(reflect.ValueOf(ind), graphNodeType, func(v reflect.Value) {
+		if id, ok := ids[v.Pointer()]; ok {
+			held[id] = true
+		}
+	}, make(map[uintptr]bool))
+
+	var inputs []*GraphNode
+	for id, ok := range held {
+		if ok {
+			inputs = append(inputs, nodes[id])
+		}
+	}
+	return inputs
+}
+
+// ClosePrice returns the close price node of s
+func (g *Graph) ClosePrice(s *series.TimeSeries) *GraphNode {
+	return g.Source(fmt.Sprintf("close@%p", s), NewClosePriceIndicator(s))
+}
+
+// SMA returns the simple moving average node of input over window
+func (g *Graph) SMA(input *GraphNode, window int) *GraphNode {
+	return g.Node("sma", []float64{float64(window)}, []*GraphNode{input}, func(in ...Indicator) Indicator {
+		return NewSimpleMovingAverage(in[0], window)
+	})
+}
+
+// EMA returns the exponential moving average node of input over window
+func (g *Graph) EMA(input *GraphNode, window int) *GraphNode {
+	return g.Node("ema", []float64{float64(window)}, []*GraphNode{input}, func(in ...Indicator) Indicator {
+		return NewEMAIndicator(in[0], window)
+	})
+}
+
+// StdDev returns the windowed standard deviation node of input, sharing the
+// SMA node of the same window.
+func (g *Graph) StdDev(input *GraphNode, window int) *GraphNode {
+	sma := g.SMA(input, window)
+	return g.Node("stddev", []float64{float64(window)}, []*GraphNode{input, sma}, func(in ...Indicator) Indicator {
+		return windowedStandardDeviationIndicator{Indicator: in[0], movingAverage: in[1], window: window}
+	})
+}
+
+// BollingerBands returns the middle, upper and lower band nodes of input,
+// built on the shared SMA and StdDev nodes of the same window.
+func (g *Graph) BollingerBands(input *GraphNode, window int, sigma float64) (middle, upper, lower *GraphNode) {
+	middle = g.SMA(input, window)
+	stdev := g.StdDev(input, window)
+	band := func(kind string, muladd float64) *GraphNode {
+		return g.Node(kind, []float64{sigma}, []*GraphNode{middle, stdev}, func(in ...Indicator) Indicator {
+			return bbandIndicator{ma: in[0], stdev: in[1], muladd: decimal.New(muladd)}
+		})
+	}
+	return middle, band("bbands.upper", sigma), band("bbands.lower", -sigma)
+}
+
+// Indicator returns the node of the registered indicator name (see
+// NewIndicatorByName) applied to input, or to the close price of s if input
+// is nil. The registry builds the whole indicator, so only nodes with the
+// same name, parameters and input are shared; the Graph builders share their
+// intermediates as well.
+func (g *Graph) Indicator(name string, s *series.TimeSeries, input *GraphNode, params map[string]float64) (*GraphNode, error) {
+	if input == nil && s != nil {
+		input = g.ClosePrice(s)
+	}
+	key := name
+	if dot := strings.IndexByte(name, '.'); dot >= 0 {
+		key = name[:dot]
+	}
+	spec, ok := LookupIndicator(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIndicator, key)
+	}
+	resolved, err := spec.ResolveParams(params)
+	if err != nil {
+		return nil, err
+	}
+	ind, err := NewIndicatorByNameFrom(name, s, input, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, len(spec.Params))
+	for i, p := range spec.Params {
+		values[i] = resolved[p.Name]
+	}
+	return g.Node(fmt.Sprintf("%s@%p", name, s), values, []*GraphNode{input}, func(...Indicator) Indicator {
+		return ind
+	}), nil
+}
+
+// Len returns the number of distinct nodes in the graph
+func (g *Graph) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.nodes)
+}
+
+// Nodes returns the nodes of the graph in the order they were added, which is
+// a topological order.
+func (g *Graph) Nodes() []*GraphNode {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]*GraphNode(nil), g.nodes...)
+}
+
+// Evaluate calculates every node at index and returns the values in the order
+// of Nodes. Nodes are calculated level by level, inputs first; the nodes of a
+// level do not depend on one another and are calculated concurrently.
+func (g *Graph) Evaluate(index int) []decimal.Decimal {
+	nodes := g.Nodes()
+	var levels [][]*GraphNode
+	for _, node := range nodes {
+		for len(levels) <= node.depth {
+			levels = append(levels, nil)
+		}
+		levels[node.depth] = append(levels[node.depth], node)
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	for _, level := range levels {
+		if len(level) == 1 || workers == 1 {
+			for _, node := range level {
+				node.Calculate(index)
+			}
+			continue
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, workers)
+		for _, node := range level {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(node *GraphNode) {
+				defer wg.Done()
+				node.Calculate(index)
+				<-sem
+			}(node)
+		}
+		wg.Wait()
+	}
+
+	results := make([]decimal.Decimal, len(nodes))
+	for i, node := range nodes {
+		results[i] = node.Calculate(index)
+	}
+	return results
+}
+
+// Calculate returns the value of the node at index, calculating it once
+func (n *GraphNode) Calculate(index int) decimal.Decimal {
+	if v, ok := n.values.get(index); ok {
+		return v
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if v, ok := n.values.get(index); ok {
+		return v
+	}
+	v := n.ind.Calculate(index)
+	n.values.set(index, v)
+	return v
+}
+
+// Key returns the identity of the node: its kind, parameters and input node
+// ids.
+func (n *GraphNode) Key() string { return n.key }
+
+// Inputs returns the nodes this node is calculated from
+func (n *GraphNode) Inputs() []*GraphNode { return append([]*GraphNode(nil), n.inputs...) }
+
+// Indicator returns the indicator the node wraps
+func (n *GraphNode) Indicator() Indicator { return n.ind }
+
+func (n *GraphNode) Lookback() int { return Lookback(n.ind) }
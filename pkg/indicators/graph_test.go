@@ -0,0 +1,182 @@
+package indicators_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+type countingIndicator struct {
+	indicators.Indicator
+	calls *atomic.Int64
+}
+
+func (c countingIndicator) Calculate(index int) decimal.Decimal {
+	c.calls.Add(1)
+	return c.Indicator.Calculate(index)
+}
+
+func TestGraph_DeduplicatesSubIndicators(t *testing.T) {
+	ts := testutils.RandomTimeSeries(200)
+	g := indicators.NewGraph()
+
+	closePrice := g.ClosePrice(ts)
+	sma := g.SMA(closePrice, 20)
+	middle, upper, lower := g.BollingerBands(g.ClosePrice(ts), 20, 2)
+	stdev := g.StdDev(closePrice, 20)
+
+	assert.Same(t, sma, middle)
+	assert.Equal(t, 5, g.Len(), "close, SMA, standard deviation and two bands")
+	assert.Equal(t, []*indicators.GraphNode{closePrice, sma}, stdev.Inputs())
+	assert.NotEqual(t, upper.Key(), lower.Key())
+	assert.Same(t, g.EMA(closePrice, 10), g.EMA(closePrice, 10))
+	assert.NotSame(t, g.EMA(closePrice, 10), g.EMA(closePrice, 12))
+
+	close := indicators.NewClosePriceIndicator(ts)
+	want := map[string]indicators.Indicator{
+		"sma":   indicators.NewSimpleMovingAverage(close, 20),
+		"upper": indicators.NewBollingerUpperBandIndicator(close, 20, 2),
+		"lower": indicators.NewBollingerLowerBandIndicator(close, 20, 2),
+		"stdev": indicators.NewWindowedStandardDeviationIndicator(close, 20),
+	}
+	got := map[string]indicators.Indicator{"sma": sma, "upper": upper, "lower": lower, "stdev": stdev}
+	for i := 0; i < 200; i++ {
+		for name, ind := range want {
+			assertSameValue(t, name, i, ind.Calculate(i), got[name].Calculate(i))
+		}
+	}
+	assert.Equal(t, 19, indicators.Lookback(upper))
+}
+
+func TestGraph_EvaluatesEachNodeOncePerBar(t *testing.T) {
+	ts := testutils.RandomTimeSeries(100)
+	g := indicators.NewGraph()
+
+	var calls atomic.Int64
+	source := g.Source("counted", countingIndicator{indicators.NewClosePriceIndicator(ts), &calls})
+	for _, window := range []int{1, 5, 10} {
+		g.BollingerBands(source, window, 2)
+		g.EMA(source, window)
+	}
+
+	nodes := g.Nodes()
+	results := make([][]decimal.Decimal, 100)
+	for i := range results {
+		results[i] = g.Evaluate(i)
+		require.Len(t, results[i], len(nodes))
+	}
+	assert.EqualValues(t, 100, calls.Load(), "the source is calculated once per bar")
+
+	for i, values := range results {
+		for j, node := range nodes {
+			assertSameValue(t, node.Key(), i, node.Indicator().Calculate(i), values[j])
+		}
+	}
+}
+
+func TestGraph_Indicator(t *testing.T) {
+	ts := testutils.RandomTimeSeries(60)
+	g := indicators.NewGraph()
+
+	rsi, err := g.Indicator("rsi", ts, nil, map[string]float64{"window": 14})
+	require.NoError(t, err)
+	again, err := g.Indicator("rsi", ts, g.ClosePrice(ts), nil)
+	require.NoError(t, err)
+	assert.Same(t, rsi, again, "defaults resolve to the same node")
+
+	want := indicators.NewRelativeStrengthIndexIndicator(indicators.NewClosePriceIndicator(ts), 14)
+	for i := 0; i < 60; i++ {
+		assertSameValue(t, "RSI(14)", i, want.Calculate(i), rsi.Calculate(i))
+	}
+
+	_, err = g.Indicator("nope", ts, nil, nil)
+	assert.ErrorIs(t, err, indicators.ErrUnknownIndicator)
+	_, err = g.Indicator("rsi", ts, nil, map[string]float64{"window": -1})
+	assert.Error(t, err)
+}
+
+func TestGraph_ConcurrentReaders(t *testing.T) {
+	ts := testutils.RandomTimeSeries(300)
+	g := indicators.NewGraph()
+	closePrice := g.ClosePrice(ts)
+	ema := g.EMA(closePrice, 10)
+	_, upper, _ := g.BollingerBands(closePrice, 20, 2)
+
+	want := indicators.ComputeAll(indicators.NewEMAIndicator(indicators.NewClosePriceIndicator(ts), 10), 300)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 299; i >= 0; i-- {
+				upper.Calculate(i)
+				ema.Calculate(i)
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 300; i++ {
+		assertSameValue(t, "EMA(10)", i, want[i], ema.Calculate(i))
+	}
+}
+
+// Run with -race: both sources read the same OBV and its cache
+func TestGraph_SourcesSharingState(t *testing.T) {
+	ts := testutils.RandomTimeSeries(200)
+	obv := indicators.NewOBVIndicator(ts)
+	g := indicators.NewGraph()
+	obvNode := g.Source("obv", obv)
+	smaNode := g.Source("obv_sma", indicators.NewSimpleMovingAverage(obv, 5))
+	g.EMA(obvNode, 10)
+	g.EMA(smaNode, 10)
+	assert.Same(t, obvNode, g.Source("again", obvNode))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				g.Evaluate(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := indicators.ComputeAll(indicators.NewSimpleMovingAverage(indicators.NewOBVIndicator(ts), 5), 200)
+	for i := 0; i < 200; i++ {
+		assertSameValue(t, "SMA(OBV)", i, want[i], smaNode.Calculate(i))
+	}
+}
+
+func TestGraph_SourceReadingNodes(t *testing.T) {
+	ts := testutils.RandomTimeSeries(50)
+	g := indicators.NewGraph()
+	obv := g.Source("obv", indicators.NewOBVIndicator(ts))
+	sma := g.Source("obv_sma", indicators.NewSimpleMovingAverage(obv, 5))
+	ema := g.EMA(sma, 3)
+	assert.Equal(t, []*indicators.GraphNode{obv}, sma.Inputs())
+
+	done := make(chan decimal.Decimal)
+	go func() { done <- sma.Calculate(10) }()
+	select {
+	case got := <-done:
+		want := indicators.NewSimpleMovingAverage(indicators.NewOBVIndicator(ts), 5).Calculate(10)
+		assertSameValue(t, "SMA(OBV)", 10, want, got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("a source reading another node deadlocked")
+	}
+
+	values := g.Evaluate(20)
+	want := indicators.NewEMAIndicator(indicators.NewSimpleMovingAverage(indicators.NewOBVIndicator(ts), 5), 3).Calculate(20)
+	assertSameValue(t, "EMA(SMA(OBV))", 20, want, values[2])
+	assert.Same(t, ema, g.Nodes()[2])
+}
@@ -0,0 +1,255 @@
+package indicators
+
+import (
+	"math"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+// The Hilbert transform indicators follow the C code of TA-Lib's HT_*
+// functions: the price is smoothed by a 4-bar WMA, split into in-phase and
+// quadrature components by Ehlers' Hilbert transform and measured for its
+// dominant cycle. The state is started 12 bars into the input, as TA-Lib
+// starts it when computing over the whole series, and each output is NaN
+// before the TA-Lib lookback. They are tested against a port of that code in
+// reftest, not against output of TA-Lib itself.
+
+const (
+	htStart          = 12
+	htPeriodLookback = 32
+	htPhaseLookback  = 63
+	htA              = 0.0962
+	htB              = 0.5769
+	htRad2Deg        = 180 / math.Pi
+)
+
+// hilbertState is the state of the transform after one bar
+type hilbertState struct {
+	smooth       float64
+	detrender    float64
+	i1           float64
+	q1           float64
+	i2           float64
+	q2           float64
+	re           float64
+	im           float64
+	period       float64
+	smoothPeriod float64
+	dcPhase      float64
+	sine         float64
+	leadSine     float64
+	// trend is the mean price over the dominant cycle and trendline its
+	// 4-bar WMA
+	trend       float64
+	trendline   float64
+	daysInTrend int
+	trendMode   float64
+}
+
+// hilbertCore runs the transform bar by bar and memoizes the state, shared by
+// the outputs of an HT indicator
+type hilbertCore struct {
+	indicator Indicator
+	base      int
+
+	mu     sync.Mutex
+	states []hilbertState
+}
+
+func newHilbertCore(indicator Indicator) *hilbertCore {
+	return &hilbertCore{indicator: indicator, base: Lookback(indicator)}
+}
+
+func (h *hilbertCore) price(index int) float64 {
+	if index < 0 {
+		return 0
+	}
+	return h.indicator.Calculate(index).Float()
+}
+
+// state returns the state at index, which must be at least base+htStart
+func (h *hilbertCore) state(index int) hilbertState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := h.base + htStart + len(h.states); i <= index; i++ {
+		h.states = append(h.states, h.step(i))
+	}
+	return h.states[index-h.base-htStart]
+}
+
+// past returns the state lag bars before the bar at i being computed, zero
+// before the transform starts
+func (h *hilbertCore) past(i, lag int) hilbertState {
+	k := i - lag - h.base - htStart
+	if k < 0 {
+		return hilbertState{}
+	}
+	return h.states[k]
+}
+
+func (h *hilbertCore) step(i int) hilbertState {
+	prev := h.past(i, 1)
+	adjustedPrevPeriod := 0.075*prev.period + 0.54
+	transform := func(x0 float64, field func(hilbertState) float64) float64 {
+		x2, x4, x6 := field(h.past(i, 2)), field(h.past(i, 4)), field(h.past(i, 6))
+		return (htA*x0 + htB*x2 - htB*x4 - htA*x6) * adjustedPrevPeriod
+	}
+
+	s := hilbertState{}
+	s.smooth = (4*h.price(i) + 3*h.price(i-1) + 2*h.price(i-2) + h.price(i-3)) / 10
+	s.detrender = transform(s.smooth, func(st hilbertState) float64 { return st.smooth })
+	s.q1 = transform(s.detrender, func(st hilbertState) float64 { return st.detrender })
+	// The in-phase component is the detrender delayed by 3 bars
+	s.i1 = h.past(i, 3).detrender
+	jI := transform(s.i1, func(st hilbertState) float64 { return st.i1 })
+	jQ := transform(s.q1, func(st hilbertState) float64 { return st.q1 })
+
+	s.q2 = 0.2*(s.q1+jI) + 0.8*prev.q2
+	s.i2 = 0.2*(s.i1-jQ) + 0.8*prev.i2
+	s.re = 0.2*(s.i2*prev.i2+s.q2*prev.q2) + 0.8*prev.re
+	s.im = 0.2*(s.i2*prev.q2-s.q2*prev.i2) + 0.8*prev.im
+
+	s.period = prev.period
+	if s.im != 0 && s.re != 0 {
+		s.period = 360 / (math.Atan(s.im/s.re) * htRad2Deg)
+	}
+	s.period = math.Max(math.Min(s.period, 1.5*prev.period), 0.67*prev.period)
+	s.period = math.Max(math.Min(s.period, 50), 6)
+	s.period = 0.2*s.period + 0.8*prev.period
+	s.smoothPeriod = 0.33*s.period + 0.67*prev.smoothPeriod
+
+	// Dominant cycle phase from a DFT of the smoothed price over one cycle
+	cycle := int(s.smoothPeriod + 0.5)
+	realPart, imagPart := 0.0, 0.0
+	for n := 0; n < cycle; n++ {
+		angle := float64(n) * 2 * math.Pi / float64(cycle)
+		smooth := s.smooth
+		if n > 0 {
+			smooth = h.past(i, n).smooth
+		}
+		realPart += math.Sin(angle) * smooth
+		imagPart += math.Cos(angle) * smooth
+	}
+	s.dcPhase = prev.dcPhase
+	if math.Abs(imagPart) > 0 {
+		s.dcPhase = math.Atan(realPart/imagPart) * htRad2Deg
+	} else if realPart < 0 {
+		s.dcPhase -= 90
+	} else if realPart > 0 {
+		s.dcPhase += 90
+	}
+	s.dcPhase += 90
+	// Compensate for the one bar lag of the WMA
+	s.dcPhase += 360 / s.smoothPeriod
+	if imagPart < 0 {
+		s.dcPhase += 180
+	}
+	if s.dcPhase > 315 {
+		s.dcPhase -= 360
+	}
+	s.sine = math.Sin(s.dcPhase / htRad2Deg)
+	s.leadSine = math.Sin((s.dcPhase + 45) / htRad2Deg)
+
+	// Instantaneous trendline: the WMA of the mean price over each cycle
+	for n := 0; n < cycle && i-n >= 0; n++ {
+		s.trend += h.price(i - n)
+	}
+	if cycle > 0 {
+		s.trend /= float64(cycle)
+	}
+	s.trendline = (4*s.trend + 3*prev.trend + 2*h.past(i, 2).trend + h.past(i, 3).trend) / 10
+
+	// Trend mode unless the sine wave crossed within half a cycle, or the
+	// phase advances at the rate of the cycle; always a trend when price is
+	// 1.5% away from the trendline
+	trend := true
+	s.daysInTrend = prev.daysInTrend
+	if s.sine > s.leadSine && prev.sine <= prev.leadSine || s.sine < s.leadSine && prev.sine >= prev.leadSine {
+		s.daysInTrend, trend = 0, false
+	}
+	s.daysInTrend++
+	if float64(s.daysInTrend) < 0.5*s.smoothPeriod {
+		trend = false
+	}
+	if delta := s.dcPhase - prev.dcPhase; s.smoothPeriod != 0 && delta > 0.67*360/s.smoothPeriod && delta < 1.5*360/s.smoothPeriod {
+		trend = false
+	}
+	if s.trendline != 0 && math.Abs((s.smooth-s.trendline)/s.trendline) >= 0.015 {
+		trend = true
+	}
+	if trend {
+		s.trendMode = 1
+	}
+	return s
+}
+
+// hilbertLine is one output of a hilbertCore
+type hilbertLine struct {
+	core     *hilbertCore
+	lookback int
+	value    func(hilbertState) float64
+}
+
+func (l hilbertLine) Calculate(index int) decimal.Decimal {
+	if index < l.Lookback() {
+		return decimal.NaN
+	}
+	return decimal.New(l.value(l.core.state(index)))
+}
+
+func (l hilbertLine) Lookback() int { return l.core.base + l.lookback }
+
+func newHilbertLine(indicator Indicator, lookback int, value func(hilbertState) float64) Indicator {
+	return hilbertLine{core: newHilbertCore(indicator), lookback: lookback, value: value}
+}
+
+// NewHTDCPeriodIndicator returns TA-Lib's HT_DCPERIOD, the Hilbert transform
+// dominant cycle period in bars
+func NewHTDCPeriodIndicator(indicator Indicator) Indicator {
+	return newHilbertLine(indicator, htPeriodLookback, func(s hilbertState) float64 { return s.smoothPeriod })
+}
+
+// NewHTDCPhaseIndicator returns TA-Lib's HT_DCPHASE, the phase of the
+// dominant cycle in degrees from -45 to 315
+func NewHTDCPhaseIndicator(indicator Indicator) Indicator {
+	return newHilbertLine(indicator, htPhaseLookback, func(s hilbertState) float64 { return s.dcPhase })
+}
+
+var (
+	htPhasorOutputs = []string{"inphase", "quadrature"}
+	htSineOutputs   = []string{"sine", "leadsine"}
+)
+
+// NewHTPhasorIndicator returns TA-Lib's HT_PHASOR, the in-phase and
+// quadrature components of the detrended price, as a MultiOutputIndicator
+// with outputs inphase and quadrature
+func NewHTPhasorIndicator(indicator Indicator) MultiOutputIndicator {
+	core := newHilbertCore(indicator)
+	return NewMultiOutputIndicator(htPhasorOutputs,
+		hilbertLine{core, htPeriodLookback, func(s hilbertState) float64 { return s.i1 }},
+		hilbertLine{core, htPeriodLookback, func(s hilbertState) float64 { return s.q1 }})
+}
+
+// NewHTSineIndicator returns TA-Lib's HT_SINE, Ehlers' MESA Sine Wave: the
+// sine of the dominant cycle phase and the lead sine 45 degrees ahead of it,
+// as a MultiOutputIndicator with outputs sine and leadsine. The lines cross
+// at cycle turning points and run apart in a trend.
+func NewHTSineIndicator(indicator Indicator) MultiOutputIndicator {
+	core := newHilbertCore(indicator)
+	return NewMultiOutputIndicator(htSineOutputs,
+		hilbertLine{core, htPhaseLookback, func(s hilbertState) float64 { return s.sine }},
+		hilbertLine{core, htPhaseLookback, func(s hilbertState) float64 { return s.leadSine }})
+}
+
+// NewHTTrendlineIndicator returns TA-Lib's HT_TRENDLINE, the instantaneous
+// trendline: a WMA of the mean price over the dominant cycle
+func NewHTTrendlineIndicator(indicator Indicator) Indicator {
+	return newHilbertLine(indicator, htPhaseLookback, func(s hilbertState) float64 { return s.trendline })
+}
+
+// NewHTTrendModeIndicator returns TA-Lib's HT_TRENDMODE: 1 while the market
+// trends and 0 while it cycles
+func NewHTTrendModeIndicator(indicator Indicator) Indicator {
+	return newHilbertLine(indicator, htPhaseLookback, func(s hilbertState) float64 { return s.trendMode })
+}
@@ -65,3 +65,8 @@ func (h *hmaIndicator) fillRawHMACache(index int) {
 		h.rawHMACache = append(h.rawHMACache, rawHMA)
 	}
 }
+
+func (h *hmaIndicator) Lookback() int {
+	sqrtWindow := max(int(math.Sqrt(float64(h.window))), 1)
+	return Lookback(h.indicator) + max(h.window, 1) + sqrtWindow - 2
+}
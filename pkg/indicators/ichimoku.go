@@ -5,8 +5,10 @@ import (
 	"github.com/irfndi/goflux/pkg/series"
 )
 
+// IchimokuIndicator is an Ichimoku Kinko Hyo indicator. Calculate returns the
+// Tenkan-sen; the outputs are tenkan, kijun, senkou_a, senkou_b and chikou.
 type IchimokuIndicator interface {
-	Indicator
+	MultiOutputIndicator
 	TenkanSen(index int) decimal.Decimal
 	KijunSen(index int) decimal.Decimal
 	SenkouSpanA(index int) decimal.Decimal
@@ -41,6 +43,33 @@ func (i *ichimokuIndicator) Calculate(index int) decimal.Decimal {
 	return i.calculateTenkanSen(index)
 }
 
+// ichimokuOutputs are the output names of IchimokuIndicator
+var ichimokuOutputs = []string{"tenkan", "kijun", "senkou_a", "senkou_b", "chikou"}
+
+func ichimokuLines(ich IchimokuIndicator) outputLines {
+	return newOutputLines(ichimokuOutputs,
+		withLookback{indicatorFunc(ich.TenkanSen), 8},
+		withLookback{indicatorFunc(ich.KijunSen), 25},
+		withLookback{indicatorFunc(ich.SenkouSpanA), 25},
+		withLookback{indicatorFunc(ich.SenkouSpanB), 51},
+		indicatorFunc(ich.ChikouSpan))
+}
+
+// Outputs returns the Ichimoku line names
+func (i *ichimokuIndicator) Outputs() []string {
+	return ichimokuLines(i).Outputs()
+}
+
+// Output returns the Ichimoku line named name, or nil
+func (i *ichimokuIndicator) Output(name string) Indicator {
+	return ichimokuLines(i).Output(name)
+}
+
+// CalculateAll returns every Ichimoku line at index
+func (i *ichimokuIndicator) CalculateAll(index int) map[string]decimal.Decimal {
+	return ichimokuLines(i).CalculateAll(index)
+}
+
 func (i *ichimokuIndicator) TenkanSen(index int) decimal.Decimal {
 	return i.calculateTenkanSen(index)
 }
@@ -117,3 +146,6 @@ func (i *ichimokuIndicator) Cloud(index int) IchimokuCloudResult {
 		ChikouSpan:  i.calculateChikouSpan(index),
 	}
 }
+
+// Lookback is the warm-up of the Tenkan-sen returned by Calculate
+func (i *ichimokuIndicator) Lookback() int { return i.period9 - 1 }
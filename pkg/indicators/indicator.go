@@ -16,7 +16,6 @@ type GenericIndicator[T any] interface {
 
 // SelfDescribingIndicator is an Indicator that can describe its requirements and properties
 type SelfDescribingIndicator interface {
-	Indicator
-	Lookback() int
+	LookbackIndicator
 	Metadata() IndicatorMetadata
 }
@@ -0,0 +1,157 @@
+package indicators
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/kalman"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+var (
+	kalmanTrendOutputs      = []string{"level", "velocity"}
+	kalmanRegressionOutputs = []string{"beta", "intercept", "spread", "spread_std"}
+)
+
+func formatNoise(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+// NewKalmanFilterIndicator returns the Kalman filter estimate of the level of
+// indicator, modelled as a random walk whose steps have variance processNoise,
+// observed with variance measurementNoise. Only their ratio matters: the
+// smaller processNoise is relative to measurementNoise, the smoother and
+// slower the estimate. The filter starts at the first ready value. Panics if
+// indicator is nil or either noise is not positive.
+func NewKalmanFilterIndicator(indicator Indicator, processNoise, measurementNoise float64) Indicator {
+	checkKalmanInputs("KalmanFilter", indicator, processNoise, measurementNoise)
+	model := kalman.Model{
+		F: [][]float64{{1}},
+		H: [][]float64{{1}},
+		Q: [][]float64{{processNoise}},
+		R: [][]float64{{measurementNoise}},
+	}
+	f := newRecursiveFilter(indicator)
+	var kf *kalman.Filter
+	f.step = func(i int) []float64 {
+		z := f.price(i)
+		if kf == nil {
+			kf = kalman.NewFilter(model, []float64{z}, [][]float64{{measurementNoise}})
+		} else {
+			kf.Predict()
+		}
+		return observe(kf, z)
+	}
+	return filterLine{filter: f}
+}
+
+// observe corrects kf with z and returns its state. Update fails only on a
+// singular innovation covariance, which a positive measurement noise rules
+// out, and would leave the state at the prediction.
+func observe(kf *kalman.Filter, z float64) []float64 {
+	_, _, _ = kf.Update([]float64{z})
+	return kf.State()
+}
+
+// NewKalmanTrendIndicator returns the Kalman filter estimate of a local
+// linear trend in indicator, as a MultiOutputIndicator with outputs level and
+// velocity, the change of the level per bar. The trend is modelled with
+// random accelerations of variance processNoise, observed with variance
+// measurementNoise. Calculate returns the level. Panics if indicator is nil or
+// either noise is not positive.
+func NewKalmanTrendIndicator(indicator Indicator, processNoise, measurementNoise float64) MultiOutputIndicator {
+	checkKalmanInputs("KalmanTrend", indicator, processNoise, measurementNoise)
+	model := kalman.Model{
+		F: [][]float64{{1, 1}, {0, 1}},
+		H: [][]float64{{1, 0}},
+		Q: [][]float64{{processNoise / 4, processNoise / 2}, {processNoise / 2, processNoise}},
+		R: [][]float64{{measurementNoise}},
+	}
+	f := newRecursiveFilter(indicator)
+	var kf *kalman.Filter
+	f.step = func(i int) []float64 {
+		z := f.price(i)
+		if kf == nil {
+			kf = kalman.NewFilter(model, []float64{z, 0}, [][]float64{{measurementNoise, 0}, {0, measurementNoise}})
+		} else {
+			kf.Predict()
+		}
+		return observe(kf, z)
+	}
+	return NewMultiOutputIndicator(kalmanTrendOutputs, filterLine{filter: f}, filterLine{filter: f, line: 1, lookback: 1})
+}
+
+// NewKalmanRegressionIndicator returns a dynamic regression of y on x, whose
+// slope and intercept follow random walks, as a MultiOutputIndicator with
+// outputs beta, the time-varying hedge ratio, intercept, spread and
+// spread_std. delta sets how fast the coefficients may move, from near 0 for
+// a nearly fixed regression towards 1; measurementNoise is the variance of y
+// about the regression line. The spread is y less its prediction from the
+// coefficients before the bar, and spread_std its predicted standard
+// deviation, so spread / spread_std is a z-score for pairs trading free of
+// look-ahead; beta and intercept include the bar. Calculate returns beta.
+// Panics if y or x is nil, delta is not in (0, 1) or measurementNoise is not
+// positive.
+func NewKalmanRegressionIndicator(y, x Indicator, delta, measurementNoise float64) MultiOutputIndicator {
+	if y == nil || x == nil {
+		panic("goflux: KalmanRegression indicators cannot be nil")
+	}
+	if delta <= 0 || delta >= 1 {
+		panic("goflux: KalmanRegression delta must be in (0, 1)")
+	}
+	if measurementNoise <= 0 {
+		panic("goflux: KalmanRegression measurement noise must be > 0")
+	}
+	telemetry.ReportUsage("KalmanRegression", map[string]string{
+		"delta":             formatNoise(delta),
+		"measurement_noise": formatNoise(measurementNoise),
+	})
+
+	q := delta / (1 - delta)
+	model := kalman.Model{
+		F: [][]float64{{1, 0}, {0, 1}},
+		H: [][]float64{{0, 1}},
+		Q: [][]float64{{q, 0}, {0, q}},
+		R: [][]float64{{measurementNoise}},
+	}
+	f := newRecursiveFilter(y)
+	f.start = MaxLookback(y, x)
+	kf := kalman.NewFilter(model, []float64{0, 0}, [][]float64{{0, 0}, {0, 0}})
+	f.step = func(i int) []float64 {
+		kf.Predict()
+		h := [][]float64{{x.Calculate(i).Float(), 1}}
+		innovation, covariance, err := kf.UpdateWith(h, []float64{f.price(i)})
+		state := kf.State()
+		if err != nil {
+			return []float64{state[0], state[1], math.NaN(), math.NaN()}
+		}
+		return []float64{state[0], state[1], innovation[0], math.Sqrt(covariance[0][0])}
+	}
+	return NewMultiOutputIndicator(kalmanRegressionOutputs,
+		kalmanLine{filterLine{filter: f}},
+		kalmanLine{filterLine{filter: f, line: 1}},
+		kalmanLine{filterLine{filter: f, line: 2}},
+		kalmanLine{filterLine{filter: f, line: 3}})
+}
+
+// kalmanLine is a filterLine that may be NaN
+type kalmanLine struct{ filterLine }
+
+func (l kalmanLine) Calculate(index int) decimal.Decimal {
+	if index < l.filter.start {
+		return decimal.ZERO
+	}
+	return finiteDecimal(l.filter.at(index)[l.line])
+}
+
+func checkKalmanInputs(name string, indicator Indicator, processNoise, measurementNoise float64) {
+	if indicator == nil {
+		panic("goflux: " + name + " indicator cannot be nil")
+	}
+	if processNoise <= 0 || measurementNoise <= 0 {
+		panic("goflux: " + name + " noise variances must be > 0")
+	}
+	telemetry.ReportUsage(name, map[string]string{
+		"process_noise":     formatNoise(processNoise),
+		"measurement_noise": formatNoise(measurementNoise),
+	})
+}
@@ -0,0 +1,84 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+)
+
+func TestKalmanFilterIndicator(t *testing.T) {
+	step := make([]float64, 60)
+	for i := range step {
+		step[i] = 10
+		if i >= 20 {
+			step[i] = 20
+		}
+	}
+	fast := indicators.NewKalmanFilterIndicator(closes(step...), 1, 1)
+	slow := indicators.NewKalmanFilterIndicator(closes(step...), 0.01, 1)
+
+	assert.InDelta(t, 10, fast.Calculate(0).Float(), 1e-9)
+	assert.InDelta(t, 10, slow.Calculate(19).Float(), 1e-9)
+	// Both follow the step, the slower filter with more lag
+	assert.Greater(t, fast.Calculate(22).Float(), slow.Calculate(22).Float())
+	assert.Less(t, slow.Calculate(22).Float(), 20.0)
+	assert.InDelta(t, 20, fast.Calculate(59).Float(), 1e-3)
+	assert.Equal(t, 0, indicators.Lookback(fast))
+
+	assert.Panics(t, func() { indicators.NewKalmanFilterIndicator(nil, 1, 1) })
+	assert.Panics(t, func() { indicators.NewKalmanFilterIndicator(closes(step...), 0, 1) })
+}
+
+func TestKalmanTrendIndicator(t *testing.T) {
+	ramp := make([]float64, 100)
+	for i := range ramp {
+		ramp[i] = 50 + 2*float64(i)
+	}
+	trend := indicators.NewKalmanTrendIndicator(closes(ramp...), 0.01, 1)
+	require.Equal(t, []string{"level", "velocity"}, trend.Outputs())
+
+	assert.InDelta(t, 2, trend.Output("velocity").Calculate(99).Float(), 1e-3)
+	assert.InDelta(t, ramp[99], trend.Calculate(99).Float(), 1e-2)
+	assert.Equal(t, 1, indicators.Lookback(trend.Output("velocity")))
+}
+
+func TestKalmanRegressionIndicator(t *testing.T) {
+	// The hedge ratio moves from 1.5 to 2 halfway
+	n := 400
+	x, y := make([]float64, n), make([]float64, n)
+	for i := range x {
+		x[i] = 100 + 10*math.Sin(float64(i)/5)
+		beta := 1.5
+		if i >= n/2 {
+			beta = 2
+		}
+		y[i] = beta*x[i] + 3
+	}
+
+	reg := indicators.NewKalmanRegressionIndicator(closes(y...), closes(x...), 1e-4, 1e-3)
+	require.Equal(t, []string{"beta", "intercept", "spread", "spread_std"}, reg.Outputs())
+	beta, intercept := reg.Output("beta"), reg.Output("intercept")
+
+	// The fit of y = beta*x + intercept is exact before and after the change
+	fit := func(i int) float64 {
+		return beta.Calculate(i).Float()*x[i] + intercept.Calculate(i).Float()
+	}
+	assert.InDelta(t, y[n/2-1], fit(n/2-1), 0.05)
+	assert.InDelta(t, 2, beta.Calculate(n-1).Float(), 0.05)
+	assert.InDelta(t, y[n-1], fit(n-1), 0.05)
+
+	// The spread jumps at the change, where the old coefficients fail
+	spread, spreadStd := reg.Output("spread"), reg.Output("spread_std")
+	assert.Greater(t, math.Abs(spread.Calculate(n/2).Float()), 10*math.Abs(spread.Calculate(n/2-1).Float()))
+	assert.Greater(t, spreadStd.Calculate(n/2).Float(), 0.0)
+
+	delayed := indicators.NewKalmanRegressionIndicator(closes(y...), indicators.NewSimpleMovingAverage(closes(x...), 3), 1e-4, 1e-3)
+	assert.Equal(t, 2, indicators.Lookback(delayed))
+	assert.True(t, delayed.Calculate(1).IsZero())
+
+	assert.Panics(t, func() { indicators.NewKalmanRegressionIndicator(closes(y...), closes(x...), 1, 1) })
+}
@@ -143,7 +143,7 @@ type emaAllIndicator struct {
 	indicator   Indicator
 	window      int
 	alpha       decimal.Decimal
-	resultCache resultCache
+	resultCache *indicatorCache
 }
 
 func newEmaAllIndicator(indicator Indicator, window int) *emaAllIndicator {
@@ -151,7 +151,7 @@ func newEmaAllIndicator(indicator Indicator, window int) *emaAllIndicator {
 		indicator:   indicator,
 		window:      window,
 		alpha:       decimal.New(2).Div(decimal.NewFromInt(int64(window + 1))),
-		resultCache: make([]*decimal.Decimal, 0, defaultCacheSize),
+		resultCache: newIndicatorCache(),
 	}
 }
 
@@ -160,40 +160,25 @@ func (ema *emaAllIndicator) Calculate(index int) decimal.Decimal {
 		return decimal.ZERO
 	}
 
-	if index >= len(ema.resultCache) {
-		expansion := make([]*decimal.Decimal, index+1-len(ema.resultCache))
-		ema.resultCache = append(ema.resultCache, expansion...)
+	if cached, ok := ema.resultCache.get(index); ok {
+		return cached
 	}
 
-	if cached := ema.resultCache[index]; cached != nil {
-		return *cached
-	}
-
-	if index == 0 {
-		val := ema.indicator.Calculate(0)
-		ema.resultCache[index] = &val
-		return val
-	}
-
-	if index < ema.window-1 {
-		val := ema.indicator.Calculate(index)
-		ema.resultCache[index] = &val
-		return val
-	}
-
-	if index == ema.window-1 {
+	var val decimal.Decimal
+	switch {
+	case index == 0 || index < ema.window-1:
+		val = ema.indicator.Calculate(index)
+	case index == ema.window-1:
 		sum := decimal.ZERO
 		for i := 0; i < ema.window; i++ {
 			sum = sum.Add(ema.indicator.Calculate(index - i))
 		}
-		val := sum.Div(decimal.NewFromInt(int64(ema.window)))
-		ema.resultCache[index] = &val
-		return val
+		val = sum.Div(decimal.NewFromInt(int64(ema.window)))
+	default:
+		todayVal := ema.indicator.Calculate(index).Mul(ema.alpha)
+		val = todayVal.Add(ema.Calculate(index - 1).Mul(decimal.ONE.Sub(ema.alpha)))
 	}
-
-	todayVal := ema.indicator.Calculate(index).Mul(ema.alpha)
-	val := todayVal.Add(ema.Calculate(index - 1).Mul(decimal.ONE.Sub(ema.alpha)))
-	ema.resultCache[index] = &val
+	ema.resultCache.set(index, val)
 	return val
 }
 
@@ -227,3 +212,12 @@ func (t *temaIndicator) Calculate(index int) decimal.Decimal {
 		Sub(t.ema2.Calculate(index).Mul(decimal.New(3))).
 		Add(t.ema3.Calculate(index))
 }
+
+func (k *kamaIndicator) Lookback() int { return k.window - 1 }
+
+func (d *demaIndicator) Lookback() int { return max(d.window, 1) - 1 }
+
+// Lookback is that of the source: the average is seeded with its first value
+func (ema *emaAllIndicator) Lookback() int { return Lookback(ema.indicator) }
+
+func (t *temaIndicator) Lookback() int { return max(t.window, 1) - 1 }
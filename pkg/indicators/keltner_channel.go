@@ -30,6 +30,17 @@ func NewKeltnerChannelLowerIndicator(series *series.TimeSeries, window int) Indi
 	}
 }
 
+// NewKeltnerChannelIndicator returns the middle (EMA of close), upper and lower Keltner bands as a
+// MultiOutputIndicator
+func NewKeltnerChannelIndicator(series *series.TimeSeries, window int) MultiOutputIndicator {
+	atr := NewAverageTrueRangeIndicator(series, window)
+	ema := NewEMAIndicator(NewClosePriceIndicator(series), window)
+	return NewMultiOutputIndicator(bandOutputs,
+		ema,
+		keltnerChannelIndicator{ema: ema, atr: atr, mul: decimal.ONE, window: window},
+		keltnerChannelIndicator{ema: ema, atr: atr, mul: decimal.ONE.Neg(), window: window})
+}
+
 func (kci keltnerChannelIndicator) Calculate(index int) decimal.Decimal {
 	if index <= kci.window-1 {
 		return decimal.ZERO
@@ -39,3 +50,7 @@ func (kci keltnerChannelIndicator) Calculate(index int) decimal.Decimal {
 
 	return kci.ema.Calculate(index).Add(kci.atr.Calculate(index).Mul(coefficient))
 }
+
+func (kci keltnerChannelIndicator) Lookback() int {
+	return max(kci.window, MaxLookback(kci.ema, kci.atr))
+}
@@ -98,3 +98,7 @@ func (v *vfIndicator) Calculate(index int) decimal.Decimal {
 
 	return v.cache[index]
 }
+
+func (k *kvoIndicator) Lookback() int { return MaxLookback(k.ema34, k.ema55) }
+
+func (v *vfIndicator) Lookback() int { return 1 }
@@ -200,6 +200,13 @@ func NewLinearRegressionChannel(indicator Indicator, window int, deviations floa
 		linearRegressionChannelLower{mid: mid, stdErr: stdErr, deviations: k}
 }
 
+// NewLinearRegressionChannelIndicator returns the Linear Regression Channel as a
+// MultiOutputIndicator with the outputs middle, upper and lower.
+func NewLinearRegressionChannelIndicator(indicator Indicator, window int, deviations float64) MultiOutputIndicator {
+	mid, upper, lower := NewLinearRegressionChannel(indicator, window, deviations)
+	return NewMultiOutputIndicator(bandOutputs, mid, upper, lower)
+}
+
 func (l linearRegressionChannelUpper) Calculate(index int) decimal.Decimal {
 	return l.mid.Calculate(index).Add(l.stdErr.Calculate(index).Mul(l.deviations))
 }
@@ -207,3 +214,9 @@ func (l linearRegressionChannelUpper) Calculate(index int) decimal.Decimal {
 func (l linearRegressionChannelLower) Calculate(index int) decimal.Decimal {
 	return l.mid.Calculate(index).Sub(l.stdErr.Calculate(index).Mul(l.deviations))
 }
+
+func (l linearRegressionBase) Lookback() int { return Lookback(l.indicator) + l.window - 1 }
+
+func (l linearRegressionChannelUpper) Lookback() int { return MaxLookback(l.mid, l.stdErr) }
+
+func (l linearRegressionChannelLower) Lookback() int { return MaxLookback(l.mid, l.stdErr) }
@@ -0,0 +1,53 @@
+package indicators
+
+import "github.com/irfndi/goflux/pkg/decimal"
+
+// LookbackIndicator is an Indicator that reports its warm-up. Values at
+// indexes below Lookback are placeholders, usually zero, computed from too
+// little data to be real readings.
+type LookbackIndicator interface {
+	Indicator
+	Lookback() int
+}
+
+// Lookback returns the warm-up of ind, or 0 if it does not report one
+func Lookback(ind Indicator) int {
+	if l, ok := ind.(LookbackIndicator); ok {
+		return max(l.Lookback(), 0)
+	}
+	return 0
+}
+
+// IsReady reports whether index is past the warm-up of every indicator in inds
+func IsReady(index int, inds ...Indicator) bool {
+	return index >= 0 && index >= MaxLookback(inds...)
+}
+
+// CalculateReady returns the value of ind at index and whether it is a real
+// reading: past the warm-up and not decimal.NaN.
+func CalculateReady(ind Indicator, index int) (decimal.Decimal, bool) {
+	if !IsReady(index, ind) {
+		return decimal.NaN, false
+	}
+	value := ind.Calculate(index)
+	return value, value.IsValid()
+}
+
+// MaxLookback returns the largest Lookback of inds, the first index at which
+// all of them are ready
+func MaxLookback(inds ...Indicator) int {
+	lookback := 0
+	for _, ind := range inds {
+		lookback = max(lookback, Lookback(ind))
+	}
+	return lookback
+}
+
+// withLookback attaches a known warm-up to an indicator that cannot report
+// one itself, such as an indicatorFunc over a multi-output method
+type withLookback struct {
+	Indicator
+	lookback int
+}
+
+func (w withLookback) Lookback() int { return w.lookback }
@@ -0,0 +1,92 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookback(t *testing.T) {
+	ts := streamingTestSeries(120)
+	closePrice := indicators.NewClosePriceIndicator(ts)
+
+	cases := []struct {
+		name string
+		ind  indicators.Indicator
+		want int
+	}{
+		{"close", closePrice, 0},
+		{"SMA(10)", indicators.NewSimpleMovingAverage(closePrice, 10), 9},
+		{"EMA(10) of SMA(5)", indicators.NewEMAIndicator(indicators.NewSimpleMovingAverage(closePrice, 5), 10), 13},
+		{"RSI(14)", indicators.NewRelativeStrengthIndexIndicator(closePrice, 14), 14},
+		{"ATR(14)", indicators.NewAverageTrueRangeIndicator(ts, 14), 13},
+		{"ADX(14)", indicators.NewADXIndicator(ts, 14), 27},
+		{"BB upper(20)", indicators.NewBollingerUpperBandIndicator(closePrice, 20, 2), 19},
+		{"MACD(12, 26)", indicators.NewMACDIndicator(closePrice, 12, 26), 25},
+		{"SMA - EMA", indicators.NewDifferenceIndicator(
+			indicators.NewSimpleMovingAverage(closePrice, 30),
+			indicators.NewEMAIndicator(closePrice, 10)), 29},
+		{"stochastic", indicators.NewStochasticIndicator(ts, 14, 3), 13},
+		{"stochastic %D", indicators.NewStochasticIndicator(ts, 14, 3).Output("d"), 15},
+		{"ichimoku kijun", indicators.NewIchimokuIndicator(ts).Output("kijun"), 25},
+		{"precomputed SMA(10)", indicators.NewPrecomputedIndicator(indicators.NewSimpleMovingAverage(closePrice, 10), 120), 9},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, indicators.Lookback(tc.ind), tc.name)
+	}
+}
+
+func TestLookback_EndsWarmUpPlaceholders(t *testing.T) {
+	ts := streamingTestSeries(120)
+	closePrice := indicators.NewClosePriceIndicator(ts)
+
+	for name, ind := range map[string]indicators.Indicator{
+		"SMA(10)":  indicators.NewSimpleMovingAverage(closePrice, 10),
+		"EMA(10)":  indicators.NewEMAIndicator(closePrice, 10),
+		"ATR(14)":  indicators.NewAverageTrueRangeIndicator(ts, 14),
+		"ADX(14)":  indicators.NewADXIndicator(ts, 14),
+		"Keltner":  indicators.NewKeltnerChannelUpperIndicator(ts, 20),
+		"Williams": indicators.NewWilliamsRIndicator(ts, 14),
+		"TRIX(5)":  indicators.NewTRIXIndicator(closePrice, 5),
+		"WMA(10)":  indicators.NewWMAIndicator(closePrice, 10),
+		"DEMA(10)": indicators.NewDEMAIndicator(ts, 10),
+		"Ultimate": indicators.NewUltimateOscillatorIndicator(ts, 7, 14, 28),
+	} {
+		lookback := indicators.Lookback(ind)
+		assert.Positive(t, lookback, name)
+		assert.True(t, ind.Calculate(lookback-1).IsZero(), "%s[%d] should be a placeholder", name, lookback-1)
+		assert.False(t, ind.Calculate(lookback).IsZero(), "%s[%d] should be a real reading", name, lookback)
+	}
+}
+
+func TestIsReady(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(streamingTestSeries(30))
+	sma := indicators.NewSimpleMovingAverage(closePrice, 5)
+	ema := indicators.NewEMAIndicator(closePrice, 10)
+
+	assert.False(t, indicators.IsReady(-1, closePrice))
+	assert.True(t, indicators.IsReady(0, closePrice))
+	assert.False(t, indicators.IsReady(3, sma))
+	assert.True(t, indicators.IsReady(4, sma))
+	assert.False(t, indicators.IsReady(8, sma, ema))
+	assert.True(t, indicators.IsReady(9, sma, ema))
+	assert.Equal(t, 9, indicators.MaxLookback(sma, ema, closePrice))
+}
+
+func TestCalculateReady(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(streamingTestSeries(30))
+	sma := indicators.NewSimpleMovingAverage(closePrice, 5)
+
+	value, ok := indicators.CalculateReady(sma, 3)
+	assert.False(t, ok)
+	assert.True(t, value.IsNaN())
+
+	value, ok = indicators.CalculateReady(sma, 4)
+	assert.True(t, ok)
+	assertSameValue(t, "SMA(5)", 4, sma.Calculate(4), value)
+
+	_, ok = indicators.CalculateReady(indicators.NewConstantIndicator(decimal.NaN.Float()), 10)
+	assert.False(t, ok, "NaN is never a real reading")
+}
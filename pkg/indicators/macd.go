@@ -20,3 +20,11 @@ func NewMACDIndicator(baseIndicator Indicator, shortwindow, longwindow int) Indi
 func NewMACDHistogramIndicator(macdIdicator Indicator, signalLinewindow int) Indicator {
 	return NewDifferenceIndicator(macdIdicator, NewEMAIndicator(macdIdicator, signalLinewindow))
 }
+
+// NewMACDLinesIndicator returns the MACD line, its signalWindow EMA signal line and the histogram between them as a
+// MultiOutputIndicator with the outputs macd, signal and histogram. Calculate returns the MACD line.
+func NewMACDLinesIndicator(baseIndicator Indicator, shortwindow, longwindow, signalWindow int) MultiOutputIndicator {
+	macd := NewMACDIndicator(baseIndicator, shortwindow, longwindow)
+	signal := NewEMAIndicator(macd, signalWindow)
+	return NewMultiOutputIndicator(macdOutputs, macd, signal, NewDifferenceIndicator(macd, signal))
+}
@@ -95,3 +95,7 @@ func (m *mamaIndicator) compute(index int) {
 		m.results = append(m.results, mamaResult{mama, fama})
 	}
 }
+
+func (m *mamaIndicator) Lookback() int { return Lookback(m.indicator) }
+
+func (f *famaIndicator) Lookback() int { return f.mama.Lookback() }
@@ -46,3 +46,5 @@ func (mdi maximumDrawdownIndicator) Calculate(index int) decimal.Decimal {
 	}
 	return maxDrawdown
 }
+
+func (mdi maximumDrawdownIndicator) Lookback() int { return Lookback(mdi.indicator) }
@@ -36,3 +36,7 @@ func (mvi maximumValueIndicator) Calculate(index int) decimal.Decimal {
 
 	return maxValue
 }
+
+func (mvi maximumValueIndicator) Lookback() int {
+	return Lookback(mvi.indicator) + max(mvi.window, 1) - 1
+}
@@ -36,3 +36,5 @@ func (mdi meanDeviationIndicator) Calculate(index int) decimal.Decimal {
 
 	return absoluteDeviations.Div(decimal.New(float64(math.Min(mdi.window, index-start+1))))
 }
+
+func (mdi meanDeviationIndicator) Lookback() int { return Lookback(mdi.movingAverage) }
@@ -36,19 +36,3 @@ func GetMetadata(name string) (IndicatorMetadata, error) {
 	meta.Inputs = append([]string(nil), meta.Inputs...)
 	return meta, nil
 }
-
-func init() {
-	RegisterMetadata("sma", IndicatorMetadata{
-		Name:     "Simple Moving Average",
-		Category: "Overlap Studies",
-		Lookback: 0, // Varies by period
-	})
-	RegisterMetadata("ema", IndicatorMetadata{
-		Name:     "Exponential Moving Average",
-		Category: "Overlap Studies",
-	})
-	RegisterMetadata("rsi", IndicatorMetadata{
-		Name:     "Relative Strength Index",
-		Category: "Momentum Indicators",
-	})
-}
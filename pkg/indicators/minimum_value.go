@@ -36,3 +36,7 @@ func (mvi minimumValueIndicator) Calculate(index int) decimal.Decimal {
 
 	return minValue
 }
+
+func (mvi minimumValueIndicator) Lookback() int {
+	return Lookback(mvi.indicator) + max(mvi.window, 1) - 1
+}
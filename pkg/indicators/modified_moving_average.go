@@ -1,16 +1,13 @@
 package indicators
 
 import (
-	"sync"
-
 	"github.com/irfndi/goflux/pkg/decimal"
 )
 
 type modifiedMovingAverageIndicator struct {
 	indicator   Indicator
 	window      int
-	resultCache resultCache
-	cacheMu     sync.RWMutex
+	resultCache *indicatorCache
 }
 
 // NewMMAIndicator returns a derivative indciator which returns the modified moving average of the underlying
@@ -21,7 +18,7 @@ func NewMMAIndicator(indicator Indicator, window int) Indicator {
 	return &modifiedMovingAverageIndicator{
 		indicator:   indicator,
 		window:      window,
-		resultCache: make([]*decimal.Decimal, 0, defaultCacheSize),
+		resultCache: newIndicatorCache(),
 	}
 }
 
@@ -44,22 +41,26 @@ func (mma *modifiedMovingAverageIndicator) Calculate(index int) decimal.Decimal
 	return result
 }
 
-func (mma *modifiedMovingAverageIndicator) cache() resultCache {
-	return mma.resultCache
+func (mma *modifiedMovingAverageIndicator) ComputeInto(dst []decimal.Decimal) {
+	NewSimpleMovingAverage(mma.indicator, mma.window).(smaIndicator).ComputeInto(dst[:min(mma.window, len(dst))])
+	if len(dst) <= mma.window {
+		return
+	}
+	src := ComputeAll(mma.indicator, len(dst))
+	factor := decimal.New(1.0 / float64(mma.window))
+	for i := mma.window; i < len(dst); i++ {
+		dst[i] = dst[i-1].Add(factor.Mul(src[i].Sub(dst[i-1])))
+	}
 }
 
-func (mma *modifiedMovingAverageIndicator) setCache(cache resultCache) {
-	mma.resultCache = cache
+func (mma *modifiedMovingAverageIndicator) indicatorCache() *indicatorCache {
+	return mma.resultCache
 }
 
 func (mma *modifiedMovingAverageIndicator) windowSize() int {
 	return mma.window
 }
 
-func (mma *modifiedMovingAverageIndicator) cacheMutex() *sync.RWMutex {
-	return &mma.cacheMu
-}
-
-func (mma *modifiedMovingAverageIndicator) maxCacheSize() int {
-	return defaultMaxCacheSize
+func (mma *modifiedMovingAverageIndicator) Lookback() int {
+	return Lookback(mma.indicator) + mma.window - 1
 }
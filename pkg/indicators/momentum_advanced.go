@@ -23,9 +23,11 @@ func (cmo cmoIndicator) Calculate(index int) decimal.Decimal {
 
 	sum := gains.Add(losses)
 	if sum.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	diff := gains.Sub(losses)
 	return diff.Div(sum).Mul(decimal.New(100))
 }
+
+func (cmo cmoIndicator) Lookback() int { return MaxLookback(cmo.gains, cmo.losses) }
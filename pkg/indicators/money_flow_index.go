@@ -72,3 +72,5 @@ func (mfi *mfiIndicator) calculateTypicalPrice(index int) decimal.Decimal {
 
 	return high.Add(low).Add(close).Div(decimal.New(3))
 }
+
+func (mfi *mfiIndicator) Lookback() int { return mfi.window }
@@ -50,7 +50,7 @@ func (vwma vwmaIndicator) Calculate(index int) decimal.Decimal {
 	}
 
 	if volumeSum.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return priceVolumeSum.Div(volumeSum)
@@ -81,8 +81,7 @@ type rmaIndicator struct {
 	indicator   Indicator
 	window      int
 	alpha       decimal.Decimal
-	resultCache resultCache
-	cacheMu     sync.RWMutex
+	resultCache *indicatorCache
 }
 
 func NewRMAIndicator(indicator Indicator, window int) Indicator {
@@ -91,7 +90,7 @@ func NewRMAIndicator(indicator Indicator, window int) Indicator {
 		indicator:   indicator,
 		window:      window,
 		alpha:       decimal.ONE.Div(decimal.New(float64(window))),
-		resultCache: make([]*decimal.Decimal, 0, defaultCacheSize),
+		resultCache: newIndicatorCache(),
 	}
 }
 
@@ -115,11 +114,8 @@ func (rma *rmaIndicator) Calculate(index int) decimal.Decimal {
 	return result
 }
 
-func (rma *rmaIndicator) cache() resultCache        { return rma.resultCache }
-func (rma *rmaIndicator) setCache(c resultCache)    { rma.resultCache = c }
-func (rma *rmaIndicator) windowSize() int           { return rma.window }
-func (rma *rmaIndicator) cacheMutex() *sync.RWMutex { return &rma.cacheMu }
-func (rma *rmaIndicator) maxCacheSize() int         { return defaultMaxCacheSize }
+func (rma *rmaIndicator) indicatorCache() *indicatorCache { return rma.resultCache }
+func (rma *rmaIndicator) windowSize() int                 { return rma.window }
 
 type trimaIndicator struct {
 	indicator Indicator
@@ -430,7 +426,12 @@ func (vidya *vidyaIndicator) Calculate(index int) decimal.Decimal {
 			continue
 		}
 
-		k := vidya.cmo.Calculate(i).Abs().Div(decimal.New(100))
+		cmo := vidya.cmo.Calculate(i)
+		if !cmo.IsValid() {
+			// A flat window has no momentum, so VIDYA holds its previous value.
+			cmo = decimal.ZERO
+		}
+		k := cmo.Abs().Div(decimal.New(100))
 		ak := vidya.alpha.Mul(k)
 		val := vidya.indicator.Calculate(i)
 		nextVal := ak.Mul(val).Add(decimal.ONE.Sub(ak).Mul(prev))
@@ -453,3 +454,17 @@ func (vidya *vidyaIndicator) Calculate(index int) decimal.Decimal {
 
 	return vidya.cache[index]
 }
+
+func (vwma vwmaIndicator) Lookback() int { return Lookback(vwma.indicator) + vwma.window - 1 }
+
+func (rma *rmaIndicator) Lookback() int { return Lookback(rma.indicator) + rma.window - 1 }
+
+func (t *trimaIndicator) Lookback() int { return Lookback(t.indicator) + t.window - 1 }
+
+func (wma wmaIndicator) Lookback() int { return Lookback(wma.indicator) + max(wma.window, 1) - 1 }
+
+func (t3 *t3Indicator) Lookback() int { return Lookback(t3.e6) }
+
+func (alma *almaIndicator) Lookback() int { return Lookback(alma.indicator) + alma.window - 1 }
+
+func (vidya *vidyaIndicator) Lookback() int { return Lookback(vidya.indicator) + vidya.window }
@@ -0,0 +1,118 @@
+package indicators
+
+import (
+	"fmt"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+// MultiOutputIndicator is an indicator with several named output lines, such
+// as the upper, middle and lower Bollinger bands. Calculate returns the first
+// output, Output returns a line by name (nil if there is no such output) and
+// CalculateAll returns every line at index keyed by output name.
+type MultiOutputIndicator interface {
+	Indicator
+	Outputs() []string
+	Output(name string) Indicator
+	CalculateAll(index int) map[string]decimal.Decimal
+}
+
+// Output names shared by the built-in multi-output indicators
+var (
+	bandOutputs       = []string{"middle", "upper", "lower"}
+	macdOutputs       = []string{"macd", "signal", "histogram"}
+	stochasticOutputs = []string{"k", "d"}
+	alligatorOutputs  = []string{"jaw", "teeth", "lips"}
+	gatorOutputs      = []string{"upper", "lower"}
+	pivotOutputs      = []string{"pp", "r1", "r2", "r3", "s1", "s2", "s3"}
+	camarillaOutputs  = []string{"pp", "r1", "r2", "r3", "r4", "s1", "s2", "s3", "s4"}
+)
+
+// outputLines implements the naming half of MultiOutputIndicator for types
+// that embed it or build it on demand.
+type outputLines struct {
+	names []string
+	lines []Indicator
+}
+
+func newOutputLines(names []string, lines ...Indicator) outputLines {
+	if len(names) == 0 || len(names) != len(lines) {
+		panic(fmt.Sprintf("goflux: %d output names for %d output lines", len(names), len(lines)))
+	}
+	return outputLines{names: names, lines: lines}
+}
+
+// Outputs returns the output names in order
+func (o outputLines) Outputs() []string {
+	return append([]string(nil), o.names...)
+}
+
+// Output returns the line named name, or nil
+func (o outputLines) Output(name string) Indicator {
+	for i, n := range o.names {
+		if n == name {
+			return o.lines[i]
+		}
+	}
+	return nil
+}
+
+// CalculateAll returns every line at index keyed by output name
+func (o outputLines) CalculateAll(index int) map[string]decimal.Decimal {
+	values := make(map[string]decimal.Decimal, len(o.names))
+	for i, n := range o.names {
+		values[n] = o.lines[i].Calculate(index)
+	}
+	return values
+}
+
+type multiOutputIndicator struct {
+	outputLines
+}
+
+// NewMultiOutputIndicator groups lines under names into a MultiOutputIndicator.
+// Panics if there are no lines or the number of names and lines differ.
+func NewMultiOutputIndicator(names []string, lines ...Indicator) MultiOutputIndicator {
+	return multiOutputIndicator{newOutputLines(append([]string(nil), names...), lines...)}
+}
+
+func (m multiOutputIndicator) Calculate(index int) decimal.Decimal {
+	return m.lines[0].Calculate(index)
+}
+
+// ExpandOutputs returns the lines of ind with column names for exporters and
+// charts: name.output for each output of a MultiOutputIndicator, or name alone
+// for any other indicator.
+func ExpandOutputs(name string, ind Indicator) ([]string, []Indicator) {
+	multi, ok := ind.(MultiOutputIndicator)
+	if !ok {
+		return []string{name}, []Indicator{ind}
+	}
+	outputs := multi.Outputs()
+	names := make([]string, len(outputs))
+	lines := make([]Indicator, len(outputs))
+	for i, output := range outputs {
+		names[i] = name + "." + output
+		lines[i] = multi.Output(output)
+	}
+	return names, lines
+}
+
+// outputIndicators returns the lines of m in output order
+func outputIndicators(m MultiOutputIndicator) []Indicator {
+	outputs := m.Outputs()
+	lines := make([]Indicator, len(outputs))
+	for i, name := range outputs {
+		lines[i] = m.Output(name)
+	}
+	return lines
+}
+
+// indicatorFunc adapts a method such as IchimokuIndicator.KijunSen to an Indicator
+type indicatorFunc func(index int) decimal.Decimal
+
+func (f indicatorFunc) Calculate(index int) decimal.Decimal {
+	return f(index)
+}
+
+func (m multiOutputIndicator) Lookback() int { return Lookback(m.lines[0]) }
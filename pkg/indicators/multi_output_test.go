@@ -0,0 +1,181 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiOutputIndicatorsMatchSingleLines(t *testing.T) {
+	ts := streamingTestSeries(120)
+	closes := indicators.NewClosePriceIndicator(ts)
+
+	jaw, teeth, lips := indicators.NewAlligatorIndicators(ts)
+	gatorUp, gatorDown := indicators.NewGatorOscillatorIndicators(ts)
+	pp, r1, r2, r3, s1, s2, s3 := indicators.NewPivotPointIndicators(ts)
+	cpp, cr1, cr2, cr3, cr4, cs1, cs2, cs3, cs4 := indicators.NewCamarillaPivotPointIndicators(ts)
+	wpp, wr1, wr2, wr3, ws1, ws2, ws3 := indicators.NewWoodiePivotPointIndicators(ts)
+	fpp, fr1, fr2, fr3, fs1, fs2, fs3 := indicators.NewFibonacciPivotPointIndicators(ts)
+	lrMid, lrUp, lrLow := indicators.NewLinearRegressionChannel(closes, 20, 2)
+	macd := indicators.NewMACDIndicator(closes, 12, 26)
+	ich := indicators.NewIchimokuIndicator(ts)
+	stochK := indicators.NewFastStochasticIndicator(ts, 14)
+
+	cases := []struct {
+		name  string
+		multi indicators.MultiOutputIndicator
+		lines map[string]indicators.Indicator
+	}{
+		{"ichimoku", ich, map[string]indicators.Indicator{
+			"tenkan":   testIndicatorFunc(ich.TenkanSen),
+			"kijun":    testIndicatorFunc(ich.KijunSen),
+			"senkou_a": testIndicatorFunc(ich.SenkouSpanA),
+			"senkou_b": testIndicatorFunc(ich.SenkouSpanB),
+			"chikou":   testIndicatorFunc(ich.ChikouSpan),
+		}},
+		{"alligator", indicators.NewAlligatorIndicator(ts), map[string]indicators.Indicator{"jaw": jaw, "teeth": teeth, "lips": lips}},
+		{"gator", indicators.NewGatorOscillatorIndicator(ts), map[string]indicators.Indicator{"upper": gatorUp, "lower": gatorDown}},
+		{"pivots", indicators.NewPivotPointsIndicator(ts), map[string]indicators.Indicator{
+			"pp": pp, "r1": r1, "r2": r2, "r3": r3, "s1": s1, "s2": s2, "s3": s3,
+		}},
+		{"camarilla", indicators.NewCamarillaPivotPointsIndicator(ts), map[string]indicators.Indicator{
+			"pp": cpp, "r1": cr1, "r2": cr2, "r3": cr3, "r4": cr4, "s1": cs1, "s2": cs2, "s3": cs3, "s4": cs4,
+		}},
+		{"woodie", indicators.NewWoodiePivotPointsIndicator(ts), map[string]indicators.Indicator{
+			"pp": wpp, "r1": wr1, "r2": wr2, "r3": wr3, "s1": ws1, "s2": ws2, "s3": ws3,
+		}},
+		{"fibpivots", indicators.NewFibonacciPivotPointsIndicator(ts), map[string]indicators.Indicator{
+			"pp": fpp, "r1": fr1, "r2": fr2, "r3": fr3, "s1": fs1, "s2": fs2, "s3": fs3,
+		}},
+		{"lrchannel", indicators.NewLinearRegressionChannelIndicator(closes, 20, 2), map[string]indicators.Indicator{
+			"middle": lrMid, "upper": lrUp, "lower": lrLow,
+		}},
+		{"macd", indicators.NewMACDLinesIndicator(closes, 12, 26, 9), map[string]indicators.Indicator{
+			"macd":      macd,
+			"signal":    indicators.NewEMAIndicator(macd, 9),
+			"histogram": indicators.NewMACDHistogramIndicator(macd, 9),
+		}},
+		{"bbands", indicators.NewBollingerBandsIndicator(closes, 20, 2), map[string]indicators.Indicator{
+			"middle": indicators.NewSimpleMovingAverage(closes, 20),
+			"upper":  indicators.NewBollingerUpperBandIndicator(closes, 20, 2),
+			"lower":  indicators.NewBollingerLowerBandIndicator(closes, 20, 2),
+		}},
+		{"donchian", indicators.NewDonchianChannelIndicator(ts, 20), map[string]indicators.Indicator{
+			"middle": indicators.NewDonchianMiddleBandIndicator(ts, 20),
+			"upper":  indicators.NewDonchianUpperBandIndicator(ts, 20),
+			"lower":  indicators.NewDonchianLowerBandIndicator(ts, 20),
+		}},
+		{"keltner", indicators.NewKeltnerChannelIndicator(ts, 20), map[string]indicators.Indicator{
+			"middle": indicators.NewEMAIndicator(closes, 20),
+			"upper":  indicators.NewKeltnerChannelUpperIndicator(ts, 20),
+			"lower":  indicators.NewKeltnerChannelLowerIndicator(ts, 20),
+		}},
+		{"stoch", indicators.NewStochasticIndicator(ts, 14, 3), map[string]indicators.Indicator{
+			"k": stochK,
+			"d": indicators.NewSlowStochasticIndicator(stochK, 3),
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputs := tc.multi.Outputs()
+			require.Len(t, outputs, len(tc.lines))
+			assert.Nil(t, tc.multi.Output("nope"))
+
+			for _, i := range []int{0, 1, 30, 60, ts.LastIndex()} {
+				all := tc.multi.CalculateAll(i)
+				require.Len(t, all, len(outputs))
+				assertSameValue(t, tc.name, i, all[outputs[0]], tc.multi.Calculate(i))
+				for name, want := range tc.lines {
+					line := tc.multi.Output(name)
+					require.NotNil(t, line, name)
+					assertSameValue(t, tc.name+"."+name, i, want.Calculate(i), line.Calculate(i))
+					assertSameValue(t, tc.name+"."+name, i, want.Calculate(i), all[name])
+				}
+			}
+		})
+	}
+}
+
+func TestStreamingMultiOutputIndicators(t *testing.T) {
+	ts := streamingTestSeries(60)
+	closes := indicators.NewClosePriceIndicator(ts)
+
+	macd := indicators.NewStreamingMACD(12, 26, 9)
+	bands := indicators.NewStreamingBollingerBands(20, 2)
+	stoch := indicators.NewStreamingStochastic(14, 3)
+	ich := indicators.NewStreamingIchimoku()
+	for _, s := range []indicators.Streamer{macd, bands, stoch, ich} {
+		s.SetHistory(0)
+	}
+	for _, c := range ts.Candles {
+		macd.NextCandle(c)
+		bands.NextCandle(c)
+		stoch.NextCandle(c)
+		ich.NextCandle(c)
+	}
+
+	batch := map[string]indicators.MultiOutputIndicator{
+		"macd":     indicators.NewMACDLinesIndicator(closes, 12, 26, 9),
+		"bbands":   indicators.NewBollingerBandsIndicator(closes, 20, 2),
+		"stoch":    indicators.NewStochasticIndicator(ts, 14, 3),
+		"ichimoku": indicators.NewIchimokuIndicator(ts),
+	}
+	streaming := map[string]indicators.MultiOutputIndicator{"macd": macd, "bbands": bands, "stoch": stoch, "ichimoku": ich}
+
+	for name, s := range streaming {
+		b := batch[name]
+		assert.Equal(t, b.Outputs(), s.Outputs(), name)
+		for i := 0; i < ts.Length(); i++ {
+			want, got := b.CalculateAll(i), s.CalculateAll(i)
+			for _, output := range b.Outputs() {
+				assertSameValue(t, name+"."+output, i, want[output], got[output])
+			}
+		}
+	}
+}
+
+func TestExpandOutputs(t *testing.T) {
+	ts := streamingTestSeries(30)
+	closes := indicators.NewClosePriceIndicator(ts)
+
+	names, lines := indicators.ExpandOutputs("bb", indicators.NewBollingerBandsIndicator(closes, 5, 2))
+	assert.Equal(t, []string{"bb.middle", "bb.upper", "bb.lower"}, names)
+	assert.Len(t, lines, 3)
+
+	names, lines = indicators.ExpandOutputs("close", closes)
+	assert.Equal(t, []string{"close"}, names)
+	assert.Equal(t, []indicators.Indicator{closes}, lines)
+}
+
+func TestNewMultiOutputIndicator(t *testing.T) {
+	a, b := indicators.NewConstantIndicator(1), indicators.NewConstantIndicator(2)
+	names := []string{"a", "b"}
+	m := indicators.NewMultiOutputIndicator(names, a, b)
+	names[0] = "changed"
+	assert.Equal(t, []string{"a", "b"}, m.Outputs())
+	assert.Equal(t, "1", m.Calculate(0).String())
+	assert.Equal(t, "2", m.CalculateAll(0)["b"].String())
+
+	assert.Panics(t, func() { indicators.NewMultiOutputIndicator([]string{"a"}, a, b) })
+	assert.Panics(t, func() { indicators.NewMultiOutputIndicator(nil) })
+
+	ts := streamingTestSeries(40)
+	byName, err := indicators.NewMultiOutputIndicatorByName("macd", ts, nil, map[string]float64{"signal": 5})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"macd", "signal", "histogram"}, byName.Outputs())
+	want := indicators.NewMACDLinesIndicator(indicators.NewClosePriceIndicator(ts), 12, 26, 5)
+	assertSameValue(t, "macd.histogram", 39, want.Output("histogram").Calculate(39), byName.Output("histogram").Calculate(39))
+
+	_, err = indicators.NewMultiOutputIndicatorByName("nope", ts, nil, nil)
+	assert.ErrorIs(t, err, indicators.ErrUnknownIndicator)
+}
+
+type testIndicatorFunc func(int) decimal.Decimal
+
+func (f testIndicatorFunc) Calculate(index int) decimal.Decimal {
+	return f(index)
+}
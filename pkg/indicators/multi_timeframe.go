@@ -0,0 +1,111 @@
+package indicators
+
+import (
+	"sync"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// TimeframeBuilder builds an indicator on a resampled, higher timeframe series
+type TimeframeBuilder func(higher *series.TimeSeries) Indicator
+
+type multiTimeframeIndicator struct {
+	base      *series.TimeSeries
+	timeframe time.Duration
+	build     TimeframeBuilder
+
+	mu     sync.Mutex
+	length int
+	ind    Indicator
+	closed []int
+}
+
+// NewMultiTimeframeIndicator returns an indicator on the base series s whose
+// value at each index is the indicator built by build on s resampled to
+// timeframe, for example a daily RSI on an hourly series:
+//
+//	NewMultiTimeframeIndicator(hourly, 24*time.Hour, func(daily *series.TimeSeries) Indicator {
+//		return NewRelativeStrengthIndexIndicator(NewClosePriceIndicator(daily), 14)
+//	})
+//
+// A higher timeframe bar only becomes visible at the base index whose candle
+// ends at or after the end of that bar, so the value never depends on base
+// candles that come later. Until the first higher bar has closed the value is
+// decimal.NaN. Higher bars are bucketed by series.Resample, so timeframe should
+// be a multiple of the base candle duration. Candles appended to s are picked
+// up on the next Calculate.
+func NewMultiTimeframeIndicator(s *series.TimeSeries, timeframe time.Duration, build TimeframeBuilder) Indicator {
+	if s == nil || build == nil || timeframe <= 0 {
+		panic("goflux: multi-timeframe indicator needs a series, a positive timeframe and a builder")
+	}
+	return &multiTimeframeIndicator{base: s, timeframe: timeframe, build: build}
+}
+
+func (m *multiTimeframeIndicator) Calculate(index int) decimal.Decimal {
+	ind, closed := m.sync()
+	if index < 0 || index >= len(closed) || closed[index] < 0 {
+		return decimal.NaN
+	}
+	return ind.Calculate(closed[index])
+}
+
+// ComputeInto computes the higher timeframe indicator once and maps it onto dst
+func (m *multiTimeframeIndicator) ComputeInto(dst []decimal.Decimal) {
+	ind, closed := m.sync()
+	higher := 0
+	for _, h := range closed {
+		if h+1 > higher {
+			higher = h + 1
+		}
+	}
+	values := ComputeAll(ind, higher)
+	for i := range dst {
+		if i >= len(closed) || closed[i] < 0 {
+			dst[i] = decimal.NaN
+			continue
+		}
+		dst[i] = values[closed[i]]
+	}
+}
+
+// sync resamples the base series and rebuilds the indicator when the base
+// series has grown since the last call
+func (m *multiTimeframeIndicator) sync() (Indicator, []int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	length := m.base.Length()
+	if m.ind != nil && length == m.length {
+		return m.ind, m.closed
+	}
+
+	candles := m.base.CandlesSnapshot()
+	snapshot := series.NewTimeSeries()
+	snapshot.Candles = candles
+	higher := series.Resample(snapshot, m.timeframe)
+
+	m.ind = m.build(higher)
+	m.closed = lastClosedBars(candles, higher.Candles)
+	m.length = len(candles)
+	return m.ind, m.closed
+}
+
+// lastClosedBars maps each base candle to the last higher timeframe candle
+// that has ended by the end of the base candle, or -1 if there is none
+func lastClosedBars(base, higher []*series.Candle) []int {
+	closed := make([]int, len(base))
+	h := -1
+	for i, c := range base {
+		if c == nil {
+			closed[i] = h
+			continue
+		}
+		for h+1 < len(higher) && !higher[h+1].Period.End.After(c.Period.End) {
+			h++
+		}
+		closed[i] = h
+	}
+	return closed
+}
@@ -0,0 +1,104 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hourlyTestSeries(hours int) *series.TimeSeries {
+	ts := series.NewTimeSeries()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < hours; i++ {
+		c := series.NewCandle(series.NewTimePeriod(start.Add(time.Duration(i)*time.Hour), time.Hour))
+		price := decimal.New(float64(100 + i%7 + i/5))
+		c.OpenPrice, c.ClosePrice = price, price
+		c.MaxPrice, c.MinPrice = price.Add(decimal.ONE), price.Sub(decimal.ONE)
+		c.Volume = decimal.New(10)
+		ts.AddCandle(c)
+	}
+	return ts
+}
+
+func dailyClose(daily *series.TimeSeries) indicators.Indicator {
+	return indicators.NewClosePriceIndicator(daily)
+}
+
+func TestMultiTimeframeIndicator_ExposesClosedBarsOnly(t *testing.T) {
+	ts := hourlyTestSeries(24*3 + 5)
+	mtf := indicators.NewMultiTimeframeIndicator(ts, 24*time.Hour, dailyClose)
+
+	for i := 0; i < 23; i++ {
+		assert.True(t, mtf.Calculate(i).IsNaN(), "index %d", i)
+	}
+	for day := 0; day < 3; day++ {
+		last := day*24 + 23
+		for i := last; i < last+24 && i < ts.Length(); i++ {
+			assert.Equal(t, ts.Candles[last].ClosePrice.String(), mtf.Calculate(i).String(), "index %d", i)
+		}
+	}
+	// The fourth day has not closed yet
+	assert.Equal(t, ts.Candles[71].ClosePrice.String(), mtf.Calculate(ts.LastIndex()).String())
+	assert.True(t, mtf.Calculate(-1).IsNaN())
+	assert.True(t, mtf.Calculate(ts.Length()).IsNaN())
+}
+
+func TestMultiTimeframeIndicator_NoLookAhead(t *testing.T) {
+	full := hourlyTestSeries(24 * 6)
+	rsi := func(daily *series.TimeSeries) indicators.Indicator {
+		return indicators.NewRelativeStrengthIndexIndicator(indicators.NewClosePriceIndicator(daily), 2)
+	}
+	fullMTF := indicators.NewMultiTimeframeIndicator(full, 24*time.Hour, rsi)
+
+	for n := 1; n < full.Length(); n += 7 {
+		partial := series.NewTimeSeries()
+		for _, c := range full.Candles[:n] {
+			partial.AddCandle(c)
+		}
+		partialMTF := indicators.NewMultiTimeframeIndicator(partial, 24*time.Hour, rsi)
+		for i := 0; i < n; i++ {
+			assertSameValue(t, "mtf", i, fullMTF.Calculate(i), partialMTF.Calculate(i))
+		}
+	}
+}
+
+func TestMultiTimeframeIndicator_FollowsGrowingSeries(t *testing.T) {
+	full := hourlyTestSeries(48)
+	ts := series.NewTimeSeries()
+	for _, c := range full.Candles[:30] {
+		ts.AddCandle(c)
+	}
+	mtf := indicators.NewMultiTimeframeIndicator(ts, 24*time.Hour, dailyClose)
+	assert.Equal(t, full.Candles[23].ClosePrice.String(), mtf.Calculate(29).String())
+
+	for _, c := range full.Candles[30:] {
+		ts.AddCandle(c)
+	}
+	assert.Equal(t, full.Candles[47].ClosePrice.String(), mtf.Calculate(47).String())
+}
+
+func TestMultiTimeframeIndicator_ComputeInto(t *testing.T) {
+	ts := hourlyTestSeries(24*4 + 3)
+	mtf := indicators.NewMultiTimeframeIndicator(ts, 4*time.Hour, func(h *series.TimeSeries) indicators.Indicator {
+		return indicators.NewEMAIndicator(indicators.NewClosePriceIndicator(h), 3)
+	})
+	_, ok := mtf.(indicators.VectorIndicator)
+	require.True(t, ok)
+
+	values := indicators.ComputeAll(mtf, ts.Length())
+	for i := range values {
+		assertSameValue(t, "mtf", i, mtf.Calculate(i), values[i])
+	}
+}
+
+func TestNewMultiTimeframeIndicator_Panics(t *testing.T) {
+	ts := hourlyTestSeries(2)
+	assert.Panics(t, func() { indicators.NewMultiTimeframeIndicator(nil, time.Hour, dailyClose) })
+	assert.Panics(t, func() { indicators.NewMultiTimeframeIndicator(ts, 0, dailyClose) })
+	assert.Panics(t, func() { indicators.NewMultiTimeframeIndicator(ts, time.Hour, nil) })
+}
@@ -61,3 +61,5 @@ func (obv *obvIndicator) Calculate(index int) decimal.Decimal {
 
 	return obv.cache[index]
 }
+
+func (obv *obvIndicator) Lookback() int { return 0 }
@@ -0,0 +1,218 @@
+package indicators
+
+import (
+	"strconv"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+// DefaultImbalanceRatio is the multiple by which one side's volume at a price
+// must exceed the other side's diagonal volume to be an imbalance
+const DefaultImbalanceRatio = 3.0
+
+// orderFlowIndicator is a value of each candle split by aggressor side, zero
+// where the candle is missing
+type orderFlowIndicator struct {
+	series *series.TimeSeries
+	value  func(c *series.Candle) decimal.Decimal
+}
+
+func (o orderFlowIndicator) Calculate(index int) decimal.Decimal {
+	candle := candleAt(o.series, index)
+	if candle == nil {
+		return decimal.ZERO
+	}
+	return o.value(candle)
+}
+
+func (o orderFlowIndicator) ComputeInto(dst []decimal.Decimal) {
+	computeCandles(o.series, dst, o.value)
+}
+
+func (o orderFlowIndicator) Lookback() int { return 0 }
+
+// NewBuyVolumeIndicator returns the volume of each candle traded by buying
+// aggressors, as recorded by Candle.AddSideTrade
+func NewBuyVolumeIndicator(s *series.TimeSeries) Indicator {
+	return orderFlowIndicator{series: s, value: func(c *series.Candle) decimal.Decimal { return c.BuyVolume }}
+}
+
+// NewSellVolumeIndicator returns the volume of each candle traded by selling
+// aggressors, as recorded by Candle.AddSideTrade
+func NewSellVolumeIndicator(s *series.TimeSeries) Indicator {
+	return orderFlowIndicator{series: s, value: func(c *series.Candle) decimal.Decimal { return c.SellVolume }}
+}
+
+// NewDeltaIndicator returns the volume delta of each candle: its buying less
+// its selling aggressor volume
+func NewDeltaIndicator(s *series.TimeSeries) Indicator {
+	return orderFlowIndicator{series: s, value: (*series.Candle).Delta}
+}
+
+// NewCumulativeDeltaIndicator returns the cumulative volume delta, the running
+// total of the volume delta from the first candle. The last candle is
+// recounted on each call while it may still be receiving trades.
+func NewCumulativeDeltaIndicator(s *series.TimeSeries) Indicator {
+	telemetry.ReportUsage("CumulativeDelta", nil)
+	return newRunningSum(NewDeltaIndicator(s), func() int {
+		if s == nil {
+			return 0
+		}
+		return max(s.Length()-1, 0)
+	})
+}
+
+// deltaDivergenceIndicator compares the extremes of the close and the
+// cumulative delta over a window
+type deltaDivergenceIndicator struct {
+	close  Indicator
+	cvd    Indicator
+	window int
+}
+
+// NewDeltaDivergenceIndicator returns -1 where the close is the highest of the
+// last window closes but the cumulative delta is below its own high over them,
+// buying not confirming the new high, and 1 where the close is the lowest but
+// the cumulative delta is above its low. It returns zero otherwise and during
+// warm-up. Panics if window < 2.
+func NewDeltaDivergenceIndicator(s *series.TimeSeries, window int) Indicator {
+	if window < 2 {
+		panic("goflux: DeltaDivergence window must be >= 2")
+	}
+	telemetry.ReportUsage("DeltaDivergence", map[string]string{"window": strconv.Itoa(window)})
+	return deltaDivergenceIndicator{
+		close:  NewClosePriceIndicator(s),
+		cvd:    NewCumulativeDeltaIndicator(s),
+		window: window,
+	}
+}
+
+func (d deltaDivergenceIndicator) Calculate(index int) decimal.Decimal {
+	if index < d.window-1 {
+		return decimal.ZERO
+	}
+	price, delta := d.close.Calculate(index), d.cvd.Calculate(index)
+	priceHigh, priceLow, deltaHigh, deltaLow := true, true, true, true
+	for i := index - d.window + 1; i < index; i++ {
+		p, c := d.close.Calculate(i), d.cvd.Calculate(i)
+		priceHigh = priceHigh && price.GT(p)
+		priceLow = priceLow && price.LT(p)
+		deltaHigh = deltaHigh && delta.GTE(c)
+		deltaLow = deltaLow && delta.LTE(c)
+	}
+	switch {
+	case priceHigh && !deltaHigh:
+		return decimal.New(-1)
+	case priceLow && !deltaLow:
+		return decimal.ONE
+	}
+	return decimal.ZERO
+}
+
+func (d deltaDivergenceIndicator) Lookback() int { return d.window - 1 }
+
+// Imbalance is a price of a candle's footprint where the aggressors of Side
+// traded at least the imbalance ratio times the volume of the other side one
+// level away diagonally
+type Imbalance struct {
+	Price decimal.Decimal
+	Side  series.Side
+}
+
+// FootprintImbalances returns the diagonal imbalances of the footprint of c in
+// ascending price order. Buying at a price is compared with selling at the
+// next lower level, and selling with buying at the next higher level, as
+// buyers lift the offer one tick above the bid sellers hit. Levels are the
+// prices traded, so a gap in the footprint is skipped over. A side with
+// volume facing none is an imbalance. Panics if ratio <= 1.
+func FootprintImbalances(c *series.Candle, ratio float64) []Imbalance {
+	if ratio <= 1 {
+		panic("goflux: imbalance ratio must be > 1")
+	}
+	if c == nil {
+		return nil
+	}
+	r := decimal.New(ratio)
+	levels := c.Footprint
+	var imbalances []Imbalance
+	for i, level := range levels {
+		if i > 0 && level.BuyVolume.IsPositive() && level.BuyVolume.GTE(levels[i-1].SellVolume.Mul(r)) {
+			imbalances = append(imbalances, Imbalance{Price: level.Price, Side: series.BuySide})
+		}
+		if i < len(levels)-1 && level.SellVolume.IsPositive() && level.SellVolume.GTE(levels[i+1].BuyVolume.Mul(r)) {
+			imbalances = append(imbalances, Imbalance{Price: level.Price, Side: series.SellSide})
+		}
+	}
+	return imbalances
+}
+
+// NewImbalanceIndicator returns the buy imbalances less the sell imbalances
+// in the footprint of each candle, as found by FootprintImbalances. Panics if
+// ratio <= 1.
+func NewImbalanceIndicator(s *series.TimeSeries, ratio float64) Indicator {
+	if ratio <= 1 {
+		panic("goflux: imbalance ratio must be > 1")
+	}
+	telemetry.ReportUsage("Imbalance", map[string]string{"ratio": strconv.FormatFloat(ratio, 'f', -1, 64)})
+	return orderFlowIndicator{series: s, value: func(c *series.Candle) decimal.Decimal {
+		net := 0
+		for _, imbalance := range FootprintImbalances(c, ratio) {
+			if imbalance.Side == series.BuySide {
+				net++
+			} else {
+				net--
+			}
+		}
+		return decimal.NewFromInt(int64(net))
+	}}
+}
+
+// absorptionIndicator flags heavy aggressive volume that fails to move price
+type absorptionIndicator struct {
+	series *series.TimeSeries
+	window int
+	factor decimal.Decimal
+}
+
+// NewAbsorptionIndicator returns 1 where selling aggressors were absorbed,
+// a candle with more than factor times the average volume of the window
+// candles before it and a negative delta that still closed at or above its
+// open, and -1 where buying aggressors were absorbed likewise. It returns zero
+// otherwise and during warm-up. Panics if window < 1 or factor <= 0.
+func NewAbsorptionIndicator(s *series.TimeSeries, window int, factor float64) Indicator {
+	if window < 1 {
+		panic("goflux: Absorption window must be >= 1")
+	}
+	if factor <= 0 {
+		panic("goflux: Absorption factor must be positive")
+	}
+	telemetry.ReportUsage("Absorption", map[string]string{"window": strconv.Itoa(window)})
+	return absorptionIndicator{series: s, window: window, factor: decimal.New(factor)}
+}
+
+func (a absorptionIndicator) Calculate(index int) decimal.Decimal {
+	candle := candleAt(a.series, index)
+	if candle == nil || index < a.window {
+		return decimal.ZERO
+	}
+	total := decimal.ZERO
+	for i := index - a.window; i < index; i++ {
+		total = total.Add(candleAt(a.series, i).Volume)
+	}
+	average := total.Div(decimal.NewFromInt(int64(a.window)))
+	if !candle.Volume.GT(average.Mul(a.factor)) {
+		return decimal.ZERO
+	}
+	delta := candle.Delta()
+	switch {
+	case delta.IsNegative() && candle.ClosePrice.GTE(candle.OpenPrice):
+		return decimal.ONE
+	case delta.IsPositive() && candle.ClosePrice.LTE(candle.OpenPrice):
+		return decimal.New(-1)
+	}
+	return decimal.ZERO
+}
+
+func (a absorptionIndicator) Lookback() int { return a.window }
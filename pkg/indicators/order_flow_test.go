@@ -0,0 +1,117 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// flowCandles returns daily candles each opening with a buy of bar[2] at
+// bar[0] and closing with a sell of bar[3] at bar[1]
+func flowCandles(bars ...[4]float64) *series.TimeSeries {
+	s := series.NewTimeSeries()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, bar := range bars {
+		c := series.NewCandle(series.NewTimePeriod(start.AddDate(0, 0, i), 24*time.Hour))
+		c.AddSideTrade(series.BuySide, decimal.New(bar[2]), decimal.New(bar[0]))
+		c.AddSideTrade(series.SellSide, decimal.New(bar[3]), decimal.New(bar[1]))
+		s.AddCandle(c)
+	}
+	return s
+}
+
+func orderFlowSeries() *series.TimeSeries {
+	return flowCandles(
+		[4]float64{10, 10, 5, 5},
+		[4]float64{10, 11, 8, 2},
+		[4]float64{11, 12, 3, 7},
+		[4]float64{12, 13, 1, 29},
+		[4]float64{13, 9, 10, 0},
+		[4]float64{9, 8, 40, 0},
+	)
+}
+
+func TestDeltaIndicators(t *testing.T) {
+	s := orderFlowSeries()
+
+	assert.Equal(t, 8.0, indicators.NewBuyVolumeIndicator(s).Calculate(1).Float())
+	assert.Equal(t, 2.0, indicators.NewSellVolumeIndicator(s).Calculate(1).Float())
+
+	delta := indicators.NewDeltaIndicator(s)
+	cvd := indicators.NewCumulativeDeltaIndicator(s)
+	wantDelta := []float64{0, 6, -4, -28, 10, 40}
+	wantCVD := []float64{0, 6, 2, -26, -16, 24}
+	all := indicators.ComputeAll(delta, len(wantDelta))
+	for i := range wantDelta {
+		assert.Equal(t, wantDelta[i], delta.Calculate(i).Float(), "delta at %d", i)
+		assert.Equal(t, wantDelta[i], all[i].Float(), "computed delta at %d", i)
+		assert.Equal(t, wantCVD[i], cvd.Calculate(i).Float(), "cvd at %d", i)
+	}
+	assert.True(t, delta.Calculate(6).IsZero())
+}
+
+func TestCumulativeDeltaLiveCandle(t *testing.T) {
+	s := orderFlowSeries()
+	cvd := indicators.NewCumulativeDeltaIndicator(s)
+	assert.Equal(t, 24.0, cvd.Calculate(5).Float())
+
+	// Trades still arriving on the last candle are counted
+	s.LastCandle().AddSideTrade(series.SellSide, decimal.New(4), decimal.New(8))
+	assert.Equal(t, 20.0, cvd.Calculate(5).Float())
+}
+
+func TestDeltaDivergenceIndicator(t *testing.T) {
+	divergence := indicators.NewDeltaDivergenceIndicator(orderFlowSeries(), 3)
+	assert.Equal(t, 2, indicators.Lookback(divergence))
+	for i, want := range []float64{0, 0, -1, -1, 1, 1} {
+		assert.Equal(t, want, divergence.Calculate(i).Float(), "divergence at %d", i)
+	}
+	assert.Panics(t, func() { indicators.NewDeltaDivergenceIndicator(orderFlowSeries(), 1) })
+}
+
+func TestAbsorptionIndicator(t *testing.T) {
+	absorption := indicators.NewAbsorptionIndicator(orderFlowSeries(), 2, 1.5)
+	assert.Equal(t, 2, indicators.Lookback(absorption))
+	// Heavy selling closing up, then heavy buying closing down
+	for i, want := range []float64{0, 0, 0, 1, 0, -1} {
+		assert.Equal(t, want, absorption.Calculate(i).Float(), "absorption at %d", i)
+	}
+	assert.Panics(t, func() { indicators.NewAbsorptionIndicator(orderFlowSeries(), 0, 1.5) })
+	assert.Panics(t, func() { indicators.NewAbsorptionIndicator(orderFlowSeries(), 2, 0) })
+}
+
+func TestFootprintImbalances(t *testing.T) {
+	c := series.NewCandle(series.NewTimePeriod(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Minute))
+	trade := func(side series.Side, amount, price float64) {
+		c.AddSideTrade(side, decimal.New(amount), decimal.New(price))
+	}
+	trade(series.SellSide, 6, 10)
+	trade(series.BuySide, 2, 11)
+	trade(series.SellSide, 1, 11)
+	trade(series.BuySide, 9, 12)
+	trade(series.BuySide, 5, 13)
+
+	imbalances := indicators.FootprintImbalances(c, indicators.DefaultImbalanceRatio)
+	require.Len(t, imbalances, 3)
+	for i, want := range []indicators.Imbalance{
+		{Price: decimal.New(10), Side: series.SellSide},
+		{Price: decimal.New(12), Side: series.BuySide},
+		{Price: decimal.New(13), Side: series.BuySide},
+	} {
+		assert.True(t, want.Price.EQ(imbalances[i].Price), "price of %d", i)
+		assert.Equal(t, want.Side, imbalances[i].Side)
+	}
+	// Buying facing no selling is an imbalance at any ratio
+	assert.Len(t, indicators.FootprintImbalances(c, 10), 1)
+
+	s := series.NewTimeSeries()
+	s.AddCandle(c)
+	assert.Equal(t, 1.0, indicators.NewImbalanceIndicator(s, indicators.DefaultImbalanceRatio).Calculate(0).Float())
+	assert.Panics(t, func() { indicators.NewImbalanceIndicator(s, 1) })
+}
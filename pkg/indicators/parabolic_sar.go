@@ -143,3 +143,6 @@ func (ps *parabolicSARIndicator) EP() decimal.Decimal {
 func (ps *parabolicSARIndicator) AF() decimal.Decimal {
 	return ps.prevAF
 }
+
+// Lookback is 1: the value at index 0 is the first high, not a stop
+func (ps *parabolicSARIndicator) Lookback() int { return 1 }
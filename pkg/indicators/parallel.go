@@ -5,6 +5,9 @@ import "github.com/irfndi/goflux/pkg/decimal"
 // MultiCalculate calculates multiple indicators for a given index.
 // Indicator implementations may maintain recursive caches, so calculations
 // are deliberately ordered to keep the helper race-free for every Indicator.
+//
+// Deprecated: build the indicators in a Graph, which calculates shared
+// sub-indicators once and independent ones concurrently, and use Evaluate.
 func MultiCalculate(index int, indicators ...Indicator) []decimal.Decimal {
 	results := make([]decimal.Decimal, len(indicators))
 	for i, ind := range indicators {
@@ -18,11 +21,29 @@ func MultiCalculate(index int, indicators ...Indicator) []decimal.Decimal {
 // BatchCalculate calculates an indicator for a range of indices.
 // NOTE: This only works for non-recursive indicators (like SMA, RSI, but NOT EMA)
 // unless the cache is already populated or the indicator handles concurrency internally.
+// A VectorIndicator is computed once in a single pass up to the largest index.
 func BatchCalculate(ind Indicator, indices []int) []decimal.Decimal {
 	results := make([]decimal.Decimal, len(indices))
 	if ind == nil {
 		return results
 	}
+	if _, ok := ind.(VectorIndicator); ok && len(indices) > 1 {
+		last := -1
+		for _, idx := range indices {
+			if idx > last {
+				last = idx
+			}
+		}
+		all := ComputeAll(ind, last+1)
+		for i, idx := range indices {
+			if idx < 0 {
+				results[i] = ind.Calculate(idx)
+				continue
+			}
+			results[i] = all[idx]
+		}
+		return results
+	}
 	for i, idx := range indices {
 		results[i] = ind.Calculate(idx)
 	}
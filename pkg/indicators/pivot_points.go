@@ -59,6 +59,32 @@ func (p *pivotPointsIndicator) GetLevels(index int) PivotPointResult {
 	return calculateStandardPivotPointResult(prev.MaxPrice, prev.MinPrice, prev.ClosePrice)
 }
 
+func (p *pivotPointsIndicator) lines() outputLines {
+	return newOutputLines(pivotOutputs,
+		indicatorFunc(p.Calculate),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).R1 }),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).R2 }),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).R3 }),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).S1 }),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).S2 }),
+		indicatorFunc(func(i int) decimal.Decimal { return p.GetLevels(i).S3 }))
+}
+
+// Outputs returns pp, r1, r2, r3, s1, s2 and s3
+func (p *pivotPointsIndicator) Outputs() []string {
+	return p.lines().Outputs()
+}
+
+// Output returns the level named name, or nil
+func (p *pivotPointsIndicator) Output(name string) Indicator {
+	return p.lines().Output(name)
+}
+
+// CalculateAll returns every pivot level at index
+func (p *pivotPointsIndicator) CalculateAll(index int) map[string]decimal.Decimal {
+	return p.lines().CalculateAll(index)
+}
+
 // --- Standard (Classic) Pivot Points ---
 
 // NewPivotPointIndicators returns standard Pivot Point indicators (P, R1-R3, S1-S3).
@@ -156,6 +182,32 @@ func NewFibonacciPivotPointIndicators(s *series.TimeSeries) (pp, r1, r2, r3, s1,
 	return levels.pp, levels.r1, levels.r2, levels.r3, levels.s1, levels.s2, levels.s3
 }
 
+// --- Multi-output constructors ---
+
+// NewCamarillaPivotPointsIndicator returns the Camarilla levels as a
+// MultiOutputIndicator with the outputs pp, r1-r4 and s1-s4.
+// Panics if s is nil.
+func NewCamarillaPivotPointsIndicator(s *series.TimeSeries) MultiOutputIndicator {
+	pp, r1, r2, r3, r4, s1, s2, s3, s4 := NewCamarillaPivotPointIndicators(s)
+	return NewMultiOutputIndicator(camarillaOutputs, pp, r1, r2, r3, r4, s1, s2, s3, s4)
+}
+
+// NewWoodiePivotPointsIndicator returns the Woodie levels as a
+// MultiOutputIndicator with the outputs pp, r1-r3 and s1-s3.
+// Panics if s is nil.
+func NewWoodiePivotPointsIndicator(s *series.TimeSeries) MultiOutputIndicator {
+	pp, r1, r2, r3, s1, s2, s3 := NewWoodiePivotPointIndicators(s)
+	return NewMultiOutputIndicator(pivotOutputs, pp, r1, r2, r3, s1, s2, s3)
+}
+
+// NewFibonacciPivotPointsIndicator returns the Fibonacci levels as a
+// MultiOutputIndicator with the outputs pp, r1-r3 and s1-s3.
+// Panics if s is nil.
+func NewFibonacciPivotPointsIndicator(s *series.TimeSeries) MultiOutputIndicator {
+	pp, r1, r2, r3, s1, s2, s3 := NewFibonacciPivotPointIndicators(s)
+	return NewMultiOutputIndicator(pivotOutputs, pp, r1, r2, r3, s1, s2, s3)
+}
+
 // --- internal helpers ---
 
 type pivotPointLevels struct {
@@ -267,3 +319,7 @@ func calculateStandardPivotPointResult(high, low, close decimal.Decimal) PivotPo
 		S1: s1, S2: s2, S3: s3,
 	}
 }
+
+func (p *pivotPointsIndicator) Lookback() int { return 1 }
+
+func (p pivotLevel) Lookback() int { return 1 }
@@ -0,0 +1,244 @@
+package indicators
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+// The volatility estimators return the standard deviation of log returns per
+// candle over a window. Range based estimators use the open, high and low as
+// well as the close, and need several times fewer candles than close to close
+// volatility for the same precision. NewAnnualizedVolatilityIndicator scales
+// any of them to a year.
+
+// rangeVolatilityIndicator applies an estimator to the candles of a window,
+// preceded by the candle before the window when withPrevious is set
+type rangeVolatilityIndicator struct {
+	series       *series.TimeSeries
+	window       int
+	withPrevious bool
+	variance     func(candles []*series.Candle) float64
+}
+
+func newRangeVolatility(name string, s *series.TimeSeries, window, minimum int, withPrevious bool, variance func([]*series.Candle) float64) Indicator {
+	if s == nil {
+		panic("goflux: " + name + " series cannot be nil")
+	}
+	if window < minimum {
+		panic("goflux: " + name + " window must be >= " + strconv.Itoa(minimum))
+	}
+	telemetry.ReportUsage(name, map[string]string{"window": strconv.Itoa(window)})
+	return rangeVolatilityIndicator{series: s, window: window, withPrevious: withPrevious, variance: variance}
+}
+
+func (r rangeVolatilityIndicator) Calculate(index int) decimal.Decimal {
+	if index < r.Lookback() || index >= len(r.series.Candles) {
+		return decimal.ZERO
+	}
+	candles := r.series.Candles[index-r.Lookback() : index+1]
+	for _, c := range candles {
+		if !c.OpenPrice.IsPositive() || !c.MaxPrice.IsPositive() || !c.MinPrice.IsPositive() || !c.ClosePrice.IsPositive() {
+			return decimal.NaN
+		}
+	}
+	variance := r.variance(candles)
+	if variance < 0 {
+		return decimal.NaN
+	}
+	return finiteDecimal(math.Sqrt(variance))
+}
+
+func (r rangeVolatilityIndicator) Lookback() int {
+	if r.withPrevious {
+		return r.window
+	}
+	return r.window - 1
+}
+
+// logRatio returns ln(a/b)
+func logRatio(a, b decimal.Decimal) float64 { return math.Log(a.Float() / b.Float()) }
+
+// meanOf returns the mean of f over the candles
+func meanOf(candles []*series.Candle, f func(c *series.Candle) float64) float64 {
+	sum := 0.0
+	for _, c := range candles {
+		sum += f(c)
+	}
+	return sum / float64(len(candles))
+}
+
+// sampleVariance returns the variance of xs with n-1 degrees of freedom
+func sampleVariance(xs []float64) float64 {
+	mu := mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - mu) * (x - mu)
+	}
+	return sum / float64(len(xs)-1)
+}
+
+// NewParkinsonVolatilityIndicator returns the Parkinson volatility over
+// window, from the high-low range of each candle. It assumes no drift and no
+// gaps between candles, and so understates volatility when either is present.
+// Panics if s is nil or window < 1.
+func NewParkinsonVolatilityIndicator(s *series.TimeSeries, window int) Indicator {
+	return newRangeVolatility("ParkinsonVolatility", s, window, 1, false, func(candles []*series.Candle) float64 {
+		return meanOf(candles, func(c *series.Candle) float64 {
+			hl := logRatio(c.MaxPrice, c.MinPrice)
+			return hl * hl
+		}) / (4 * math.Ln2)
+	})
+}
+
+// NewGarmanKlassVolatilityIndicator returns the Garman-Klass volatility over
+// window, from the open, high, low and close of each candle. Like Parkinson it
+// assumes no drift and no gaps. Panics if s is nil or window < 1.
+func NewGarmanKlassVolatilityIndicator(s *series.TimeSeries, window int) Indicator {
+	return newRangeVolatility("GarmanKlassVolatility", s, window, 1, false, func(candles []*series.Candle) float64 {
+		return meanOf(candles, func(c *series.Candle) float64 {
+			hl, co := logRatio(c.MaxPrice, c.MinPrice), logRatio(c.ClosePrice, c.OpenPrice)
+			return 0.5*hl*hl - (2*math.Ln2-1)*co*co
+		})
+	})
+}
+
+func rogersSatchell(c *series.Candle) float64 {
+	return logRatio(c.MaxPrice, c.ClosePrice)*logRatio(c.MaxPrice, c.OpenPrice) +
+		logRatio(c.MinPrice, c.ClosePrice)*logRatio(c.MinPrice, c.OpenPrice)
+}
+
+// NewRogersSatchellVolatilityIndicator returns the Rogers-Satchell volatility
+// over window, which stays unbiased when prices drift but still ignores gaps
+// between candles. Panics if s is nil or window < 1.
+func NewRogersSatchellVolatilityIndicator(s *series.TimeSeries, window int) Indicator {
+	return newRangeVolatility("RogersSatchellVolatility", s, window, 1, false, func(candles []*series.Candle) float64 {
+		return meanOf(candles, rogersSatchell)
+	})
+}
+
+// NewYangZhangVolatilityIndicator returns the Yang-Zhang volatility over
+// window: the overnight variance from the previous close to each open, plus a
+// weighted mix of the open to close and Rogers-Satchell variances. It handles
+// both drift and gaps, and is the estimator to use for markets that close.
+// Panics if s is nil or window < 2.
+func NewYangZhangVolatilityIndicator(s *series.TimeSeries, window int) Indicator {
+	n := float64(window)
+	k := 0.34 / (1.34 + (n+1)/(n-1))
+	return newRangeVolatility("YangZhangVolatility", s, window, 2, true, func(candles []*series.Candle) float64 {
+		overnight, openClose := make([]float64, window), make([]float64, window)
+		for i, c := range candles[1:] {
+			overnight[i] = logRatio(c.OpenPrice, candles[i].ClosePrice)
+			openClose[i] = logRatio(c.ClosePrice, c.OpenPrice)
+		}
+		return sampleVariance(overnight) + k*sampleVariance(openClose) + (1-k)*meanOf(candles[1:], rogersSatchell)
+	})
+}
+
+// NewHistoricalVolatilityIndicator returns the close to close volatility over
+// window: the sample standard deviation of the log returns of the last window
+// candles. Panics if s is nil or window < 2.
+func NewHistoricalVolatilityIndicator(s *series.TimeSeries, window int) Indicator {
+	return newRangeVolatility("HistoricalVolatility", s, window, 2, true, func(candles []*series.Candle) float64 {
+		returns := make([]float64, window)
+		for i, c := range candles[1:] {
+			returns[i] = logRatio(c.ClosePrice, candles[i].ClosePrice)
+		}
+		return sampleVariance(returns)
+	})
+}
+
+// PeriodsPerYear returns the number of candles of the given length in a year
+// of tradingDays days of tradingHours hours each. Candles shorter than a
+// trading day are counted over the trading hours, so hourly candles give
+// 6.5 * 252 = 1638 for equities; candles of a trading day up to a calendar
+// day give one per trading day; longer candles are counted over the calendar
+// year, so weekly candles give about 52. Use 252 days of 6.5 hours for US
+// equities and 365 days of 24 hours for markets that never close. Returns
+// zero for a non-positive length or trading hours.
+func PeriodsPerYear(length time.Duration, tradingDays, tradingHours float64) float64 {
+	const day = 24 * time.Hour
+	if length <= 0 || tradingHours <= 0 {
+		return 0
+	}
+	session := tradingHours * float64(time.Hour)
+	switch {
+	case float64(length) < session:
+		return tradingDays * session / float64(length)
+	case length <= day:
+		return tradingDays
+	default:
+		return 365.25 * float64(day) / float64(length)
+	}
+}
+
+type annualizedVolatilityIndicator struct {
+	series       *series.TimeSeries
+	volatility   Indicator
+	tradingDays  float64
+	tradingHours float64
+}
+
+// NewAnnualizedVolatilityIndicator scales a per candle volatility of s to a
+// year, multiplying it by the square root of PeriodsPerYear for the length of
+// each candle. Panics if s or volatility is nil, tradingDays <= 0 or
+// tradingHours is not in (0, 24].
+func NewAnnualizedVolatilityIndicator(s *series.TimeSeries, volatility Indicator, tradingDays, tradingHours float64) Indicator {
+	if s == nil || volatility == nil {
+		panic("goflux: AnnualizedVolatility series and volatility cannot be nil")
+	}
+	if tradingDays <= 0 {
+		panic("goflux: AnnualizedVolatility trading days must be > 0")
+	}
+	if tradingHours <= 0 || tradingHours > 24 {
+		panic("goflux: AnnualizedVolatility trading hours must be in (0, 24]")
+	}
+	return annualizedVolatilityIndicator{series: s, volatility: volatility, tradingDays: tradingDays, tradingHours: tradingHours}
+}
+
+func (a annualizedVolatilityIndicator) Calculate(index int) decimal.Decimal {
+	if index < 0 || index >= len(a.series.Candles) {
+		return decimal.ZERO
+	}
+	periods := PeriodsPerYear(a.series.Candles[index].Period.Length(), a.tradingDays, a.tradingHours)
+	if periods == 0 {
+		return decimal.NaN
+	}
+	return a.volatility.Calculate(index).Mul(decimal.New(math.Sqrt(periods)))
+}
+
+func (a annualizedVolatilityIndicator) Lookback() int { return Lookback(a.volatility) }
+
+type volatilityOfVolatilityIndicator struct {
+	volatility Indicator
+	window     int
+}
+
+// NewVolatilityOfVolatilityIndicator returns the sample standard deviation of
+// the log changes of a volatility over window, NaN where the volatility is not
+// positive. Panics if volatility is nil or window < 2.
+func NewVolatilityOfVolatilityIndicator(volatility Indicator, window int) Indicator {
+	checkStatInputs("VolatilityOfVolatility", window, 2, volatility)
+	return volatilityOfVolatilityIndicator{volatility: volatility, window: window}
+}
+
+func (v volatilityOfVolatilityIndicator) Calculate(index int) decimal.Decimal {
+	if index < v.Lookback() {
+		return decimal.ZERO
+	}
+	levels := windowFloats(v.volatility, index, v.window+1)
+	changes := make([]float64, v.window)
+	for i := range changes {
+		if levels[i] <= 0 || levels[i+1] <= 0 {
+			return decimal.NaN
+		}
+		changes[i] = math.Log(levels[i+1] / levels[i])
+	}
+	return finiteDecimal(math.Sqrt(sampleVariance(changes)))
+}
+
+func (v volatilityOfVolatilityIndicator) Lookback() int { return Lookback(v.volatility) + v.window }
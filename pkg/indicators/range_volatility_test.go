@@ -0,0 +1,97 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+// rangeCandles returns n candles opening and closing at 100 with a high and
+// low a log distance of 0.1 away, a log range of 0.2
+func rangeCandles(n int) *series.TimeSeries {
+	values := make([][]float64, n)
+	for i := range values {
+		values[i] = []float64{100, 100, 100 * math.Exp(0.1), 100 * math.Exp(-0.1)}
+	}
+	return testutils.MockTimeSeriesOCHL(values...)
+}
+
+// dailyCandles returns daily candles that open, close, and trade at each price
+func dailyCandles(prices ...float64) *series.TimeSeries {
+	s := series.NewTimeSeries()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, p := range prices {
+		c := series.NewCandle(series.NewTimePeriod(start.AddDate(0, 0, i), 24*time.Hour))
+		c.OpenPrice, c.ClosePrice, c.MaxPrice, c.MinPrice = decimal.New(p), decimal.New(p), decimal.New(p), decimal.New(p)
+		s.AddCandle(c)
+	}
+	return s
+}
+
+func TestRangeVolatilityEstimators(t *testing.T) {
+	s := rangeCandles(10)
+
+	parkinson := indicators.NewParkinsonVolatilityIndicator(s, 5)
+	assert.True(t, parkinson.Calculate(3).IsZero())
+	assert.InDelta(t, 0.2/(2*math.Sqrt(math.Ln2)), parkinson.Calculate(4).Float(), 1e-9)
+	assert.Equal(t, 4, indicators.Lookback(parkinson))
+
+	assert.InDelta(t, math.Sqrt(0.02), indicators.NewGarmanKlassVolatilityIndicator(s, 5).Calculate(9).Float(), 1e-9)
+	assert.InDelta(t, math.Sqrt(0.02), indicators.NewRogersSatchellVolatilityIndicator(s, 5).Calculate(9).Float(), 1e-9)
+
+	assert.Panics(t, func() { indicators.NewParkinsonVolatilityIndicator(nil, 5) })
+	assert.Panics(t, func() { indicators.NewYangZhangVolatilityIndicator(s, 1) })
+}
+
+func TestCloseToCloseVolatility(t *testing.T) {
+	// Gaps only: the candles have no range, so Yang-Zhang reduces to the
+	// variance of the overnight returns, which are the close to close returns
+	s := dailyCandles(100, 110, 100, 110, 100)
+	want := math.Sqrt2 * math.Log(1.1)
+
+	hv := indicators.NewHistoricalVolatilityIndicator(s, 2)
+	assert.True(t, hv.Calculate(1).IsZero())
+	assert.InDelta(t, want, hv.Calculate(2).Float(), 1e-9)
+	assert.Equal(t, 2, indicators.Lookback(hv))
+
+	yz := indicators.NewYangZhangVolatilityIndicator(s, 2)
+	assert.InDelta(t, want, yz.Calculate(4).Float(), 1e-9)
+	assert.True(t, indicators.NewParkinsonVolatilityIndicator(s, 2).Calculate(4).IsZero(), "no range")
+
+	annual := indicators.NewAnnualizedVolatilityIndicator(s, hv, 252, 6.5)
+	assert.InDelta(t, want*math.Sqrt(252), annual.Calculate(4).Float(), 1e-9)
+	assert.Equal(t, 2, indicators.Lookback(annual))
+	assert.Panics(t, func() { indicators.NewAnnualizedVolatilityIndicator(s, hv, 252, 0) })
+	assert.Panics(t, func() { indicators.NewAnnualizedVolatilityIndicator(s, hv, 252, 25) })
+
+	assert.True(t, indicators.NewHistoricalVolatilityIndicator(dailyCandles(100, 0, 100), 2).Calculate(2).IsNaN())
+}
+
+func TestPeriodsPerYear(t *testing.T) {
+	assert.InDelta(t, 252, indicators.PeriodsPerYear(24*time.Hour, 252, 6.5), 1e-9)
+	assert.InDelta(t, 1638, indicators.PeriodsPerYear(time.Hour, 252, 6.5), 1e-9)
+	assert.InDelta(t, 252*13, indicators.PeriodsPerYear(30*time.Minute, 252, 6.5), 1e-9)
+	assert.InDelta(t, 252, indicators.PeriodsPerYear(8*time.Hour, 252, 6.5), 1e-9, "a candle spans the session")
+	assert.InDelta(t, 365*24, indicators.PeriodsPerYear(time.Hour, 365, 24), 1e-9)
+	assert.InDelta(t, 365*2, indicators.PeriodsPerYear(12*time.Hour, 365, 24), 1e-9)
+	assert.InDelta(t, 52.18, indicators.PeriodsPerYear(7*24*time.Hour, 252, 6.5), 0.01)
+	assert.Zero(t, indicators.PeriodsPerYear(0, 252, 6.5))
+	assert.Zero(t, indicators.PeriodsPerYear(time.Hour, 252, 0))
+}
+
+func TestVolatilityOfVolatility(t *testing.T) {
+	vov := indicators.NewVolatilityOfVolatilityIndicator(closes(1, 2, 1, 2, 2, 2), 2)
+	assert.True(t, vov.Calculate(1).IsZero())
+	assert.InDelta(t, math.Sqrt2*math.Ln2, vov.Calculate(2).Float(), 1e-9)
+	assert.InDelta(t, 0, vov.Calculate(5).Float(), 1e-9)
+	assert.Equal(t, 2, indicators.Lookback(vov))
+
+	assert.True(t, indicators.NewVolatilityOfVolatilityIndicator(closes(1, 0, 1), 2).Calculate(2).IsNaN())
+}
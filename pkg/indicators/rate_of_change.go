@@ -30,7 +30,7 @@ func (ri *rocIndicator) Calculate(index int) decimal.Decimal {
 	previousValue := ri.indicator.Calculate(periodIndex)
 
 	if previousValue.Zero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	roc := currentValue.Sub(previousValue).Div(previousValue).Mul(decimal.New(100))
@@ -63,3 +63,7 @@ func (mi *momIndicator) Calculate(index int) decimal.Decimal {
 
 	return currentValue.Sub(previousValue)
 }
+
+func (ri *rocIndicator) Lookback() int { return max(ri.period, 0) }
+
+func (mi *momIndicator) Lookback() int { return max(mi.period, 0) }
@@ -0,0 +1,82 @@
+package indicators
+
+import (
+	"reflect"
+	"sync"
+)
+
+// reachable calls visit with each non-nil pointer of type target reachable
+// from v through struct fields, interfaces, pointers, slices and arrays,
+// visiting each pointer once. Maps are not walked, since a cache may be
+// writing to them.
+func reachable(v reflect.Value, target reflect.Type, visit func(reflect.Value), seen map[uintptr]bool) {
+	if !v.IsValid() || !mayReach(v.Type(), target) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		if v.Type() == target {
+			visit(v)
+			return
+		}
+		reachable(v.Elem(), target, visit, seen)
+	case reflect.Interface:
+		reachable(v.Elem(), target, visit, seen)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			reachable(v.Field(i), target, visit, seen)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			reachable(v.Index(i), target, visit, seen)
+		}
+	}
+}
+
+type reachKey struct{ from, target reflect.Type }
+
+var reaches sync.Map // reachKey -> bool
+
+// mayReach reports whether a value of type t can hold a pointer of type
+// target, so reachable skips decimals and the like without walking them
+func mayReach(t, target reflect.Type) bool {
+	if reached, ok := reaches.Load(reachKey{t, target}); ok {
+		return reached.(bool)
+	}
+	return typeReaches(t, target, make(map[reflect.Type]bool))
+}
+
+func typeReaches(t, target reflect.Type, visiting map[reflect.Type]bool) bool {
+	key := reachKey{t, target}
+	if reached, ok := reaches.Load(key); ok {
+		return reached.(bool)
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	reached := false
+	switch t.Kind() {
+	case reflect.Interface:
+		reached = true
+	case reflect.Pointer:
+		reached = t == target || typeReaches(t.Elem(), target, visiting)
+	case reflect.Slice, reflect.Array:
+		reached = typeReaches(t.Elem(), target, visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !reached; i++ {
+			reached = typeReaches(t.Field(i).Type, target, visiting)
+		}
+	}
+	delete(visiting, t)
+	// A type found not to reach target while one enclosing it was still being
+	// walked may reach it through that type, so only settled answers are kept
+	if reached || len(visiting) == 0 {
+		reaches.Store(key, reached)
+	}
+	return reached
+}
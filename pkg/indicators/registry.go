@@ -0,0 +1,305 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// Input requirements reported in IndicatorMetadata.Inputs
+const (
+	InputOpen   = "open"
+	InputHigh   = "high"
+	InputLow    = "low"
+	InputClose  = "close"
+	InputVolume = "volume"
+	// InputSource means the indicator is computed from an arbitrary source
+	// indicator, which defaults to the close price.
+	InputSource = "source"
+)
+
+// DefaultOutput is the output name of single-output indicators
+const DefaultOutput = "value"
+
+// ParamType is the type of an indicator parameter
+type ParamType string
+
+// Parameter types
+const (
+	ParamInt   ParamType = "int"
+	ParamFloat ParamType = "float"
+)
+
+// ParamSpec describes one parameter of an indicator. Min and Max are
+// inclusive; a Max of zero means the parameter has no upper bound. GridMin,
+// GridMax and GridStep are the values an optimizer searches by default,
+// usually far fewer than are valid; a GridStep of zero means there is no
+// default grid.
+type ParamSpec struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Default     float64   `json:"default"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max,omitempty"`
+	GridMin     float64   `json:"grid_min,omitempty"`
+	GridMax     float64   `json:"grid_max,omitempty"`
+	GridStep    float64   `json:"grid_step,omitempty"`
+	Description string    `json:"description,omitempty"`
+}
+
+// Validate checks v against the type and bounds of the parameter
+func (ps ParamSpec) Validate(v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("%w: %s must be finite", ErrInvalidParam, ps.Name)
+	}
+	if ps.Type == ParamInt && v != math.Trunc(v) {
+		return fmt.Errorf("%w: %s must be a whole number, got %g", ErrInvalidParam, ps.Name, v)
+	}
+	if v < ps.Min {
+		return fmt.Errorf("%w: %s must be at least %g, got %g", ErrInvalidParam, ps.Name, ps.Min, v)
+	}
+	if ps.Max != 0 && v > ps.Max {
+		return fmt.Errorf("%w: %s must be at most %g, got %g", ErrInvalidParam, ps.Name, ps.Max, v)
+	}
+	return nil
+}
+
+// Params holds validated parameter values keyed by name
+type Params map[string]float64
+
+// Int returns the named parameter as an int
+func (p Params) Int(name string) int {
+	return int(p[name])
+}
+
+// Float returns the named parameter
+func (p Params) Float(name string) float64 {
+	return p[name]
+}
+
+// IndicatorConstructor builds the outputs of an indicator in the order of
+// IndicatorSpec.Outputs. Params are validated and defaulted before the
+// constructor is called, and source is never nil.
+type IndicatorConstructor func(s *series.TimeSeries, source Indicator, p Params) []Indicator
+
+// IndicatorSpec describes how to build an indicator by name. Metadata.Inputs
+// lists the candle fields the indicator reads, or InputSource when it is
+// computed from a source indicator.
+type IndicatorSpec struct {
+	Key string `json:"key"`
+	IndicatorMetadata
+	Params  []ParamSpec          `json:"params"`
+	Outputs []string             `json:"outputs"`
+	New     IndicatorConstructor `json:"-"`
+}
+
+// UsesSource reports whether the indicator is computed from a source indicator
+func (spec IndicatorSpec) UsesSource() bool {
+	for _, input := range spec.Inputs {
+		if input == InputSource {
+			return true
+		}
+	}
+	return false
+}
+
+// Param returns the parameter named name
+func (spec IndicatorSpec) Param(name string) (ParamSpec, bool) {
+	for _, ps := range spec.Params {
+		if ps.Name == name {
+			return ps, true
+		}
+	}
+	return ParamSpec{}, false
+}
+
+// Defaults returns the default value of every parameter
+func (spec IndicatorSpec) Defaults() Params {
+	p := make(Params, len(spec.Params))
+	for _, ps := range spec.Params {
+		p[ps.Name] = ps.Default
+	}
+	return p
+}
+
+// ResolveParams fills in defaults for missing parameters and validates the
+// rest. Unknown parameter names are an error.
+func (spec IndicatorSpec) ResolveParams(values map[string]float64) (Params, error) {
+	p := spec.Defaults()
+	for name, v := range values {
+		ps, ok := spec.Param(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no parameter %q", ErrInvalidParam, spec.Key, name)
+		}
+		if err := ps.Validate(v); err != nil {
+			return nil, err
+		}
+		p[name] = v
+	}
+	return p, nil
+}
+
+func (spec IndicatorSpec) copy() IndicatorSpec {
+	spec.Inputs = append([]string(nil), spec.Inputs...)
+	spec.Params = append([]ParamSpec(nil), spec.Params...)
+	spec.Outputs = append([]string(nil), spec.Outputs...)
+	return spec
+}
+
+var (
+	// ErrUnknownIndicator is returned when no indicator is registered under a name
+	ErrUnknownIndicator = errors.New("unknown indicator")
+	// ErrInvalidParam is returned when an indicator parameter is unknown or out of range
+	ErrInvalidParam = errors.New("invalid indicator parameter")
+	// ErrInvalidIndicatorSpec is returned when registering a malformed IndicatorSpec
+	ErrInvalidIndicatorSpec = errors.New("invalid indicator spec")
+)
+
+var indicatorRegistry = make(map[string]IndicatorSpec)
+var indicatorRegistryMu sync.RWMutex
+
+// RegisterIndicator adds or replaces the spec under spec.Key and registers its
+// metadata. Keys are case-insensitive. A spec without Outputs has the single
+// output DefaultOutput.
+func RegisterIndicator(spec IndicatorSpec) error {
+	spec.Key = strings.ToLower(strings.TrimSpace(spec.Key))
+	if spec.Key == "" || strings.Contains(spec.Key, ".") {
+		return fmt.Errorf("%w: key %q must be non-empty and contain no dots", ErrInvalidIndicatorSpec, spec.Key)
+	}
+	if spec.New == nil {
+		return fmt.Errorf("%w: %s has no constructor", ErrInvalidIndicatorSpec, spec.Key)
+	}
+	if len(spec.Outputs) == 0 {
+		spec.Outputs = []string{DefaultOutput}
+	}
+	seen := make(map[string]bool, len(spec.Params))
+	for _, ps := range spec.Params {
+		if ps.Name == "" || seen[ps.Name] {
+			return fmt.Errorf("%w: %s has an empty or duplicate parameter %q", ErrInvalidIndicatorSpec, spec.Key, ps.Name)
+		}
+		seen[ps.Name] = true
+		if ps.Type != ParamInt && ps.Type != ParamFloat {
+			return fmt.Errorf("%w: %s parameter %s has unknown type %q", ErrInvalidIndicatorSpec, spec.Key, ps.Name, ps.Type)
+		}
+		if err := ps.Validate(ps.Default); err != nil {
+			return fmt.Errorf("%w: %s default: %v", ErrInvalidIndicatorSpec, spec.Key, err)
+		}
+	}
+
+	spec = spec.copy()
+	indicatorRegistryMu.Lock()
+	indicatorRegistry[spec.Key] = spec
+	indicatorRegistryMu.Unlock()
+	RegisterMetadata(spec.Key, spec.IndicatorMetadata)
+	return nil
+}
+
+// LookupIndicator returns the spec registered under key
+func LookupIndicator(key string) (IndicatorSpec, bool) {
+	indicatorRegistryMu.RLock()
+	spec, ok := indicatorRegistry[strings.ToLower(strings.TrimSpace(key))]
+	indicatorRegistryMu.RUnlock()
+	if !ok {
+		return IndicatorSpec{}, false
+	}
+	return spec.copy(), true
+}
+
+// RegisteredIndicators returns every registered spec sorted by key
+func RegisteredIndicators() []IndicatorSpec {
+	indicatorRegistryMu.RLock()
+	specs := make([]IndicatorSpec, 0, len(indicatorRegistry))
+	for _, spec := range indicatorRegistry {
+		specs = append(specs, spec.copy())
+	}
+	indicatorRegistryMu.RUnlock()
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Key < specs[j].Key })
+	return specs
+}
+
+// NewIndicatorByName builds a registered indicator from the close price of s.
+// Name is a key such as "bbands", which selects the first output, or a key and
+// output separated by a dot such as "bbands.lower". Missing params take their
+// defaults.
+func NewIndicatorByName(name string, s *series.TimeSeries, params map[string]float64) (Indicator, error) {
+	return NewIndicatorByNameFrom(name, s, nil, params)
+}
+
+// NewIndicatorByNameFrom is NewIndicatorByName with an explicit source
+// indicator for indicators that use one. A nil source means the close price.
+func NewIndicatorByNameFrom(name string, s *series.TimeSeries, source Indicator, params map[string]float64) (Indicator, error) {
+	key, output := name, ""
+	if dot := strings.IndexByte(name, '.'); dot >= 0 {
+		key, output = name[:dot], name[dot+1:]
+	}
+	spec, ok := LookupIndicator(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIndicator, key)
+	}
+	outputs, err := spec.Build(s, source, params)
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return outputs[spec.Outputs[0]], nil
+	}
+	ind, ok := outputs[output]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no output %q (outputs: %s)", ErrUnknownIndicator, spec.Key, output, strings.Join(spec.Outputs, ", "))
+	}
+	return ind, nil
+}
+
+// NewMultiOutputIndicatorByName builds every output of the registered
+// indicator key as a MultiOutputIndicator. A nil source means the close price.
+func NewMultiOutputIndicatorByName(key string, s *series.TimeSeries, source Indicator, params map[string]float64) (MultiOutputIndicator, error) {
+	spec, ok := LookupIndicator(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIndicator, key)
+	}
+	outputs, err := spec.Build(s, source, params)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]Indicator, len(spec.Outputs))
+	for i, name := range spec.Outputs {
+		lines[i] = outputs[name]
+	}
+	return NewMultiOutputIndicator(spec.Outputs, lines...), nil
+}
+
+// Build resolves params and returns the outputs of the indicator keyed by
+// output name. A nil source means the close price of s. Constructor panics
+// are returned as errors.
+func (spec IndicatorSpec) Build(s *series.TimeSeries, source Indicator, params map[string]float64) (outputs map[string]Indicator, err error) {
+	if s == nil {
+		return nil, fmt.Errorf("%s: series cannot be nil", spec.Key)
+	}
+	p, err := spec.ResolveParams(params)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		source = NewClosePriceIndicator(s)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			outputs, err = nil, fmt.Errorf("%s: %v", spec.Key, r)
+		}
+	}()
+	built := spec.New(s, source, p)
+	if len(built) != len(spec.Outputs) {
+		return nil, fmt.Errorf("%s: constructor returned %d outputs, spec declares %d", spec.Key, len(built), len(spec.Outputs))
+	}
+	outputs = make(map[string]Indicator, len(built))
+	for i, name := range spec.Outputs {
+		outputs[name] = built[i]
+	}
+	return outputs, nil
+}
@@ -0,0 +1,711 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// Indicator categories used by the built-in registrations
+const (
+	CategoryPrice      = "Price Transform"
+	CategoryOverlap    = "Overlap Studies"
+	CategoryMomentum   = "Momentum Indicators"
+	CategoryVolatility = "Volatility Indicators"
+	CategoryVolume     = "Volume Indicators"
+	CategoryTrend      = "Trend Indicators"
+	CategoryCycle      = "Cycle Indicators"
+	CategoryStatistic  = "Statistic Functions"
+)
+
+// maxWindowParam bounds the whole-number parameters of the built-in
+// indicators; their default grids are much narrower.
+const maxWindowParam = 1000
+
+var (
+	inputsHL          = []string{InputHigh, InputLow}
+	inputsHLC         = []string{InputHigh, InputLow, InputClose}
+	inputsOHLC        = []string{InputOpen, InputHigh, InputLow, InputClose}
+	inputsHLCV        = []string{InputHigh, InputLow, InputClose, InputVolume}
+	inputsCloseVolume = []string{InputClose, InputVolume}
+	inputsSource      = []string{InputSource}
+)
+
+func intParam(name string, def, minimum float64, description string) ParamSpec {
+	return withGrid(ParamSpec{Name: name, Type: ParamInt, Default: def, Min: minimum, Max: maxWindowParam, Description: description})
+}
+
+func floatParam(name string, def, minimum, maximum float64, description string) ParamSpec {
+	return withGrid(ParamSpec{Name: name, Type: ParamFloat, Default: def, Min: minimum, Max: maximum, Description: description})
+}
+
+// withGrid sets the default grid of ps to steps of about a sixteenth of the
+// range from a quarter to four times its default, rounded to one significant
+// digit, through the default and within its bounds. A parameter defaulting to
+// zero selects a behaviour rather than a size and is searched at zero only.
+func withGrid(ps ParamSpec) ParamSpec {
+	lo, hi := max(ps.Default/4, ps.Min), ps.Default*4
+	if ps.Max != 0 {
+		hi = min(hi, ps.Max)
+	}
+	ps.GridMin, ps.GridMax, ps.GridStep = ps.Default, ps.Default, 1
+	if hi <= lo {
+		return ps
+	}
+
+	digit := int(math.Floor(math.Log10((hi - lo) / 16)))
+	step := roundDigits((hi-lo)/16, digit)
+	if ps.Type == ParamInt {
+		step, digit = max(step, 1), max(digit, 0)
+	}
+	// The bounds are rounded well below the step to drop binary noise only
+	ps.GridMin = roundDigits(ps.Default-math.Floor((ps.Default-lo)/step)*step, digit-6)
+	ps.GridMax = roundDigits(ps.Default+math.Floor((hi-ps.Default)/step)*step, digit-6)
+	ps.GridStep = step
+	return ps
+}
+
+// roundDigits rounds x to the nearest multiple of 10^digit
+func roundDigits(x float64, digit int) float64 {
+	if digit < 0 {
+		scale := math.Pow10(-digit)
+		return math.Round(x*scale) / scale
+	}
+	scale := math.Pow10(digit)
+	return math.Round(x/scale) * scale
+}
+
+func windowParam(def, minimum float64) ParamSpec {
+	return intParam("window", def, minimum, "lookback window in bars")
+}
+
+func indicatorMeta(name, category, description string, inputs []string) IndicatorMetadata {
+	return IndicatorMetadata{Name: name, Category: category, Description: description, Inputs: inputs}
+}
+
+func single(ind Indicator) []Indicator {
+	return []Indicator{ind}
+}
+
+func priceSpec(key, name, description string, inputs []string, ctor func(*series.TimeSeries) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, CategoryPrice, description, inputs),
+		New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+			return single(ctor(s))
+		},
+	}
+}
+
+func candleSpec(key, name, category, description string, inputs []string, ctor func(*series.TimeSeries) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, category, description, inputs),
+		New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+			return single(ctor(s))
+		},
+	}
+}
+
+func cycleSpec(key, name, description string, ctor func(Indicator) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, CategoryCycle, description, inputsSource),
+		New: func(_ *series.TimeSeries, src Indicator, _ Params) []Indicator {
+			return single(ctor(src))
+		},
+	}
+}
+
+func sourceWindowSpec(key, name, category, description string, def, minimum float64, ctor func(Indicator, int) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, category, description, inputsSource),
+		Params:            []ParamSpec{windowParam(def, minimum)},
+		New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+			return single(ctor(src, p.Int("window")))
+		},
+	}
+}
+
+func candleWindowSpec(key, name, category, description string, inputs []string, def, minimum float64, ctor func(*series.TimeSeries, int) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, category, description, inputs),
+		Params:            []ParamSpec{windowParam(def, minimum)},
+		New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+			return single(ctor(s, p.Int("window")))
+		},
+	}
+}
+
+// volatilityEstimatorSpec registers a volatility estimator of the candles,
+// annualized over trading_days days of trading_hours unless trading_days is
+// zero
+func volatilityEstimatorSpec(key, name, description string, def, minimum float64, ctor func(*series.TimeSeries, int) Indicator) IndicatorSpec {
+	return IndicatorSpec{
+		Key:               key,
+		IndicatorMetadata: indicatorMeta(name, CategoryVolatility, description, inputsOHLC),
+		Params: []ParamSpec{
+			windowParam(def, minimum),
+			floatParam("trading_days", 252, 0, 366, "trading days per year to annualize over, 0 for per candle volatility"),
+			floatParam("trading_hours", 6.5, 0.25, 24, "trading hours per day, counting intraday candles when annualizing"),
+		},
+		New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+			vol := ctor(s, p.Int("window"))
+			if days := p.Float("trading_days"); days > 0 {
+				vol = NewAnnualizedVolatilityIndicator(s, vol, days, p.Float("trading_hours"))
+			}
+			return single(vol)
+		},
+	}
+}
+
+func kalmanNoiseParams() []ParamSpec {
+	return []ParamSpec{
+		floatParam("process_noise", 0.01, 1e-9, 1e6, "variance of the state per bar"),
+		floatParam("measurement_noise", 1, 1e-9, 1e6, "variance of the observations about the state"),
+	}
+}
+
+func builtinIndicators() []IndicatorSpec {
+	return []IndicatorSpec{
+		priceSpec("open", "Open Price", "candle open price", []string{InputOpen}, NewOpenPriceIndicator),
+		priceSpec("high", "High Price", "candle high price", []string{InputHigh}, NewHighPriceIndicator),
+		priceSpec("low", "Low Price", "candle low price", []string{InputLow}, NewLowPriceIndicator),
+		priceSpec("close", "Close Price", "candle close price", []string{InputClose}, NewClosePriceIndicator),
+		priceSpec("volume", "Volume", "candle volume", []string{InputVolume}, NewVolumeIndicator),
+		priceSpec("hl2", "Median Price", "(high + low) / 2", inputsHL, NewMedianPriceIndicator),
+		priceSpec("hlc3", "Typical Price", "(high + low + close) / 3", inputsHLC, NewTypicalPriceIndicator),
+		priceSpec("ohlc4", "Average Price", "(open + high + low + close) / 4", inputsOHLC, NewAveragePriceIndicator),
+		priceSpec("hlcc4", "Weighted Close Price", "(high + low + 2*close) / 4", inputsHLC, NewWeightedCloseIndicator),
+
+		sourceWindowSpec("sma", "Simple Moving Average", CategoryOverlap, "arithmetic mean over the window", 20, 1, NewSimpleMovingAverage),
+		sourceWindowSpec("ema", "Exponential Moving Average", CategoryOverlap, "exponentially weighted mean seeded with an SMA", 20, 1, NewEMAIndicator),
+		sourceWindowSpec("mma", "Modified Moving Average", CategoryOverlap, "Wilder's smoothing", 14, 1, NewMMAIndicator),
+		sourceWindowSpec("rma", "Running Moving Average", CategoryOverlap, "running moving average", 14, 1, NewRMAIndicator),
+		sourceWindowSpec("wma", "Weighted Moving Average", CategoryOverlap, "linearly weighted mean", 20, 1, NewWMAIndicator),
+		sourceWindowSpec("hma", "Hull Moving Average", CategoryOverlap, "Hull moving average", 20, 1, NewHMAIndicator),
+		sourceWindowSpec("trima", "Triangular Moving Average", CategoryOverlap, "double smoothed SMA", 20, 1, NewTRIMAIndicator),
+		sourceWindowSpec("vidya", "Variable Index Dynamic Average", CategoryOverlap, "EMA scaled by the Chande momentum oscillator", 14, 1, NewVIDYAIndicator),
+		candleWindowSpec("dema", "Double Exponential Moving Average", CategoryOverlap, "double EMA of close", []string{InputClose}, 20, 1, NewDEMAIndicator),
+		candleWindowSpec("tema", "Triple Exponential Moving Average", CategoryOverlap, "triple EMA of close", []string{InputClose}, 20, 1, NewTEMAIndicator),
+		candleWindowSpec("kama", "Kaufman Adaptive Moving Average", CategoryOverlap, "efficiency ratio adaptive average of close", []string{InputClose}, 10, 1, NewKAMAIndicator),
+		candleWindowSpec("vwma", "Volume Weighted Moving Average", CategoryOverlap, "volume weighted mean of close", inputsCloseVolume, 20, 1, NewVWMAIndicatorFromSeries),
+		{
+			Key:               "t3",
+			IndicatorMetadata: indicatorMeta("Tillson T3", CategoryOverlap, "six-fold smoothed EMA", inputsSource),
+			Params: []ParamSpec{
+				windowParam(6, 1),
+				floatParam("vfactor", 0.7, 0, 1, "volume factor"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewT3Indicator(src, p.Int("window"), p.Float("vfactor")))
+			},
+		},
+		{
+			Key:               "alma",
+			IndicatorMetadata: indicatorMeta("Arnaud Legoux Moving Average", CategoryOverlap, "Gaussian weighted mean", inputsSource),
+			Params: []ParamSpec{
+				windowParam(9, 1),
+				floatParam("offset", 0.85, 0, 1, "position of the Gaussian peak within the window"),
+				floatParam("sigma", 6, 0.01, 100, "width of the Gaussian"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewALMAIndicator(src, p.Int("window"), p.Float("offset"), p.Float("sigma")))
+			},
+		},
+		{
+			Key:               "mama",
+			IndicatorMetadata: indicatorMeta("MESA Adaptive Moving Average", CategoryCycle, "MAMA and its following average FAMA", inputsSource),
+			Params: []ParamSpec{
+				floatParam("fast_limit", 0.5, 0.01, 1, "fastest smoothing factor"),
+				floatParam("slow_limit", 0.05, 0.01, 1, "slowest smoothing factor"),
+			},
+			Outputs: []string{"mama", "fama"},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				fast, slow := p.Float("fast_limit"), p.Float("slow_limit")
+				return []Indicator{NewMAMAIndicator(src, fast, slow), NewFAMAIndicator(src, fast, slow)}
+			},
+		},
+		{
+			Key:               "bbands",
+			IndicatorMetadata: indicatorMeta("Bollinger Bands", CategoryOverlap, "SMA with bands k standard deviations away", inputsSource),
+			Params: []ParamSpec{
+				windowParam(20, 1),
+				floatParam("sigma", 2, 0, 10, "band width in standard deviations"),
+			},
+			Outputs: bandOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewBollingerBandsIndicator(src, p.Int("window"), p.Float("sigma")))
+			},
+		},
+		{
+			Key:               "bbwidth",
+			IndicatorMetadata: indicatorMeta("Bollinger Bandwidth", CategoryVolatility, "distance between the Bollinger bands", inputsSource),
+			Params: []ParamSpec{
+				windowParam(20, 1),
+				floatParam("sigma", 2, 0, 10, "band width in standard deviations"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewBollingerBandwidthIndicator(src, p.Int("window"), p.Float("sigma")))
+			},
+		},
+		{
+			Key:               "keltner",
+			IndicatorMetadata: indicatorMeta("Keltner Channel", CategoryVolatility, "EMA of close with ATR bands", inputsHLC),
+			Params:            []ParamSpec{windowParam(20, 2)},
+			Outputs:           bandOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return outputIndicators(NewKeltnerChannelIndicator(s, p.Int("window")))
+			},
+		},
+		{
+			Key:               "donchian",
+			IndicatorMetadata: indicatorMeta("Donchian Channel", CategoryVolatility, "highest high and lowest low over the window", inputsHL),
+			Params:            []ParamSpec{windowParam(20, 1)},
+			Outputs:           bandOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return outputIndicators(NewDonchianChannelIndicator(s, p.Int("window")))
+			},
+		},
+		{
+			Key:               "lrchannel",
+			IndicatorMetadata: indicatorMeta("Linear Regression Channel", CategoryStatistic, "regression line with standard error bands", inputsSource),
+			Params: []ParamSpec{
+				windowParam(20, 2),
+				floatParam("deviations", 2, 0, 10, "band width in standard errors"),
+			},
+			Outputs: bandOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewLinearRegressionChannelIndicator(src, p.Int("window"), p.Float("deviations")))
+			},
+		},
+		{
+			Key:               "supertrend",
+			IndicatorMetadata: indicatorMeta("SuperTrend", CategoryTrend, "ATR trailing stop that flips with the trend", inputsHLC),
+			Params: []ParamSpec{
+				windowParam(10, 2),
+				floatParam("multiplier", 3, 0, 20, "ATR multiplier"),
+			},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewSuperTrendIndicator(s, p.Int("window"), p.Float("multiplier")))
+			},
+		},
+		{
+			Key:               "chandelier",
+			IndicatorMetadata: indicatorMeta("Chandelier Exit", CategoryVolatility, "ATR stops hung from the highest high and lowest low", inputsHLC),
+			Params: []ParamSpec{
+				intParam("period", 22, 1, "lookback for the highest high and lowest low"),
+				intParam("atr_window", 22, 2, "ATR window"),
+				floatParam("multiplier", 3, 0, 20, "ATR multiplier"),
+			},
+			Outputs: []string{"long", "short"},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				period, atr, mult := p.Int("period"), p.Int("atr_window"), p.Float("multiplier")
+				return []Indicator{NewChandelierExitLong(s, period, atr, mult), NewChandelierExitShort(s, period, atr, mult)}
+			},
+		},
+		candleSpec("sar", "Parabolic SAR", CategoryTrend, "parabolic stop and reverse", inputsHL, NewParabolicSARIndicator),
+		{
+			Key:               "ichimoku",
+			IndicatorMetadata: indicatorMeta("Ichimoku Cloud", CategoryTrend, "Ichimoku Kinko Hyo with 9/26/52 periods", inputsHLC),
+			Outputs:           ichimokuOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewIchimokuIndicator(s))
+			},
+		},
+		{
+			Key:               "alligator",
+			IndicatorMetadata: indicatorMeta("Williams Alligator", CategoryTrend, "displaced smoothed averages of the median price", inputsHL),
+			Params: []ParamSpec{
+				intParam("jaw_period", 13, 1, "jaw smoothing period"),
+				intParam("jaw_shift", 8, 0, "jaw displacement in bars"),
+				intParam("teeth_period", 8, 1, "teeth smoothing period"),
+				intParam("teeth_shift", 5, 0, "teeth displacement in bars"),
+				intParam("lips_period", 5, 1, "lips smoothing period"),
+				intParam("lips_shift", 3, 0, "lips displacement in bars"),
+			},
+			Outputs: alligatorOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return outputIndicators(NewAlligatorIndicatorCustom(s,
+					p.Int("jaw_period"), p.Int("jaw_shift"),
+					p.Int("teeth_period"), p.Int("teeth_shift"),
+					p.Int("lips_period"), p.Int("lips_shift")))
+			},
+		},
+		{
+			Key:               "gator",
+			IndicatorMetadata: indicatorMeta("Gator Oscillator", CategoryTrend, "distances between the Alligator lines", inputsHL),
+			Outputs:           gatorOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewGatorOscillatorIndicator(s))
+			},
+		},
+		{
+			Key:               "pivots",
+			IndicatorMetadata: indicatorMeta("Pivot Points", CategoryTrend, "classic floor pivots from the previous candle", inputsHLC),
+			Outputs:           pivotOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewPivotPointsIndicator(s))
+			},
+		},
+		{
+			Key:               "sr",
+			IndicatorMetadata: indicatorMeta("Support and Resistance", CategoryTrend, "nearest zones of clustered swing highs and lows below and above the close", inputsHLC),
+			Params: []ParamSpec{
+				intParam("strength", 2, 1, "candles on each side of a swing"),
+				floatParam("tolerance", 0.005, 0.0001, 0.5, "zone width relative to its price"),
+				intParam("touches", 1, 1, "minimum swings in a zone"),
+			},
+			Outputs: supportResistanceOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				strength := p.Int("strength")
+				return outputIndicators(NewSupportResistanceIndicator(s, SupportResistanceConfig{
+					ZoneConfig: ZoneConfig{Tolerance: p.Float("tolerance"), MinTouches: p.Int("touches")},
+					Left:       strength,
+					Right:      strength,
+				}))
+			},
+		},
+		{
+			Key:               "camarilla",
+			IndicatorMetadata: indicatorMeta("Camarilla Pivot Points", CategoryTrend, "Camarilla pivots from the previous candle", inputsHLC),
+			Outputs:           camarillaOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewCamarillaPivotPointsIndicator(s))
+			},
+		},
+		{
+			Key:               "woodie",
+			IndicatorMetadata: indicatorMeta("Woodie Pivot Points", CategoryTrend, "Woodie pivots from the previous candle", inputsOHLC),
+			Outputs:           pivotOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewWoodiePivotPointsIndicator(s))
+			},
+		},
+		{
+			Key:               "fibpivots",
+			IndicatorMetadata: indicatorMeta("Fibonacci Pivot Points", CategoryTrend, "Fibonacci pivots from the previous candle", inputsHLC),
+			Outputs:           pivotOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewFibonacciPivotPointsIndicator(s))
+			},
+		},
+		{
+			Key:               "fibretracement",
+			IndicatorMetadata: indicatorMeta("Fibonacci Retracement", CategoryTrend, "retracement level of the recent swing range", inputsHL),
+			Params: []ParamSpec{
+				intParam("lookback", 50, 1, "bars used to find the swing high and low"),
+				floatParam("level", 0.618, 0, 10, "retracement ratio"),
+			},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewFibonacciRetracementIndicator(s, p.Int("lookback"), p.Float("level")))
+			},
+		},
+		{
+			Key:               "zigzag",
+			IndicatorMetadata: indicatorMeta("ZigZag", CategoryTrend, "swing pivots filtered by a minimum percent move", inputsHL),
+			Params:            []ParamSpec{floatParam("percent", 5, 0.01, 100, "minimum reversal in percent")},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewZigZagIndicator(s, p.Float("percent")))
+			},
+		},
+		sourceWindowSpec("trendline", "Trendline", CategoryTrend, "least squares trendline value", 20, 2, NewTrendlineIndicator),
+
+		sourceWindowSpec("rsi", "Relative Strength Index", CategoryMomentum, "ratio of average gains to average losses scaled to 0-100", 14, 1, NewRelativeStrengthIndexIndicator),
+		sourceWindowSpec("cmo", "Chande Momentum Oscillator", CategoryMomentum, "net gains over total movement scaled to -100..100", 14, 1, NewChandeMomentumOscillatorIndicator),
+		sourceWindowSpec("trix", "TRIX", CategoryMomentum, "rate of change of a triple smoothed EMA", 15, 1, NewTRIXIndicator),
+		sourceWindowSpec("avggain", "Average Gains", CategoryMomentum, "mean gain over the window", 14, 1, NewAverageGainsIndicator),
+		sourceWindowSpec("avgloss", "Average Losses", CategoryMomentum, "mean loss over the window", 14, 1, NewAverageLossesIndicator),
+		{
+			Key:               "macd",
+			IndicatorMetadata: indicatorMeta("MACD", CategoryMomentum, "difference of a fast and a slow EMA", inputsSource),
+			Params: []ParamSpec{
+				intParam("fast", 12, 1, "fast EMA window"),
+				intParam("slow", 26, 1, "slow EMA window"),
+				intParam("signal", 9, 1, "signal EMA window"),
+			},
+			Outputs: macdOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewMACDLinesIndicator(src, p.Int("fast"), p.Int("slow"), p.Int("signal")))
+			},
+		},
+		{
+			Key:               "stoch",
+			IndicatorMetadata: indicatorMeta("Stochastic Oscillator", CategoryMomentum, "position of close within the high-low range", inputsHLC),
+			Params: []ParamSpec{
+				windowParam(14, 1),
+				intParam("d_window", 3, 1, "%D smoothing window"),
+			},
+			Outputs: stochasticOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return outputIndicators(NewStochasticIndicator(s, p.Int("window"), p.Int("d_window")))
+			},
+		},
+		candleWindowSpec("roc", "Rate of Change", CategoryMomentum, "percent change of close over the period", []string{InputClose}, 12, 1, NewROCIndicator),
+		candleWindowSpec("mom", "Momentum", CategoryMomentum, "change of close over the period", []string{InputClose}, 10, 1, NewMomentumIndicator),
+		candleWindowSpec("willr", "Williams %R", CategoryMomentum, "position of close below the highest high", inputsHLC, 14, 1, NewWilliamsRIndicator),
+		candleWindowSpec("cci", "Commodity Channel Index", CategoryMomentum, "typical price deviation from its mean", inputsHLC, 20, 1, NewCCIIndicator),
+		candleWindowSpec("adx", "Average Directional Index", CategoryTrend, "strength of the directional movement", inputsHLC, 14, 1, NewADXIndicator),
+		candleWindowSpec("vortex", "Vortex Indicator", CategoryTrend, "ratio of positive and negative vortex movement", inputsHLC, 14, 1, NewVortexIndicator),
+		candleWindowSpec("aroonosc", "Aroon Oscillator", CategoryTrend, "Aroon up minus Aroon down", inputsHL, 25, 1, NewAroonOscillatorFromSeries),
+		{
+			Key:               "aroon",
+			IndicatorMetadata: indicatorMeta("Aroon", CategoryTrend, "bars since the highest high and the lowest low", inputsHL),
+			Params:            []ParamSpec{windowParam(25, 1)},
+			Outputs:           []string{"up", "down"},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				window := p.Int("window")
+				return []Indicator{
+					NewAroonUpIndicator(NewHighPriceIndicator(s), window),
+					NewAroonDownIndicator(NewLowPriceIndicator(s), window),
+				}
+			},
+		},
+		{
+			Key:               "ao",
+			IndicatorMetadata: indicatorMeta("Awesome Oscillator", CategoryMomentum, "difference of fast and slow SMAs of the median price", inputsHL),
+			Params: []ParamSpec{
+				intParam("fast", 5, 1, "fast SMA window"),
+				intParam("slow", 34, 1, "slow SMA window"),
+			},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewAwesomeOscillatorIndicator(s, p.Int("fast"), p.Int("slow")))
+			},
+		},
+		{
+			Key:               "ultosc",
+			IndicatorMetadata: indicatorMeta("Ultimate Oscillator", CategoryMomentum, "weighted buying pressure over three periods", inputsHLC),
+			Params: []ParamSpec{
+				intParam("period1", 7, 1, "short period"),
+				intParam("period2", 14, 1, "medium period"),
+				intParam("period3", 28, 1, "long period"),
+			},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewUltimateOscillatorIndicator(s, p.Int("period1"), p.Int("period2"), p.Int("period3")))
+			},
+		},
+		{
+			Key:               "rvi",
+			IndicatorMetadata: indicatorMeta("Relative Vigor Index", CategoryMomentum, "close-open range relative to high-low range", inputsOHLC),
+			Outputs:           []string{"rvi", "signal"},
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return []Indicator{NewRelativeVigorIndexIndicator(s), NewRelativeVigorSignalLine(s)}
+			},
+		},
+
+		candleSpec("tr", "True Range", CategoryVolatility, "greatest of high-low and the gaps from the previous close", inputsHLC, NewTrueRangeIndicator),
+		candleWindowSpec("atr", "Average True Range", CategoryVolatility, "Wilder smoothed true range", inputsHLC, 14, 2, NewAverageTrueRangeIndicator),
+		volatilityEstimatorSpec("hv", "Historical Volatility", "close to close standard deviation of log returns", 20, 2, NewHistoricalVolatilityIndicator),
+		volatilityEstimatorSpec("parkinson", "Parkinson Volatility", "volatility from the high-low range", 20, 1, NewParkinsonVolatilityIndicator),
+		volatilityEstimatorSpec("garmanklass", "Garman-Klass Volatility", "volatility from the open, high, low and close", 20, 1, NewGarmanKlassVolatilityIndicator),
+		volatilityEstimatorSpec("rogerssatchell", "Rogers-Satchell Volatility", "drift independent range volatility", 20, 1, NewRogersSatchellVolatilityIndicator),
+		volatilityEstimatorSpec("yangzhang", "Yang-Zhang Volatility", "range volatility with overnight gaps", 20, 2, NewYangZhangVolatilityIndicator),
+		candleWindowSpec("atrratio", "ATR Ratio", CategoryVolatility, "ATR divided by close", inputsHLC, 14, 2, NewATRRatioIndicatorFromSeries),
+		sourceWindowSpec("stdev", "Standard Deviation", CategoryStatistic, "population standard deviation over the window", 20, 1, NewWindowedStandardDeviationIndicator),
+		sourceWindowSpec("meandev", "Mean Deviation", CategoryStatistic, "mean absolute deviation over the window", 20, 1, NewMeanDeviationIndicator),
+		sourceWindowSpec("highest", "Highest Value", CategoryStatistic, "maximum over the window", 20, 1, NewMaximumValueIndicator),
+		sourceWindowSpec("lowest", "Lowest Value", CategoryStatistic, "minimum over the window", 20, 1, NewMinimumValueIndicator),
+		sourceWindowSpec("maxdrawdown", "Maximum Drawdown", CategoryStatistic, "largest peak to trough decline over the window", 20, 1, NewMaximumDrawdownIndicator),
+		sourceWindowSpec("linreg", "Linear Regression", CategoryStatistic, "least squares fit value at the last bar", 14, 2, NewLinearRegressionIndicator),
+		sourceWindowSpec("slope", "Linear Regression Slope", CategoryStatistic, "least squares slope", 14, 2, NewLinearRegressionSlopeIndicator),
+		sourceWindowSpec("intercept", "Linear Regression Intercept", CategoryStatistic, "least squares intercept", 14, 2, NewLinearRegressionInterceptIndicator),
+		sourceWindowSpec("angle", "Linear Regression Angle", CategoryStatistic, "least squares slope in degrees", 14, 2, NewLinearRegressionAngleIndicator),
+		sourceWindowSpec("stderr", "Standard Error", CategoryStatistic, "standard error of the regression", 14, 2, NewStandardErrorIndicator),
+		sourceWindowSpec("zscore", "Z-Score", CategoryStatistic, "standard deviations from the mean of the window", 20, 2, NewZScoreIndicator),
+		sourceWindowSpec("percentrank", "Percent Rank", CategoryStatistic, "percentage of the previous window at or below the value", 20, 1, NewPercentRankIndicator),
+		sourceWindowSpec("skew", "Skewness", CategoryStatistic, "population skewness over the window", 20, 3, NewSkewnessIndicator),
+		sourceWindowSpec("kurtosis", "Kurtosis", CategoryStatistic, "population excess kurtosis over the window", 20, 4, NewKurtosisIndicator),
+		sourceWindowSpec("hurst", "Hurst Exponent", CategoryStatistic, "persistence of the series, 0.5 for a random walk", 100, 8, NewHurstExponentIndicator),
+		sourceWindowSpec("halflife", "Half-Life", CategoryStatistic, "bars for a deviation to halve under mean reversion", 100, 3, NewHalfLifeIndicator),
+		{
+			Key:               "vratio",
+			IndicatorMetadata: indicatorMeta("Variance Ratio", CategoryStatistic, "Lo-MacKinlay variance ratio, 1 for a random walk", inputsSource),
+			Params: []ParamSpec{
+				windowParam(100, 4),
+				intParam("lag", 2, 2, "bars per long difference"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewVarianceRatioIndicator(src, p.Int("window"), p.Int("lag")))
+			},
+		},
+		{
+			Key:               "adf",
+			IndicatorMetadata: indicatorMeta("Augmented Dickey-Fuller", CategoryStatistic, "unit root test statistic with MacKinnon critical values", inputsSource),
+			Params: []ParamSpec{
+				windowParam(100, 4),
+				intParam("lags", 1, 0, "lagged changes in the regression"),
+			},
+			Outputs: adfOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewADFIndicator(src, p.Int("window"), p.Int("lags")))
+			},
+		},
+
+		candleSpec("obv", "On Balance Volume", CategoryVolume, "cumulative volume signed by the close direction", inputsCloseVolume, NewOBVIndicator),
+		candleSpec("adline", "Accumulation/Distribution Line", CategoryVolume, "cumulative money flow volume", inputsHLCV, NewADLineIndicator),
+		candleSpec("vwap", "Volume Weighted Average Price", CategoryVolume, "cumulative VWAP of the typical price", inputsHLCV, NewVWAPIndicator),
+		candleSpec("kvo", "Klinger Volume Oscillator", CategoryVolume, "volume force oscillator", inputsHLCV, NewKVOIndicator),
+		candleWindowSpec("wvwap", "Windowed VWAP", CategoryVolume, "VWAP of the typical price over the window", inputsHLCV, 20, 1, NewWindowedVWAPIndicator),
+		{
+			Key:               "vprofile",
+			IndicatorMetadata: indicatorMeta("Volume Profile", CategoryVolume, "point of control and value area of the volume at price over the window", inputsHLCV),
+			Params: []ParamSpec{
+				windowParam(50, 1),
+				floatParam("bucket", 0, 0, 1e9, "price height of a level, 0 for 24 levels per profile"),
+				floatParam("value_area", DefaultValueArea, 0.01, 1, "share of volume in the value area"),
+			},
+			Outputs: profileOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				cfg := ProfileConfig{BucketSize: p.Float("bucket"), ValueArea: p.Float("value_area")}
+				return outputIndicators(NewVolumeProfileIndicator(s, p.Int("window"), cfg))
+			},
+		},
+		{
+			Key:               "svwap",
+			IndicatorMetadata: indicatorMeta("Session VWAP", CategoryVolume, "VWAP anchored at each UTC day with 1, 2 and 3 sigma bands", inputsHLCV),
+			Outputs:           anchoredVWAPOutputs,
+			New: func(s *series.TimeSeries, _ Indicator, _ Params) []Indicator {
+				return outputIndicators(NewAnchoredVWAPIndicator(s, AnchorAtSession(DailySessions(nil))))
+			},
+		},
+		candleWindowSpec("mfi", "Money Flow Index", CategoryVolume, "volume weighted RSI of the typical price", inputsHLCV, 14, 1, NewMFIIndicator),
+		candleWindowSpec("cmf", "Chaikin Money Flow", CategoryVolume, "money flow volume over volume", inputsHLCV, 20, 1, NewChaikinMoneyFlowIndicator),
+		candleWindowSpec("eom", "Ease of Movement", CategoryVolume, "price change per unit of volume", []string{InputHigh, InputLow, InputVolume}, 14, 1, NewEaseOfMovementIndicator),
+		candleWindowSpec("force", "Force Index", CategoryVolume, "EMA of close change times volume", inputsCloseVolume, 13, 1, NewForceIndexIndicator),
+		candleWindowSpec("vroc", "Volume Rate of Change", CategoryVolume, "percent change of volume over the period", []string{InputVolume}, 14, 1, NewVolumeROCIndicator),
+		candleSpec("buyvolume", "Buy Volume", CategoryVolume, "volume traded by buying aggressors", []string{InputVolume}, NewBuyVolumeIndicator),
+		candleSpec("sellvolume", "Sell Volume", CategoryVolume, "volume traded by selling aggressors", []string{InputVolume}, NewSellVolumeIndicator),
+		candleSpec("delta", "Volume Delta", CategoryVolume, "buying less selling aggressor volume", []string{InputVolume}, NewDeltaIndicator),
+		candleSpec("cvd", "Cumulative Volume Delta", CategoryVolume, "running total of the volume delta", []string{InputVolume}, NewCumulativeDeltaIndicator),
+		candleWindowSpec("deltadiv", "Delta Divergence", CategoryVolume, "-1 at a new high unconfirmed by cumulative delta, 1 at such a new low", inputsCloseVolume, 20, 2, NewDeltaDivergenceIndicator),
+		{
+			Key:               "imbalance",
+			IndicatorMetadata: indicatorMeta("Footprint Imbalance", CategoryVolume, "diagonal buy imbalances less sell imbalances in each candle's footprint", []string{InputVolume}),
+			Params:            []ParamSpec{floatParam("ratio", DefaultImbalanceRatio, 1.01, 0, "multiple of the diagonal volume that is an imbalance")},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewImbalanceIndicator(s, p.Float("ratio")))
+			},
+		},
+		{
+			Key:               "absorption",
+			IndicatorMetadata: indicatorMeta("Absorption", CategoryVolume, "1 where heavy selling failed to lower the close, -1 where heavy buying failed to raise it", []string{InputOpen, InputClose, InputVolume}),
+			Params: []ParamSpec{
+				windowParam(20, 1),
+				floatParam("factor", 2, 0.01, 0, "multiple of the average volume that is heavy"),
+			},
+			New: func(s *series.TimeSeries, _ Indicator, p Params) []Indicator {
+				return single(NewAbsorptionIndicator(s, p.Int("window"), p.Float("factor")))
+			},
+		},
+
+		cycleSpec("ht_dcperiod", "Hilbert Transform Dominant Cycle Period", "TA-Lib HT_DCPERIOD dominant cycle period", NewHTDCPeriodIndicator),
+		cycleSpec("ht_dcphase", "Hilbert Transform Dominant Cycle Phase", "TA-Lib HT_DCPHASE dominant cycle phase in degrees", NewHTDCPhaseIndicator),
+		{
+			Key:               "ht_phasor",
+			IndicatorMetadata: indicatorMeta("Hilbert Transform Phasor Components", CategoryCycle, "TA-Lib HT_PHASOR in-phase and quadrature components", inputsSource),
+			Outputs:           htPhasorOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, _ Params) []Indicator {
+				return outputIndicators(NewHTPhasorIndicator(src))
+			},
+		},
+		{
+			Key:               "ht_sine",
+			IndicatorMetadata: indicatorMeta("Hilbert Transform SineWave", CategoryCycle, "TA-Lib HT_SINE, the MESA Sine Wave and its lead", inputsSource),
+			Outputs:           htSineOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, _ Params) []Indicator {
+				return outputIndicators(NewHTSineIndicator(src))
+			},
+		},
+		cycleSpec("ht_trendline", "Hilbert Transform Instantaneous Trendline", "TA-Lib HT_TRENDLINE instantaneous trendline", NewHTTrendlineIndicator),
+		cycleSpec("ht_trendmode", "Hilbert Transform Trend vs Cycle Mode", "TA-Lib HT_TRENDMODE, 1 in a trend and 0 in a cycle", NewHTTrendModeIndicator),
+		sourceWindowSpec("supersmoother", "Ehlers SuperSmoother", CategoryCycle, "two-pole Butterworth low-pass filter", 10, 2, NewSuperSmootherIndicator),
+		{
+			Key:               "roofing",
+			IndicatorMetadata: indicatorMeta("Ehlers Roofing Filter", CategoryCycle, "high-pass filter smoothed by a SuperSmoother", inputsSource),
+			Params: []ParamSpec{
+				intParam("high_pass", 48, 3, "longest cycle passed in bars"),
+				intParam("low_pass", 10, 2, "shortest cycle passed in bars"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewRoofingFilterIndicator(src, p.Int("high_pass"), p.Int("low_pass")))
+			},
+		},
+		sourceWindowSpec("decycler", "Ehlers Simple Decycler", CategoryCycle, "input less a one-pole high-pass filter", 125, 5, NewDecyclerIndicator),
+		{
+			Key:               "itrend",
+			IndicatorMetadata: indicatorMeta("Ehlers Instantaneous Trendline", CategoryCycle, "low-lag trend filter and its trigger", inputsSource),
+			Params:            []ParamSpec{floatParam("alpha", 0.07, 0.001, 0.999, "smoothing factor")},
+			Outputs:           instantaneousTrendlineOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewInstantaneousTrendlineIndicator(src, p.Float("alpha")))
+			},
+		},
+		{
+			Key:               "cybercycle",
+			IndicatorMetadata: indicatorMeta("Ehlers Cyber Cycle", CategoryCycle, "cycle component of the smoothed input and its trigger", inputsSource),
+			Params:            []ParamSpec{floatParam("alpha", 0.07, 0.001, 0.999, "smoothing factor")},
+			Outputs:           cyberCycleOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewCyberCycleIndicator(src, p.Float("alpha")))
+			},
+		},
+		{
+			Key:               "fisher",
+			IndicatorMetadata: indicatorMeta("Ehlers Fisher Transform", CategoryCycle, "Fisher transform of the position in the window range", inputsSource),
+			Params:            []ParamSpec{windowParam(10, 1)},
+			Outputs:           fisherTransformOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewFisherTransformIndicator(src, p.Int("window")))
+			},
+		},
+		cycleSpec("ift", "Inverse Fisher Transform", "tanh of the input", NewInverseFisherTransformIndicator),
+		{
+			Key:               "cog",
+			IndicatorMetadata: indicatorMeta("Ehlers Center of Gravity", CategoryCycle, "age weighted balance point of the window and its trigger", inputsSource),
+			Params:            []ParamSpec{windowParam(10, 1)},
+			Outputs:           centerOfGravityOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewCenterOfGravityIndicator(src, p.Int("window")))
+			},
+		},
+		{
+			Key:               "acp",
+			IndicatorMetadata: indicatorMeta("Ehlers Autocorrelation Periodogram", CategoryCycle, "dominant cycle period from the autocorrelation spectrum", inputsSource),
+			Params: []ParamSpec{
+				intParam("min_period", 10, 3, "shortest period measured"),
+				intParam("max_period", 48, 4, "longest period measured"),
+				intParam("avg_length", 3, 0, "bars averaged per correlation, 0 for the lag"),
+			},
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewAutocorrelationPeriodogramIndicator(src, p.Int("min_period"), p.Int("max_period"), p.Int("avg_length")))
+			},
+		},
+		{
+			Key:               "kalman",
+			IndicatorMetadata: indicatorMeta("Kalman Filter", CategoryOverlap, "Kalman filter estimate of a random walk level", inputsSource),
+			Params:            kalmanNoiseParams(),
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return single(NewKalmanFilterIndicator(src, p.Float("process_noise"), p.Float("measurement_noise")))
+			},
+		},
+		{
+			Key:               "kalmantrend",
+			IndicatorMetadata: indicatorMeta("Kalman Trend", CategoryOverlap, "Kalman filter estimate of a local linear trend and its velocity", inputsSource),
+			Params:            kalmanNoiseParams(),
+			Outputs:           kalmanTrendOutputs,
+			New: func(_ *series.TimeSeries, src Indicator, p Params) []Indicator {
+				return outputIndicators(NewKalmanTrendIndicator(src, p.Float("process_noise"), p.Float("measurement_noise")))
+			},
+		},
+	}
+}
+
+func init() {
+	for _, spec := range builtinIndicators() {
+		if err := RegisterIndicator(spec); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package indicators_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredIndicatorsBuildWithDefaults(t *testing.T) {
+	ts := streamingTestSeries(120)
+	specs := indicators.RegisteredIndicators()
+	require.NotEmpty(t, specs)
+
+	for i := 1; i < len(specs); i++ {
+		assert.Less(t, specs[i-1].Key, specs[i].Key)
+	}
+
+	for _, spec := range specs {
+		t.Run(spec.Key, func(t *testing.T) {
+			assert.NotEmpty(t, spec.Name)
+			assert.NotEmpty(t, spec.Category)
+			assert.NotEmpty(t, spec.Inputs)
+			require.NotEmpty(t, spec.Outputs)
+
+			outputs, err := spec.Build(ts, nil, nil)
+			require.NoError(t, err)
+			require.Len(t, outputs, len(spec.Outputs))
+			for _, name := range spec.Outputs {
+				require.NotNil(t, outputs[name], name)
+				outputs[name].Calculate(ts.LastIndex())
+			}
+
+			meta, err := indicators.GetMetadata(spec.Key)
+			require.NoError(t, err)
+			assert.Equal(t, spec.Name, meta.Name)
+		})
+	}
+}
+
+func TestRegisteredIndicatorGrids(t *testing.T) {
+	for _, spec := range indicators.RegisteredIndicators() {
+		for _, p := range spec.Params {
+			name := spec.Key + "." + p.Name
+			require.Positive(t, p.GridStep, name)
+			require.NoError(t, p.Validate(p.GridMin), name)
+			require.NoError(t, p.Validate(p.GridMax), name)
+			require.NoError(t, p.Validate(p.GridStep+p.GridMin), name)
+
+			steps := (p.GridMax - p.GridMin) / p.GridStep
+			assert.LessOrEqual(t, steps, 24.0, name)
+			fromMin := (p.Default - p.GridMin) / p.GridStep
+			assert.InDelta(t, math.Round(fromMin), fromMin, 1e-9, "%s grid misses the default", name)
+		}
+	}
+	bbands, ok := indicators.LookupIndicator("bbands")
+	require.True(t, ok)
+	assert.Equal(t, []float64{5, 80, 5}, []float64{bbands.Params[0].GridMin, bbands.Params[0].GridMax, bbands.Params[0].GridStep})
+}
+
+func TestNewIndicatorByName(t *testing.T) {
+	ts := streamingTestSeries(60)
+	closes := indicators.NewClosePriceIndicator(ts)
+
+	sma, err := indicators.NewIndicatorByName("SMA", ts, map[string]float64{"window": 10})
+	require.NoError(t, err)
+	expected := indicators.NewSimpleMovingAverage(closes, 10)
+	for i := 0; i < ts.Length(); i++ {
+		assertSameValue(t, "sma", i, expected.Calculate(i), sma.Calculate(i))
+	}
+
+	lower, err := indicators.NewIndicatorByName("bbands.lower", ts, nil)
+	require.NoError(t, err)
+	expected = indicators.NewBollingerLowerBandIndicator(closes, 20, 2)
+	assertSameValue(t, "bbands.lower", ts.LastIndex(), expected.Calculate(ts.LastIndex()), lower.Calculate(ts.LastIndex()))
+
+	highs := indicators.NewHighPriceIndicator(ts)
+	ema, err := indicators.NewIndicatorByNameFrom("ema", ts, highs, map[string]float64{"window": 5})
+	require.NoError(t, err)
+	expected = indicators.NewEMAIndicator(highs, 5)
+	assertSameValue(t, "ema(high)", ts.LastIndex(), expected.Calculate(ts.LastIndex()), ema.Calculate(ts.LastIndex()))
+}
+
+func TestNewIndicatorByNameErrors(t *testing.T) {
+	ts := streamingTestSeries(10)
+
+	tests := []struct {
+		name   string
+		params map[string]float64
+		want   error
+	}{
+		{"nope", nil, indicators.ErrUnknownIndicator},
+		{"bbands.middle_band", nil, indicators.ErrUnknownIndicator},
+		{"sma", map[string]float64{"period": 10}, indicators.ErrInvalidParam},
+		{"sma", map[string]float64{"window": 0}, indicators.ErrInvalidParam},
+		{"sma", map[string]float64{"window": 2.5}, indicators.ErrInvalidParam},
+		{"atr", map[string]float64{"window": 1}, indicators.ErrInvalidParam},
+		{"bbands", map[string]float64{"sigma": 11}, indicators.ErrInvalidParam},
+	}
+	for _, tt := range tests {
+		_, err := indicators.NewIndicatorByName(tt.name, ts, tt.params)
+		assert.True(t, errors.Is(err, tt.want), "%s %v: %v", tt.name, tt.params, err)
+	}
+
+	_, err := indicators.NewIndicatorByName("sma", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestRegisterIndicator(t *testing.T) {
+	spec := indicators.IndicatorSpec{
+		Key:               "Test_Double",
+		IndicatorMetadata: indicators.IndicatorMetadata{Name: "Double", Category: "Test", Inputs: []string{indicators.InputSource}},
+		Params: []indicators.ParamSpec{
+			{Name: "factor", Type: indicators.ParamFloat, Default: 2, Min: 0},
+		},
+		New: func(_ *series.TimeSeries, src indicators.Indicator, p indicators.Params) []indicators.Indicator {
+			return []indicators.Indicator{indicators.NewProductIndicator(src, indicators.NewConstantIndicator(p.Float("factor")))}
+		},
+	}
+	require.NoError(t, indicators.RegisterIndicator(spec))
+
+	got, ok := indicators.LookupIndicator("test_double")
+	require.True(t, ok)
+	assert.Equal(t, []string{indicators.DefaultOutput}, got.Outputs)
+	assert.True(t, got.UsesSource())
+
+	ts := streamingTestSeries(5)
+	ind, err := indicators.NewIndicatorByName("test_double.value", ts, map[string]float64{"factor": 3})
+	require.NoError(t, err)
+	assertSameValue(t, "test_double", 4, ts.Candles[4].ClosePrice.Mul(decimal.New(3)), ind.Calculate(4))
+
+	bad := spec
+	bad.New = nil
+	assert.ErrorIs(t, indicators.RegisterIndicator(bad), indicators.ErrInvalidIndicatorSpec)
+	bad = spec
+	bad.Params = []indicators.ParamSpec{{Name: "factor", Type: indicators.ParamInt, Default: 1.5}}
+	assert.ErrorIs(t, indicators.RegisterIndicator(bad), indicators.ErrInvalidIndicatorSpec)
+	bad = spec
+	bad.Key = "a.b"
+	assert.ErrorIs(t, indicators.RegisterIndicator(bad), indicators.ErrInvalidIndicatorSpec)
+}
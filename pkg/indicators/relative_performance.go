@@ -0,0 +1,243 @@
+package indicators
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+type alignedCloseIndicator struct {
+	base  *series.TimeSeries
+	other *series.TimeSeries
+}
+
+// NewAlignedClosePriceIndicator returns the close of other at each candle of
+// base: the close of the last candle of other ending no later than the base
+// candle, so that series with different calendars or gaps can be compared
+// without looking ahead. It is NaN before the first candle of other. Panics
+// if base or other is nil.
+func NewAlignedClosePriceIndicator(base, other *series.TimeSeries) Indicator {
+	if base == nil || other == nil {
+		panic("goflux: AlignedClosePrice series cannot be nil")
+	}
+	return alignedCloseIndicator{base: base, other: other}
+}
+
+func (a alignedCloseIndicator) Calculate(index int) decimal.Decimal {
+	if index < 0 || index >= len(a.base.Candles) {
+		return decimal.NaN
+	}
+	end := a.base.Candles[index].Period.End
+	j := sort.Search(len(a.other.Candles), func(k int) bool { return a.other.Candles[k].Period.End.After(end) }) - 1
+	if j < 0 {
+		return decimal.NaN
+	}
+	return a.other.Candles[j].ClosePrice
+}
+
+func (a alignedCloseIndicator) Lookback() int { return 0 }
+
+// NewRelativeStrengthRatioIndicator returns the comparative relative strength
+// of s against benchmark: the close of s divided by the aligned close of the
+// benchmark. It rises while s outperforms the benchmark. NaN where the
+// benchmark close is zero or not yet known. Panics if s or benchmark is nil.
+func NewRelativeStrengthRatioIndicator(s, benchmark *series.TimeSeries) Indicator {
+	if s == nil || benchmark == nil {
+		panic("goflux: RelativeStrengthRatio series cannot be nil")
+	}
+	return NewQuotientIndicator(NewClosePriceIndicator(s), NewAlignedClosePriceIndicator(s, benchmark))
+}
+
+type mansfieldRSIndicator struct {
+	ratio  Indicator
+	window int
+}
+
+// NewMansfieldRSIndicator returns Mansfield relative strength: the percentage
+// by which the relative strength ratio of s to benchmark is above its simple
+// moving average over window, positive while s leads. Stan Weinstein used a
+// 52 week average on weekly candles. Panics if s or benchmark is nil or
+// window < 2.
+func NewMansfieldRSIndicator(s, benchmark *series.TimeSeries, window int) Indicator {
+	ratio := NewRelativeStrengthRatioIndicator(s, benchmark)
+	checkStatInputs("MansfieldRS", window, 2, ratio)
+	return mansfieldRSIndicator{ratio: ratio, window: window}
+}
+
+func (m mansfieldRSIndicator) Calculate(index int) decimal.Decimal {
+	if index < m.Lookback() {
+		return decimal.ZERO
+	}
+	values := windowFloats(m.ratio, index, m.window)
+	average := mean(values)
+	if average == 0 {
+		return decimal.NaN
+	}
+	return finiteDecimal(100 * (values[len(values)-1]/average - 1))
+}
+
+func (m mansfieldRSIndicator) Lookback() int { return Lookback(m.ratio) + m.window - 1 }
+
+// rsRatingWeights weight the returns over one to four periods, the most recent
+// period counting double, as in the IBD relative strength rating
+var rsRatingWeights = [4]float64{0.4, 0.2, 0.2, 0.2}
+
+type rsRatingIndicator struct {
+	closes []Indicator
+	period int
+}
+
+// NewRSRatingIndicator returns the relative strength rating of s within a
+// universe of series: the percentage of the universe whose performance score
+// is below that of s, ties counting half, from 0 to 100. The score weights the
+// returns over 1, 2, 3 and 4 periods by 40%, 20%, 20% and 20%; a period of 63
+// daily candles gives the quarterly weighting of the IBD rating. The universe
+// is aligned to the candles of s and its members without a score yet are left
+// out; s itself may be in it. NaN when no member has a score. Panics if s is
+// nil, the universe is empty or contains nil, or period < 1.
+func NewRSRatingIndicator(s *series.TimeSeries, universe []*series.TimeSeries, period int) Indicator {
+	if s == nil || len(universe) == 0 {
+		panic("goflux: RSRating needs a series and a universe")
+	}
+	if period < 1 {
+		panic("goflux: RSRating period must be >= 1")
+	}
+	telemetry.ReportUsage("RSRating", map[string]string{
+		"period":   strconv.Itoa(period),
+		"universe": strconv.Itoa(len(universe)),
+	})
+	closes := []Indicator{NewClosePriceIndicator(s)}
+	for _, member := range universe {
+		if member == nil {
+			panic("goflux: RSRating universe cannot contain nil")
+		}
+		if member == s {
+			continue
+		}
+		closes = append(closes, NewAlignedClosePriceIndicator(s, member))
+	}
+	return rsRatingIndicator{closes: closes, period: period}
+}
+
+// score returns the weighted performance of the closes at index, NaN without
+// the history
+func (r rsRatingIndicator) score(closes Indicator, index int) float64 {
+	last := closes.Calculate(index).Float()
+	score := 0.0
+	for k, weight := range rsRatingWeights {
+		past := closes.Calculate(index - (k+1)*r.period).Float()
+		if past <= 0 || math.IsNaN(past) {
+			return math.NaN()
+		}
+		score += weight * (last/past - 1)
+	}
+	return score
+}
+
+func (r rsRatingIndicator) Calculate(index int) decimal.Decimal {
+	if index < r.Lookback() {
+		return decimal.ZERO
+	}
+	own := r.score(r.closes[0], index)
+	if math.IsNaN(own) {
+		return decimal.NaN
+	}
+	below, count := 0.0, 0
+	for _, closes := range r.closes[1:] {
+		score := r.score(closes, index)
+		if math.IsNaN(score) {
+			continue
+		}
+		count++
+		if score < own {
+			below++
+		} else if score == own {
+			below += 0.5
+		}
+	}
+	if count == 0 {
+		return decimal.NaN
+	}
+	return decimal.New(100 * below / float64(count))
+}
+
+func (r rsRatingIndicator) Lookback() int { return len(rsRatingWeights) * r.period }
+
+// Quadrant is the quadrant of a Relative Rotation Graph
+type Quadrant int
+
+const (
+	// Leading has an RS-Ratio and RS-Momentum above 100
+	Leading Quadrant = iota + 1
+	// Weakening has an RS-Ratio above 100 and RS-Momentum below
+	Weakening
+	// Lagging has an RS-Ratio and RS-Momentum below 100
+	Lagging
+	// Improving has an RS-Ratio below 100 and RS-Momentum above
+	Improving
+)
+
+var quadrantNames = map[Quadrant]string{
+	Leading:   "Leading",
+	Weakening: "Weakening",
+	Lagging:   "Lagging",
+	Improving: "Improving",
+}
+
+func (q Quadrant) String() string {
+	if s, ok := quadrantNames[q]; ok {
+		return s
+	}
+	return "None"
+}
+
+// RRGQuadrant returns the quadrant of an RS-Ratio and RS-Momentum, zero when
+// either is NaN. Values of exactly 100 count as above.
+func RRGQuadrant(ratio, momentum decimal.Decimal) Quadrant {
+	if ratio.IsNaN() || momentum.IsNaN() {
+		return 0
+	}
+	hundred := decimal.New(100)
+	switch strong, rising := ratio.GTE(hundred), momentum.GTE(hundred); {
+	case strong && rising:
+		return Leading
+	case strong:
+		return Weakening
+	case rising:
+		return Improving
+	default:
+		return Lagging
+	}
+}
+
+var rrgOutputs = []string{"rs_ratio", "rs_momentum"}
+
+// rrgLine is 100 plus a z-score, zero before its lookback
+type rrgLine struct{ zscore Indicator }
+
+func (l rrgLine) Calculate(index int) decimal.Decimal {
+	if index < l.Lookback() {
+		return decimal.ZERO
+	}
+	return l.zscore.Calculate(index).Add(decimal.New(100))
+}
+
+func (l rrgLine) Lookback() int { return Lookback(l.zscore) }
+
+// NewRRGIndicator returns the coordinates of s against benchmark on a
+// Relative Rotation Graph, as a MultiOutputIndicator with outputs rs_ratio
+// and rs_momentum, both centred on 100. RS-Ratio is 100 plus the z-score of
+// the relative strength ratio over window, and RS-Momentum is 100 plus the
+// z-score of the change of RS-Ratio over window. The published JdK RS-Ratio
+// uses an undisclosed normalization, so values differ but the quadrants, given
+// by RRGQuadrant, rotate alike. Calculate returns rs_ratio. Panics if s or
+// benchmark is nil or window < 2.
+func NewRRGIndicator(s, benchmark *series.TimeSeries, window int) MultiOutputIndicator {
+	ratio := rrgLine{NewZScoreIndicator(NewRelativeStrengthRatioIndicator(s, benchmark), window)}
+	momentum := rrgLine{NewZScoreIndicator(NewDerivativeIndicator(ratio), window)}
+	return NewMultiOutputIndicator(rrgOutputs, ratio, momentum)
+}
@@ -0,0 +1,122 @@
+package indicators_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// datedCandles returns daily candles from 2024-01-01 closing at the price of
+// each day given in closes, skipping the other days
+func datedCandles(closes map[int]float64) *series.TimeSeries {
+	s := series.NewTimeSeries()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < 366; day++ {
+		p, ok := closes[day]
+		if !ok {
+			continue
+		}
+		c := series.NewCandle(series.NewTimePeriod(start.AddDate(0, 0, day), 24*time.Hour))
+		c.OpenPrice, c.ClosePrice, c.MaxPrice, c.MinPrice = decimal.New(p), decimal.New(p), decimal.New(p), decimal.New(p)
+		s.AddCandle(c)
+	}
+	return s
+}
+
+// growth returns n daily candles growing by rate per day from 100
+func growth(n int, rate float64) *series.TimeSeries {
+	prices := make([]float64, n)
+	for i := range prices {
+		prices[i] = 100 * math.Pow(1+rate, float64(i))
+	}
+	return dailyCandles(prices...)
+}
+
+func TestAlignedClosePriceIndicator(t *testing.T) {
+	base := datedCandles(map[int]float64{0: 1, 1: 1, 2: 1, 3: 1, 4: 1})
+	other := datedCandles(map[int]float64{1: 10, 3: 30})
+
+	aligned := indicators.NewAlignedClosePriceIndicator(base, other)
+	assert.True(t, aligned.Calculate(0).IsNaN(), "before the other series starts")
+	assert.Equal(t, 10.0, aligned.Calculate(1).Float())
+	assert.Equal(t, 10.0, aligned.Calculate(2).Float(), "the last known close")
+	assert.Equal(t, 30.0, aligned.Calculate(4).Float())
+	assert.True(t, aligned.Calculate(5).IsNaN())
+
+	assert.Panics(t, func() { indicators.NewAlignedClosePriceIndicator(base, nil) })
+}
+
+func TestRelativeStrengthRatioAndMansfield(t *testing.T) {
+	benchmark := dailyCandles(50, 50, 50, 50, 50)
+	s := dailyCandles(100, 100, 100, 200, 100)
+
+	ratio := indicators.NewRelativeStrengthRatioIndicator(s, benchmark)
+	assert.Equal(t, 2.0, ratio.Calculate(0).Float())
+	assert.Equal(t, 4.0, ratio.Calculate(3).Float())
+
+	mansfield := indicators.NewMansfieldRSIndicator(s, benchmark, 4)
+	assert.True(t, mansfield.Calculate(2).IsZero())
+	// 4 against an average of 2.5
+	assert.InDelta(t, 60, mansfield.Calculate(3).Float(), 1e-9)
+	assert.InDelta(t, -20, mansfield.Calculate(4).Float(), 1e-9)
+	assert.Equal(t, 3, indicators.Lookback(mansfield))
+
+	assert.Panics(t, func() { indicators.NewMansfieldRSIndicator(s, benchmark, 1) })
+}
+
+func TestRSRatingIndicator(t *testing.T) {
+	universe := []*series.TimeSeries{growth(20, 0.01), growth(20, 0.02), growth(20, 0.03), growth(20, 0.04), growth(20, 0.05)}
+
+	middle := indicators.NewRSRatingIndicator(universe[2], universe, 2)
+	assert.True(t, middle.Calculate(7).IsZero())
+	assert.Equal(t, 8, indicators.Lookback(middle))
+	assert.InDelta(t, 50, middle.Calculate(8).Float(), 1e-9)
+	assert.InDelta(t, 100, indicators.NewRSRatingIndicator(universe[4], universe, 2).Calculate(19).Float(), 1e-9)
+	assert.InDelta(t, 0, indicators.NewRSRatingIndicator(universe[0], universe, 2).Calculate(19).Float(), 1e-9)
+
+	// A member without the history is left out, and ties count half
+	late := datedCandles(map[int]float64{15: 100, 16: 110, 17: 120, 18: 130, 19: 140})
+	withLate := indicators.NewRSRatingIndicator(universe[2], []*series.TimeSeries{late, universe[2], growth(20, 0.03)}, 2)
+	assert.InDelta(t, 50, withLate.Calculate(19).Float(), 1e-9)
+	assert.True(t, indicators.NewRSRatingIndicator(universe[2], []*series.TimeSeries{late}, 2).Calculate(19).IsNaN())
+
+	assert.Panics(t, func() { indicators.NewRSRatingIndicator(universe[0], nil, 2) })
+}
+
+func TestRRGIndicator(t *testing.T) {
+	// s outperforms the benchmark at an accelerating rate
+	n := 40
+	prices := make([]float64, n)
+	for i := range prices {
+		prices[i] = 100 + 0.05*float64(i*i)
+	}
+	s, benchmark := dailyCandles(prices...), growth(n, 0)
+
+	rrg := indicators.NewRRGIndicator(s, benchmark, 10)
+	require.Equal(t, []string{"rs_ratio", "rs_momentum"}, rrg.Outputs())
+	ratio, momentum := rrg.Output("rs_ratio"), rrg.Output("rs_momentum")
+	assert.True(t, ratio.Calculate(8).IsZero())
+	assert.Equal(t, 9, indicators.Lookback(ratio))
+	assert.Equal(t, 19, indicators.Lookback(momentum))
+
+	assert.Greater(t, ratio.Calculate(39).Float(), 100.0)
+	assert.Greater(t, momentum.Calculate(39).Float(), 100.0)
+	assert.Equal(t, indicators.Leading, indicators.RRGQuadrant(ratio.Calculate(39), momentum.Calculate(39)))
+}
+
+func TestRRGQuadrant(t *testing.T) {
+	d := decimal.New
+	assert.Equal(t, indicators.Leading, indicators.RRGQuadrant(d(101), d(100)))
+	assert.Equal(t, indicators.Weakening, indicators.RRGQuadrant(d(101), d(99)))
+	assert.Equal(t, indicators.Lagging, indicators.RRGQuadrant(d(99), d(99)))
+	assert.Equal(t, indicators.Improving, indicators.RRGQuadrant(d(99), d(101)))
+	assert.Equal(t, indicators.Quadrant(0), indicators.RRGQuadrant(decimal.NaN, d(101)))
+	assert.Equal(t, "Improving", indicators.Improving.String())
+}
@@ -30,6 +30,13 @@ func (rsi relativeStrengthIndexIndicator) Calculate(index int) decimal.Decimal {
 	return rsi.oneHundred.Sub(rsi.oneHundred.Div(decimal.ONE.Add(relativeStrength)))
 }
 
+func (rsi relativeStrengthIndexIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(rsi.rsIndicator, dst)
+	for i := range dst {
+		dst[i] = rsi.oneHundred.Sub(rsi.oneHundred.Div(decimal.ONE.Add(dst[i])))
+	}
+}
+
 type relativeStrengthIndicator struct {
 	avgGain Indicator
 	avgLoss Indicator
@@ -64,3 +71,24 @@ func (rs relativeStrengthIndicator) Calculate(index int) decimal.Decimal {
 
 	return avgGain.Div(avgLoss)
 }
+
+func (rs relativeStrengthIndicator) ComputeInto(dst []decimal.Decimal) {
+	avgGain := ComputeAll(rs.avgGain, len(dst))
+	avgLoss := ComputeAll(rs.avgLoss, len(dst))
+	for i := range dst {
+		switch {
+		case i < rs.window-1:
+			dst[i] = decimal.ZERO
+		case !avgLoss[i].EQ(decimal.ZERO):
+			dst[i] = avgGain[i].Div(avgLoss[i])
+		case avgGain[i].EQ(decimal.ZERO):
+			dst[i] = decimal.ONE
+		default:
+			dst[i] = decimal.New(math.Inf(1))
+		}
+	}
+}
+
+func (rsi relativeStrengthIndexIndicator) Lookback() int { return Lookback(rsi.rsIndicator) }
+
+func (rs relativeStrengthIndicator) Lookback() int { return MaxLookback(rs.avgGain, rs.avgLoss) }
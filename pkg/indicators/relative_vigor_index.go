@@ -69,3 +69,7 @@ func (rvsn relativeVigorIndexSignalLine) Calculate(index int) decimal.Decimal {
 
 	return (rvi.Add(i).Add(j).Add(k)).Div(decimal.NewFromString("6"))
 }
+
+func (rvii relativeVigorIndexIndicator) Lookback() int { return 3 }
+
+func (rvsn relativeVigorIndexSignalLine) Lookback() int { return Lookback(rvsn.relativeVigorIndex) + 3 }
@@ -0,0 +1,62 @@
+package indicators
+
+import (
+	"time"
+
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// SessionKey returns the start of the session a candle beginning at t belongs
+// to. Consecutive candles with the same key form one session.
+type SessionKey func(t time.Time) time.Time
+
+// DailySessions starts a session at midnight in loc, or UTC if loc is nil
+func DailySessions(loc *time.Location) SessionKey {
+	return SessionsStartingAt(0, loc)
+}
+
+// SessionsStartingAt starts a session every day at offset after midnight in
+// loc, such as 17 hours for a New York FX day. A nil loc means UTC.
+func SessionsStartingAt(offset time.Duration, loc *time.Location) SessionKey {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Add(-offset).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc).Add(offset)
+	}
+}
+
+// SessionRange is the inclusive range of candle indexes of one session
+type SessionRange struct {
+	Start int
+	End   int
+}
+
+// Sessions splits s into consecutive sessions by key
+func Sessions(s *series.TimeSeries, key SessionKey) []SessionRange {
+	if s == nil || len(s.Candles) == 0 {
+		return nil
+	}
+	var sessions []SessionRange
+	current := SessionRange{}
+	currentKey := key(s.Candles[0].Period.Start)
+	for i := 1; i < len(s.Candles); i++ {
+		if k := key(s.Candles[i].Period.Start); !k.Equal(currentKey) {
+			current.End = i - 1
+			sessions = append(sessions, current)
+			current, currentKey = SessionRange{Start: i}, k
+		}
+	}
+	current.End = len(s.Candles) - 1
+	return append(sessions, current)
+}
+
+// sessionStart returns the index of the first candle in the session of index
+func sessionStart(s *series.TimeSeries, key SessionKey, index int) int {
+	k := key(s.Candles[index].Period.Start)
+	for index > 0 && key(s.Candles[index-1].Period.Start).Equal(k) {
+		index--
+	}
+	return index
+}
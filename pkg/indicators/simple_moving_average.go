@@ -38,8 +38,19 @@ func (sma smaIndicator) Calculate(index int) decimal.Decimal {
 	return result
 }
 
+// ComputeInto computes the average with a running window sum instead of
+// re-summing the window for every index.
+func (sma smaIndicator) ComputeInto(dst []decimal.Decimal) {
+	ComputeInto(sma.indicator, dst)
+	computeWindowSum(dst, sma.window, dst)
+	windowDec := decimal.NewFromInt(int64(sma.window))
+	for i := sma.window - 1; i < len(dst); i++ {
+		dst[i] = dst[i].Div(windowDec)
+	}
+}
+
 func (sma smaIndicator) Lookback() int {
-	return sma.window - 1
+	return Lookback(sma.indicator) + sma.window - 1
 }
 
 func (sma smaIndicator) Metadata() IndicatorMetadata {
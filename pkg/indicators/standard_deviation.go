@@ -18,3 +18,5 @@ type standardDeviationIndicator struct {
 func (sdi standardDeviationIndicator) Calculate(index int) decimal.Decimal {
 	return sdi.indicator.Calculate(index).Sqrt()
 }
+
+func (sdi standardDeviationIndicator) Lookback() int { return Lookback(sdi.indicator) }
@@ -0,0 +1,528 @@
+package indicators
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/telemetry"
+)
+
+// windowStatIndicator computes a statistic of the windows of its inputs
+// ending at each index. It is zero until every input has a full window, and
+// NaN where the statistic is undefined.
+type windowStatIndicator struct {
+	inputs   []Indicator
+	window   int
+	lookback int
+	stat     func(windows [][]float64) float64
+}
+
+func newWindowStat(window int, stat func([][]float64) float64, inputs ...Indicator) windowStatIndicator {
+	return windowStatIndicator{
+		inputs:   inputs,
+		window:   window,
+		lookback: MaxLookback(inputs...) + window - 1,
+		stat:     stat,
+	}
+}
+
+func (w windowStatIndicator) Calculate(index int) decimal.Decimal {
+	if index < w.lookback {
+		return decimal.ZERO
+	}
+	windows := make([][]float64, len(w.inputs))
+	for k, ind := range w.inputs {
+		windows[k] = windowFloats(ind, index, w.window)
+	}
+	return finiteDecimal(w.stat(windows))
+}
+
+func (w windowStatIndicator) Lookback() int { return w.lookback }
+
+// windowFloats returns the values of ind over the window ending at index,
+// oldest first
+func windowFloats(ind Indicator, index, window int) []float64 {
+	values := make([]float64, window)
+	for i := range values {
+		values[i] = ind.Calculate(index - window + 1 + i).Float()
+	}
+	return values
+}
+
+// finiteDecimal converts v, with NaN for NaN and infinities
+func finiteDecimal(v float64) decimal.Decimal {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return decimal.NaN
+	}
+	return decimal.New(v)
+}
+
+func mean(xs []float64) float64 {
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// centralMoments returns the mean and the second to fourth population
+// central moments of xs
+func centralMoments(xs []float64) (mu, m2, m3, m4 float64) {
+	mu = mean(xs)
+	for _, x := range xs {
+		d := x - mu
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	n := float64(len(xs))
+	return mu, m2 / n, m3 / n, m4 / n
+}
+
+// covariance returns the population covariance of xs and ys and the
+// variances of each
+func covariance(xs, ys []float64) (cov, varX, varY float64) {
+	mx, my := mean(xs), mean(ys)
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	n := float64(len(xs))
+	return cov / n, varX / n, varY / n
+}
+
+func pearson(xs, ys []float64) float64 {
+	cov, varX, varY := covariance(xs, ys)
+	if varX == 0 || varY == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// ranks returns the 1-based ranks of xs, ties sharing their mean rank
+func ranks(xs []float64) []float64 {
+	order := make([]int, len(xs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return xs[order[a]] < xs[order[b]] })
+	r := make([]float64, len(xs))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && xs[order[end+1]] == xs[order[start]] {
+			end++
+		}
+		for k := start; k <= end; k++ {
+			r[order[k]] = float64(start+end)/2 + 1
+		}
+		start = end + 1
+	}
+	return r
+}
+
+// NewZScoreIndicator returns how many population standard deviations the
+// value is from its mean over window, NaN when the window is flat. Panics if
+// indicator is nil or window < 2.
+func NewZScoreIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("ZScore", window, 2, indicator)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		mu, m2, _, _ := centralMoments(w[0])
+		if m2 == 0 {
+			return math.NaN()
+		}
+		return (w[0][len(w[0])-1] - mu) / math.Sqrt(m2)
+	}, indicator)
+}
+
+type percentRankIndicator struct {
+	indicator Indicator
+	window    int
+}
+
+// NewPercentRankIndicator returns the percentage, from 0 to 100, of the window
+// values before each index that are less than or equal to the value at it.
+// Panics if indicator is nil or window < 1.
+func NewPercentRankIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("PercentRank", window, 1, indicator)
+	return percentRankIndicator{indicator: indicator, window: window}
+}
+
+func (p percentRankIndicator) Calculate(index int) decimal.Decimal {
+	if index < p.Lookback() {
+		return decimal.ZERO
+	}
+	current := p.indicator.Calculate(index)
+	count := 0
+	for i := index - p.window; i < index; i++ {
+		if p.indicator.Calculate(i).LTE(current) {
+			count++
+		}
+	}
+	return decimal.New(100 * float64(count) / float64(p.window))
+}
+
+func (p percentRankIndicator) Lookback() int { return Lookback(p.indicator) + p.window }
+
+// NewSkewnessIndicator returns the population skewness of the values over
+// window, NaN when the window is flat. Panics if indicator is nil or
+// window < 3.
+func NewSkewnessIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("Skewness", window, 3, indicator)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		_, m2, m3, _ := centralMoments(w[0])
+		if m2 == 0 {
+			return math.NaN()
+		}
+		return m3 / math.Pow(m2, 1.5)
+	}, indicator)
+}
+
+// NewKurtosisIndicator returns the population excess kurtosis of the values
+// over window, zero for a normal distribution and NaN when the window is
+// flat. Panics if indicator is nil or window < 4.
+func NewKurtosisIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("Kurtosis", window, 4, indicator)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		_, m2, _, m4 := centralMoments(w[0])
+		if m2 == 0 {
+			return math.NaN()
+		}
+		return m4/(m2*m2) - 3
+	}, indicator)
+}
+
+// NewCorrelationIndicator returns the Pearson correlation of a and b over
+// window, NaN when either is flat. Pass returns rather than prices to
+// correlate moves. Panics if a or b is nil or window < 2.
+func NewCorrelationIndicator(a, b Indicator, window int) Indicator {
+	checkStatInputs("Correlation", window, 2, a, b)
+	return newWindowStat(window, func(w [][]float64) float64 { return pearson(w[0], w[1]) }, a, b)
+}
+
+// NewSpearmanCorrelationIndicator returns the Spearman rank correlation of a
+// and b over window, NaN when either is flat. Panics if a or b is nil or
+// window < 2.
+func NewSpearmanCorrelationIndicator(a, b Indicator, window int) Indicator {
+	checkStatInputs("SpearmanCorrelation", window, 2, a, b)
+	return newWindowStat(window, func(w [][]float64) float64 { return pearson(ranks(w[0]), ranks(w[1])) }, a, b)
+}
+
+// NewBetaIndicator returns the beta of asset to benchmark over window, their
+// covariance over the variance of benchmark, NaN when benchmark is flat. Pass
+// the returns of both. Panics if asset or benchmark is nil or window < 2.
+func NewBetaIndicator(asset, benchmark Indicator, window int) Indicator {
+	checkStatInputs("Beta", window, 2, asset, benchmark)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		cov, _, varBenchmark := covariance(w[0], w[1])
+		if varBenchmark == 0 {
+			return math.NaN()
+		}
+		return cov / varBenchmark
+	}, asset, benchmark)
+}
+
+// NewHurstExponentIndicator returns the Hurst exponent of the values over
+// window, estimated from how the standard deviation of their differences
+// grows with the lag, for lags of 1 to window/4. It is about 0.5 for a random
+// walk, below for a mean reverting series and above for a trending one. Pass
+// log prices. NaN when the window is flat. Panics if indicator is nil or
+// window < 8.
+func NewHurstExponentIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("HurstExponent", window, 8, indicator)
+	maxLag := window / 4
+	return newWindowStat(window, func(w [][]float64) float64 {
+		xs, ys := make([]float64, 0, maxLag), make([]float64, 0, maxLag)
+		for lag := 1; lag <= maxLag; lag++ {
+			diffs := make([]float64, len(w[0])-lag)
+			for i := range diffs {
+				diffs[i] = w[0][i+lag] - w[0][i]
+			}
+			_, m2, _, _ := centralMoments(diffs)
+			if m2 == 0 {
+				return math.NaN()
+			}
+			xs, ys = append(xs, math.Log(float64(lag))), append(ys, 0.5*math.Log(m2))
+		}
+		cov, varX, _ := covariance(xs, ys)
+		return cov / varX
+	}, indicator)
+}
+
+// NewVarianceRatioIndicator returns the Lo-MacKinlay variance ratio of the
+// values over window: the variance of their lag-bar differences over lag
+// times the variance of their one-bar differences. It is about 1 for a random
+// walk, below 1 for a mean reverting series and above 1 for a trending one.
+// NaN when the window is flat. Panics if indicator is nil, lag < 2 or
+// window <= lag+1.
+func NewVarianceRatioIndicator(indicator Indicator, window, lag int) Indicator {
+	if lag < 2 {
+		panic("goflux: VarianceRatio lag must be >= 2")
+	}
+	checkStatInputs("VarianceRatio", window, lag+2, indicator)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		xs := w[0]
+		n := len(xs) - 1
+		drift := (xs[n] - xs[0]) / float64(n)
+		var1, varLag := 0.0, 0.0
+		for i := 1; i <= n; i++ {
+			d := xs[i] - xs[i-1] - drift
+			var1 += d * d
+		}
+		for i := lag; i <= n; i++ {
+			d := xs[i] - xs[i-lag] - float64(lag)*drift
+			varLag += d * d
+		}
+		var1 /= float64(n)
+		varLag /= float64(n - lag + 1)
+		if var1 == 0 {
+			return math.NaN()
+		}
+		return varLag / (float64(lag) * var1)
+	}, indicator)
+}
+
+// NewHalfLifeIndicator returns the half-life of mean reversion, in bars, of
+// the values over window, from the regression of each change on the previous
+// value: -ln(2) over the slope. NaN when the series does not revert, with a
+// slope of zero or more. Panics if indicator is nil or window < 3.
+func NewHalfLifeIndicator(indicator Indicator, window int) Indicator {
+	checkStatInputs("HalfLife", window, 3, indicator)
+	return newWindowStat(window, func(w [][]float64) float64 {
+		xs := w[0]
+		levels, changes := xs[:len(xs)-1], make([]float64, len(xs)-1)
+		for i := range changes {
+			changes[i] = xs[i+1] - xs[i]
+		}
+		cov, varLevel, _ := covariance(levels, changes)
+		if varLevel == 0 {
+			return math.NaN()
+		}
+		slope := cov / varLevel
+		if slope >= 0 {
+			return math.NaN()
+		}
+		return -math.Ln2 / slope
+	}, indicator)
+}
+
+var (
+	adfOutputs          = []string{"stat", "critical1", "critical5", "critical10"}
+	engleGrangerOutputs = []string{"stat", "critical1", "critical5", "critical10", "hedge"}
+)
+
+// mackinnonCritical holds the response surface coefficients of MacKinnon
+// (2010) for the 1%, 5% and 10% critical values of the Dickey-Fuller t
+// statistic with a constant, for one series (ADF) and for the residuals of
+// two (Engle-Granger)
+var mackinnonCritical = [2][3][4]float64{
+	{
+		{-3.43035, -6.5393, -16.786, -79.433},
+		{-2.86154, -2.8903, -4.234, -40.040},
+		{-2.56677, -1.5384, -2.809, 0},
+	},
+	{
+		{-3.89644, -10.9519, -22.527, 0},
+		{-3.33613, -6.1101, -6.823, 0},
+		{-3.04445, -4.2412, -2.720, 0},
+	},
+}
+
+// criticalValues returns the 1%, 5% and 10% critical values for nobs
+// observations and the given number of series
+func criticalValues(series, nobs int) [3]float64 {
+	var cv [3]float64
+	t := float64(nobs)
+	for k, b := range mackinnonCritical[series-1] {
+		cv[k] = b[0] + b[1]/t + b[2]/(t*t) + b[3]/(t*t*t)
+	}
+	return cv
+}
+
+// dickeyFuller returns the t statistic of the lagged level in the regression
+// of the changes of ys on their lagged level, a constant when constant is
+// set, and lags lagged changes, with the number of observations used
+func dickeyFuller(ys []float64, lags int, constant bool) (stat float64, nobs int) {
+	changes := make([]float64, len(ys)-1)
+	for i := range changes {
+		changes[i] = ys[i+1] - ys[i]
+	}
+	var x [][]float64
+	var y []float64
+	for t := lags; t < len(changes); t++ {
+		row := []float64{ys[t]}
+		if constant {
+			row = append(row, 1)
+		}
+		for l := 1; l <= lags; l++ {
+			row = append(row, changes[t-l])
+		}
+		x, y = append(x, row), append(y, changes[t])
+	}
+	coef, stderr, ok := leastSquares(x, y)
+	if !ok || stderr[0] == 0 {
+		return math.NaN(), len(y)
+	}
+	return coef[0] / stderr[0], len(y)
+}
+
+// leastSquares fits y = x*coef and returns the coefficients with their
+// standard errors. ok is false when x is singular or leaves no degrees of
+// freedom.
+func leastSquares(x [][]float64, y []float64) (coef, stderr []float64, ok bool) {
+	if len(x) == 0 {
+		return nil, nil, false
+	}
+	n, k := len(x), len(x[0])
+	if n <= k {
+		return nil, nil, false
+	}
+	// Invert X'X by Gauss-Jordan elimination with partial pivoting
+	xtx := make([][]float64, k)
+	xty := make([]float64, k)
+	for a := 0; a < k; a++ {
+		xtx[a] = make([]float64, 2*k)
+		xtx[a][k+a] = 1
+		for r := 0; r < n; r++ {
+			xty[a] += x[r][a] * y[r]
+			for b := 0; b < k; b++ {
+				xtx[a][b] += x[r][a] * x[r][b]
+			}
+		}
+	}
+	for col := 0; col < k; col++ {
+		pivot := col
+		for r := col + 1; r < k; r++ {
+			if math.Abs(xtx[r][col]) > math.Abs(xtx[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(xtx[pivot][col]) < 1e-12 {
+			return nil, nil, false
+		}
+		xtx[col], xtx[pivot] = xtx[pivot], xtx[col]
+		scale := xtx[col][col]
+		for c := range xtx[col] {
+			xtx[col][c] /= scale
+		}
+		for r := 0; r < k; r++ {
+			if r == col || xtx[r][col] == 0 {
+				continue
+			}
+			factor := xtx[r][col]
+			for c := range xtx[r] {
+				xtx[r][c] -= factor * xtx[col][c]
+			}
+		}
+	}
+
+	coef = make([]float64, k)
+	for a := 0; a < k; a++ {
+		for b := 0; b < k; b++ {
+			coef[a] += xtx[a][k+b] * xty[b]
+		}
+	}
+	rss := 0.0
+	for r := 0; r < n; r++ {
+		fit := 0.0
+		for a := 0; a < k; a++ {
+			fit += x[r][a] * coef[a]
+		}
+		rss += (y[r] - fit) * (y[r] - fit)
+	}
+	sigma2 := rss / float64(n-k)
+	stderr = make([]float64, k)
+	for a := 0; a < k; a++ {
+		stderr[a] = math.Sqrt(sigma2 * xtx[a][k+a])
+	}
+	return coef, stderr, true
+}
+
+// NewADFIndicator returns the Augmented Dickey-Fuller test of the values over
+// window, with a constant and lags lagged changes, as a MultiOutputIndicator
+// with outputs stat, the t statistic, and critical1, critical5 and critical10,
+// the MacKinnon critical values at 1%, 5% and 10%. A stat below a critical
+// value rejects a unit root at that level: the series is stationary and mean
+// reverting. Calculate returns the stat, NaN for a flat window. Panics if
+// indicator is nil, lags < 0 or window < 2*lags+4.
+func NewADFIndicator(indicator Indicator, window, lags int) MultiOutputIndicator {
+	if lags < 0 {
+		panic("goflux: ADF lags must be >= 0")
+	}
+	checkStatInputs("ADF", window, 2*lags+4, indicator)
+	nobs := window - 1 - lags
+	cv := criticalValues(1, nobs)
+	stat := newWindowStat(window, func(w [][]float64) float64 {
+		stat, _ := dickeyFuller(w[0], lags, true)
+		return stat
+	}, indicator)
+	return NewMultiOutputIndicator(adfOutputs, stat,
+		constantLine(cv[0], stat.lookback), constantLine(cv[1], stat.lookback), constantLine(cv[2], stat.lookback))
+}
+
+// NewEngleGrangerIndicator returns the Engle-Granger cointegration test of y
+// and x over window: y is regressed on x with a constant, and the residual
+// spread is tested for a unit root by a Dickey-Fuller regression with lags
+// lagged changes. It is a MultiOutputIndicator with outputs stat, critical1,
+// critical5 and critical10 as for NewADFIndicator, using the two-series
+// MacKinnon values, and hedge, the slope of y on x, which is the hedge ratio
+// of the spread y - hedge*x. Calculate returns the stat. Panics if y or x is
+// nil, lags < 0 or window < 2*lags+4.
+func NewEngleGrangerIndicator(y, x Indicator, window, lags int) MultiOutputIndicator {
+	if lags < 0 {
+		panic("goflux: EngleGranger lags must be >= 0")
+	}
+	checkStatInputs("EngleGranger", window, 2*lags+4, y, x)
+	nobs := window - 1 - lags
+	cv := criticalValues(2, nobs)
+	hedge := func(w [][]float64) float64 {
+		cov, varX, _ := covariance(w[1], w[0])
+		if varX == 0 {
+			return math.NaN()
+		}
+		return cov / varX
+	}
+	stat := newWindowStat(window, func(w [][]float64) float64 {
+		beta := hedge(w)
+		if math.IsNaN(beta) {
+			return beta
+		}
+		alpha := mean(w[0]) - beta*mean(w[1])
+		spread := make([]float64, len(w[0]))
+		for i := range spread {
+			spread[i] = w[0][i] - alpha - beta*w[1][i]
+		}
+		stat, _ := dickeyFuller(spread, lags, false)
+		return stat
+	}, y, x)
+	return NewMultiOutputIndicator(engleGrangerOutputs, stat,
+		constantLine(cv[0], stat.lookback), constantLine(cv[1], stat.lookback), constantLine(cv[2], stat.lookback),
+		newWindowStat(window, hedge, y, x))
+}
+
+// constantLine is v from lookback on and zero before
+func constantLine(v float64, lookback int) Indicator {
+	return withLookback{indicatorFunc(func(index int) decimal.Decimal {
+		if index < lookback {
+			return decimal.ZERO
+		}
+		return decimal.New(v)
+	}), lookback}
+}
+
+// checkStatInputs panics unless every input is set and window >= minimum, and
+// reports the usage of the statistic
+func checkStatInputs(name string, window, minimum int, inputs ...Indicator) {
+	for _, ind := range inputs {
+		if ind == nil {
+			panic("goflux: " + name + " indicator cannot be nil")
+		}
+	}
+	if window < minimum {
+		panic("goflux: " + name + " window must be >= " + strconv.Itoa(minimum))
+	}
+	telemetry.ReportUsage(name, map[string]string{"window": strconv.Itoa(window)})
+}
@@ -0,0 +1,173 @@
+package indicators_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+func closes(values ...float64) indicators.Indicator {
+	return indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(values...))
+}
+
+// randomWalk returns n steps of a seeded Gaussian random walk from 100
+func randomWalk(seed int64, n int) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	values := make([]float64, n)
+	level := 100.0
+	for i := range values {
+		level += r.NormFloat64()
+		values[i] = level
+	}
+	return values
+}
+
+// whiteNoise returns n seeded Gaussian values around 100
+func whiteNoise(seed int64, n int) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 100 + r.NormFloat64()
+	}
+	return values
+}
+
+func TestZScoreIndicator(t *testing.T) {
+	z := indicators.NewZScoreIndicator(closes(1, 2, 3, 4, 5, 5, 5, 5, 5), 5)
+	assert.True(t, z.Calculate(3).IsZero())
+	assert.InDelta(t, math.Sqrt2, z.Calculate(4).Float(), 1e-9)
+	assert.True(t, z.Calculate(8).IsNaN(), "flat window")
+	assert.Equal(t, 4, indicators.Lookback(z))
+
+	assert.Panics(t, func() { indicators.NewZScoreIndicator(nil, 5) })
+	assert.Panics(t, func() { indicators.NewZScoreIndicator(closes(1), 1) })
+}
+
+func TestPercentRankIndicator(t *testing.T) {
+	rank := indicators.NewPercentRankIndicator(closes(1, 2, 3, 4, 5, 2, 3), 4)
+	assert.True(t, rank.Calculate(3).IsZero())
+	assert.InDelta(t, 100, rank.Calculate(4).Float(), 1e-9)
+	// 2 against 2, 3, 4, 5
+	assert.InDelta(t, 25, rank.Calculate(5).Float(), 1e-9)
+	// 3 against 3, 4, 5, 2
+	assert.InDelta(t, 50, rank.Calculate(6).Float(), 1e-9)
+	assert.Equal(t, 4, indicators.Lookback(rank))
+}
+
+func TestSkewnessAndKurtosisIndicators(t *testing.T) {
+	values := closes(1, 2, 3, 4, 5, 1, 1, 1, 1, 10)
+	skew := indicators.NewSkewnessIndicator(values, 5)
+	assert.InDelta(t, 0, skew.Calculate(4).Float(), 1e-9)
+	assert.InDelta(t, 1.5, skew.Calculate(9).Float(), 1e-9)
+
+	kurt := indicators.NewKurtosisIndicator(values, 5)
+	assert.InDelta(t, -1.3, kurt.Calculate(4).Float(), 1e-9)
+	assert.InDelta(t, 0.25, kurt.Calculate(9).Float(), 1e-9)
+
+	assert.True(t, indicators.NewSkewnessIndicator(closes(2, 2, 2), 3).Calculate(2).IsNaN())
+	assert.Panics(t, func() { indicators.NewKurtosisIndicator(values, 3) })
+}
+
+func TestCorrelationIndicators(t *testing.T) {
+	n := 30
+	a, twice, inverse, cubed := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+	for i := range a {
+		x := float64(i + 1)
+		a[i], twice[i], inverse[i], cubed[i] = x, 2*x+1, -x, x*x*x
+	}
+
+	pearson := indicators.NewCorrelationIndicator(closes(a...), closes(twice...), 10)
+	assert.True(t, pearson.Calculate(8).IsZero())
+	assert.InDelta(t, 1, pearson.Calculate(9).Float(), 1e-9)
+	assert.InDelta(t, -1, indicators.NewCorrelationIndicator(closes(a...), closes(inverse...), 10).Calculate(29).Float(), 1e-9)
+
+	// Ranks see a monotonic relation as perfect, Pearson does not
+	assert.Less(t, indicators.NewCorrelationIndicator(closes(a...), closes(cubed...), 10).Calculate(9).Float(), 0.99)
+	assert.InDelta(t, 1, indicators.NewSpearmanCorrelationIndicator(closes(a...), closes(cubed...), 10).Calculate(9).Float(), 1e-9)
+
+	beta := indicators.NewBetaIndicator(closes(twice...), closes(a...), 10)
+	assert.InDelta(t, 2, beta.Calculate(29).Float(), 1e-9)
+	assert.Equal(t, 9, indicators.Lookback(beta))
+	assert.True(t, indicators.NewBetaIndicator(closes(a...), closes(make([]float64, n)...), 10).Calculate(29).IsNaN())
+
+	assert.Panics(t, func() { indicators.NewCorrelationIndicator(closes(a...), nil, 10) })
+}
+
+func TestSpearmanCorrelationTies(t *testing.T) {
+	// Ranks 1.5, 1.5, 3, 4 against 1, 2, 3, 4
+	spearman := indicators.NewSpearmanCorrelationIndicator(closes(1, 1, 2, 3), closes(1, 2, 3, 4), 4)
+	assert.InDelta(t, 0.9486833, spearman.Calculate(3).Float(), 1e-6)
+}
+
+func TestMeanReversionIndicators(t *testing.T) {
+	walk := closes(randomWalk(1, 600)...)
+	noise := closes(whiteNoise(2, 600)...)
+
+	hurstWalk := indicators.NewHurstExponentIndicator(walk, 500).Calculate(599).Float()
+	hurstNoise := indicators.NewHurstExponentIndicator(noise, 500).Calculate(599).Float()
+	assert.InDelta(t, 0.5, hurstWalk, 0.15)
+	assert.Less(t, hurstNoise, 0.15)
+
+	assert.InDelta(t, 1, indicators.NewVarianceRatioIndicator(walk, 500, 4).Calculate(599).Float(), 0.25)
+	assert.InDelta(t, 0.25, indicators.NewVarianceRatioIndicator(noise, 500, 4).Calculate(599).Float(), 0.1)
+	assert.Panics(t, func() { indicators.NewVarianceRatioIndicator(walk, 5, 4) })
+}
+
+func TestHalfLifeIndicator(t *testing.T) {
+	// A deviation from 10 that halves every bar
+	values := make([]float64, 8)
+	for i := range values {
+		values[i] = 10 + 64*math.Pow(0.5, float64(i))
+	}
+	halfLife := indicators.NewHalfLifeIndicator(closes(values...), 6)
+	assert.True(t, halfLife.Calculate(4).IsZero())
+	assert.InDelta(t, math.Ln2/0.5, halfLife.Calculate(7).Float(), 1e-9)
+
+	assert.True(t, indicators.NewHalfLifeIndicator(closes(1, 2, 4, 8, 16), 5).Calculate(4).IsNaN(), "diverging")
+}
+
+func TestADFIndicator(t *testing.T) {
+	adf := indicators.NewADFIndicator(closes(whiteNoise(3, 300)...), 200, 1)
+	require.Equal(t, []string{"stat", "critical1", "critical5", "critical10"}, adf.Outputs())
+	stat, critical1 := adf.Output("stat"), adf.Output("critical1")
+	critical5, critical10 := adf.Output("critical5"), adf.Output("critical10")
+	assert.Equal(t, 199, indicators.Lookback(adf))
+	assert.True(t, stat.Calculate(198).IsZero())
+
+	// MacKinnon values for 198 observations
+	assert.InDelta(t, -3.4638, critical1.Calculate(299).Float(), 1e-3)
+	assert.InDelta(t, -2.8762, critical5.Calculate(299).Float(), 1e-3)
+	assert.InDelta(t, -2.5746, critical10.Calculate(299).Float(), 1e-3)
+
+	// Noise is stationary, a random walk is not
+	assert.Less(t, stat.Calculate(299).Float(), critical1.Calculate(299).Float())
+	walk := indicators.NewADFIndicator(closes(randomWalk(4, 300)...), 200, 1)
+	assert.Greater(t, walk.Calculate(299).Float(), walk.Output("critical5").Calculate(299).Float())
+
+	assert.True(t, indicators.NewADFIndicator(closes(make([]float64, 10)...), 8, 1).Calculate(9).IsNaN())
+	assert.Panics(t, func() { indicators.NewADFIndicator(stat, 5, 1) })
+}
+
+func TestEngleGrangerIndicator(t *testing.T) {
+	x := randomWalk(5, 300)
+	noise := whiteNoise(6, 300)
+	pair, unrelated := make([]float64, 300), randomWalk(7, 300)
+	for i := range pair {
+		pair[i] = 2*x[i] + noise[i] - 100
+	}
+
+	eg := indicators.NewEngleGrangerIndicator(closes(pair...), closes(x...), 200, 1)
+	require.Equal(t, []string{"stat", "critical1", "critical5", "critical10", "hedge"}, eg.Outputs())
+	assert.InDelta(t, 2, eg.Output("hedge").Calculate(299).Float(), 0.05)
+	assert.InDelta(t, -3.9523, eg.Output("critical1").Calculate(299).Float(), 1e-3)
+	assert.Less(t, eg.Calculate(299).Float(), eg.Output("critical1").Calculate(299).Float())
+
+	apart := indicators.NewEngleGrangerIndicator(closes(unrelated...), closes(x...), 200, 1)
+	assert.Greater(t, apart.Calculate(299).Float(), apart.Output("critical5").Calculate(299).Float())
+}
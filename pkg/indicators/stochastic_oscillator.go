@@ -78,6 +78,17 @@ func NewSlowStochasticIndicator(k Indicator, window int) Indicator {
 	return dIndicator{k, window}
 }
 
+// NewStochasticIndicator returns the fast %K over kWindow and its dWindow %D as
+// a MultiOutputIndicator with the outputs k and d
+func NewStochasticIndicator(series *series.TimeSeries, kWindow, dWindow int) MultiOutputIndicator {
+	k := NewFastStochasticIndicator(series, kWindow)
+	return NewMultiOutputIndicator(stochasticOutputs, k, NewSlowStochasticIndicator(k, dWindow))
+}
+
 func (d dIndicator) Calculate(index int) decimal.Decimal {
 	return NewSimpleMovingAverage(d.k, d.window).Calculate(index)
 }
+
+func (k kIndicator) Lookback() int { return max(k.window, 1) - 1 }
+
+func (d dIndicator) Lookback() int { return Lookback(d.k) + safeWindow(d.window) - 1 }
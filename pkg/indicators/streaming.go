@@ -2,79 +2,396 @@ package indicators
 
 import (
 	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
 )
 
+// StreamSnapshot describes a streaming indicator after its most recent update
+type StreamSnapshot struct {
+	// Count is the number of bars consumed since construction or the last Reset
+	Count int
+	// Value is the output for the most recent bar, or zero before the first bar
+	Value decimal.Decimal
+}
+
+// Streamer is the contract shared by all streaming indicators. Each update is
+// O(1) amortized unless documented otherwise, and the output for bar i equals
+// the batch indicator's Calculate(i) over the same data.
+//
+// Calculate(index) returns the output previously produced for bar index, so a
+// streamer can be handed to rules that look back at earlier values. Only the
+// bars of the indicator's window are kept by default, so memory stays bounded
+// on an endless feed; older bars read as NaN. SetHistory keeps more, or every
+// bar. Streamers are not safe for concurrent updates.
+type Streamer interface {
+	Indicator
+	Reset()
+	Snapshot() StreamSnapshot
+	SetHistory(bars int)
+}
+
 // StreamingIndicator is an interface for indicators that can be updated with new values in real-time
 type StreamingIndicator interface {
-	Indicator
+	Streamer
 	Next(val decimal.Decimal) decimal.Decimal
 }
 
+// CandleStreamingIndicator is a streaming indicator that consumes whole candles
+type CandleStreamingIndicator interface {
+	Streamer
+	NextCandle(c *series.Candle) decimal.Decimal
+}
+
+// minStreamHistory is the fewest bars a streamer keeps by default, enough
+// for rules comparing a bar with the one before it
+const minStreamHistory = 2
+
+// history keeps the values of the last size bars recorded, or of every bar
+// if size is not positive
+type history[T any] struct {
+	values []T // ring of the kept values, the oldest at head
+	head   int
+	count  int
+	size   int
+}
+
+// newHistory returns a history keeping the values of the last bars bars
+func newHistory[T any](bars int) history[T] {
+	return history[T]{size: max(bars, minStreamHistory)}
+}
+
+func (h *history[T]) push(val T) {
+	h.count++
+	if h.size <= 0 || len(h.values) < h.size {
+		h.values = append(h.values, val)
+		return
+	}
+	h.values[h.head] = val
+	h.head = (h.head + 1) % len(h.values)
+}
+
+// at returns the value of bar index, and false if the bar has not been
+// recorded or is no longer kept
+func (h *history[T]) at(index int) (T, bool) {
+	first := h.count - len(h.values)
+	if index < first || index >= h.count {
+		var zero T
+		return zero, false
+	}
+	return h.values[(h.head+index-first)%len(h.values)], true
+}
+
+// dropped reports whether bar index has been recorded but is no longer kept
+func (h *history[T]) dropped(index int) bool {
+	return index >= 0 && index < h.count-len(h.values)
+}
+
+func (h *history[T]) resize(bars int) {
+	kept := make([]T, 0, len(h.values))
+	for i := h.count - len(h.values); i < h.count; i++ {
+		val, _ := h.at(i)
+		kept = append(kept, val)
+	}
+	if bars > 0 && len(kept) > bars {
+		kept = kept[len(kept)-bars:]
+	}
+	h.values, h.head, h.size = kept, 0, bars
+}
+
+func (h *history[T]) clear() {
+	h.values, h.head, h.count = h.values[:0], 0, 0
+}
+
+// streamHistory records the outputs of a streamer so Calculate can serve
+// earlier bars. It keeps the bars of the indicator's window unless
+// SetHistory says otherwise.
+type streamHistory struct {
+	outputs history[decimal.Decimal]
+}
+
+func newStreamHistory(bars int) streamHistory {
+	return streamHistory{outputs: newHistory[decimal.Decimal](bars)}
+}
+
+// Calculate returns the output of bar index: zero before the first bar or
+// after the latest, and NaN for a bar no longer kept
+func (h *streamHistory) Calculate(index int) decimal.Decimal {
+	if val, ok := h.outputs.at(index); ok {
+		return val
+	}
+	if h.outputs.dropped(index) {
+		return decimal.NaN
+	}
+	return decimal.ZERO
+}
+
+// SetHistory sets how many of the latest bars the streamer keeps the outputs
+// of, for Calculate and the other per-bar accessors; every bar if bars is not
+// positive. By default it keeps the bars of the indicator's window, at least
+// two.
+func (h *streamHistory) SetHistory(bars int) {
+	h.outputs.resize(bars)
+}
+
+func (h *streamHistory) Snapshot() StreamSnapshot {
+	if h.outputs.count == 0 {
+		return StreamSnapshot{Value: decimal.ZERO}
+	}
+	return StreamSnapshot{Count: h.outputs.count, Value: h.Calculate(h.outputs.count - 1)}
+}
+
+func (h *streamHistory) record(val decimal.Decimal) decimal.Decimal {
+	h.outputs.push(val)
+	return val
+}
+
+// index returns the bar index the next recorded value will have.
+func (h *streamHistory) index() int {
+	return h.outputs.count
+}
+
+func (h *streamHistory) clear() {
+	h.outputs.clear()
+}
+
+// rollingWindow keeps the last size values with their running sum and sum of
+// squares. The sums are rebuilt from the buffer once per window so rounding
+// error cannot accumulate over long sessions.
+type rollingWindow struct {
+	buf      []decimal.Decimal
+	head     int
+	size     int
+	sum      decimal.Decimal
+	sumSq    decimal.Decimal
+	sinceFix int
+}
+
+func newRollingWindow(size int) *rollingWindow {
+	size = safeWindow(size)
+	return &rollingWindow{buf: make([]decimal.Decimal, 0, size), size: size, sum: decimal.ZERO, sumSq: decimal.ZERO}
+}
+
+func (w *rollingWindow) push(val decimal.Decimal) {
+	if len(w.buf) < w.size {
+		w.buf = append(w.buf, val)
+		w.sum = w.sum.Add(val)
+		w.sumSq = w.sumSq.Add(val.Mul(val))
+		return
+	}
+	old := w.buf[w.head]
+	w.buf[w.head] = val
+	w.head = (w.head + 1) % w.size
+	w.sinceFix++
+	if w.sinceFix >= w.size {
+		w.resync()
+		return
+	}
+	w.sum = w.sum.Sub(old).Add(val)
+	w.sumSq = w.sumSq.Sub(old.Mul(old)).Add(val.Mul(val))
+}
+
+func (w *rollingWindow) resync() {
+	w.sinceFix = 0
+	w.sum = decimal.ZERO
+	w.sumSq = decimal.ZERO
+	for i := 0; i < len(w.buf); i++ {
+		val := w.at(i)
+		w.sum = w.sum.Add(val)
+		w.sumSq = w.sumSq.Add(val.Mul(val))
+	}
+}
+
+// at returns the i-th oldest value in the window.
+func (w *rollingWindow) at(i int) decimal.Decimal {
+	return w.buf[(w.head+i)%len(w.buf)]
+}
+
+// newest returns the most recently pushed value.
+func (w *rollingWindow) newest() decimal.Decimal {
+	return w.at(len(w.buf) - 1)
+}
+
+func (w *rollingWindow) len() int {
+	return len(w.buf)
+}
+
+func (w *rollingWindow) full() bool {
+	return len(w.buf) == w.size
+}
+
+func (w *rollingWindow) reset() {
+	w.buf = w.buf[:0]
+	w.head = 0
+	w.sinceFix = 0
+	w.sum = decimal.ZERO
+	w.sumSq = decimal.ZERO
+}
+
+// extremumWindow tracks the maximum or minimum of the last size values with a
+// monotonic deque. A non-positive size tracks the extremum of all values.
+type extremumWindow struct {
+	size  int
+	max   bool
+	count int
+	idx   []int
+	vals  []decimal.Decimal
+}
+
+func newExtremumWindow(size int, max bool) *extremumWindow {
+	return &extremumWindow{size: size, max: max}
+}
+
+func (w *extremumWindow) push(val decimal.Decimal) {
+	for n := len(w.vals); n > 0; n = len(w.vals) {
+		back := w.vals[n-1]
+		if (w.max && back.GT(val)) || (!w.max && back.LT(val)) {
+			break
+		}
+		w.idx = w.idx[:n-1]
+		w.vals = w.vals[:n-1]
+	}
+	w.idx = append(w.idx, w.count)
+	w.vals = append(w.vals, val)
+	w.count++
+	if w.size > 0 {
+		for w.idx[0] <= w.count-1-w.size {
+			w.idx = w.idx[1:]
+			w.vals = w.vals[1:]
+		}
+	}
+}
+
+func (w *extremumWindow) value() decimal.Decimal {
+	if len(w.vals) == 0 {
+		return decimal.ZERO
+	}
+	return w.vals[0]
+}
+
+func (w *extremumWindow) reset() {
+	w.count = 0
+	w.idx = w.idx[:0]
+	w.vals = w.vals[:0]
+}
+
 // StreamingSMA is a streaming version of SMA
 type StreamingSMA struct {
+	streamHistory
 	window int
-	values []decimal.Decimal
-	sum    decimal.Decimal
+	values *rollingWindow
 }
 
+// NewStreamingSMA returns a streaming counterpart of NewSimpleMovingAverage
 func NewStreamingSMA(window int) *StreamingSMA {
-	if window <= 0 {
-		window = 1
-	}
+	window = safeWindow(window)
 	return &StreamingSMA{
-		window: window,
-		values: make([]decimal.Decimal, 0, window),
-		sum:    decimal.ZERO,
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		values:        newRollingWindow(window),
 	}
 }
 
-func (s *StreamingSMA) Calculate(index int) decimal.Decimal {
-	// Not really applicable for streaming but we implement it for compatibility
-	return decimal.ZERO
-}
-
+// Next consumes the next input value and returns the moving average
 func (s *StreamingSMA) Next(val decimal.Decimal) decimal.Decimal {
-	if len(s.values) >= s.window {
-		s.sum = s.sum.Sub(s.values[0])
-		s.values = s.values[1:]
+	s.values.push(val)
+	if !s.values.full() {
+		return s.record(decimal.ZERO)
 	}
-	s.values = append(s.values, val)
-	s.sum = s.sum.Add(val)
+	return s.record(s.values.sum.Div(decimal.NewFromInt(int64(s.window))))
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingSMA) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
 
-	return s.sum.Div(decimal.New(float64(len(s.values))))
+// Reset clears all state
+func (s *StreamingSMA) Reset() {
+	s.clear()
+	s.values.reset()
 }
 
-// StreamingEMA is a streaming version of EMA
+// StreamingEMA is a streaming version of EMA. Like NewEMAIndicator it is
+// seeded with the simple average of the first window values.
 type StreamingEMA struct {
-	window    int
-	alpha     decimal.Decimal
-	lastValue decimal.Decimal
-	isFirst   bool
+	streamHistory
+	window int
+	alpha  decimal.Decimal
+	seed   *StreamingSMA
+	last   decimal.Decimal
 }
 
+// NewStreamingEMA returns a streaming counterpart of NewEMAIndicator
 func NewStreamingEMA(window int) *StreamingEMA {
-	if window <= 0 {
-		window = 1
-	}
+	window = safeWindow(window)
 	return &StreamingEMA{
-		window:  window,
-		alpha:   decimal.New(2).Div(decimal.New(float64(window + 1))),
-		isFirst: true,
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		alpha:         decimal.New(2).Div(decimal.NewFromInt(int64(window + 1))),
+		seed:          NewStreamingSMA(window),
 	}
 }
 
-func (s *StreamingEMA) Calculate(index int) decimal.Decimal {
-	return s.lastValue
+// Next consumes the next input value and returns the moving average
+func (s *StreamingEMA) Next(val decimal.Decimal) decimal.Decimal {
+	index := s.index()
+	if index < s.window {
+		s.last = s.seed.Next(val)
+		return s.record(s.last)
+	}
+	s.last = val.Mul(s.alpha).Add(s.last.Mul(decimal.ONE.Sub(s.alpha)))
+	return s.record(s.last)
 }
 
-func (s *StreamingEMA) Next(val decimal.Decimal) decimal.Decimal {
-	if s.isFirst {
-		s.lastValue = val
-		s.isFirst = false
-		return val
+// NextCandle consumes the close price of c
+func (s *StreamingEMA) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingEMA) Reset() {
+	s.clear()
+	s.seed.Reset()
+	s.last = decimal.ZERO
+}
+
+// StreamingMMA is a streaming version of the modified (Wilder) moving average
+type StreamingMMA struct {
+	streamHistory
+	window int
+	factor decimal.Decimal
+	seed   *StreamingSMA
+	last   decimal.Decimal
+}
+
+// NewStreamingMMA returns a streaming counterpart of NewMMAIndicator
+func NewStreamingMMA(window int) *StreamingMMA {
+	window = safeWindow(window)
+	return &StreamingMMA{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		factor:        decimal.New(1.0 / float64(window)),
+		seed:          NewStreamingSMA(window),
 	}
+}
+
+// Next consumes the next input value and returns the moving average
+func (s *StreamingMMA) Next(val decimal.Decimal) decimal.Decimal {
+	if s.index() < s.window {
+		s.last = s.seed.Next(val)
+		return s.record(s.last)
+	}
+	s.last = s.last.Add(s.factor.Mul(val.Sub(s.last)))
+	return s.record(s.last)
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingMMA) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
 
-	// EMA = alpha * val + (1 - alpha) * lastValue
-	s.lastValue = val.Mul(s.alpha).Add(s.lastValue.Mul(decimal.ONE.Sub(s.alpha)))
-	return s.lastValue
+// Reset clears all state
+func (s *StreamingMMA) Reset() {
+	s.clear()
+	s.seed.Reset()
+	s.last = decimal.ZERO
 }
@@ -0,0 +1,389 @@
+package indicators
+
+import (
+	"math"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// StreamingRSI is a streaming version of NewRelativeStrengthIndexIndicator
+type StreamingRSI struct {
+	streamHistory
+	window     int
+	prev       decimal.Decimal
+	avgGain    *StreamingMMA
+	avgLoss    *StreamingMMA
+	oneHundred decimal.Decimal
+}
+
+// NewStreamingRSI returns a streaming RSI over window values
+func NewStreamingRSI(window int) *StreamingRSI {
+	return &StreamingRSI{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		avgGain:       NewStreamingMMA(window),
+		avgLoss:       NewStreamingMMA(window),
+		oneHundred:    decimal.NewFromString("100"),
+	}
+}
+
+// Next consumes the next input value and returns the RSI
+func (s *StreamingRSI) Next(val decimal.Decimal) decimal.Decimal {
+	index := s.index()
+	gain, loss := decimal.ZERO, decimal.ZERO
+	if index > 0 {
+		delta := val.Sub(s.prev)
+		if delta.GT(decimal.ZERO) {
+			gain = delta
+		} else if delta.LT(decimal.ZERO) {
+			loss = delta.Neg()
+		}
+	}
+	s.prev = val
+	avgGain := s.avgGain.Next(gain)
+	avgLoss := s.avgLoss.Next(loss)
+
+	rs := decimal.ZERO
+	if index >= s.window-1 {
+		switch {
+		case !avgLoss.EQ(decimal.ZERO):
+			rs = avgGain.Div(avgLoss)
+		case avgGain.EQ(decimal.ZERO):
+			rs = decimal.ONE
+		default:
+			rs = decimal.New(math.Inf(1))
+		}
+	}
+	return s.record(s.oneHundred.Sub(s.oneHundred.Div(decimal.ONE.Add(rs))))
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingRSI) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingRSI) Reset() {
+	s.clear()
+	s.prev = decimal.ZERO
+	s.avgGain.Reset()
+	s.avgLoss.Reset()
+}
+
+// StreamingMACD is a streaming version of NewMACDIndicator. It also tracks the
+// signal line and the histogram of NewMACDHistogramIndicator.
+type StreamingMACD struct {
+	streamHistory
+	short     *StreamingEMA
+	long      *StreamingEMA
+	signal    *StreamingEMA
+	histogram streamHistory
+}
+
+// NewStreamingMACD returns a streaming MACD with the given EMA windows
+func NewStreamingMACD(shortWindow, longWindow, signalWindow int) *StreamingMACD {
+	s := &StreamingMACD{
+		streamHistory: newStreamHistory(longWindow),
+		short:         NewStreamingEMA(shortWindow),
+		long:          NewStreamingEMA(longWindow),
+		signal:        NewStreamingEMA(signalWindow),
+		histogram:     newStreamHistory(longWindow),
+	}
+	s.signal.SetHistory(max(longWindow, minStreamHistory))
+	return s
+}
+
+// Next consumes the next input value and returns the MACD line
+func (s *StreamingMACD) Next(val decimal.Decimal) decimal.Decimal {
+	macd := s.short.Next(val).Sub(s.long.Next(val))
+	s.histogram.record(macd.Sub(s.signal.Next(macd)))
+	return s.record(macd)
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingMACD) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Signal returns the signal line at index
+func (s *StreamingMACD) Signal(index int) decimal.Decimal {
+	return s.signal.Calculate(index)
+}
+
+// Histogram returns the MACD line minus the signal line at index
+func (s *StreamingMACD) Histogram(index int) decimal.Decimal {
+	return s.histogram.Calculate(index)
+}
+
+// SetHistory sets how many of the latest bars every line keeps, as
+// streamHistory.SetHistory does
+func (s *StreamingMACD) SetHistory(bars int) {
+	s.streamHistory.SetHistory(bars)
+	s.signal.SetHistory(bars)
+	s.histogram.SetHistory(bars)
+}
+
+func (s *StreamingMACD) lines() outputLines {
+	return newOutputLines(macdOutputs, indicatorFunc(s.Calculate), indicatorFunc(s.Signal), indicatorFunc(s.Histogram))
+}
+
+// Outputs returns macd, signal and histogram
+func (s *StreamingMACD) Outputs() []string {
+	return s.lines().Outputs()
+}
+
+// Output returns the line named name, or nil
+func (s *StreamingMACD) Output(name string) Indicator {
+	return s.lines().Output(name)
+}
+
+// CalculateAll returns every line of a streamed index
+func (s *StreamingMACD) CalculateAll(index int) map[string]decimal.Decimal {
+	return s.lines().CalculateAll(index)
+}
+
+// Reset clears all state
+func (s *StreamingMACD) Reset() {
+	s.clear()
+	s.short.Reset()
+	s.long.Reset()
+	s.signal.Reset()
+	s.histogram.clear()
+}
+
+// StreamingStochastic is a streaming version of NewFastStochasticIndicator. It
+// also tracks the slow %D line of NewSlowStochasticIndicator.
+type StreamingStochastic struct {
+	streamHistory
+	high *extremumWindow
+	low  *extremumWindow
+	d    *StreamingSMA
+}
+
+// NewStreamingStochastic returns a streaming %K over kWindow candles with a %D
+// smoothing of dWindow. A non-positive kWindow uses the whole history.
+func NewStreamingStochastic(kWindow, dWindow int) *StreamingStochastic {
+	s := &StreamingStochastic{
+		streamHistory: newStreamHistory(kWindow),
+		high:          newExtremumWindow(kWindow, true),
+		low:           newExtremumWindow(kWindow, false),
+		d:             NewStreamingSMA(dWindow),
+	}
+	s.d.SetHistory(max(kWindow, minStreamHistory))
+	return s
+}
+
+// NextCandle consumes the next candle and returns %K
+func (s *StreamingStochastic) NextCandle(c *series.Candle) decimal.Decimal {
+	s.high.push(c.MaxPrice)
+	s.low.push(c.MinPrice)
+	maxVal, minVal := s.high.value(), s.low.value()
+
+	k := decimal.New(flatStochasticValue)
+	if !minVal.EQ(maxVal) {
+		k = c.ClosePrice.Sub(minVal).Div(maxVal.Sub(minVal)).Mul(decimal.New(100))
+	}
+	s.d.Next(k)
+	return s.record(k)
+}
+
+// D returns the slow %D line at index
+func (s *StreamingStochastic) D(index int) decimal.Decimal {
+	return s.d.Calculate(index)
+}
+
+// SetHistory sets how many of the latest bars both lines keep, as
+// streamHistory.SetHistory does
+func (s *StreamingStochastic) SetHistory(bars int) {
+	s.streamHistory.SetHistory(bars)
+	s.d.SetHistory(bars)
+}
+
+func (s *StreamingStochastic) lines() outputLines {
+	return newOutputLines(stochasticOutputs, indicatorFunc(s.Calculate), indicatorFunc(s.D))
+}
+
+// Outputs returns k and d
+func (s *StreamingStochastic) Outputs() []string {
+	return s.lines().Outputs()
+}
+
+// Output returns the line named name, or nil
+func (s *StreamingStochastic) Output(name string) Indicator {
+	return s.lines().Output(name)
+}
+
+// CalculateAll returns every line of a streamed index
+func (s *StreamingStochastic) CalculateAll(index int) map[string]decimal.Decimal {
+	return s.lines().CalculateAll(index)
+}
+
+// Reset clears all state
+func (s *StreamingStochastic) Reset() {
+	s.clear()
+	s.high.reset()
+	s.low.reset()
+	s.d.Reset()
+}
+
+// StreamingWilliamsR is a streaming version of NewWilliamsRIndicator
+type StreamingWilliamsR struct {
+	streamHistory
+	window int
+	high   *extremumWindow
+	low    *extremumWindow
+}
+
+// NewStreamingWilliamsR returns a streaming Williams %R over window candles
+func NewStreamingWilliamsR(window int) *StreamingWilliamsR {
+	window = safeWindow(window)
+	return &StreamingWilliamsR{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		high:          newExtremumWindow(window, true),
+		low:           newExtremumWindow(window, false),
+	}
+}
+
+// NextCandle consumes the next candle and returns %R
+func (s *StreamingWilliamsR) NextCandle(c *series.Candle) decimal.Decimal {
+	s.high.push(c.MaxPrice)
+	s.low.push(c.MinPrice)
+	if s.index() < s.window-1 {
+		return s.record(decimal.ZERO)
+	}
+
+	highestHigh := s.high.value()
+	rangeVal := highestHigh.Sub(s.low.value())
+	if rangeVal.Zero() {
+		return s.record(decimal.NaN)
+	}
+	return s.record(highestHigh.Sub(c.ClosePrice).Div(rangeVal).Mul(decimal.New(-100)))
+}
+
+// Reset clears all state
+func (s *StreamingWilliamsR) Reset() {
+	s.clear()
+	s.high.reset()
+	s.low.reset()
+}
+
+// StreamingCCI is a streaming version of NewCCIIndicator. The mean deviation
+// term has no running form, so each update costs O(window).
+type StreamingCCI struct {
+	streamHistory
+	window   int
+	typical  *rollingWindow
+	closes   *rollingWindow
+	three    decimal.Decimal
+	constant decimal.Decimal
+}
+
+// NewStreamingCCI returns a streaming CCI over window candles
+func NewStreamingCCI(window int) *StreamingCCI {
+	window = safeWindow(window)
+	return &StreamingCCI{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		typical:       newRollingWindow(window),
+		closes:        newRollingWindow(window),
+		three:         decimal.NewFromString("3"),
+		constant:      decimal.NewFromString("0.015"),
+	}
+}
+
+// NextCandle consumes the next candle and returns the CCI
+func (s *StreamingCCI) NextCandle(c *series.Candle) decimal.Decimal {
+	tp := c.MaxPrice.Add(c.MinPrice).Add(c.ClosePrice).Div(s.three)
+	s.typical.push(tp)
+	s.closes.push(c.ClosePrice)
+
+	tpAverage := decimal.ZERO
+	meanDeviation := decimal.ZERO
+	if s.typical.full() {
+		windowDec := decimal.NewFromInt(int64(s.window))
+		tpAverage = s.typical.sum.Div(windowDec)
+		average := s.closes.sum.Div(windowDec)
+		for i := 0; i < s.closes.len(); i++ {
+			meanDeviation = meanDeviation.Add(average.Sub(s.closes.at(i)).Abs())
+		}
+		meanDeviation = meanDeviation.Div(decimal.New(float64(s.window)))
+	}
+	return s.record(tp.Sub(tpAverage).DivOrNaN(meanDeviation.Mul(s.constant)))
+}
+
+// Reset clears all state
+func (s *StreamingCCI) Reset() {
+	s.clear()
+	s.typical.reset()
+	s.closes.reset()
+}
+
+// StreamingROC is a streaming version of NewROCIndicator
+type StreamingROC struct {
+	streamHistory
+	period int
+	values *rollingWindow
+}
+
+// NewStreamingROC returns a streaming rate of change over period values
+func NewStreamingROC(period int) *StreamingROC {
+	return &StreamingROC{streamHistory: newStreamHistory(period), period: period, values: newRollingWindow(period + 1)}
+}
+
+// Next consumes the next input value and returns the rate of change
+func (s *StreamingROC) Next(val decimal.Decimal) decimal.Decimal {
+	s.values.push(val)
+	if s.index() < s.period {
+		return s.record(decimal.ZERO)
+	}
+	previous := s.values.at(0)
+	if previous.Zero() {
+		return s.record(decimal.NaN)
+	}
+	return s.record(val.Sub(previous).Div(previous).Mul(decimal.New(100)))
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingROC) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingROC) Reset() {
+	s.clear()
+	s.values.reset()
+}
+
+// StreamingMomentum is a streaming version of NewMomentumIndicator
+type StreamingMomentum struct {
+	streamHistory
+	period int
+	values *rollingWindow
+}
+
+// NewStreamingMomentum returns a streaming momentum over period values
+func NewStreamingMomentum(period int) *StreamingMomentum {
+	return &StreamingMomentum{streamHistory: newStreamHistory(period), period: period, values: newRollingWindow(period + 1)}
+}
+
+// Next consumes the next input value and returns the momentum
+func (s *StreamingMomentum) Next(val decimal.Decimal) decimal.Decimal {
+	s.values.push(val)
+	if s.index() < s.period {
+		return s.record(decimal.ZERO)
+	}
+	return s.record(val.Sub(s.values.at(0)))
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingMomentum) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingMomentum) Reset() {
+	s.clear()
+	s.values.reset()
+}
@@ -1,37 +1,238 @@
 package indicators_test
 
 import (
+	"math"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/irfndi/goflux/pkg/decimal"
 	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
 )
 
 func TestStreamingSMA(t *testing.T) {
 	sma := indicators.NewStreamingSMA(3)
 
-	v1 := sma.Next(decimal.New(100))
-	assert.Equal(t, "100.00", v1.FormattedString(2))
-
-	v2 := sma.Next(decimal.New(110))
-	assert.Equal(t, "105.00", v2.FormattedString(2)) // (100+110)/2
+	assert.True(t, sma.Next(decimal.New(100)).Zero())
+	assert.True(t, sma.Next(decimal.New(110)).Zero())
 
 	v3 := sma.Next(decimal.New(120))
 	assert.Equal(t, "110.00", v3.FormattedString(2)) // (100+110+120)/3
 
 	v4 := sma.Next(decimal.New(130))
 	assert.Equal(t, "120.00", v4.FormattedString(2)) // (110+120+130)/3
+
+	assert.Equal(t, "110.00", sma.Calculate(2).FormattedString(2))
+	assert.Equal(t, 4, sma.Snapshot().Count)
 }
 
 func TestStreamingEMA(t *testing.T) {
-	ema := indicators.NewStreamingEMA(9) // window 9 => alpha = 2/10 = 0.2
+	ema := indicators.NewStreamingEMA(2) // alpha = 2/3
 
-	v1 := ema.Next(decimal.New(100))
-	assert.Equal(t, "100.00", v1.FormattedString(2))
+	assert.True(t, ema.Next(decimal.New(100)).Zero())
 
 	v2 := ema.Next(decimal.New(110))
-	// 110 * 0.2 + 100 * 0.8 = 22 + 80 = 102
-	assert.Equal(t, "102.00", v2.FormattedString(2))
+	assert.Equal(t, "105.00", v2.FormattedString(2)) // seeded with SMA
+
+	v3 := ema.Next(decimal.New(120))
+	// 120 * 2/3 + 105 * 1/3 = 80 + 35 = 115
+	assert.Equal(t, "115.00", v3.FormattedString(2))
+}
+
+func TestStreamingReset(t *testing.T) {
+	ts := streamingTestSeries(60)
+	rsi := indicators.NewStreamingRSI(14)
+	for _, c := range ts.Candles {
+		rsi.NextCandle(c)
+	}
+	first := rsi.Snapshot()
+	assert.Equal(t, 60, first.Count)
+
+	rsi.Reset()
+	assert.Equal(t, indicators.StreamSnapshot{Value: decimal.ZERO}, rsi.Snapshot())
+	assert.True(t, rsi.Calculate(0).Zero())
+
+	for _, c := range ts.Candles {
+		rsi.NextCandle(c)
+	}
+	assert.Equal(t, first.Count, rsi.Snapshot().Count)
+	assert.True(t, first.Value.EQ(rsi.Snapshot().Value))
+}
+
+// streamingTestSeries returns a deterministic random walk of OHLCV candles.
+func streamingTestSeries(n int) *series.TimeSeries {
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec // deterministic test data
+	ts := series.NewTimeSeries()
+	price := 100.0
+	for i := 0; i < n; i++ {
+		open := price
+		price += rng.NormFloat64() * 2
+		if i%17 == 0 {
+			price = open // flat closes exercise the equality branches
+		}
+		high := math.Max(open, price) + rng.Float64()*1.5
+		low := math.Min(open, price) - rng.Float64()*1.5
+
+		c := series.NewCandle(series.NewTimePeriod(time.Unix(int64(i)*60, 0), time.Minute))
+		c.OpenPrice = decimal.New(open)
+		c.ClosePrice = decimal.New(price)
+		c.MaxPrice = decimal.New(high)
+		c.MinPrice = decimal.New(low)
+		c.Volume = decimal.New(float64(rng.Intn(1000) + 1))
+		ts.AddCandle(c)
+	}
+	return ts
+}
+
+func assertSameValue(t *testing.T, name string, index int, expected, actual decimal.Decimal) {
+	t.Helper()
+	if expected.IsNaN() || actual.IsNaN() {
+		require.Truef(t, expected.IsNaN() && actual.IsNaN(), "%s[%d]: expected %s, got %s", name, index, expected, actual)
+		return
+	}
+	e, a := expected.Float(), actual.Float()
+	if math.IsInf(e, 0) || math.IsInf(a, 0) {
+		require.Equalf(t, e, a, "%s[%d]", name, index)
+		return
+	}
+	require.InDeltaf(t, e, a, 1e-9*math.Max(1, math.Abs(e)), "%s[%d]", name, index)
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	const n = 300
+	ts := streamingTestSeries(n)
+	closes := indicators.NewClosePriceIndicator(ts)
+	macdLine := indicators.NewMACDIndicator(closes, 12, 26)
+	fastK := indicators.NewFastStochasticIndicator(ts, 14)
+	ichimoku := indicators.NewIchimokuIndicator(ts)
+	superTrend := indicators.NewSuperTrendIndicator(ts, 10, 3)
+
+	type pair struct {
+		name      string
+		batch     indicators.Indicator
+		streaming indicators.CandleStreamingIndicator
+	}
+
+	streamingMACD := indicators.NewStreamingMACD(12, 26, 9)
+	streamingStoch := indicators.NewStreamingStochastic(14, 3)
+	streamingBB := indicators.NewStreamingBollingerBands(20, 2)
+	streamingIchimoku := indicators.NewStreamingIchimoku()
+	streamingSuperTrend := indicators.NewStreamingSuperTrend(10, 3)
+
+	pairs := []pair{
+		{"SMA", indicators.NewSimpleMovingAverage(closes, 20), indicators.NewStreamingSMA(20)},
+		{"EMA", indicators.NewEMAIndicator(closes, 20), indicators.NewStreamingEMA(20)},
+		{"MMA", indicators.NewMMAIndicator(closes, 14), indicators.NewStreamingMMA(14)},
+		{"RSI", indicators.NewRelativeStrengthIndexIndicator(closes, 14), indicators.NewStreamingRSI(14)},
+		{"MACD", macdLine, streamingMACD},
+		{"Stochastic", fastK, streamingStoch},
+		{"WilliamsR", indicators.NewWilliamsRIndicator(ts, 14), indicators.NewStreamingWilliamsR(14)},
+		{"CCI", indicators.NewCCIIndicator(ts, 20), indicators.NewStreamingCCI(20)},
+		{"ROC", indicators.NewROCIndicator(ts, 10), indicators.NewStreamingROC(10)},
+		{"Momentum", indicators.NewMomentumIndicator(ts, 10), indicators.NewStreamingMomentum(10)},
+		{"TrueRange", indicators.NewTrueRangeIndicator(ts), indicators.NewStreamingTrueRange()},
+		{"ATR", indicators.NewAverageTrueRangeIndicator(ts, 14), indicators.NewStreamingATR(14)},
+		{"StdDev", indicators.NewWindowedStandardDeviationIndicator(closes, 20), indicators.NewStreamingStandardDeviation(20)},
+		{"Bollinger", indicators.NewSimpleMovingAverage(closes, 20), streamingBB},
+		{"SuperTrend", superTrend, streamingSuperTrend},
+		{"ADX", indicators.NewADXIndicator(ts, 14), indicators.NewStreamingADX(14)},
+		{"ParabolicSAR", indicators.NewParabolicSARIndicator(ts), indicators.NewStreamingParabolicSAR()},
+		{"Ichimoku", ichimoku, streamingIchimoku},
+		{"KAMA", indicators.NewKAMAIndicator(ts, 10), indicators.NewStreamingKAMA(10)},
+		{"OBV", indicators.NewOBVIndicator(ts), indicators.NewStreamingOBV()},
+		{"VWAP", indicators.NewVWAPIndicator(ts), indicators.NewStreamingVWAP()},
+	}
+
+	macdHistogram := indicators.NewMACDHistogramIndicator(macdLine, 9)
+	slowD := indicators.NewSlowStochasticIndicator(fastK, 3)
+	bbUpper := indicators.NewBollingerUpperBandIndicator(closes, 20, 2)
+	bbLower := indicators.NewBollingerLowerBandIndicator(closes, 20, 2)
+
+	// Every output is kept so earlier bars can be checked at the end.
+	for _, p := range pairs {
+		p.streaming.SetHistory(0)
+	}
+
+	for i, c := range ts.Candles {
+		for _, p := range pairs {
+			got := p.streaming.NextCandle(c)
+			assertSameValue(t, p.name, i, p.batch.Calculate(i), got)
+			assert.Equal(t, i+1, p.streaming.Snapshot().Count)
+		}
+
+		assertSameValue(t, "MACD histogram", i, macdHistogram.Calculate(i), streamingMACD.Histogram(i))
+		assertSameValue(t, "Stochastic %D", i, slowD.Calculate(i), streamingStoch.D(i))
+		assertSameValue(t, "Bollinger upper", i, bbUpper.Calculate(i), streamingBB.Upper(i))
+		assertSameValue(t, "Bollinger lower", i, bbLower.Calculate(i), streamingBB.Lower(i))
+		assertSameValue(t, "Kijun-sen", i, ichimoku.KijunSen(i), streamingIchimoku.KijunSen(i))
+		assertSameValue(t, "Senkou span A", i, ichimoku.SenkouSpanA(i), streamingIchimoku.SenkouSpanA(i))
+		assertSameValue(t, "Senkou span B", i, ichimoku.SenkouSpanB(i), streamingIchimoku.SenkouSpanB(i))
+		assertSameValue(t, "Chikou span", i, ichimoku.ChikouSpan(i), streamingIchimoku.ChikouSpan(i))
+		assert.Equal(t, superTrend.(interface{ Trend(int) int }).Trend(i), streamingSuperTrend.Trend(i))
+	}
+
+	// Earlier outputs stay addressable through Calculate.
+	for _, p := range pairs {
+		for _, i := range []int{0, 50, n - 1} {
+			assertSameValue(t, p.name, i, p.batch.Calculate(i), p.streaming.Calculate(i))
+		}
+	}
+}
+
+func TestStreamingHistory(t *testing.T) {
+	const n = 100
+	ts := streamingTestSeries(n)
+	sma := indicators.NewStreamingSMA(20)
+	bb := indicators.NewStreamingBollingerBands(20, 2)
+	macd := indicators.NewStreamingMACD(12, 26, 9)
+	ichimoku := indicators.NewStreamingIchimoku()
+	superTrend := indicators.NewStreamingSuperTrend(10, 3)
+	obv := indicators.NewStreamingOBV()
+	for _, c := range ts.Candles {
+		sma.NextCandle(c)
+		bb.NextCandle(c)
+		macd.NextCandle(c)
+		ichimoku.NextCandle(c)
+		superTrend.NextCandle(c)
+		obv.NextCandle(c)
+	}
+
+	// By default only the window is kept.
+	assert.True(t, sma.Calculate(n-21).IsNaN())
+	assert.False(t, sma.Calculate(n-20).IsNaN())
+	assert.True(t, sma.Calculate(n).Zero())
+	assert.Equal(t, n, sma.Snapshot().Count)
+	assert.True(t, bb.Upper(n-21).IsNaN())
+	assert.False(t, bb.Lower(n-20).IsNaN())
+	assert.True(t, macd.Signal(n-27).IsNaN())
+	assert.False(t, macd.Histogram(n-26).IsNaN())
+	assert.True(t, ichimoku.Cloud(n-53).KijunSen.IsNaN())
+	assert.False(t, ichimoku.Cloud(n-52).KijunSen.IsNaN())
+	assert.Zero(t, superTrend.Trend(n-11))
+	assert.NotZero(t, superTrend.Trend(n-10))
+	assert.True(t, obv.Calculate(n-3).IsNaN())
+	assert.False(t, obv.Calculate(n-2).IsNaN())
+
+	// Shrinking keeps the latest bars, which match those streamed before.
+	last := sma.Calculate(n - 1)
+	sma.SetHistory(5)
+	assert.True(t, sma.Calculate(n-6).IsNaN())
+	assert.True(t, last.EQ(sma.Calculate(n-1)))
+	sma.NextCandle(ts.Candles[0])
+	assert.True(t, sma.Calculate(n-5).IsNaN())
+	assert.True(t, last.EQ(sma.Calculate(n-1)))
+
+	sma.Reset()
+	assert.Equal(t, 0, sma.Snapshot().Count)
+	assert.True(t, sma.Calculate(0).Zero())
+}
+
+func TestStreamingIchimokuImplementsIchimokuIndicator(t *testing.T) {
+	var _ indicators.IchimokuIndicator = indicators.NewStreamingIchimoku()
+	var _ indicators.StreamingIndicator = indicators.NewStreamingKAMA(10)
+	var _ indicators.StreamingIndicator = indicators.NewStreamingBollingerBands(20, 2)
 }
@@ -0,0 +1,432 @@
+package indicators
+
+import (
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// StreamingADX is a streaming version of NewADXIndicator
+type StreamingADX struct {
+	streamHistory
+	period    int
+	periodDec decimal.Decimal
+	prev      *series.Candle
+	smTR      decimal.Decimal
+	smPlus    decimal.Decimal
+	smMinus   decimal.Decimal
+	sumDX     decimal.Decimal
+	adx       decimal.Decimal
+}
+
+// NewStreamingADX returns a streaming ADX over period candles
+func NewStreamingADX(period int) *StreamingADX {
+	period = safeWindow(period)
+	s := &StreamingADX{streamHistory: newStreamHistory(period), period: period, periodDec: decimal.NewFromInt(int64(period))}
+	s.Reset()
+	return s
+}
+
+// NextCandle consumes the next candle and returns the ADX
+func (s *StreamingADX) NextCandle(c *series.Candle) decimal.Decimal {
+	i := s.index()
+	tr, plusDM, minusDM := s.movement(c)
+	s.prev = c
+
+	switch {
+	case i == 0:
+		return s.record(decimal.ZERO)
+	case i < s.period:
+		s.smTR = s.smTR.Add(tr)
+		s.smPlus = s.smPlus.Add(plusDM)
+		s.smMinus = s.smMinus.Add(minusDM)
+		return s.record(decimal.ZERO)
+	case i == s.period:
+		s.smTR = s.smTR.Add(tr)
+		s.smPlus = s.smPlus.Add(plusDM)
+		s.smMinus = s.smMinus.Add(minusDM)
+	default:
+		s.smTR = s.smTR.Sub(s.smTR.Div(s.periodDec)).Add(tr)
+		s.smPlus = s.smPlus.Sub(s.smPlus.Div(s.periodDec)).Add(plusDM)
+		s.smMinus = s.smMinus.Sub(s.smMinus.Div(s.periodDec)).Add(minusDM)
+	}
+
+	plusDI := decimal.ZERO
+	minusDI := decimal.ZERO
+	if !s.smTR.Zero() {
+		plusDI = s.smPlus.Div(s.smTR).Mul(decimal.New(100))
+		minusDI = s.smMinus.Div(s.smTR).Mul(decimal.New(100))
+	}
+
+	dx := decimal.ZERO
+	sumDI := plusDI.Add(minusDI)
+	if !sumDI.Zero() {
+		dx = plusDI.Sub(minusDI).Abs().Div(sumDI).Mul(decimal.New(100))
+	}
+
+	firstADXIndex := 2*s.period - 1
+	switch {
+	case i < firstADXIndex:
+		s.sumDX = s.sumDX.Add(dx)
+		return s.record(decimal.ZERO)
+	case i == firstADXIndex:
+		s.adx = s.sumDX.Add(dx).Div(s.periodDec)
+	default:
+		s.adx = s.adx.Mul(decimal.NewFromInt(int64(s.period - 1))).Add(dx).Div(s.periodDec)
+	}
+	return s.record(s.adx)
+}
+
+// movement returns the true range and directional movements of c against the previous candle.
+func (s *StreamingADX) movement(c *series.Candle) (tr, plusDM, minusDM decimal.Decimal) {
+	if s.prev == nil {
+		return c.MaxPrice.Sub(c.MinPrice), decimal.ZERO, decimal.ZERO
+	}
+
+	tr = c.MaxPrice.Sub(c.MinPrice)
+	if hc := c.MaxPrice.Sub(s.prev.ClosePrice).Abs(); hc.GT(tr) {
+		tr = hc
+	}
+	if lc := c.MinPrice.Sub(s.prev.ClosePrice).Abs(); lc.GT(tr) {
+		tr = lc
+	}
+
+	plusDM, minusDM = decimal.ZERO, decimal.ZERO
+	up := c.MaxPrice.Sub(s.prev.MaxPrice)
+	down := s.prev.MinPrice.Sub(c.MinPrice)
+	if up.GT(down) && up.IsPositive() {
+		plusDM = up
+	} else if down.GT(up) && down.IsPositive() {
+		minusDM = down
+	}
+	return tr, plusDM, minusDM
+}
+
+// Reset clears all state
+func (s *StreamingADX) Reset() {
+	s.clear()
+	s.prev = nil
+	s.smTR = decimal.ZERO
+	s.smPlus = decimal.ZERO
+	s.smMinus = decimal.ZERO
+	s.sumDX = decimal.ZERO
+	s.adx = decimal.ZERO
+}
+
+// StreamingParabolicSAR is a streaming version of NewParabolicSARIndicator
+type StreamingParabolicSAR struct {
+	streamHistory
+	af    decimal.Decimal
+	maxAF decimal.Decimal
+	prev  *series.Candle
+	sar   decimal.Decimal
+	ep    decimal.Decimal
+	curAF decimal.Decimal
+	trend int
+}
+
+// NewStreamingParabolicSAR returns a streaming parabolic SAR
+func NewStreamingParabolicSAR() *StreamingParabolicSAR {
+	return &StreamingParabolicSAR{
+		streamHistory: newStreamHistory(minStreamHistory),
+		af:            decimal.New(0.02),
+		maxAF:         decimal.New(0.2),
+	}
+}
+
+// NextCandle consumes the next candle and returns the SAR
+func (s *StreamingParabolicSAR) NextCandle(c *series.Candle) decimal.Decimal {
+	prev := s.prev
+	s.prev = c
+
+	switch s.index() {
+	case 0:
+		return s.record(c.MaxPrice)
+	case 1:
+		s.initialize(prev, c)
+	default:
+		s.step(prev, c)
+	}
+	return s.record(s.sar)
+}
+
+func (s *StreamingParabolicSAR) initialize(first, second *series.Candle) {
+	switch {
+	case second.MaxPrice.GT(first.MaxPrice) && second.MinPrice.GT(first.MinPrice):
+		s.trend, s.ep, s.sar = 1, second.MaxPrice, first.MinPrice
+	case second.MaxPrice.LT(first.MaxPrice) && second.MinPrice.LT(first.MinPrice):
+		s.trend, s.ep, s.sar = -1, second.MinPrice, first.MaxPrice
+	case second.MaxPrice.GT(first.MaxPrice):
+		s.trend, s.ep, s.sar = 1, second.MaxPrice, second.MinPrice
+	default:
+		s.trend, s.ep, s.sar = -1, second.MinPrice, second.MaxPrice
+	}
+	s.curAF = s.af
+}
+
+func (s *StreamingParabolicSAR) step(prev, c *series.Candle) {
+	switch s.trend {
+	case 1:
+		if c.MaxPrice.GT(s.ep) {
+			s.ep = c.MaxPrice
+			s.accelerate()
+		}
+		s.sar = s.sar.Add(s.curAF.Mul(s.ep.Sub(s.sar)))
+		if c.MinPrice.LT(s.sar) {
+			s.trend, s.curAF, s.ep = -1, s.af, prev.MinPrice
+			s.sar = s.ep
+		}
+	case -1:
+		if c.MinPrice.LT(s.ep) {
+			s.ep = c.MinPrice
+			s.accelerate()
+		}
+		s.sar = s.sar.Add(s.curAF.Mul(s.ep.Sub(s.sar)))
+		if c.MaxPrice.GT(s.sar) {
+			s.trend, s.curAF, s.ep = 1, s.af, prev.MaxPrice
+			s.sar = s.ep
+		}
+	}
+}
+
+func (s *StreamingParabolicSAR) accelerate() {
+	s.curAF = s.curAF.Add(s.af)
+	if s.curAF.GT(s.maxAF) {
+		s.curAF = s.maxAF
+	}
+}
+
+// Trend returns 1 for an up trend, -1 for a down trend, or 0 before the second candle
+func (s *StreamingParabolicSAR) Trend() int {
+	return s.trend
+}
+
+// EP returns the current extreme point
+func (s *StreamingParabolicSAR) EP() decimal.Decimal {
+	return s.ep
+}
+
+// AF returns the current acceleration factor
+func (s *StreamingParabolicSAR) AF() decimal.Decimal {
+	return s.curAF
+}
+
+// Reset clears all state
+func (s *StreamingParabolicSAR) Reset() {
+	s.clear()
+	s.prev = nil
+	s.sar = decimal.ZERO
+	s.ep = decimal.ZERO
+	s.curAF = decimal.ZERO
+	s.trend = 0
+}
+
+// donchianMidpoint tracks (highest high + lowest low) / 2 over a window.
+type donchianMidpoint struct {
+	period int
+	high   *extremumWindow
+	low    *extremumWindow
+}
+
+func newDonchianMidpoint(period int) *donchianMidpoint {
+	return &donchianMidpoint{
+		period: period,
+		high:   newExtremumWindow(period, true),
+		low:    newExtremumWindow(period, false),
+	}
+}
+
+func (d *donchianMidpoint) push(index int, c *series.Candle) decimal.Decimal {
+	d.high.push(c.MaxPrice)
+	d.low.push(c.MinPrice)
+	if index < d.period-1 {
+		return decimal.ZERO
+	}
+	return d.high.value().Add(d.low.value()).Div(decimal.New(2))
+}
+
+func (d *donchianMidpoint) reset() {
+	d.high.reset()
+	d.low.reset()
+}
+
+// StreamingIchimoku is a streaming version of NewIchimokuIndicator
+type StreamingIchimoku struct {
+	streamHistory
+	tenkan *donchianMidpoint
+	kijun  *donchianMidpoint
+	spanB  *donchianMidpoint
+	clouds history[IchimokuCloudResult]
+}
+
+// NewStreamingIchimoku returns a streaming Ichimoku cloud with the standard 9/26/52 periods
+func NewStreamingIchimoku() *StreamingIchimoku {
+	return &StreamingIchimoku{
+		streamHistory: newStreamHistory(52),
+		tenkan:        newDonchianMidpoint(9),
+		kijun:         newDonchianMidpoint(26),
+		spanB:         newDonchianMidpoint(52),
+		clouds:        newHistory[IchimokuCloudResult](52),
+	}
+}
+
+// NextCandle consumes the next candle and returns the Tenkan-sen
+func (s *StreamingIchimoku) NextCandle(c *series.Candle) decimal.Decimal {
+	index := s.index()
+	cloud := IchimokuCloudResult{
+		TenkanSen:   s.tenkan.push(index, c),
+		KijunSen:    s.kijun.push(index, c),
+		SenkouSpanB: s.spanB.push(index, c),
+		ChikouSpan:  c.ClosePrice,
+	}
+	cloud.SenkouSpanA = cloud.TenkanSen.Add(cloud.KijunSen).Div(decimal.New(2))
+	s.clouds.push(cloud)
+	return s.record(cloud.TenkanSen)
+}
+
+// Cloud returns all Ichimoku lines at index, NaN for a bar no longer kept
+func (s *StreamingIchimoku) Cloud(index int) IchimokuCloudResult {
+	if cloud, ok := s.clouds.at(index); ok {
+		return cloud
+	}
+	val := decimal.ZERO
+	if s.clouds.dropped(index) {
+		val = decimal.NaN
+	}
+	return IchimokuCloudResult{
+		TenkanSen:   val,
+		KijunSen:    val,
+		SenkouSpanA: val,
+		SenkouSpanB: val,
+		ChikouSpan:  val,
+	}
+}
+
+// SetHistory sets how many of the latest bars every line keeps, as
+// streamHistory.SetHistory does
+func (s *StreamingIchimoku) SetHistory(bars int) {
+	s.streamHistory.SetHistory(bars)
+	s.clouds.resize(bars)
+}
+
+// Outputs returns the Ichimoku line names
+func (s *StreamingIchimoku) Outputs() []string {
+	return ichimokuLines(s).Outputs()
+}
+
+// Output returns the Ichimoku line named name, or nil
+func (s *StreamingIchimoku) Output(name string) Indicator {
+	return ichimokuLines(s).Output(name)
+}
+
+// CalculateAll returns every Ichimoku line of a streamed index
+func (s *StreamingIchimoku) CalculateAll(index int) map[string]decimal.Decimal {
+	return ichimokuLines(s).CalculateAll(index)
+}
+
+// TenkanSen returns the conversion line at index
+func (s *StreamingIchimoku) TenkanSen(index int) decimal.Decimal {
+	return s.Cloud(index).TenkanSen
+}
+
+// KijunSen returns the base line at index
+func (s *StreamingIchimoku) KijunSen(index int) decimal.Decimal {
+	return s.Cloud(index).KijunSen
+}
+
+// SenkouSpanA returns leading span A at index
+func (s *StreamingIchimoku) SenkouSpanA(index int) decimal.Decimal {
+	return s.Cloud(index).SenkouSpanA
+}
+
+// SenkouSpanB returns leading span B at index
+func (s *StreamingIchimoku) SenkouSpanB(index int) decimal.Decimal {
+	return s.Cloud(index).SenkouSpanB
+}
+
+// ChikouSpan returns the lagging span at index
+func (s *StreamingIchimoku) ChikouSpan(index int) decimal.Decimal {
+	return s.Cloud(index).ChikouSpan
+}
+
+// Reset clears all state
+func (s *StreamingIchimoku) Reset() {
+	s.clear()
+	s.tenkan.reset()
+	s.kijun.reset()
+	s.spanB.reset()
+	s.clouds.clear()
+}
+
+// StreamingKAMA is a streaming version of NewKAMAIndicator
+type StreamingKAMA struct {
+	streamHistory
+	window     int
+	prices     *rollingWindow
+	diffs      *rollingWindow
+	smoothing1 decimal.Decimal
+	smoothing2 decimal.Decimal
+	kama       decimal.Decimal
+}
+
+// NewStreamingKAMA returns a streaming Kaufman adaptive moving average
+func NewStreamingKAMA(window int) *StreamingKAMA {
+	window = safeWindow(window)
+	return &StreamingKAMA{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		prices:        newRollingWindow(window + 1),
+		diffs:         newRollingWindow(window),
+		smoothing1:    decimal.New(2).Div(decimal.New(31)),
+		smoothing2:    decimal.New(2).Div(decimal.New(31)).Sub(decimal.ONE).Pow(2),
+	}
+}
+
+// Next consumes the next input value and returns the moving average
+func (s *StreamingKAMA) Next(val decimal.Decimal) decimal.Decimal {
+	index := s.index()
+	if index > 0 {
+		change := val.Sub(s.prices.newest())
+		s.diffs.push(change.Mul(change))
+	}
+	s.prices.push(val)
+
+	switch {
+	case index < s.window-1:
+		return s.record(val)
+	case index == s.window-1:
+		s.kama = s.prices.sum.Div(decimal.New(float64(s.window)))
+		return s.record(s.kama)
+	}
+
+	change := val.Sub(s.prices.at(0))
+	volatility := s.diffs.sum.Sqrt()
+
+	er := decimal.ZERO
+	if !volatility.Zero() {
+		er = change.Abs().Div(volatility)
+	}
+
+	alpha := er.Mul(s.smoothing1.Add(s.smoothing2)).Add(s.smoothing2)
+	if alpha.GT(decimal.ONE) {
+		alpha = decimal.ONE
+	}
+	if alpha.LT(decimal.New(0.0001)) {
+		alpha = decimal.New(0.0001)
+	}
+
+	s.kama = s.kama.Add(alpha.Mul(val.Sub(s.kama)))
+	return s.record(s.kama)
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingKAMA) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingKAMA) Reset() {
+	s.clear()
+	s.prices.reset()
+	s.diffs.reset()
+	s.kama = decimal.ZERO
+}
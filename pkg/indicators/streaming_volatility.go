@@ -0,0 +1,294 @@
+package indicators
+
+import (
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// StreamingTrueRange is a streaming version of NewTrueRangeIndicator
+type StreamingTrueRange struct {
+	streamHistory
+	prevClose decimal.Decimal
+}
+
+// NewStreamingTrueRange returns a streaming true range
+func NewStreamingTrueRange() *StreamingTrueRange {
+	return &StreamingTrueRange{streamHistory: newStreamHistory(minStreamHistory)}
+}
+
+// NextCandle consumes the next candle and returns its true range
+func (s *StreamingTrueRange) NextCandle(c *series.Candle) decimal.Decimal {
+	var tr decimal.Decimal
+	if s.index() == 0 {
+		tr = c.MaxPrice.Sub(c.MinPrice).Abs()
+	} else {
+		tr = c.MaxPrice.Max(s.prevClose).Sub(c.MinPrice.Min(s.prevClose))
+	}
+	s.prevClose = c.ClosePrice
+	return s.record(tr)
+}
+
+// Reset clears all state
+func (s *StreamingTrueRange) Reset() {
+	s.clear()
+	s.prevClose = decimal.ZERO
+}
+
+// StreamingATR is a streaming version of NewAverageTrueRangeIndicator
+type StreamingATR struct {
+	streamHistory
+	window int
+	tr     *StreamingTrueRange
+	ranges *rollingWindow
+}
+
+// NewStreamingATR returns a streaming ATR. Panics if window < 2, like the batch indicator.
+func NewStreamingATR(window int) *StreamingATR {
+	if window < 2 {
+		panic("goflux: ATR window must be >= 2")
+	}
+	return &StreamingATR{
+		streamHistory: newStreamHistory(window),
+		window:        window,
+		tr:            NewStreamingTrueRange(),
+		ranges:        newRollingWindow(window),
+	}
+}
+
+// NextCandle consumes the next candle and returns the ATR
+func (s *StreamingATR) NextCandle(c *series.Candle) decimal.Decimal {
+	s.ranges.push(s.tr.NextCandle(c))
+	if !s.ranges.full() {
+		return s.record(decimal.ZERO)
+	}
+	return s.record(s.ranges.sum.Div(decimal.New(float64(s.window))))
+}
+
+// Reset clears all state
+func (s *StreamingATR) Reset() {
+	s.clear()
+	s.tr.Reset()
+	s.ranges.reset()
+}
+
+// StreamingStandardDeviation is a streaming version of NewWindowedStandardDeviationIndicator
+type StreamingStandardDeviation struct {
+	streamHistory
+	window int
+	values *rollingWindow
+}
+
+// NewStreamingStandardDeviation returns a streaming standard deviation over window values
+func NewStreamingStandardDeviation(window int) *StreamingStandardDeviation {
+	window = safeWindow(window)
+	return &StreamingStandardDeviation{streamHistory: newStreamHistory(window), window: window, values: newRollingWindow(window)}
+}
+
+// Next consumes the next input value and returns the standard deviation
+func (s *StreamingStandardDeviation) Next(val decimal.Decimal) decimal.Decimal {
+	s.values.push(val)
+	return s.record(s.current())
+}
+
+// current returns the deviation of the window around its simple average. Like
+// the batch indicator, the average is zero until the window has filled.
+func (s *StreamingStandardDeviation) current() decimal.Decimal {
+	n := decimal.New(float64(s.values.len()))
+	avg := decimal.ZERO
+	if s.values.full() {
+		avg = s.values.sum.Div(decimal.NewFromInt(int64(s.window)))
+	}
+	// Σ(x-avg)² = Σx² - 2·avg·Σx + n·avg²
+	variance := s.values.sumSq.Sub(avg.Mul(s.values.sum).Mul(decimal.New(2))).Add(n.Mul(avg).Mul(avg))
+	if variance.IsNegative() {
+		variance = decimal.ZERO
+	}
+	return variance.Div(n).Sqrt()
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingStandardDeviation) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Reset clears all state
+func (s *StreamingStandardDeviation) Reset() {
+	s.clear()
+	s.values.reset()
+}
+
+// StreamingBollingerBands is a streaming version of the Bollinger band
+// indicators. Calculate returns the middle band.
+type StreamingBollingerBands struct {
+	streamHistory
+	sma   *StreamingSMA
+	stdev *StreamingStandardDeviation
+	upper streamHistory
+	lower streamHistory
+	sigma decimal.Decimal
+	neg   decimal.Decimal
+}
+
+// NewStreamingBollingerBands returns streaming Bollinger bands of sigma standard deviations over window values
+func NewStreamingBollingerBands(window int, sigma float64) *StreamingBollingerBands {
+	return &StreamingBollingerBands{
+		streamHistory: newStreamHistory(window),
+		sma:           NewStreamingSMA(window),
+		stdev:         NewStreamingStandardDeviation(window),
+		upper:         newStreamHistory(window),
+		lower:         newStreamHistory(window),
+		sigma:         decimal.New(sigma),
+		neg:           decimal.New(-sigma),
+	}
+}
+
+// Next consumes the next input value and returns the middle band
+func (s *StreamingBollingerBands) Next(val decimal.Decimal) decimal.Decimal {
+	ma := s.sma.Next(val)
+	stdev := s.stdev.Next(val)
+	s.upper.record(ma.Add(stdev.Mul(s.sigma)))
+	s.lower.record(ma.Add(stdev.Mul(s.neg)))
+	return s.record(ma)
+}
+
+// NextCandle consumes the close price of c
+func (s *StreamingBollingerBands) NextCandle(c *series.Candle) decimal.Decimal {
+	return s.Next(c.ClosePrice)
+}
+
+// Upper returns the upper band at index
+func (s *StreamingBollingerBands) Upper(index int) decimal.Decimal {
+	return s.upper.Calculate(index)
+}
+
+// Lower returns the lower band at index
+func (s *StreamingBollingerBands) Lower(index int) decimal.Decimal {
+	return s.lower.Calculate(index)
+}
+
+// SetHistory sets how many of the latest bars every band keeps, as
+// streamHistory.SetHistory does
+func (s *StreamingBollingerBands) SetHistory(bars int) {
+	s.streamHistory.SetHistory(bars)
+	s.upper.SetHistory(bars)
+	s.lower.SetHistory(bars)
+}
+
+func (s *StreamingBollingerBands) lines() outputLines {
+	return newOutputLines(bandOutputs, indicatorFunc(s.Calculate), indicatorFunc(s.Upper), indicatorFunc(s.Lower))
+}
+
+// Outputs returns middle, upper and lower
+func (s *StreamingBollingerBands) Outputs() []string {
+	return s.lines().Outputs()
+}
+
+// Output returns the band named name, or nil
+func (s *StreamingBollingerBands) Output(name string) Indicator {
+	return s.lines().Output(name)
+}
+
+// CalculateAll returns every band of a streamed index
+func (s *StreamingBollingerBands) CalculateAll(index int) map[string]decimal.Decimal {
+	return s.lines().CalculateAll(index)
+}
+
+// Reset clears all state
+func (s *StreamingBollingerBands) Reset() {
+	s.clear()
+	s.sma.Reset()
+	s.stdev.Reset()
+	s.upper.clear()
+	s.lower.clear()
+}
+
+// StreamingSuperTrend is a streaming version of NewSuperTrendIndicator
+type StreamingSuperTrend struct {
+	streamHistory
+	atr        *StreamingATR
+	multiplier decimal.Decimal
+	trend      history[int]
+	prevClose  decimal.Decimal
+	finalUpper decimal.Decimal
+	finalLower decimal.Decimal
+}
+
+// NewStreamingSuperTrend returns a streaming SuperTrend
+func NewStreamingSuperTrend(window int, multiplier float64) *StreamingSuperTrend {
+	return &StreamingSuperTrend{
+		streamHistory: newStreamHistory(window),
+		trend:         newHistory[int](window),
+		atr:           NewStreamingATR(window),
+		multiplier:    decimal.New(multiplier),
+	}
+}
+
+// NextCandle consumes the next candle and returns the SuperTrend line
+func (s *StreamingSuperTrend) NextCandle(c *series.Candle) decimal.Decimal {
+	atr := s.atr.NextCandle(c)
+	prevClose := s.prevClose
+	s.prevClose = c.ClosePrice
+
+	if s.index() == 0 {
+		s.finalUpper = decimal.ZERO
+		s.finalLower = decimal.ZERO
+		s.trend.push(1)
+		return s.record(decimal.ZERO)
+	}
+
+	median := c.MaxPrice.Add(c.MinPrice).Div(decimal.New(2))
+	basicUpper := median.Add(s.multiplier.Mul(atr))
+	basicLower := median.Sub(s.multiplier.Mul(atr))
+
+	if basicUpper.LT(s.finalUpper) || prevClose.GT(s.finalUpper) {
+		s.finalUpper = basicUpper
+	}
+	if basicLower.GT(s.finalLower) || prevClose.LT(s.finalLower) {
+		s.finalLower = basicLower
+	}
+
+	trend, _ := s.trend.at(s.index() - 1)
+	var value decimal.Decimal
+	if trend == 1 {
+		if c.ClosePrice.LT(s.finalLower) {
+			trend = -1
+			value = s.finalUpper
+		} else {
+			value = s.finalLower
+		}
+	} else {
+		if c.ClosePrice.GT(s.finalUpper) {
+			trend = 1
+			value = s.finalLower
+		} else {
+			value = s.finalUpper
+		}
+	}
+
+	s.trend.push(trend)
+	return s.record(value)
+}
+
+// Trend returns 1 for an up trend and -1 for a down trend at index, or 0 if
+// index has not been consumed or is no longer kept
+func (s *StreamingSuperTrend) Trend(index int) int {
+	trend, _ := s.trend.at(index)
+	return trend
+}
+
+// SetHistory sets how many of the latest bars the line and trend keep, as
+// streamHistory.SetHistory does
+func (s *StreamingSuperTrend) SetHistory(bars int) {
+	s.streamHistory.SetHistory(bars)
+	s.trend.resize(bars)
+}
+
+// Reset clears all state
+func (s *StreamingSuperTrend) Reset() {
+	s.clear()
+	s.atr.Reset()
+	s.trend.clear()
+	s.prevClose = decimal.ZERO
+	s.finalUpper = decimal.ZERO
+	s.finalLower = decimal.ZERO
+}
@@ -0,0 +1,74 @@
+package indicators
+
+import (
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// StreamingOBV is a streaming version of NewOBVIndicator
+type StreamingOBV struct {
+	streamHistory
+	prevClose decimal.Decimal
+	obv       decimal.Decimal
+}
+
+// NewStreamingOBV returns a streaming on-balance volume
+func NewStreamingOBV() *StreamingOBV {
+	return &StreamingOBV{streamHistory: newStreamHistory(minStreamHistory)}
+}
+
+// NextCandle consumes the next candle and returns the on-balance volume
+func (s *StreamingOBV) NextCandle(c *series.Candle) decimal.Decimal {
+	switch {
+	case s.index() == 0:
+		s.obv = c.Volume
+	case c.ClosePrice.GT(s.prevClose):
+		s.obv = s.obv.Add(c.Volume)
+	case c.ClosePrice.LT(s.prevClose):
+		s.obv = s.obv.Sub(c.Volume)
+	}
+	s.prevClose = c.ClosePrice
+	return s.record(s.obv)
+}
+
+// Reset clears all state
+func (s *StreamingOBV) Reset() {
+	s.clear()
+	s.prevClose = decimal.ZERO
+	s.obv = decimal.ZERO
+}
+
+// StreamingVWAP is a streaming version of NewVWAPIndicator. The average is
+// cumulative since construction or the last Reset, so calling Reset at each
+// session open yields a session VWAP.
+type StreamingVWAP struct {
+	streamHistory
+	three decimal.Decimal
+	sumPV decimal.Decimal
+	sumV  decimal.Decimal
+}
+
+// NewStreamingVWAP returns a streaming cumulative VWAP
+func NewStreamingVWAP() *StreamingVWAP {
+	s := &StreamingVWAP{streamHistory: newStreamHistory(minStreamHistory), three: decimal.NewFromString("3")}
+	s.Reset()
+	return s
+}
+
+// NextCandle consumes the next candle and returns the VWAP
+func (s *StreamingVWAP) NextCandle(c *series.Candle) decimal.Decimal {
+	tp := c.MaxPrice.Add(c.MinPrice).Add(c.ClosePrice).Div(s.three)
+	s.sumPV = s.sumPV.Add(tp.Mul(c.Volume))
+	s.sumV = s.sumV.Add(c.Volume)
+	if s.sumV.IsZero() {
+		return s.record(decimal.NaN)
+	}
+	return s.record(s.sumPV.Div(s.sumV))
+}
+
+// Reset clears all state
+func (s *StreamingVWAP) Reset() {
+	s.clear()
+	s.sumPV = decimal.ZERO
+	s.sumV = decimal.ZERO
+}
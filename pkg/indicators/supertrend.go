@@ -106,3 +106,5 @@ func (st *superTrendIndicator) Trend(index int) int {
 	}
 	return st.cacheTrend[index]
 }
+
+func (st *superTrendIndicator) Lookback() int { return max(1, Lookback(st.atr)) }
@@ -0,0 +1,526 @@
+package indicators
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// SwingPoint is a swing high or low of a series. Index is the candle of the
+// extreme and Confirmed the first index at which the swing is known, so a
+// backtest at index may only use swings with Confirmed <= index.
+type SwingPoint struct {
+	Index     int
+	Confirmed int
+	High      bool
+	Price     decimal.Decimal
+}
+
+// FindPivots returns the N-bar pivots of s in index order: candles whose high
+// is above the highs of the left candles before and the right candles after
+// (swing highs), or whose low is below their lows (swing lows). A pivot is
+// confirmed right candles after it.
+func FindPivots(s *series.TimeSeries, left, right int) []SwingPoint {
+	if s == nil {
+		return nil
+	}
+	return NewPivotScanner(s, left, right).Scan(len(s.Candles) - 1)
+}
+
+// FindFractals returns the Williams fractals of s: pivots with two candles on
+// each side
+func FindFractals(s *series.TimeSeries) []SwingPoint {
+	return FindPivots(s, 2, 2)
+}
+
+// isPivot reports whether the candle at pivot is a swing high (or low) against
+// the left and right candles around it
+func isPivot(s *series.TimeSeries, pivot, left, right int, high bool) bool {
+	for i := pivot - left; i <= pivot+right; i++ {
+		if i == pivot {
+			continue
+		}
+		if high && !s.Candles[pivot].MaxPrice.GT(s.Candles[i].MaxPrice) ||
+			!high && !s.Candles[pivot].MinPrice.LT(s.Candles[i].MinPrice) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindZigZagPivots returns the alternating swing highs and lows of a ZigZag
+// over the highs and lows of s. A leg ends when price reverses from its extreme
+// by at least deviation (0.05 for 5%) and the extreme is at least depth
+// candles after the previous pivot. The pivot is confirmed on the reversing
+// candle; the final, still open leg is not returned.
+func FindZigZagPivots(s *series.TimeSeries, depth int, deviation float64) []SwingPoint {
+	if s == nil || len(s.Candles) == 0 {
+		return nil
+	}
+	return NewZigZagScanner(s, depth, deviation).Scan(len(s.Candles) - 1)
+}
+
+// SwingScanner finds the swings of a series candle by candle, remembering
+// them so that each candle is scanned once however many indices are asked
+// about. It is safe for concurrent use.
+type SwingScanner struct {
+	series *series.TimeSeries
+	// confirm returns the swings confirmed on the candle at index, and peek
+	// does the same without moving on to the next candle
+	confirm func(index int) []SwingPoint
+	peek    func(index int) []SwingPoint
+
+	mu      sync.Mutex
+	scanned int
+	swings  []SwingPoint
+}
+
+// NewPivotScanner returns a SwingScanner over the N-bar pivots of s, as found
+// by FindPivots
+func NewPivotScanner(s *series.TimeSeries, left, right int) *SwingScanner {
+	left, right = max(left, 1), max(right, 1)
+	confirm := func(index int) []SwingPoint {
+		i := index - right
+		if i < left {
+			return nil
+		}
+		var swings []SwingPoint
+		c := s.Candles[i]
+		if isPivot(s, i, left, right, true) {
+			swings = append(swings, SwingPoint{Index: i, Confirmed: index, High: true, Price: c.MaxPrice})
+		}
+		if isPivot(s, i, left, right, false) {
+			swings = append(swings, SwingPoint{Index: i, Confirmed: index, High: false, Price: c.MinPrice})
+		}
+		return swings
+	}
+	return &SwingScanner{series: s, confirm: confirm, peek: confirm}
+}
+
+// NewZigZagScanner returns a SwingScanner over the ZigZag pivots of s, as
+// found by FindZigZagPivots
+func NewZigZagScanner(s *series.TimeSeries, depth int, deviation float64) *SwingScanner {
+	z := &zigZag{series: s, depth: max(depth, 1), threshold: decimal.New(deviation)}
+	return &SwingScanner{series: s, confirm: z.step, peek: func(index int) []SwingPoint {
+		next := *z
+		return next.step(index)
+	}}
+}
+
+// Scan returns the swings confirmed by index in index order, scanning the
+// candles up to index that have not been scanned yet. Candles are scanned
+// once, so a candle still changing should not be scanned before it closes.
+// The returned slice is shared and must not be modified.
+func (sc *SwingScanner) Scan(index int) []SwingPoint {
+	if sc.series == nil {
+		return nil
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for ; sc.scanned <= index && sc.scanned < len(sc.series.Candles); sc.scanned++ {
+		sc.swings = append(sc.swings, sc.confirm(sc.scanned)...)
+	}
+	n := sort.Search(len(sc.swings), func(i int) bool { return sc.swings[i].Confirmed > index })
+	return sc.swings[:n:n]
+}
+
+// Pending returns the swings confirmed on the candle at index. The candles
+// before it are scanned, but a candle not scanned yet is read without being
+// scanned, so the last candle can be asked about while it is still changing.
+func (sc *SwingScanner) Pending(index int) []SwingPoint {
+	if sc.series == nil || index < 0 || index >= len(sc.series.Candles) {
+		return nil
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for ; sc.scanned < index; sc.scanned++ {
+		sc.swings = append(sc.swings, sc.confirm(sc.scanned)...)
+	}
+	if sc.scanned == index {
+		return sc.peek(index)
+	}
+	from := sort.Search(len(sc.swings), func(i int) bool { return sc.swings[i].Confirmed >= index })
+	to := sort.Search(len(sc.swings), func(i int) bool { return sc.swings[i].Confirmed > index })
+	return append([]SwingPoint(nil), sc.swings[from:to]...)
+}
+
+// zigZag is the state of a ZigZag between candles
+type zigZag struct {
+	series    *series.TimeSeries
+	depth     int
+	threshold decimal.Decimal
+
+	// Until the first reversal both extremes are candidates; afterwards ext is
+	// the high of an up leg or the low of a down leg
+	direction, last int
+	high, low, ext  int
+}
+
+func (z *zigZag) extremeOf(from, to int, high bool) int {
+	candles := z.series.Candles
+	best := from
+	for i := from + 1; i <= to; i++ {
+		if high && candles[i].MaxPrice.GT(candles[best].MaxPrice) ||
+			!high && candles[i].MinPrice.LT(candles[best].MinPrice) {
+			best = i
+		}
+	}
+	return best
+}
+
+// step moves the ZigZag onto the candle at i and returns the pivot it
+// confirms, if any
+func (z *zigZag) step(i int) []SwingPoint {
+	if i == 0 {
+		return nil
+	}
+	candles := z.series.Candles
+	c := candles[i]
+	if z.direction == 0 {
+		z.high, z.low = z.extremeOf(z.high, i, true), z.extremeOf(z.low, i, false)
+		switch {
+		case z.high < i && changedBy(candles[z.high].MaxPrice.Sub(c.MinPrice), candles[z.high].MaxPrice, z.threshold):
+			z.direction, z.last, z.ext = -1, z.high, z.extremeOf(z.high+1, i, false)
+			return []SwingPoint{{Index: z.high, Confirmed: i, High: true, Price: candles[z.high].MaxPrice}}
+		case z.low < i && changedBy(c.MaxPrice.Sub(candles[z.low].MinPrice), candles[z.low].MinPrice, z.threshold):
+			z.direction, z.last, z.ext = 1, z.low, z.extremeOf(z.low+1, i, true)
+			return []SwingPoint{{Index: z.low, Confirmed: i, High: false, Price: candles[z.low].MinPrice}}
+		}
+		return nil
+	}
+
+	up := z.direction > 0
+	z.ext = z.extremeOf(z.ext, i, up)
+	ext := z.ext
+	if ext == i || ext-z.last < z.depth {
+		return nil
+	}
+	if up && changedBy(candles[ext].MaxPrice.Sub(c.MinPrice), candles[ext].MaxPrice, z.threshold) {
+		z.direction, z.last, z.ext = -1, ext, z.extremeOf(ext+1, i, false)
+		return []SwingPoint{{Index: ext, Confirmed: i, High: true, Price: candles[ext].MaxPrice}}
+	}
+	if !up && changedBy(c.MaxPrice.Sub(candles[ext].MinPrice), candles[ext].MinPrice, z.threshold) {
+		z.direction, z.last, z.ext = 1, ext, z.extremeOf(ext+1, i, true)
+		return []SwingPoint{{Index: ext, Confirmed: i, High: false, Price: candles[ext].MinPrice}}
+	}
+	return nil
+}
+
+// SupportResistanceZone is a horizontal band where swings cluster
+type SupportResistanceZone struct {
+	Low   decimal.Decimal
+	High  decimal.Decimal
+	Level decimal.Decimal
+	// Touches counts the swings in the zone, Highs of them swing highs
+	Touches int
+	Highs   int
+	// FirstIndex and LastIndex are the candles of the oldest and newest swing
+	FirstIndex int
+	LastIndex  int
+	// Strength is the number of touches weighted by recency: a touch at the
+	// newest swing counts 2, one at the start of the series 1
+	Strength float64
+}
+
+// ZoneConfig configures the clustering of swings into zones
+type ZoneConfig struct {
+	// Tolerance is the widest a zone may be relative to its lowest price,
+	// 0.005 (half a percent) if zero
+	Tolerance float64
+	// MinTouches drops zones with fewer touches
+	MinTouches int
+}
+
+func (cfg ZoneConfig) tolerance() decimal.Decimal {
+	if cfg.Tolerance <= 0 {
+		return decimal.New(0.005)
+	}
+	return decimal.New(cfg.Tolerance)
+}
+
+// FindSupportResistanceZones clusters swings by price into zones, in
+// ascending price order. Level is the mean price of the zone's swings.
+func FindSupportResistanceZones(swings []SwingPoint, cfg ZoneConfig) []SupportResistanceZone {
+	if len(swings) == 0 {
+		return nil
+	}
+	sorted := append([]SwingPoint(nil), swings...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Price.LT(sorted[j].Price) })
+	newest := 0
+	for _, sw := range swings {
+		newest = max(newest, sw.Index)
+	}
+
+	tolerance := decimal.ONE.Add(cfg.tolerance())
+	var zones []SupportResistanceZone
+	for start := 0; start < len(sorted); {
+		ceiling := sorted[start].Price.Mul(tolerance)
+		end := start
+		for end+1 < len(sorted) && sorted[end+1].Price.LTE(ceiling) {
+			end++
+		}
+		zone := newZone(sorted[start:end+1], newest)
+		if zone.Touches >= cfg.MinTouches {
+			zones = append(zones, zone)
+		}
+		start = end + 1
+	}
+	return zones
+}
+
+func newZone(swings []SwingPoint, newest int) SupportResistanceZone {
+	zone := SupportResistanceZone{
+		Low:        swings[0].Price,
+		High:       swings[len(swings)-1].Price,
+		Touches:    len(swings),
+		FirstIndex: swings[0].Index,
+		LastIndex:  swings[0].Index,
+	}
+	sum := decimal.ZERO
+	for _, sw := range swings {
+		sum = sum.Add(sw.Price)
+		if sw.High {
+			zone.Highs++
+		}
+		zone.FirstIndex = min(zone.FirstIndex, sw.Index)
+		zone.LastIndex = max(zone.LastIndex, sw.Index)
+		zone.Strength += 1 + float64(sw.Index)/float64(max(newest, 1))
+	}
+	zone.Level = sum.Div(decimal.New(float64(len(swings))))
+	return zone
+}
+
+var supportResistanceOutputs = []string{"support", "resistance"}
+
+// SupportResistanceConfig configures NewSupportResistanceIndicator
+type SupportResistanceConfig struct {
+	ZoneConfig
+	// Left and Right are the pivot strengths of the swings, 2 if zero, which
+	// gives Williams fractals
+	Left  int
+	Right int
+	// Window limits the swings to those in the last Window candles; zero
+	// uses every swing
+	Window int
+}
+
+type supportResistanceIndicator struct {
+	series  *series.TimeSeries
+	cfg     SupportResistanceConfig
+	swings  *SwingScanner
+	ceiling decimal.Decimal
+
+	mu sync.Mutex
+	// zones holds the swings in the window by price, marking the swing each
+	// zone starts at. used swings of the scanner have been added and dropped
+	// of them have left the window.
+	zones   []zoneSwing
+	used    int
+	dropped int
+	results [][2]decimal.Decimal
+}
+
+type zoneSwing struct {
+	SwingPoint
+	start bool
+}
+
+// NewSupportResistanceIndicator returns the levels of the nearest support
+// zone below and resistance zone above the close, from the swings confirmed by
+// each index, as a MultiOutputIndicator with outputs support and resistance.
+// Calculate returns the support. A level is NaN when there is no zone on that
+// side. The zones are updated as each swing is confirmed or leaves the
+// window, rather than clustered afresh at every index; the last candle, which
+// may still be changing, is applied to the zones of the candle before on each
+// call and not recorded. Panics if s is nil.
+func NewSupportResistanceIndicator(s *series.TimeSeries, cfg SupportResistanceConfig) MultiOutputIndicator {
+	if s == nil {
+		panic("goflux: SupportResistance series cannot be nil")
+	}
+	if cfg.Left <= 0 {
+		cfg.Left = 2
+	}
+	if cfg.Right <= 0 {
+		cfg.Right = 2
+	}
+	sr := &supportResistanceIndicator{
+		series:  s,
+		cfg:     cfg,
+		swings:  NewPivotScanner(s, cfg.Left, cfg.Right),
+		ceiling: decimal.ONE.Add(cfg.tolerance()),
+	}
+	lookback := cfg.Left + cfg.Right
+	return NewMultiOutputIndicator(supportResistanceOutputs,
+		withLookback{indicatorFunc(func(index int) decimal.Decimal { return sr.levels(index)[0] }), lookback},
+		withLookback{indicatorFunc(func(index int) decimal.Decimal { return sr.levels(index)[1] }), lookback})
+}
+
+// inWindow reports whether a swing is among those used at index
+func (sr *supportResistanceIndicator) inWindow(sw SwingPoint, index int) bool {
+	return sr.cfg.Window <= 0 || sw.Index > index-sr.cfg.Window
+}
+
+func (sr *supportResistanceIndicator) levels(index int) [2]decimal.Decimal {
+	if index < 0 || index >= len(sr.series.Candles) {
+		return [2]decimal.Decimal{decimal.ZERO, decimal.ZERO}
+	}
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	if index < len(sr.series.Candles)-1 {
+		sr.advance(index)
+		return sr.results[index]
+	}
+
+	// The last candle is not scanned: the swings it confirms, which pivots find
+	// without state, and those leaving the window at it are applied to the
+	// zones of the candle before, then taken back out
+	sr.advance(index - 1)
+	var left []SwingPoint
+	if sr.used > 0 {
+		swings := sr.swings.Scan(index - 1)
+		for k := sr.dropped; k < sr.used && !sr.inWindow(swings[k], index); k++ {
+			left = append(left, swings[k])
+		}
+	}
+	var confirmed []SwingPoint
+	for _, sw := range sr.swings.confirm(index) {
+		if sr.inWindow(sw, index) {
+			confirmed = append(confirmed, sw)
+		}
+	}
+	for _, sw := range left {
+		sr.remove(sw)
+	}
+	for _, sw := range confirmed {
+		sr.add(sw)
+	}
+	levels := sr.nearest(sr.series.Candles[index].ClosePrice)
+	for _, sw := range confirmed {
+		sr.remove(sw)
+	}
+	for _, sw := range left {
+		sr.add(sw)
+	}
+	return levels
+}
+
+// advance updates the zones candle by candle up to index, recording the
+// levels at each
+func (sr *supportResistanceIndicator) advance(index int) {
+	for i := len(sr.results); i <= index; i++ {
+		swings := sr.swings.Scan(i)
+		for ; sr.used < len(swings); sr.used++ {
+			sr.add(swings[sr.used])
+		}
+		for ; sr.dropped < sr.used && !sr.inWindow(swings[sr.dropped], i); sr.dropped++ {
+			sr.remove(swings[sr.dropped])
+		}
+		sr.results = append(sr.results, sr.nearest(sr.series.Candles[i].ClosePrice))
+	}
+}
+
+// add inserts a swing into the zones. FindSupportResistanceZones starts a
+// zone at the lowest swing and each next zone at the first swing above the
+// ceiling of the one before, so a swing joining a zone leaves the other
+// zones as they are, and one above the ceiling of its zone starts a new zone
+// and moves the starts above it.
+func (sr *supportResistanceIndicator) add(sw SwingPoint) {
+	pos := sort.Search(len(sr.zones), func(i int) bool { return sr.zones[i].Price.GT(sw.Price) })
+	sr.zones = append(sr.zones, zoneSwing{})
+	copy(sr.zones[pos+1:], sr.zones[pos:])
+	sr.zones[pos] = zoneSwing{SwingPoint: sw}
+
+	start := pos - 1
+	for start >= 0 && !sr.zones[start].start {
+		start--
+	}
+	if start >= 0 && sw.Price.LTE(sr.zones[start].Price.Mul(sr.ceiling)) {
+		return
+	}
+	sr.restart(pos)
+}
+
+// remove takes a swing out of the zones. Removing the swing a zone starts at
+// starts it at the next swing instead and moves the starts above it.
+func (sr *supportResistanceIndicator) remove(sw SwingPoint) {
+	pos := sort.Search(len(sr.zones), func(i int) bool { return sr.zones[i].Price.GTE(sw.Price) })
+	for sr.zones[pos].Index != sw.Index || sr.zones[pos].High != sw.High {
+		pos++
+	}
+	start := sr.zones[pos].start
+	sr.zones = append(sr.zones[:pos], sr.zones[pos+1:]...)
+	if start && pos < len(sr.zones) {
+		sr.restart(pos)
+	}
+}
+
+// restart starts a zone at pos and moves the starts of the zones after it
+// until one lands where a zone already starts, after which nothing changes
+func (sr *supportResistanceIndicator) restart(pos int) {
+	if sr.zones[pos].start {
+		return
+	}
+	sr.zones[pos].start = true
+	for {
+		ceiling := sr.zones[pos].Price.Mul(sr.ceiling)
+		next := pos + 1
+		for ; next < len(sr.zones) && sr.zones[next].Price.LTE(ceiling); next++ {
+			sr.zones[next].start = false
+		}
+		if next == len(sr.zones) || sr.zones[next].start {
+			return
+		}
+		sr.zones[next].start = true
+		pos = next
+	}
+}
+
+// zoneAt returns the level and touches of the zone starting at start, and
+// where the next zone starts
+func (sr *supportResistanceIndicator) zoneAt(start int) (decimal.Decimal, int, int) {
+	sum := sr.zones[start].Price
+	end := start + 1
+	for ; end < len(sr.zones) && !sr.zones[end].start; end++ {
+		sum = sum.Add(sr.zones[end].Price)
+	}
+	touches := end - start
+	return sum.Div(decimal.New(float64(touches))), touches, end
+}
+
+// nearest returns the levels of the nearest zones with enough touches below
+// and above price. Levels rise from zone to zone, so the search starts at
+// the zones around price.
+func (sr *supportResistanceIndicator) nearest(price decimal.Decimal) [2]decimal.Decimal {
+	levels := [2]decimal.Decimal{decimal.NaN, decimal.NaN}
+	if len(sr.zones) == 0 {
+		return levels
+	}
+	below := sort.Search(len(sr.zones), func(i int) bool { return sr.zones[i].Price.GTE(price) })
+	startOf := func(i int) int {
+		for i > 0 && !sr.zones[i].start {
+			i--
+		}
+		return i
+	}
+
+	for end := below; end > 0; {
+		start := startOf(end - 1)
+		level, touches, _ := sr.zoneAt(start)
+		if touches >= sr.cfg.MinTouches && level.LT(price) {
+			levels[0] = level
+			break
+		}
+		end = start
+	}
+	for start := startOf(min(below, len(sr.zones)-1)); start < len(sr.zones); {
+		level, touches, end := sr.zoneAt(start)
+		if touches >= sr.cfg.MinTouches && level.GT(price) {
+			levels[1] = level
+			break
+		}
+		start = end
+	}
+	return levels
+}
@@ -0,0 +1,228 @@
+package indicators_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+func swingSeriesForTest() [][3]float64 {
+	return [][3]float64{
+		{9, 10, 1},
+		{10, 12, 1},
+		{8, 11, 1},
+		{11, 13, 1},
+		{9, 11, 1},
+		{7, 10, 1},
+		{11, 12, 1},
+	}
+}
+
+func swingSummary(swings []indicators.SwingPoint) []string {
+	out := make([]string, len(swings))
+	for i, sw := range swings {
+		kind := "L"
+		if sw.High {
+			kind = "H"
+		}
+		out[i] = fmt.Sprintf("%s%s@%d/%d", kind, sw.Price, sw.Index, sw.Confirmed)
+	}
+	return out
+}
+
+func TestFindPivots(t *testing.T) {
+	ts := rangeSeries(time.Unix(0, 0), time.Minute, swingSeriesForTest()...)
+
+	assert.Equal(t, []string{"H12@1/2", "L8@2/3", "H13@3/4", "L7@5/6"}, swingSummary(indicators.FindPivots(ts, 1, 1)))
+	assert.Equal(t, []string{"L8@2/4", "H13@3/5"}, swingSummary(indicators.FindFractals(ts)))
+	assert.Empty(t, indicators.FindPivots(nil, 1, 1))
+}
+
+func TestFindZigZagPivots(t *testing.T) {
+	prices := []float64{100, 110, 120, 115, 105, 100, 110, 125, 130, 120, 110}
+	bars := make([][3]float64, len(prices))
+	for i, p := range prices {
+		bars[i] = [3]float64{p, p, 1}
+	}
+	ts := rangeSeries(time.Unix(0, 0), time.Minute, bars...)
+
+	assert.Equal(t, []string{"L100@0/1", "H120@2/4", "L100@5/6", "H130@8/10"},
+		swingSummary(indicators.FindZigZagPivots(ts, 1, 0.1)))
+	assert.Equal(t, []string{"L100@0/1", "H130@8/10"},
+		swingSummary(indicators.FindZigZagPivots(ts, 4, 0.1)), "legs shorter than depth are absorbed")
+	assert.Empty(t, indicators.FindZigZagPivots(ts, 1, 0.5))
+}
+
+func TestFindSupportResistanceZones(t *testing.T) {
+	swing := func(index int, high bool, price float64) indicators.SwingPoint {
+		return indicators.SwingPoint{Index: index, Confirmed: index + 2, High: high, Price: decimal.New(price)}
+	}
+	swings := []indicators.SwingPoint{
+		swing(10, false, 100),
+		swing(20, true, 105),
+		swing(30, true, 100.3),
+		swing(40, false, 99.9),
+		swing(50, true, 105.2),
+	}
+
+	zones := indicators.FindSupportResistanceZones(swings, indicators.ZoneConfig{})
+	require.Len(t, zones, 2)
+	assert.Equal(t, 3, zones[0].Touches)
+	assert.Equal(t, 1, zones[0].Highs)
+	assert.Equal(t, "99.9", zones[0].Low.String())
+	assert.Equal(t, "100.3", zones[0].High.String())
+	assert.InDelta(t, 100.0667, zones[0].Level.Float(), 1e-4)
+	assert.Equal(t, 10, zones[0].FirstIndex)
+	assert.Equal(t, 40, zones[0].LastIndex)
+	assert.InDelta(t, 3+(10+30+40)/50.0, zones[0].Strength, 1e-9)
+	assert.Equal(t, 2, zones[1].Touches)
+
+	strong := indicators.FindSupportResistanceZones(swings, indicators.ZoneConfig{MinTouches: 3})
+	require.Len(t, strong, 1)
+	assert.Equal(t, zones[0], strong[0])
+
+	wide := indicators.FindSupportResistanceZones(swings, indicators.ZoneConfig{Tolerance: 0.1})
+	require.Len(t, wide, 1)
+	assert.Equal(t, 5, wide[0].Touches)
+	assert.Nil(t, indicators.FindSupportResistanceZones(nil, indicators.ZoneConfig{}))
+}
+
+func TestSupportResistanceIndicator(t *testing.T) {
+	ts := rangeSeries(time.Unix(0, 0), time.Minute, swingSeriesForTest()...)
+	sr := indicators.NewSupportResistanceIndicator(ts, indicators.SupportResistanceConfig{Left: 1, Right: 1})
+	assert.Equal(t, []string{"support", "resistance"}, sr.Outputs())
+	assert.Equal(t, 2, indicators.Lookback(sr))
+
+	assert.True(t, sr.Calculate(1).IsNaN(), "no swing is confirmed yet")
+	assert.True(t, sr.Calculate(2).IsNaN())
+	assert.Equal(t, "12", sr.Output("resistance").Calculate(2).String())
+
+	// At index 6 the close is 12: zones at 7, 8, 12 and 13
+	assert.Equal(t, "8", sr.Calculate(6).String())
+	assert.Equal(t, "13", sr.Output("resistance").Calculate(6).String())
+
+	windowed := indicators.NewSupportResistanceIndicator(ts, indicators.SupportResistanceConfig{Left: 1, Right: 1, Window: 2})
+	assert.Equal(t, "7", windowed.Calculate(6).String(), "only the swing low at 5 is in the window")
+	assert.True(t, windowed.Output("resistance").Calculate(6).IsNaN())
+
+	assert.Panics(t, func() { indicators.NewSupportResistanceIndicator(nil, indicators.SupportResistanceConfig{}) })
+}
+
+// supportResistanceAt clusters the swings known at index afresh and returns
+// the nearest levels below and above the close
+func supportResistanceAt(ts *series.TimeSeries, index int, cfg indicators.SupportResistanceConfig) [2]decimal.Decimal {
+	var known []indicators.SwingPoint
+	for _, sw := range indicators.FindPivots(ts, cfg.Left, cfg.Right) {
+		if sw.Confirmed <= index && (cfg.Window <= 0 || sw.Index > index-cfg.Window) {
+			known = append(known, sw)
+		}
+	}
+	price := ts.Candles[index].ClosePrice
+	levels := [2]decimal.Decimal{decimal.NaN, decimal.NaN}
+	for _, zone := range indicators.FindSupportResistanceZones(known, cfg.ZoneConfig) {
+		if zone.Level.LT(price) {
+			levels[0] = zone.Level
+		} else if zone.Level.GT(price) && levels[1].IsNaN() {
+			levels[1] = zone.Level
+		}
+	}
+	return levels
+}
+
+func TestSupportResistanceIndicator_FollowsZones(t *testing.T) {
+	ts := streamingTestSeries(600)
+	for _, cfg := range []indicators.SupportResistanceConfig{
+		{Left: 2, Right: 2},
+		{Left: 1, Right: 1, ZoneConfig: indicators.ZoneConfig{Tolerance: 0.01, MinTouches: 2}},
+		{Left: 2, Right: 3, Window: 40, ZoneConfig: indicators.ZoneConfig{Tolerance: 0.02}},
+		{Left: 1, Right: 2, Window: 2},
+	} {
+		sr := indicators.NewSupportResistanceIndicator(ts, cfg)
+		for i := range ts.Candles {
+			want := supportResistanceAt(ts, i, cfg)
+			for line, name := range sr.Outputs() {
+				got := sr.Output(name).Calculate(i)
+				require.Truef(t, want[line].Cmp(got) == 0, "%s at %d with %+v: want %s, got %s", name, i, cfg, want[line], got)
+			}
+		}
+	}
+}
+
+func TestSupportResistanceIndicator_GrowingSeries(t *testing.T) {
+	full := streamingTestSeries(300)
+	for _, cfg := range []indicators.SupportResistanceConfig{
+		{Left: 2, Right: 2},
+		{Left: 1, Right: 2, Window: 30, ZoneConfig: indicators.ZoneConfig{Tolerance: 0.01, MinTouches: 2}},
+	} {
+		ts := series.NewTimeSeries()
+		sr := indicators.NewSupportResistanceIndicator(ts, cfg)
+		check := func(index int) {
+			want := supportResistanceAt(ts, index, cfg)
+			for line, name := range sr.Outputs() {
+				got := sr.Output(name).Calculate(index)
+				require.Truef(t, want[line].Cmp(got) == 0, "%s at %d of %d with %+v: want %s, got %s", name, index, ts.Length(), cfg, want[line], got)
+			}
+		}
+		for i, c := range full.Candles {
+			// The open candle first spikes, then settles on its final prices
+			open := *c
+			open.MaxPrice = c.MaxPrice.Mul(decimal.New(1.05))
+			open.MinPrice = c.MinPrice.Mul(decimal.New(0.95))
+			ts.AddCandle(&open)
+			check(i)
+			open.MaxPrice, open.MinPrice = c.MaxPrice, c.MinPrice
+			check(i)
+			if i > 0 {
+				check(i - 1)
+			}
+		}
+	}
+}
+
+func TestSwingScanner_Pending(t *testing.T) {
+	full := streamingTestSeries(300)
+	find := map[string]func(*series.TimeSeries) []indicators.SwingPoint{
+		"pivots": func(s *series.TimeSeries) []indicators.SwingPoint { return indicators.FindPivots(s, 2, 3) },
+		"zigzag": func(s *series.TimeSeries) []indicators.SwingPoint { return indicators.FindZigZagPivots(s, 2, 0.02) },
+	}
+	for name, swingsOf := range find {
+		ts := series.NewTimeSeries()
+		scanner := indicators.NewPivotScanner(ts, 2, 3)
+		if name == "zigzag" {
+			scanner = indicators.NewZigZagScanner(ts, 2, 0.02)
+		}
+		pending := 0
+		for i, c := range full.Candles {
+			ts.AddCandle(c)
+			var want []indicators.SwingPoint
+			for _, sw := range swingsOf(ts) {
+				if sw.Confirmed == i {
+					want = append(want, sw)
+				}
+			}
+			// Reading the last candle leaves it to be scanned once it closes
+			assert.Equal(t, want, scanner.Pending(i), "%s at %d", name, i)
+			assert.Equal(t, want, scanner.Pending(i), "%s at %d", name, i)
+			var scanned []indicators.SwingPoint
+			for _, sw := range swingsOf(ts) {
+				if sw.Confirmed < i {
+					scanned = append(scanned, sw)
+				}
+			}
+			assert.Equal(t, scanned, scanner.Scan(i-1), "%s at %d", name, i)
+			pending += len(want)
+		}
+		all := swingsOf(full)
+		assert.Equal(t, all, scanner.Scan(len(full.Candles)-1), name)
+		last := all[len(all)-1]
+		assert.Contains(t, scanner.Pending(last.Confirmed), last, "a scanned candle is read from its swings")
+		assert.Positive(t, pending, name)
+	}
+}
@@ -73,3 +73,6 @@ func sumX2(decimals []decimal.Decimal) decimal.Decimal {
 
 	return b
 }
+
+// Lookback is one past the source: a single value has no slope
+func (tli trendLineIndicator) Lookback() int { return Lookback(tli.indicator) + 1 }
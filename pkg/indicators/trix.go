@@ -49,8 +49,10 @@ func (t trixIndicator) Calculate(index int) decimal.Decimal {
 	previous := t.tripleEMA.Calculate(index - 1)
 
 	if previous.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return current.Sub(previous).Div(previous).Mul(decimal.New(100))
 }
+
+func (t trixIndicator) Lookback() int { return max(6*t.window, Lookback(t.tripleEMA)+1) }
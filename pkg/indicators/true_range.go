@@ -40,3 +40,26 @@ func (tri trueRangeIndicator) Calculate(index int) decimal.Decimal {
 
 	return trueHigh.Sub(trueLow)
 }
+
+func (tri trueRangeIndicator) ComputeInto(dst []decimal.Decimal) {
+	var candles []*series.Candle
+	if tri.series != nil {
+		candles = tri.series.CandlesSnapshot()
+	}
+	for i := range dst {
+		dst[i] = decimal.ZERO
+		if i >= len(candles) || candles[i] == nil {
+			continue
+		}
+		candle := candles[i]
+		if i == 0 {
+			dst[i] = candle.MaxPrice.Sub(candle.MinPrice).Abs()
+			continue
+		}
+		if previous := candles[i-1]; previous != nil {
+			dst[i] = candle.MaxPrice.Max(previous.ClosePrice).Sub(candle.MinPrice.Min(previous.ClosePrice))
+		}
+	}
+}
+
+func (tri trueRangeIndicator) Lookback() int { return 0 }
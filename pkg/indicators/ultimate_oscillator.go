@@ -91,8 +91,10 @@ func (uo *ultimateOscillatorIndicator) calculateAverage(index int, period int) d
 	}
 
 	if trueRangeSum.Zero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return rawBuyingPressureSum.Div(trueRangeSum)
 }
+
+func (uo *ultimateOscillatorIndicator) Lookback() int { return max(uo.period1, uo.period2, uo.period3) }
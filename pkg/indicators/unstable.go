@@ -23,3 +23,5 @@ func (ui unstableIndicator) Calculate(index int) decimal.Decimal {
 	}
 	return ui.indicator.Calculate(index)
 }
+
+func (ui unstableIndicator) Lookback() int { return max(ui.unstablePeriod, Lookback(ui.indicator)) }
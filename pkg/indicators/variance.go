@@ -31,3 +31,5 @@ func (vi varianceIndicator) Calculate(index int) decimal.Decimal {
 
 	return variance.Div(decimal.New(float64(index + 1)))
 }
+
+func (vi varianceIndicator) Lookback() int { return Lookback(vi.Indicator) }
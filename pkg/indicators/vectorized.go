@@ -0,0 +1,107 @@
+package indicators
+
+import (
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// VectorIndicator is implemented by indicators that can compute a whole series
+// in a single pass instead of one Calculate call per bar. ComputeInto must
+// store the same value Calculate(i) returns in dst[i] for every index of dst.
+type VectorIndicator interface {
+	Indicator
+	ComputeInto(dst []decimal.Decimal)
+}
+
+// ComputeAll returns the values of ind for indices [0, length). It uses the
+// single-pass VectorIndicator implementation when available and falls back to
+// calling Calculate for each index otherwise.
+func ComputeAll(ind Indicator, length int) []decimal.Decimal {
+	if length < 0 {
+		length = 0
+	}
+	dst := make([]decimal.Decimal, length)
+	ComputeInto(ind, dst)
+	return dst
+}
+
+// ComputeInto fills a preallocated dst with the values of ind for indices [0, len(dst))
+func ComputeInto(ind Indicator, dst []decimal.Decimal) {
+	if ind == nil {
+		for i := range dst {
+			dst[i] = decimal.ZERO
+		}
+		return
+	}
+	if v, ok := ind.(VectorIndicator); ok {
+		v.ComputeInto(dst)
+		return
+	}
+	for i := range dst {
+		dst[i] = ind.Calculate(i)
+	}
+}
+
+type precomputedIndicator struct {
+	Indicator
+	values []decimal.Decimal
+}
+
+// NewPrecomputedIndicator computes the first length values of ind up front and
+// serves them from memory. Indices outside that range fall through to ind, so
+// the result stays correct if the underlying series grows.
+func NewPrecomputedIndicator(ind Indicator, length int) Indicator {
+	if ind == nil {
+		return nil
+	}
+	if p, ok := ind.(precomputedIndicator); ok && len(p.values) >= length {
+		return p
+	}
+	return precomputedIndicator{Indicator: ind, values: ComputeAll(ind, length)}
+}
+
+func (p precomputedIndicator) Calculate(index int) decimal.Decimal {
+	if index >= 0 && index < len(p.values) {
+		return p.values[index]
+	}
+	return p.Indicator.Calculate(index)
+}
+
+func (p precomputedIndicator) ComputeInto(dst []decimal.Decimal) {
+	n := copy(dst, p.values)
+	for i := n; i < len(dst); i++ {
+		dst[i] = p.Indicator.Calculate(i)
+	}
+}
+
+// computeCandles fills dst with fn applied to each candle of s, or zero where
+// the candle is missing.
+func computeCandles(s *series.TimeSeries, dst []decimal.Decimal, fn func(c *series.Candle) decimal.Decimal) {
+	var candles []*series.Candle
+	if s != nil {
+		candles = s.CandlesSnapshot()
+	}
+	for i := range dst {
+		if i < len(candles) && candles[i] != nil {
+			dst[i] = fn(candles[i])
+		} else {
+			dst[i] = decimal.ZERO
+		}
+	}
+}
+
+// computeWindowSum fills dst with the sum of the last window values of src,
+// or zero until the window has filled.
+func computeWindowSum(src []decimal.Decimal, window int, dst []decimal.Decimal) {
+	w := newRollingWindow(window)
+	for i, val := range src {
+		w.push(val)
+		if w.full() {
+			dst[i] = w.sum
+		} else {
+			dst[i] = decimal.ZERO
+		}
+	}
+}
+
+func (p precomputedIndicator) Lookback() int { return Lookback(p.Indicator) }
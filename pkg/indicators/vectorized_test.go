@@ -0,0 +1,82 @@
+package indicators_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+)
+
+func TestComputeAllMatchesCalculate(t *testing.T) {
+	const n = 250
+	ts := streamingTestSeries(n)
+	closes := indicators.NewClosePriceIndicator(ts)
+	macd := indicators.NewMACDIndicator(closes, 12, 26)
+
+	cases := map[string]indicators.Indicator{
+		"Close":          closes,
+		"Typical":        indicators.NewTypicalPriceIndicator(ts),
+		"Volume":         indicators.NewVolumeIndicator(ts),
+		"Constant":       indicators.NewConstantIndicator(30),
+		"SMA":            indicators.NewSimpleMovingAverage(closes, 20),
+		"EMA":            indicators.NewEMAIndicator(closes, 20),
+		"MMA":            indicators.NewMMAIndicator(closes, 14),
+		"RSI":            indicators.NewRelativeStrengthIndexIndicator(closes, 14),
+		"MACD":           macd,
+		"MACD histogram": indicators.NewMACDHistogramIndicator(macd, 9),
+		"StdDev":         indicators.NewWindowedStandardDeviationIndicator(closes, 20),
+		"Bollinger":      indicators.NewBollingerUpperBandIndicator(closes, 20, 2),
+		"TrueRange":      indicators.NewTrueRangeIndicator(ts),
+		"ATR":            indicators.NewAverageTrueRangeIndicator(ts, 14),
+	}
+
+	for name, ind := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, ok := ind.(indicators.VectorIndicator)
+			assert.True(t, ok)
+
+			// Past the end of the series both paths yield zero.
+			all := indicators.ComputeAll(ind, n+5)
+			for i := range all {
+				assertSameValue(t, name, i, ind.Calculate(i), all[i])
+			}
+		})
+	}
+}
+
+func TestComputeIntoFallsBackToCalculate(t *testing.T) {
+	ts := streamingTestSeries(60)
+	sar := indicators.NewParabolicSARIndicator(ts)
+
+	dst := make([]decimal.Decimal, 60)
+	indicators.ComputeInto(sar, dst)
+	for i := range dst {
+		assert.True(t, sar.Calculate(i).EQ(dst[i]))
+	}
+
+	assert.Len(t, indicators.ComputeAll(nil, 3), 3)
+}
+
+func TestPrecomputedIndicator(t *testing.T) {
+	ts := streamingTestSeries(40)
+	sma := indicators.NewSimpleMovingAverage(indicators.NewClosePriceIndicator(ts), 5)
+	pre := indicators.NewPrecomputedIndicator(sma, 30)
+
+	for i := 0; i < 40; i++ {
+		assertSameValue(t, "SMA", i, sma.Calculate(i), pre.Calculate(i))
+	}
+	assert.Nil(t, indicators.NewPrecomputedIndicator(nil, 10))
+}
+
+func TestBatchCalculateVectorized(t *testing.T) {
+	ts := streamingTestSeries(80)
+	ema := indicators.NewEMAIndicator(indicators.NewClosePriceIndicator(ts), 10)
+	indices := []int{79, 3, 40, 9, -1}
+
+	results := indicators.BatchCalculate(ema, indices)
+	for i, idx := range indices {
+		assertSameValue(t, "EMA", idx, ema.Calculate(idx), results[i])
+	}
+}
@@ -23,7 +23,7 @@ func NewBollingerBandwidthIndicator(indicator Indicator, window int, sigma float
 func (bbw bbandWidthIndicator) Calculate(index int) decimal.Decimal {
 	middle := bbw.middle.Calculate(index)
 	if middle.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return bbw.upper.Calculate(index).Sub(bbw.lower.Calculate(index))
@@ -49,7 +49,11 @@ func NewATRRatioIndicatorFromSeries(s *series.TimeSeries, atrWindow int) Indicat
 func (ari atrRatioIndicator) Calculate(index int) decimal.Decimal {
 	price := ari.price.Calculate(index)
 	if price.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 	return ari.atr.Calculate(index).Div(price)
 }
+
+func (bbw bbandWidthIndicator) Lookback() int { return MaxLookback(bbw.upper, bbw.lower, bbw.middle) }
+
+func (ari atrRatioIndicator) Lookback() int { return MaxLookback(ari.atr, ari.price) }
@@ -0,0 +1,419 @@
+package indicators
+
+import (
+	"sync"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+const (
+	// DefaultValueArea is the share of volume (or TPOs) in the value area
+	DefaultValueArea = 0.70
+
+	// defaultProfileLevels is the number of price levels used when a
+	// ProfileConfig has no BucketSize
+	defaultProfileLevels = 24
+)
+
+var profileOutputs = []string{"poc", "vah", "val"}
+
+// ProfileConfig configures the price levels of volume and TPO profiles
+type ProfileConfig struct {
+	// BucketSize is the price height of a level. Levels are aligned to
+	// multiples of it so profiles of different ranges line up. Zero splits
+	// each profile's range into 24 levels.
+	BucketSize float64
+	// ValueArea is the share of the total in the value area, DefaultValueArea
+	// if zero
+	ValueArea float64
+}
+
+// ProfileLevel is one price level of a volume profile, covering [Low, High)
+type ProfileLevel struct {
+	Low    decimal.Decimal
+	High   decimal.Decimal
+	Volume decimal.Decimal
+}
+
+// VolumeProfile is the volume traded at each price level over the candles
+// Start to End inclusive. Each candle's volume is spread evenly over its
+// low-high range.
+type VolumeProfile struct {
+	Start  int
+	End    int
+	Levels []ProfileLevel
+	Total  decimal.Decimal
+	// POC is the middle of the level with the most volume; ties go to the
+	// level nearest the middle of the range
+	POC decimal.Decimal
+	// VAH and VAL are the top and bottom of the value area, grown from the
+	// POC one level at a time towards the side with more volume
+	VAH decimal.Decimal
+	VAL decimal.Decimal
+	// HighVolumeNodes and LowVolumeNodes are the middles of the levels that
+	// are local volume peaks above the average level, and local troughs
+	// below it
+	HighVolumeNodes []decimal.Decimal
+	LowVolumeNodes  []decimal.Decimal
+}
+
+// NewVolumeProfile returns the volume profile of the candles of s from start
+// to end inclusive. The range is clamped to the series; an empty range gives
+// an empty profile.
+func NewVolumeProfile(s *series.TimeSeries, start, end int, cfg ProfileConfig) VolumeProfile {
+	vp := VolumeProfile{Start: start, End: end, Total: decimal.ZERO}
+	if s == nil {
+		return vp
+	}
+	start, end = max(start, 0), min(end, len(s.Candles)-1)
+	if start > end {
+		return vp
+	}
+	vp.Start, vp.End = start, end
+
+	grid := newProfileGrid(s.Candles[start:end+1], cfg)
+	volumes := make([]decimal.Decimal, grid.levels)
+	for i := range volumes {
+		volumes[i] = decimal.ZERO
+	}
+	for _, c := range s.Candles[start : end+1] {
+		if !c.Volume.IsPositive() {
+			continue
+		}
+		lo, hi := grid.level(c.MinPrice), grid.level(c.MaxPrice)
+		spread := c.MaxPrice.Sub(c.MinPrice)
+		if lo == hi || !spread.IsPositive() {
+			volumes[lo] = volumes[lo].Add(c.Volume)
+			continue
+		}
+		for k := lo; k <= hi; k++ {
+			low, high := grid.bounds(k)
+			overlap := c.MaxPrice.Min(high).Sub(c.MinPrice.Max(low))
+			volumes[k] = volumes[k].Add(c.Volume.Mul(overlap).Div(spread))
+		}
+	}
+
+	vp.Levels = make([]ProfileLevel, grid.levels)
+	for k, v := range volumes {
+		low, high := grid.bounds(k)
+		vp.Levels[k] = ProfileLevel{Low: low, High: high, Volume: v}
+		vp.Total = vp.Total.Add(v)
+	}
+	poc, val, vah := profileValueArea(volumes, cfg.valueArea())
+	vp.POC = grid.middle(poc)
+	vp.VAL, _ = grid.bounds(val)
+	_, vp.VAH = grid.bounds(vah)
+	hvn, lvn := profileNodes(volumes)
+	for _, k := range hvn {
+		vp.HighVolumeNodes = append(vp.HighVolumeNodes, grid.middle(k))
+	}
+	for _, k := range lvn {
+		vp.LowVolumeNodes = append(vp.LowVolumeNodes, grid.middle(k))
+	}
+	return vp
+}
+
+// SessionVolumeProfiles returns the volume profile of each session of s
+func SessionVolumeProfiles(s *series.TimeSeries, key SessionKey, cfg ProfileConfig) []VolumeProfile {
+	sessions := Sessions(s, key)
+	profiles := make([]VolumeProfile, len(sessions))
+	for i, session := range sessions {
+		profiles[i] = NewVolumeProfile(s, session.Start, session.End, cfg)
+	}
+	return profiles
+}
+
+// TPOLevel is one price level of a market profile, covering [Low, High). Count
+// is the number of periods that traded at the level and Letters names them, A
+// for the first period of the session.
+type TPOLevel struct {
+	Low     decimal.Decimal
+	High    decimal.Decimal
+	Count   int
+	Letters string
+}
+
+// TPOProfile is a market profile: the time price opportunities of the candles
+// Start to End inclusive, split into periods of equal length from the first
+// candle.
+type TPOProfile struct {
+	Start  int
+	End    int
+	Levels []TPOLevel
+	// POC, VAH and VAL are found as for a VolumeProfile, counting TPOs
+	// instead of volume
+	POC decimal.Decimal
+	VAH decimal.Decimal
+	VAL decimal.Decimal
+	// InitialBalanceHigh and InitialBalanceLow are the range of the first
+	// two periods
+	InitialBalanceHigh decimal.Decimal
+	InitialBalanceLow  decimal.Decimal
+}
+
+const tpoLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// NewTPOProfile returns the market profile of the candles of s from start to
+// end inclusive with periods of the given length, typically 30 minutes.
+// Panics if period is not positive.
+func NewTPOProfile(s *series.TimeSeries, start, end int, period time.Duration, cfg ProfileConfig) TPOProfile {
+	if period <= 0 {
+		panic("goflux: TPO period must be positive")
+	}
+	tp := TPOProfile{Start: start, End: end}
+	if s == nil {
+		return tp
+	}
+	start, end = max(start, 0), min(end, len(s.Candles)-1)
+	if start > end {
+		return tp
+	}
+	tp.Start, tp.End = start, end
+	candles := s.Candles[start : end+1]
+
+	// The high and low of each period
+	type bracket struct {
+		high, low decimal.Decimal
+		traded    bool
+	}
+	var brackets []bracket
+	open := candles[0].Period.Start
+	for _, c := range candles {
+		n := int(c.Period.Start.Sub(open) / period)
+		for len(brackets) <= n {
+			brackets = append(brackets, bracket{})
+		}
+		b := &brackets[n]
+		if !b.traded {
+			*b = bracket{high: c.MaxPrice, low: c.MinPrice, traded: true}
+			continue
+		}
+		b.high, b.low = b.high.Max(c.MaxPrice), b.low.Min(c.MinPrice)
+	}
+
+	grid := newProfileGrid(candles, cfg)
+	counts := make([]decimal.Decimal, grid.levels)
+	letters := make([][]byte, grid.levels)
+	for i := range counts {
+		counts[i] = decimal.ZERO
+	}
+	tp.InitialBalanceHigh, tp.InitialBalanceLow = brackets[0].high, brackets[0].low
+	for n, b := range brackets {
+		if !b.traded {
+			continue
+		}
+		for k := grid.level(b.low); k <= grid.level(b.high); k++ {
+			counts[k] = counts[k].Add(decimal.ONE)
+			letters[k] = append(letters[k], tpoLetters[n%len(tpoLetters)])
+		}
+		if n == 1 {
+			tp.InitialBalanceHigh = tp.InitialBalanceHigh.Max(b.high)
+			tp.InitialBalanceLow = tp.InitialBalanceLow.Min(b.low)
+		}
+	}
+
+	tp.Levels = make([]TPOLevel, grid.levels)
+	for k := range counts {
+		low, high := grid.bounds(k)
+		tp.Levels[k] = TPOLevel{Low: low, High: high, Count: len(letters[k]), Letters: string(letters[k])}
+	}
+	poc, val, vah := profileValueArea(counts, cfg.valueArea())
+	tp.POC = grid.middle(poc)
+	tp.VAL, _ = grid.bounds(val)
+	_, tp.VAH = grid.bounds(vah)
+	return tp
+}
+
+// SessionTPOProfiles returns the market profile of each session of s
+func SessionTPOProfiles(s *series.TimeSeries, key SessionKey, period time.Duration, cfg ProfileConfig) []TPOProfile {
+	sessions := Sessions(s, key)
+	profiles := make([]TPOProfile, len(sessions))
+	for i, session := range sessions {
+		profiles[i] = NewTPOProfile(s, session.Start, session.End, period, cfg)
+	}
+	return profiles
+}
+
+func (cfg ProfileConfig) valueArea() decimal.Decimal {
+	if cfg.ValueArea <= 0 || cfg.ValueArea > 1 {
+		return decimal.New(DefaultValueArea)
+	}
+	return decimal.New(cfg.ValueArea)
+}
+
+// profileGrid maps prices to levels: level k covers
+// [origin+k*size, origin+(k+1)*size). An automatic grid ends exactly at top.
+type profileGrid struct {
+	origin decimal.Decimal
+	size   decimal.Decimal
+	levels int
+	top    *decimal.Decimal
+}
+
+func newProfileGrid(candles []*series.Candle, cfg ProfileConfig) profileGrid {
+	low, high := candles[0].MinPrice, candles[0].MaxPrice
+	for _, c := range candles[1:] {
+		low, high = low.Min(c.MinPrice), high.Max(c.MaxPrice)
+	}
+
+	if cfg.BucketSize > 0 {
+		size := decimal.New(cfg.BucketSize)
+		origin := low.Div(size).Floor().Mul(size)
+		levels := int(high.Sub(origin).Div(size).Floor().Float()) + 1
+		return profileGrid{origin: origin, size: size, levels: levels}
+	}
+	if !high.GT(low) {
+		return profileGrid{origin: low, size: decimal.ZERO, levels: 1}
+	}
+	return profileGrid{
+		origin: low,
+		size:   high.Sub(low).Div(decimal.New(defaultProfileLevels)),
+		levels: defaultProfileLevels,
+		top:    &high,
+	}
+}
+
+func (g profileGrid) level(price decimal.Decimal) int {
+	if g.size.IsZero() {
+		return 0
+	}
+	k := int(price.Sub(g.origin).Div(g.size).Floor().Float())
+	return min(max(k, 0), g.levels-1)
+}
+
+func (g profileGrid) bounds(k int) (low, high decimal.Decimal) {
+	low = g.origin.Add(g.size.Mul(decimal.New(float64(k))))
+	if k == g.levels-1 && g.top != nil {
+		return low, *g.top
+	}
+	return low, low.Add(g.size)
+}
+
+func (g profileGrid) middle(k int) decimal.Decimal {
+	low, high := g.bounds(k)
+	return low.Add(high).Div(decTwo)
+}
+
+// profileValueArea returns the point of control and the lowest and highest
+// levels of the value area holding fraction of the total
+func profileValueArea(values []decimal.Decimal, fraction decimal.Decimal) (poc, low, high int) {
+	total := decimal.ZERO
+	for i, v := range values {
+		total = total.Add(v)
+		if v.GT(values[poc]) || v.EQ(values[poc]) && centerDistance(i, len(values)) < centerDistance(poc, len(values)) {
+			poc = i
+		}
+	}
+
+	target := total.Mul(fraction)
+	low, high = poc, poc
+	covered := values[poc]
+	for covered.LT(target) && (low > 0 || high < len(values)-1) {
+		if high < len(values)-1 && (low == 0 || values[high+1].GTE(values[low-1])) {
+			high++
+			covered = covered.Add(values[high])
+		} else {
+			low--
+			covered = covered.Add(values[low])
+		}
+	}
+	return poc, low, high
+}
+
+func centerDistance(i, n int) int {
+	d := 2*i - (n - 1)
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// profileNodes returns the levels that are strict local peaks above the
+// average level and strict interior troughs below it
+func profileNodes(values []decimal.Decimal) (peaks, troughs []int) {
+	if len(values) < 3 {
+		return nil, nil
+	}
+	total := decimal.ZERO
+	for _, v := range values {
+		total = total.Add(v)
+	}
+	average := total.Div(decimal.New(float64(len(values))))
+
+	for i, v := range values {
+		higher := (i == 0 || v.GT(values[i-1])) && (i == len(values)-1 || v.GT(values[i+1]))
+		if higher && v.GT(average) {
+			peaks = append(peaks, i)
+		}
+		if i > 0 && i < len(values)-1 && v.LT(values[i-1]) && v.LT(values[i+1]) && v.LT(average) {
+			troughs = append(troughs, i)
+		}
+	}
+	return peaks, troughs
+}
+
+// volumeProfileIndicator reports the POC, VAH and VAL of the profile of the
+// bars up to each index: a rolling window, or the session so far
+type volumeProfileIndicator struct {
+	series *series.TimeSeries
+	cfg    ProfileConfig
+	window int
+	key    SessionKey
+
+	mu      sync.Mutex
+	results [][3]decimal.Decimal
+	done    []bool
+}
+
+// NewVolumeProfileIndicator returns the POC, VAH and VAL of the volume profile
+// of the last window bars as a MultiOutputIndicator. Calculate returns the
+// POC.
+func NewVolumeProfileIndicator(s *series.TimeSeries, window int, cfg ProfileConfig) MultiOutputIndicator {
+	return (&volumeProfileIndicator{series: s, cfg: cfg, window: safeWindow(window)}).multiOutput()
+}
+
+// NewSessionVolumeProfileIndicator returns the POC, VAH and VAL of the
+// developing volume profile of each session, from its first bar up to and
+// including the current one, as a MultiOutputIndicator. Calculate returns the
+// POC.
+func NewSessionVolumeProfileIndicator(s *series.TimeSeries, key SessionKey, cfg ProfileConfig) MultiOutputIndicator {
+	return (&volumeProfileIndicator{series: s, cfg: cfg, key: key}).multiOutput()
+}
+
+func (v *volumeProfileIndicator) multiOutput() MultiOutputIndicator {
+	lines := make([]Indicator, len(profileOutputs))
+	for i := range lines {
+		i := i
+		lines[i] = withLookback{indicatorFunc(func(index int) decimal.Decimal { return v.levels(index)[i] }), v.Lookback()}
+	}
+	return NewMultiOutputIndicator(profileOutputs, lines...)
+}
+
+func (v *volumeProfileIndicator) levels(index int) [3]decimal.Decimal {
+	if v.series == nil || index < 0 || index >= len(v.series.Candles) || index < v.Lookback() {
+		return [3]decimal.Decimal{decimal.ZERO, decimal.ZERO, decimal.ZERO}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if index < len(v.done) && v.done[index] {
+		return v.results[index]
+	}
+	if index >= len(v.done) {
+		v.results = append(v.results, make([][3]decimal.Decimal, index+1-len(v.results))...)
+		v.done = append(v.done, make([]bool, index+1-len(v.done))...)
+	}
+
+	start := index - v.window + 1
+	if v.key != nil {
+		start = sessionStart(v.series, v.key, index)
+	}
+	vp := NewVolumeProfile(v.series, start, index, v.cfg)
+	v.results[index] = [3]decimal.Decimal{vp.POC, vp.VAH, vp.VAL}
+	v.done[index] = true
+	return v.results[index]
+}
+
+func (v *volumeProfileIndicator) Lookback() int { return max(v.window, 1) - 1 }
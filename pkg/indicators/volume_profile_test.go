@@ -0,0 +1,140 @@
+package indicators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/series"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+// rangeSeries builds candles of the given step from low, high and volume triples
+func rangeSeries(start time.Time, step time.Duration, bars ...[3]float64) *series.TimeSeries {
+	ts := series.NewTimeSeries()
+	for i, bar := range bars {
+		c := series.NewCandle(series.NewTimePeriod(start.Add(time.Duration(i)*step), step))
+		c.MinPrice, c.MaxPrice = decimal.New(bar[0]), decimal.New(bar[1])
+		c.OpenPrice, c.ClosePrice = c.MinPrice, c.MaxPrice
+		c.Volume = decimal.New(bar[2])
+		ts.AddCandle(c)
+	}
+	return ts
+}
+
+func decimalStrings(values []decimal.Decimal) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = v.String()
+	}
+	return out
+}
+
+func TestNewVolumeProfile(t *testing.T) {
+	ts := rangeSeries(time.Unix(0, 0), time.Hour,
+		[3]float64{10, 12, 100},
+		[3]float64{11, 12, 300},
+		[3]float64{13, 14, 50},
+	)
+
+	vp := indicators.NewVolumeProfile(ts, 0, 2, indicators.ProfileConfig{BucketSize: 1})
+	require.Len(t, vp.Levels, 5)
+	volumes := make([]decimal.Decimal, len(vp.Levels))
+	for i, level := range vp.Levels {
+		volumes[i] = level.Volume
+	}
+	assert.Equal(t, []string{"50", "350", "0", "50", "0"}, decimalStrings(volumes))
+	assert.Equal(t, "10", vp.Levels[0].Low.String())
+	assert.Equal(t, "15", vp.Levels[4].High.String())
+	assert.Equal(t, "450", vp.Total.String())
+	assert.Equal(t, "11.5", vp.POC.String())
+	assert.Equal(t, "11", vp.VAL.String())
+	assert.Equal(t, "12", vp.VAH.String())
+	assert.Equal(t, []string{"11.5"}, decimalStrings(vp.HighVolumeNodes))
+	assert.Equal(t, []string{"12.5"}, decimalStrings(vp.LowVolumeNodes))
+
+	wide := indicators.NewVolumeProfile(ts, 0, 2, indicators.ProfileConfig{BucketSize: 1, ValueArea: 0.95})
+	assert.Equal(t, "10", wide.VAL.String())
+	assert.Equal(t, "14", wide.VAH.String())
+
+	auto := indicators.NewVolumeProfile(ts, -5, 10, indicators.ProfileConfig{})
+	assert.Equal(t, 0, auto.Start)
+	assert.Equal(t, 2, auto.End)
+	assert.Len(t, auto.Levels, 24)
+	assert.Equal(t, "14", auto.Levels[23].High.String())
+	assert.InDelta(t, 450, auto.Total.Float(), 1e-9)
+
+	assert.Empty(t, indicators.NewVolumeProfile(ts, 2, 1, indicators.ProfileConfig{}).Levels)
+}
+
+func TestNewTPOProfile(t *testing.T) {
+	ts := rangeSeries(time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC), 15*time.Minute,
+		[3]float64{10, 11, 1},
+		[3]float64{10.5, 11.5, 1},
+		[3]float64{11, 12, 1},
+		[3]float64{11, 12.5, 1},
+	)
+
+	tp := indicators.NewTPOProfile(ts, 0, 3, 30*time.Minute, indicators.ProfileConfig{BucketSize: 1})
+	require.Len(t, tp.Levels, 3)
+	assert.Equal(t, []int{1, 2, 1}, []int{tp.Levels[0].Count, tp.Levels[1].Count, tp.Levels[2].Count})
+	assert.Equal(t, []string{"A", "AB", "B"}, []string{tp.Levels[0].Letters, tp.Levels[1].Letters, tp.Levels[2].Letters})
+	assert.Equal(t, "11.5", tp.POC.String())
+	assert.Equal(t, "11", tp.VAL.String())
+	assert.Equal(t, "13", tp.VAH.String())
+	assert.Equal(t, "12.5", tp.InitialBalanceHigh.String())
+	assert.Equal(t, "10", tp.InitialBalanceLow.String())
+
+	assert.Panics(t, func() { indicators.NewTPOProfile(ts, 0, 3, 0, indicators.ProfileConfig{}) })
+}
+
+func TestSessions(t *testing.T) {
+	ts := rangeSeries(time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC), 2*time.Hour,
+		[3]float64{1, 2, 1}, [3]float64{1, 2, 1}, [3]float64{1, 2, 1},
+		[3]float64{1, 2, 1}, [3]float64{1, 2, 1},
+	)
+
+	assert.Equal(t, []indicators.SessionRange{{Start: 0, End: 1}, {Start: 2, End: 4}},
+		indicators.Sessions(ts, indicators.DailySessions(nil)))
+	assert.Equal(t, []indicators.SessionRange{{Start: 0, End: 4}},
+		indicators.Sessions(ts, indicators.SessionsStartingAt(17*time.Hour, time.UTC)))
+	assert.Nil(t, indicators.Sessions(series.NewTimeSeries(), indicators.DailySessions(nil)))
+
+	profiles := indicators.SessionVolumeProfiles(ts, indicators.DailySessions(nil), indicators.ProfileConfig{})
+	require.Len(t, profiles, 2)
+	assert.InDelta(t, 3, profiles[1].Total.Float(), 1e-9)
+	assert.Len(t, indicators.SessionTPOProfiles(ts, indicators.DailySessions(nil), time.Hour, indicators.ProfileConfig{}), 2)
+}
+
+func TestVolumeProfileIndicators(t *testing.T) {
+	ts := testutils.RandomTimeSeries(120)
+	cfg := indicators.ProfileConfig{BucketSize: 0.5}
+
+	rolling := indicators.NewVolumeProfileIndicator(ts, 20, cfg)
+	assert.Equal(t, []string{"poc", "vah", "val"}, rolling.Outputs())
+	assert.Equal(t, 19, indicators.Lookback(rolling.Output("vah")))
+	assert.True(t, rolling.Calculate(18).IsZero())
+	for i := 19; i < 120; i += 7 {
+		vp := indicators.NewVolumeProfile(ts, i-19, i, cfg)
+		assertSameValue(t, "poc", i, vp.POC, rolling.Calculate(i))
+		assertSameValue(t, "vah", i, vp.VAH, rolling.Output("vah").Calculate(i))
+		assertSameValue(t, "val", i, vp.VAL, rolling.Output("val").Calculate(i))
+	}
+
+	key := func(t time.Time) time.Time { return t.Truncate(30 * time.Second) }
+	session := indicators.NewSessionVolumeProfileIndicator(ts, key, cfg)
+	for _, s := range indicators.Sessions(ts, key) {
+		for i := s.Start; i <= s.End; i++ {
+			vp := indicators.NewVolumeProfile(ts, s.Start, i, cfg)
+			assertSameValue(t, "session poc", i, vp.POC, session.Calculate(i))
+		}
+	}
+
+	byName, err := indicators.NewIndicatorByName("vprofile.val", ts, map[string]float64{"window": 20, "bucket": 0.5})
+	require.NoError(t, err)
+	assertSameValue(t, "vprofile.val", 100, rolling.Output("val").Calculate(100), byName.Calculate(100))
+}
@@ -29,8 +29,10 @@ func (v *volumeROCIndicator) Calculate(index int) decimal.Decimal {
 	prevVol := v.volume.Calculate(prevIdx)
 
 	if prevVol.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return currVol.Sub(prevVol).Div(prevVol).Mul(decimal.New(100))
 }
+
+func (v *volumeROCIndicator) Lookback() int { return max(v.period, 0) }
@@ -34,7 +34,7 @@ func (v *vortexIndicator) Calculate(index int) decimal.Decimal {
 	negativeVM := v.calculateNegativeVMSum(index)
 
 	if trueRange.Zero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	positiveVI := positiveVM.Div(trueRange)
@@ -107,3 +107,5 @@ func (v *vortexIndicator) calculateNegativeVMSum(index int) decimal.Decimal {
 
 	return vmSum
 }
+
+func (v *vortexIndicator) Lookback() int { return v.period }
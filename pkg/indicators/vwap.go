@@ -40,7 +40,7 @@ func (v *vwapIndicator) Calculate(index int) decimal.Decimal {
 			sumV = sumV.Add(v.cacheV[i])
 		}
 		if sumV.IsZero() {
-			return decimal.ZERO
+			return decimal.NaN
 		}
 		return sumPV.Div(sumV)
 	}
@@ -64,7 +64,7 @@ func (v *vwapIndicator) Calculate(index int) decimal.Decimal {
 	}
 
 	if sumV.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return sumPV.Div(sumV)
@@ -105,8 +105,12 @@ func (v *windowedVWAPIndicator) Calculate(index int) decimal.Decimal {
 	}
 
 	if sumV.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return sumPV.Div(sumV)
 }
+
+func (v *vwapIndicator) Lookback() int { return 0 }
+
+func (v *windowedVWAPIndicator) Lookback() int { return max(v.window, 1) - 1 }
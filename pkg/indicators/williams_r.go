@@ -36,7 +36,7 @@ func (wi *williamsRIndicator) Calculate(index int) decimal.Decimal {
 	rangeVal := highestHigh.Sub(lowestLow)
 
 	if rangeVal.Zero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	numerator := highestHigh.Sub(closePrice)
@@ -44,3 +44,5 @@ func (wi *williamsRIndicator) Calculate(index int) decimal.Decimal {
 
 	return result
 }
+
+func (wi *williamsRIndicator) Lookback() int { return max(wi.window, 1) - 1 }
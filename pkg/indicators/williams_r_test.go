@@ -86,3 +86,21 @@ func TestWilliamsROverboughtOversold(t *testing.T) {
 		t.Errorf("WilliamsR() should be below -10 for overbought, got %v", result)
 	}
 }
+
+func TestWilliamsR_FlatRangeIsNaN(t *testing.T) {
+	s := series.NewTimeSeries()
+	for i := 0; i < 5; i++ {
+		s.AddCandle(&series.Candle{
+			OpenPrice:  decimal.New(100),
+			MaxPrice:   decimal.New(100),
+			MinPrice:   decimal.New(100),
+			ClosePrice: decimal.New(100),
+			Volume:     decimal.New(1000),
+		})
+	}
+
+	williamsR := NewWilliamsRIndicator(s, 3)
+	if got := williamsR.Calculate(4); !got.IsNaN() {
+		t.Errorf("expected NaN for a zero high-low range, got %v", got)
+	}
+}
@@ -32,3 +32,20 @@ func (sdi windowedStandardDeviationIndicator) Calculate(index int) decimal.Decim
 
 	return variance.Div(decimal.New(float64(realwindow))).Sqrt()
 }
+
+func (sdi windowedStandardDeviationIndicator) ComputeInto(dst []decimal.Decimal) {
+	if sdi.window <= 0 {
+		for i := range dst {
+			dst[i] = sdi.Calculate(i)
+		}
+		return
+	}
+	src := ComputeAll(sdi.Indicator, len(dst))
+	s := NewStreamingStandardDeviation(sdi.window)
+	for i, val := range src {
+		s.values.push(val)
+		dst[i] = s.current()
+	}
+}
+
+func (sdi windowedStandardDeviationIndicator) Lookback() int { return Lookback(sdi.movingAverage) }
@@ -96,3 +96,5 @@ func signOf(value decimal.Decimal) int {
 	}
 	return 0
 }
+
+func (z *zigzagIndicator) Lookback() int { return 0 }
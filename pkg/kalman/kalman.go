@@ -0,0 +1,177 @@
+// Package kalman implements a linear Kalman filter and the Rauch-Tung-Striebel
+// smoother over a state-space model:
+//
+//	x[t] = F x[t-1] + w,  w ~ N(0, Q)
+//	z[t] = H x[t] + v,    v ~ N(0, R)
+//
+// where x is the hidden state and z the observation. Vectors are []float64 and
+// matrices [][]float64 in row-major order.
+package kalman
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSingular is returned when the innovation covariance cannot be inverted
+var ErrSingular = errors.New("kalman: singular innovation covariance")
+
+// Model is a linear state-space model
+type Model struct {
+	// F is the state transition, n x n
+	F [][]float64
+	// H is the observation matrix, m x n
+	H [][]float64
+	// Q is the process noise covariance, n x n
+	Q [][]float64
+	// R is the observation noise covariance, m x m
+	R [][]float64
+}
+
+// validate panics unless the dimensions of the model and of an initial state
+// and covariance agree
+func (m Model) validate(x0 []float64, p0 [][]float64) {
+	n := len(x0)
+	square := func(a [][]float64, size int) bool {
+		if len(a) != size {
+			return false
+		}
+		for _, row := range a {
+			if len(row) != size {
+				return false
+			}
+		}
+		return true
+	}
+	if n == 0 || !square(m.F, n) || !square(m.Q, n) || !square(p0, n) {
+		panic("goflux: Kalman F, Q and the initial covariance must be n x n for a state of n")
+	}
+	if len(m.H) == 0 || !square(m.R, len(m.H)) {
+		panic("goflux: Kalman R must be m x m for an H of m rows")
+	}
+	for _, row := range m.H {
+		if len(row) != n {
+			panic("goflux: Kalman H must have a column per state")
+		}
+	}
+}
+
+// Filter is a Kalman filter running over a Model
+type Filter struct {
+	model Model
+	x     []float64
+	p     [][]float64
+}
+
+// NewFilter returns a filter over m starting from state x0 with covariance p0.
+// Panics if the dimensions disagree.
+func NewFilter(m Model, x0 []float64, p0 [][]float64) *Filter {
+	m.validate(x0, p0)
+	return &Filter{model: m, x: clone(x0), p: cloneMatrix(p0)}
+}
+
+// State returns a copy of the state estimate
+func (f *Filter) State() []float64 { return clone(f.x) }
+
+// Covariance returns a copy of the covariance of the state estimate
+func (f *Filter) Covariance() [][]float64 { return cloneMatrix(f.p) }
+
+// Predict advances the state to the next step: x = F x, P = F P F' + Q
+func (f *Filter) Predict() {
+	f.x = mulVec(f.model.F, f.x)
+	f.p = add(mul(mul(f.model.F, f.p), transpose(f.model.F)), f.model.Q)
+}
+
+// Update corrects the state with observation z and returns the innovation,
+// z less its prediction, with its covariance. An observation containing NaN
+// is treated as missing and leaves the state unchanged.
+func (f *Filter) Update(z []float64) (innovation []float64, covariance [][]float64, err error) {
+	return f.UpdateWith(f.model.H, z)
+}
+
+// UpdateWith is Update with observation matrix h in place of the model's, for
+// models whose observation varies by step such as dynamic regressions
+func (f *Filter) UpdateWith(h [][]float64, z []float64) (innovation []float64, covariance [][]float64, err error) {
+	if len(h) != len(z) {
+		panic("goflux: Kalman observation must have a value per row of H")
+	}
+	predicted := mulVec(h, f.x)
+	innovation = make([]float64, len(z))
+	for i := range z {
+		innovation[i] = z[i] - predicted[i]
+	}
+	pht := mul(f.p, transpose(h))
+	covariance = add(mul(h, pht), f.model.R)
+	for _, v := range z {
+		if math.IsNaN(v) {
+			return innovation, covariance, nil
+		}
+	}
+
+	inverse, ok := invert(covariance)
+	if !ok {
+		return innovation, covariance, ErrSingular
+	}
+	gain := mul(pht, inverse)
+	correction := mulVec(gain, innovation)
+	for i := range f.x {
+		f.x[i] += correction[i]
+	}
+	f.p = sub(f.p, mul(gain, mul(h, f.p)))
+	return innovation, covariance, nil
+}
+
+// Step is the filtered and predicted estimates at one step, kept by Smooth
+type Step struct {
+	// State and Covariance are the estimate after the observation
+	State      []float64
+	Covariance [][]float64
+	// Predicted and PredictedCovariance are the estimate before it
+	Predicted           []float64
+	PredictedCovariance [][]float64
+}
+
+// Smooth filters the observations with m from x0 and p0, predicting before
+// each, then runs the Rauch-Tung-Striebel smoother backwards over the results.
+// It returns the smoothed state and covariance at each step, which use all
+// the observations and so look ahead: use them to study a series, not to
+// trade it. Missing observations are rows containing NaN.
+func Smooth(m Model, x0 []float64, p0 [][]float64, observations [][]float64) (states [][]float64, covariances [][][]float64, err error) {
+	f := NewFilter(m, x0, p0)
+	steps := make([]Step, len(observations))
+	for t, z := range observations {
+		f.Predict()
+		steps[t].Predicted, steps[t].PredictedCovariance = f.State(), f.Covariance()
+		if _, _, err := f.Update(z); err != nil {
+			return nil, nil, err
+		}
+		steps[t].State, steps[t].Covariance = f.State(), f.Covariance()
+	}
+
+	states = make([][]float64, len(steps))
+	covariances = make([][][]float64, len(steps))
+	for t := len(steps) - 1; t >= 0; t-- {
+		if t == len(steps)-1 {
+			states[t], covariances[t] = steps[t].State, steps[t].Covariance
+			continue
+		}
+		next := steps[t+1]
+		inverse, ok := invert(next.PredictedCovariance)
+		if !ok {
+			return nil, nil, ErrSingular
+		}
+		// C = P F' P[t+1|t]^-1
+		c := mul(mul(steps[t].Covariance, transpose(m.F)), inverse)
+		diff := make([]float64, len(x0))
+		for i := range diff {
+			diff[i] = states[t+1][i] - next.Predicted[i]
+		}
+		adjust := mulVec(c, diff)
+		states[t] = clone(steps[t].State)
+		for i := range states[t] {
+			states[t][i] += adjust[i]
+		}
+		covariances[t] = add(steps[t].Covariance, mul(mul(c, sub(covariances[t+1], next.PredictedCovariance)), transpose(c)))
+	}
+	return states, covariances, nil
+}
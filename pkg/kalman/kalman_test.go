@@ -0,0 +1,117 @@
+package kalman
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWalkModel(q, r float64) Model {
+	return Model{F: [][]float64{{1}}, H: [][]float64{{1}}, Q: [][]float64{{q}}, R: [][]float64{{r}}}
+}
+
+func TestFilterUpdate(t *testing.T) {
+	f := NewFilter(randomWalkModel(0, 1), []float64{0}, [][]float64{{1}})
+	innovation, covariance, err := f.Update([]float64{2})
+	require.NoError(t, err)
+	assert.InDelta(t, 2, innovation[0], 1e-12)
+	assert.InDelta(t, 2, covariance[0][0], 1e-12)
+	assert.InDelta(t, 1, f.State()[0], 1e-12)
+	assert.InDelta(t, 0.5, f.Covariance()[0][0], 1e-12)
+
+	f.Predict()
+	assert.InDelta(t, 0.5, f.Covariance()[0][0], 1e-12, "no process noise")
+
+	// A missing observation leaves the estimate as predicted
+	_, _, err = f.Update([]float64{math.NaN()})
+	require.NoError(t, err)
+	assert.InDelta(t, 1, f.State()[0], 1e-12)
+	assert.InDelta(t, 0.5, f.Covariance()[0][0], 1e-12)
+}
+
+func TestFilterConverges(t *testing.T) {
+	f := NewFilter(randomWalkModel(1e-4, 1), []float64{0}, [][]float64{{100}})
+	for i := 0; i < 3000; i++ {
+		f.Predict()
+		_, _, err := f.Update([]float64{5})
+		require.NoError(t, err)
+	}
+	assert.InDelta(t, 5, f.State()[0], 1e-6)
+	// The steady state variance solves P = (P + q) r / (P + q + r)
+	p := f.Covariance()[0][0]
+	assert.InDelta(t, p, (p+1e-4)/(p+1e-4+1), 1e-9)
+}
+
+func TestFilterSingular(t *testing.T) {
+	f := NewFilter(randomWalkModel(0, 0), []float64{0}, [][]float64{{0}})
+	_, _, err := f.Update([]float64{1})
+	assert.ErrorIs(t, err, ErrSingular)
+}
+
+func TestFilterDimensions(t *testing.T) {
+	assert.Panics(t, func() { NewFilter(randomWalkModel(1, 1), []float64{0, 0}, [][]float64{{1}}) })
+	assert.Panics(t, func() {
+		NewFilter(Model{F: [][]float64{{1}}, H: [][]float64{{1, 0}}, Q: [][]float64{{1}}, R: [][]float64{{1}}}, []float64{0}, [][]float64{{1}})
+	})
+	f := NewFilter(randomWalkModel(1, 1), []float64{0}, [][]float64{{1}})
+	assert.Panics(t, func() { _, _, _ = f.Update([]float64{1, 2}) })
+}
+
+func TestUpdateWithRegression(t *testing.T) {
+	// Recover y = 2x + 1 with a static regression whose H is [x 1]
+	m := Model{
+		F: [][]float64{{1, 0}, {0, 1}},
+		H: [][]float64{{0, 1}},
+		Q: [][]float64{{0, 0}, {0, 0}},
+		R: [][]float64{{0.01}},
+	}
+	f := NewFilter(m, []float64{0, 0}, [][]float64{{1e6, 0}, {0, 1e6}})
+	for i := 0; i < 50; i++ {
+		x := float64(i%7) - 3
+		_, _, err := f.UpdateWith([][]float64{{x, 1}}, []float64{2*x + 1})
+		require.NoError(t, err)
+	}
+	assert.InDelta(t, 2, f.State()[0], 1e-4)
+	assert.InDelta(t, 1, f.State()[1], 1e-4)
+}
+
+func TestSmooth(t *testing.T) {
+	// A constant velocity target observed with noise
+	m := Model{
+		F: [][]float64{{1, 1}, {0, 1}},
+		H: [][]float64{{1, 0}},
+		Q: [][]float64{{1e-4, 0}, {0, 1e-4}},
+		R: [][]float64{{4}},
+	}
+	r := rand.New(rand.NewSource(1))
+	n := 200
+	truth, observations := make([]float64, n), make([][]float64, n)
+	for i := range truth {
+		truth[i] = 0.5 * float64(i)
+		observations[i] = []float64{truth[i] + 2*r.NormFloat64()}
+	}
+	observations[100] = []float64{math.NaN()}
+
+	x0, p0 := []float64{0, 0}, [][]float64{{100, 0}, {0, 100}}
+	states, covariances, err := Smooth(m, x0, p0, observations)
+	require.NoError(t, err)
+	require.Len(t, states, n)
+
+	f := NewFilter(m, x0, p0)
+	filteredError, smoothedError := 0.0, 0.0
+	for i, z := range observations {
+		f.Predict()
+		_, _, err := f.Update(z)
+		require.NoError(t, err)
+		filteredError += math.Abs(f.State()[0] - truth[i])
+		smoothedError += math.Abs(states[i][0] - truth[i])
+		assert.LessOrEqual(t, covariances[i][0][0], f.Covariance()[0][0]+1e-9)
+	}
+	assert.Less(t, smoothedError, filteredError)
+	// The last smoothed estimate is the filtered one
+	assert.InDeltaSlice(t, f.State(), states[n-1], 1e-12)
+	assert.InDelta(t, 0.5, states[n/2][1], 0.05)
+}
@@ -0,0 +1,117 @@
+package kalman
+
+import "math"
+
+func clone(v []float64) []float64 { return append([]float64(nil), v...) }
+
+func cloneMatrix(a [][]float64) [][]float64 {
+	c := make([][]float64, len(a))
+	for i, row := range a {
+		c[i] = clone(row)
+	}
+	return c
+}
+
+func newMatrix(rows, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+func mul(a, b [][]float64) [][]float64 {
+	c := newMatrix(len(a), len(b[0]))
+	for i := range a {
+		for k := range b {
+			if a[i][k] == 0 {
+				continue
+			}
+			for j := range b[k] {
+				c[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return c
+}
+
+func mulVec(a [][]float64, v []float64) []float64 {
+	c := make([]float64, len(a))
+	for i, row := range a {
+		for j, x := range row {
+			c[i] += x * v[j]
+		}
+	}
+	return c
+}
+
+func transpose(a [][]float64) [][]float64 {
+	t := newMatrix(len(a[0]), len(a))
+	for i, row := range a {
+		for j, x := range row {
+			t[j][i] = x
+		}
+	}
+	return t
+}
+
+func add(a, b [][]float64) [][]float64 {
+	c := cloneMatrix(a)
+	for i := range c {
+		for j := range c[i] {
+			c[i][j] += b[i][j]
+		}
+	}
+	return c
+}
+
+func sub(a, b [][]float64) [][]float64 {
+	c := cloneMatrix(a)
+	for i := range c {
+		for j := range c[i] {
+			c[i][j] -= b[i][j]
+		}
+	}
+	return c
+}
+
+// invert returns the inverse of a by Gauss-Jordan elimination with partial
+// pivoting, and false when a is singular
+func invert(a [][]float64) ([][]float64, bool) {
+	n := len(a)
+	w := newMatrix(n, 2*n)
+	for i := range a {
+		copy(w[i], a[i])
+		w[i][n+i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(w[r][col]) > math.Abs(w[pivot][col]) {
+				pivot = r
+			}
+		}
+		if w[pivot][col] == 0 || math.IsNaN(w[pivot][col]) {
+			return nil, false
+		}
+		w[col], w[pivot] = w[pivot], w[col]
+		scale := w[col][col]
+		for c := range w[col] {
+			w[col][c] /= scale
+		}
+		for r := 0; r < n; r++ {
+			if r == col || w[r][col] == 0 {
+				continue
+			}
+			factor := w[r][col]
+			for c := range w[r] {
+				w[r][c] -= factor * w[col][c]
+			}
+		}
+	}
+	inverse := newMatrix(n, n)
+	for i := range inverse {
+		copy(inverse[i], w[i][n:])
+	}
+	return inverse, true
+}
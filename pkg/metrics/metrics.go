@@ -8,16 +8,18 @@ import (
 
 // SharpeRatio calculates the Sharpe ratio for a series of returns.
 // The risk-free rate should be a decimal number (e.g., 0.02 for 2%).
+// It returns decimal.NaN when the ratio is undefined (fewer than two returns
+// or zero volatility).
 func SharpeRatio(returns []decimal.Decimal, riskFreeRate decimal.Decimal) decimal.Decimal {
 	if len(returns) < 2 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	mean := meanReturn(returns)
 	stdDev := standardDeviation(returns, mean)
 
 	if stdDev.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	excessReturn := mean.Sub(riskFreeRate)
@@ -26,16 +28,17 @@ func SharpeRatio(returns []decimal.Decimal, riskFreeRate decimal.Decimal) decima
 
 // SortinoRatio calculates the Sortino ratio for a series of returns.
 // The risk-free rate should be a decimal number (e.g., 0.02 for 2%).
+// It returns decimal.NaN when the ratio is undefined.
 func SortinoRatio(returns []decimal.Decimal, riskFreeRate decimal.Decimal) decimal.Decimal {
 	if len(returns) < 2 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	mean := meanReturn(returns)
 	downsideDev := downsideDeviation(returns, mean)
 
 	if downsideDev.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	excessReturn := mean.Sub(riskFreeRate)
@@ -43,17 +46,19 @@ func SortinoRatio(returns []decimal.Decimal, riskFreeRate decimal.Decimal) decim
 }
 
 // CalmarRatio calculates the Calmar ratio given CAGR and maximum drawdown.
+// It returns decimal.NaN when there was no drawdown.
 func CalmarRatio(cagr decimal.Decimal, maxDrawdown decimal.Decimal) decimal.Decimal {
 	if maxDrawdown.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 	return cagr.Div(maxDrawdown)
 }
 
 // CAGR calculates the Compound Annual Growth Rate.
+// It returns decimal.NaN for a zero initial equity or a non-positive period.
 func CAGR(initialEquity, finalEquity decimal.Decimal, years int) decimal.Decimal {
 	if initialEquity.IsZero() || years <= 0 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	equityRatio := finalEquity.Div(initialEquity)
@@ -62,10 +67,14 @@ func CAGR(initialEquity, finalEquity decimal.Decimal, years int) decimal.Decimal
 }
 
 // BurkeRatio calculates the Burke ratio given average return and drawdowns.
+// It returns decimal.NaN when there are no non-zero drawdowns.
 func BurkeRatio(averageReturn decimal.Decimal, drawdowns []float64) decimal.Decimal {
-	if averageReturn.IsZero() || len(drawdowns) == 0 {
+	if averageReturn.IsZero() {
 		return decimal.ZERO
 	}
+	if len(drawdowns) == 0 {
+		return decimal.NaN
+	}
 
 	sumSquaredDrawdowns := 0.0
 	for _, dd := range drawdowns {
@@ -73,7 +82,7 @@ func BurkeRatio(averageReturn decimal.Decimal, drawdowns []float64) decimal.Deci
 	}
 
 	if sumSquaredDrawdowns == 0 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return averageReturn.Div(decimal.New(sumSquaredDrawdowns))
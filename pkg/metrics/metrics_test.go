@@ -46,3 +46,14 @@ func TestBurkeRatio(t *testing.T) {
 	br := BurkeRatio(decimal.New(0.1), []float64{0.05, 0.02})
 	assert.True(t, br.GT(decimal.ZERO))
 }
+
+func TestRatiosUndefinedAreNaN(t *testing.T) {
+	flat := []decimal.Decimal{decimal.New(0.01), decimal.New(0.01), decimal.New(0.01)}
+
+	assert.True(t, SharpeRatio(flat, decimal.ZERO).IsNaN())
+	assert.True(t, SharpeRatio(flat[:1], decimal.ZERO).IsNaN())
+	assert.True(t, SortinoRatio(nil, decimal.ZERO).IsNaN())
+	assert.True(t, CalmarRatio(decimal.New(0.1), decimal.ZERO).IsNaN())
+	assert.True(t, CAGR(decimal.ZERO, decimal.New(100), 1).IsNaN())
+	assert.True(t, BurkeRatio(decimal.New(0.1), nil).IsNaN())
+}
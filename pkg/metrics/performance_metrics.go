@@ -57,6 +57,12 @@ type PerformanceMetrics struct {
 	TradingDays          int
 }
 
+// NewPerformanceMetrics returns a PerformanceMetrics with a 2% risk-free rate.
+//
+// Ratios and averages that are mathematically undefined for the given trades
+// (for example ProfitFactor with no losing trades, or SharpeRatio with zero
+// volatility) are reported as decimal.NaN rather than zero; use IsValid to
+// distinguish them.
 func NewPerformanceMetrics() *PerformanceMetrics {
 	return &PerformanceMetrics{
 		RiskFreeRate: decimal.New(0.02),
@@ -70,34 +76,35 @@ func (pm *PerformanceMetrics) Calculate(trades []Trade, equityCurve []EquityPoin
 	pm.TotalTrades = 0
 	pm.WinningTrades = 0
 	pm.LosingTrades = 0
-	pm.WinRate = decimal.ZERO
+	pm.WinRate = decimal.NaN
 	pm.TotalProfit = decimal.ZERO
 	pm.GrossProfit = decimal.ZERO
 	pm.GrossLoss = decimal.ZERO
-	pm.ProfitFactor = decimal.ZERO
-	pm.AverageWin = decimal.ZERO
-	pm.AverageLoss = decimal.ZERO
-	pm.AverageTrade = decimal.ZERO
-	pm.AverageWinPct = decimal.ZERO
-	pm.AverageLossPct = decimal.ZERO
+	pm.ProfitFactor = decimal.NaN
+	pm.AverageWin = decimal.NaN
+	pm.AverageLoss = decimal.NaN
+	pm.AverageTrade = decimal.NaN
+	pm.AverageWinPct = decimal.NaN
+	pm.AverageLossPct = decimal.NaN
 	pm.MaxConsecutiveWins = 0
 	pm.MaxConsecutiveLosses = 0
 	pm.MaxDrawdown = decimal.ZERO
 	pm.MaxDrawdownPct = decimal.ZERO
 	pm.AvgDrawdown = decimal.ZERO
 	pm.AvgDrawdownPct = decimal.ZERO
-	pm.RecoveryFactor = decimal.ZERO
-	pm.RiskRewardRatio = decimal.ZERO
-	pm.CAGR = decimal.ZERO
-	pm.SharpeRatio = decimal.ZERO
-	pm.SortinoRatio = decimal.ZERO
-	pm.CalmarRatio = decimal.ZERO
-	pm.SterlingRatio = decimal.ZERO
-	pm.BurkeRatio = decimal.ZERO
-	pm.Skewness = decimal.ZERO
-	pm.Kurtosis = decimal.ZERO
-	pm.TotalReturn = decimal.ZERO
-	pm.TotalReturnPct = decimal.ZERO
+	pm.RecoveryFactor = decimal.NaN
+	pm.RiskRewardRatio = decimal.NaN
+	pm.CAGR = decimal.NaN
+	pm.SharpeRatio = decimal.NaN
+	pm.SortinoRatio = decimal.NaN
+	pm.CalmarRatio = decimal.NaN
+	pm.SterlingRatio = decimal.NaN
+	pm.BurkeRatio = decimal.NaN
+	pm.Skewness = decimal.NaN
+	pm.Kurtosis = decimal.NaN
+
+	pm.TotalReturn = finalEquity.Sub(initialEquity)
+	pm.TotalReturnPct = pm.TotalReturn.DivOrNaN(initialEquity)
 
 	if len(trades) == 0 {
 		return
@@ -153,14 +160,7 @@ func (pm *PerformanceMetrics) Calculate(trades []Trade, equityCurve []EquityPoin
 		pm.WinRate = decimal.New(float64(pm.WinningTrades)).Div(decimal.New(float64(pm.TotalTrades)))
 	}
 
-	if !pm.GrossLoss.IsZero() {
-		pm.ProfitFactor = pm.GrossProfit.Div(pm.GrossLoss)
-	}
-
-	pm.TotalReturn = finalEquity.Sub(initialEquity)
-	if !initialEquity.IsZero() {
-		pm.TotalReturnPct = pm.TotalReturn.Div(initialEquity)
-	}
+	pm.ProfitFactor = pm.GrossProfit.DivOrNaN(pm.GrossLoss)
 
 	pm.calculateDrawdownMetrics(equityCurve)
 	pm.calculateRiskAdjustedMetrics(trades)
@@ -198,12 +198,10 @@ func (pm *PerformanceMetrics) calculateDrawdownMetrics(equityCurve []EquityPoint
 		pm.AvgDrawdownPct = totalDrawdownPct.Div(decimal.New(float64(len(equityCurve))))
 	}
 
-	if !maxDrawdown.IsZero() {
-		pm.RecoveryFactor = pm.TotalProfit.Div(maxDrawdown)
-	}
+	pm.RecoveryFactor = pm.TotalProfit.DivOrNaN(maxDrawdown)
 
-	if !pm.AverageLoss.IsZero() && pm.AverageWin.GT(pm.AverageLoss) {
-		pm.RiskRewardRatio = pm.AverageWin.Div(pm.AverageLoss)
+	if pm.AverageWin.IsValid() && pm.AverageLoss.IsValid() {
+		pm.RiskRewardRatio = pm.AverageWin.DivOrNaN(pm.AverageLoss)
 	}
 }
 
@@ -237,12 +235,12 @@ func (pm *PerformanceMetrics) calculateRiskAdjustedMetrics(trades []Trade) {
 
 func (pm *PerformanceMetrics) calculateCAGR() decimal.Decimal {
 	if pm.InitialEquity.IsZero() || pm.FinalEquity.LT(decimal.ZERO) {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	years := decimal.New(float64(pm.TradingDays)).Div(decimal.New(365.0))
 	if years.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	equityRatio := pm.FinalEquity.Div(pm.InitialEquity)
@@ -254,14 +252,14 @@ func (pm *PerformanceMetrics) calculateCAGR() decimal.Decimal {
 
 func (pm *PerformanceMetrics) calculateSharpeRatio(trades []Trade, annualizationFactor decimal.Decimal) decimal.Decimal {
 	if len(trades) < 2 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	meanReturn := pm.calculateMeanReturn(trades)
 	stdDev := pm.calculateStandardDeviation(trades, meanReturn)
 
 	if stdDev.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	excessReturn := meanReturn.Sub(pm.RiskFreeRate.Div(decimal.New(365.0)))
@@ -272,14 +270,14 @@ func (pm *PerformanceMetrics) calculateSharpeRatio(trades []Trade, annualization
 
 func (pm *PerformanceMetrics) calculateSortinoRatio(trades []Trade, annualizationFactor decimal.Decimal) decimal.Decimal {
 	if len(trades) < 2 {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	meanReturn := pm.calculateMeanReturn(trades)
 	downsideDev := pm.calculateDownsideDeviation(trades, meanReturn)
 
 	if downsideDev.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	excessReturn := meanReturn.Sub(pm.RiskFreeRate.Div(decimal.New(365.0)))
@@ -290,36 +288,22 @@ func (pm *PerformanceMetrics) calculateSortinoRatio(trades []Trade, annualizatio
 
 func (pm *PerformanceMetrics) calculateCalmarRatio(cagr decimal.Decimal) decimal.Decimal {
 	if pm.MaxDrawdown.IsZero() || pm.MaxDrawdownPct.IsZero() {
-		return decimal.ZERO
+		return decimal.NaN
 	}
 
 	return cagr.Div(pm.MaxDrawdownPct)
 }
 
 func (pm *PerformanceMetrics) calculateSterlingRatio(cagr decimal.Decimal) decimal.Decimal {
-	if pm.AvgDrawdownPct.IsZero() {
-		return decimal.ZERO
-	}
-
 	adjustedDrawdown := pm.AvgDrawdownPct.Mul(decimal.New(1.5))
-	if adjustedDrawdown.IsZero() {
-		return decimal.ZERO
-	}
 
-	return cagr.Div(adjustedDrawdown)
+	return cagr.DivOrNaN(adjustedDrawdown)
 }
 
 func (pm *PerformanceMetrics) calculateBurkeRatio() decimal.Decimal {
-	if pm.MaxDrawdown.IsZero() {
-		return decimal.ZERO
-	}
-
 	drawdownVariance := pm.MaxDrawdown.Pow(2)
-	if drawdownVariance.IsZero() {
-		return decimal.ZERO
-	}
 
-	return pm.TotalProfit.Div(drawdownVariance)
+	return pm.TotalProfit.DivOrNaN(drawdownVariance)
 }
 
 func (pm *PerformanceMetrics) calculateMeanReturn(trades []Trade) decimal.Decimal {
@@ -257,3 +257,54 @@ func TestBurkeRatio_PerformanceMetrics(t *testing.T) {
 
 	t.Logf("Burke Ratio: %v", pm.BurkeRatio)
 }
+
+func TestPerformanceMetrics_UndefinedRatiosAreNaN(t *testing.T) {
+	trades := []Trade{
+		{Profit: decimal.New(100), ProfitPct: decimal.New(0.01), IsWin: true},
+		{Profit: decimal.New(100), ProfitPct: decimal.New(0.01), IsWin: true},
+	}
+	equityCurve := []EquityPoint{
+		{Equity: decimal.New(10000)},
+		{Equity: decimal.New(10200)},
+	}
+
+	pm := NewPerformanceMetrics()
+	pm.Calculate(trades, equityCurve, decimal.New(10000), decimal.New(10200), 252)
+
+	if !pm.ProfitFactor.IsNaN() {
+		t.Errorf("expected NaN profit factor without losses, got %v", pm.ProfitFactor)
+	}
+	if !pm.AverageLoss.IsNaN() {
+		t.Errorf("expected NaN average loss without losses, got %v", pm.AverageLoss)
+	}
+	if !pm.SharpeRatio.IsNaN() {
+		t.Errorf("expected NaN Sharpe ratio with zero volatility, got %v", pm.SharpeRatio)
+	}
+	if !pm.WinRate.EQ(decimal.ONE) {
+		t.Errorf("expected win rate 1, got %v", pm.WinRate)
+	}
+	if !pm.RiskRewardRatio.IsNaN() {
+		t.Errorf("expected NaN risk/reward ratio without losses, got %v", pm.RiskRewardRatio)
+	}
+
+	pm.Calculate(nil, nil, decimal.New(10000), decimal.New(10000), 0)
+	if !pm.WinRate.IsNaN() {
+		t.Errorf("expected NaN win rate without trades, got %v", pm.WinRate)
+	}
+}
+
+func TestRiskRewardRatio(t *testing.T) {
+	trades := []Trade{
+		{Profit: decimal.New(50), IsWin: true},
+		{Profit: decimal.New(-100), IsWin: false},
+	}
+	equityCurve := []EquityPoint{{Equity: decimal.New(10000)}}
+
+	pm := NewPerformanceMetrics()
+	pm.Calculate(trades, equityCurve, decimal.New(10000), decimal.New(9950), 252)
+
+	// Reported below 1 too, rather than as zero
+	if !pm.RiskRewardRatio.EQ(decimal.New(0.5)) {
+		t.Errorf("expected risk/reward ratio 0.5, got %v", pm.RiskRewardRatio)
+	}
+}
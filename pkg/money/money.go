@@ -0,0 +1,167 @@
+// Package money provides currency-aware amounts and foreign-exchange conversion.
+//
+// A Money value pairs a decimal amount with a Currency. Arithmetic between two
+// Money values refuses to mix currencies; amounts must be converted explicitly
+// through a RateProvider first.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+// ErrCurrencyMismatch is returned when an operation combines two different currencies
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// ErrInvalidCurrency is returned when a currency code is malformed
+var ErrInvalidCurrency = errors.New("money: invalid currency code")
+
+// Currency is an ISO 4217 code (USD, EUR) or a crypto asset ticker (USDT, BTC).
+type Currency string
+
+// Common currencies
+const (
+	USD  Currency = "USD"
+	EUR  Currency = "EUR"
+	GBP  Currency = "GBP"
+	JPY  Currency = "JPY"
+	CHF  Currency = "CHF"
+	USDT Currency = "USDT"
+	USDC Currency = "USDC"
+	BTC  Currency = "BTC"
+	ETH  Currency = "ETH"
+)
+
+// ParseCurrency normalizes s to upper case and validates it. Codes must be 2 to
+// 10 ASCII letters or digits, which covers ISO 4217 codes and crypto tickers.
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(s)))
+	if !c.Valid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCurrency, s)
+	}
+	return c, nil
+}
+
+// Valid reports whether c is a well-formed currency code
+func (c Currency) Valid() bool {
+	if len(c) < 2 || len(c) > 10 {
+		return false
+	}
+	for _, r := range c {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the currency code
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Money is an amount denominated in a single currency
+type Money struct {
+	Amount   decimal.Decimal
+	Currency Currency
+}
+
+// New returns a Money of amount in currency c
+func New(amount decimal.Decimal, c Currency) Money {
+	return Money{Amount: amount, Currency: c}
+}
+
+// Zero returns a zero amount in currency c
+func Zero(c Currency) Money {
+	return Money{Amount: decimal.ZERO, Currency: c}
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// Add returns m + o, or ErrCurrencyMismatch if the currencies differ
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), Currency: m.Currency}, nil
+}
+
+// Sub returns m - o, or ErrCurrencyMismatch if the currencies differ
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), Currency: m.Currency}, nil
+}
+
+// Cmp compares m and o like decimal.Decimal.Cmp, or returns ErrCurrencyMismatch
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+// Mul scales m by a dimensionless factor
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{Amount: m.Amount.Mul(factor), Currency: m.Currency}
+}
+
+// Div divides m by a dimensionless divisor. Division by zero yields a NaN amount.
+func (m Money) Div(divisor decimal.Decimal) Money {
+	return Money{Amount: m.Amount.DivOrNaN(divisor), Currency: m.Currency}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Amount: m.Amount.Neg(), Currency: m.Currency}
+}
+
+// Abs returns |m|
+func (m Money) Abs() Money {
+	return Money{Amount: m.Amount.Abs(), Currency: m.Currency}
+}
+
+// IsZero returns true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// IsNegative returns true if the amount is negative
+func (m Money) IsNegative() bool {
+	return m.Amount.IsNegative()
+}
+
+// IsPositive returns true if the amount is positive
+func (m Money) IsPositive() bool {
+	return m.Amount.IsPositive()
+}
+
+// Convert returns m expressed in currency to, using the rate provider at time at
+func (m Money) Convert(to Currency, rates RateProvider, at time.Time) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	if rates == nil {
+		return Money{}, fmt.Errorf("%w: %s/%s", ErrRateNotFound, m.Currency, to)
+	}
+	rate, err := rates.Rate(m.Currency, to, at)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Mul(rate), Currency: to}, nil
+}
+
+// String returns the amount followed by the currency code, e.g. "12.5 USD"
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
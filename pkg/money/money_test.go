@@ -0,0 +1,77 @@
+package money
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+)
+
+func TestParseCurrency(t *testing.T) {
+	c, err := ParseCurrency(" usdt ")
+	require.NoError(t, err)
+	assert.Equal(t, USDT, c)
+
+	for _, bad := range []string{"", "U", "US-D", "TOOLONGCODE1"} {
+		_, err := ParseCurrency(bad)
+		assert.ErrorIs(t, err, ErrInvalidCurrency, bad)
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := New(decimal.New(10), USD)
+	b := New(decimal.New(2.5), USD)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "12.5 USD", sum.String())
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, diff.Amount.Float())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	assert.Equal(t, 20.0, a.Mul(decimal.New(2)).Amount.Float())
+	assert.Equal(t, 5.0, a.Div(decimal.New(2)).Amount.Float())
+	assert.True(t, a.Div(decimal.ZERO).Amount.IsNaN())
+	assert.True(t, a.Neg().IsNegative())
+	assert.True(t, a.Neg().Abs().IsPositive())
+	assert.True(t, Zero(EUR).IsZero())
+}
+
+func TestMoney_RefusesMixedCurrencies(t *testing.T) {
+	usd := New(decimal.New(10), USD)
+	eur := New(decimal.New(10), EUR)
+
+	_, err := usd.Add(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = usd.Sub(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = usd.Cmp(eur)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_Convert(t *testing.T) {
+	rates := NewStaticRates().Set(EUR, USD, decimal.New(1.25))
+	eur := New(decimal.New(100), EUR)
+
+	usd, err := eur.Convert(USD, rates, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, USD, usd.Currency)
+	assert.InDelta(t, 125.0, usd.Amount.Float(), 1e-9)
+
+	same, err := eur.Convert(EUR, nil, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, eur, same)
+
+	_, err = eur.Convert(JPY, rates, time.Time{})
+	assert.ErrorIs(t, err, ErrRateNotFound)
+	_, err = eur.Convert(USD, nil, time.Time{})
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
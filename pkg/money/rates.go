@@ -0,0 +1,127 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// ErrRateNotFound is returned when a provider has no rate for a currency pair
+var ErrRateNotFound = errors.New("money: exchange rate not found")
+
+// RateProvider returns the number of units of to that one unit of from buys at time at.
+type RateProvider interface {
+	Rate(from, to Currency, at time.Time) (decimal.Decimal, error)
+}
+
+type pair struct {
+	from Currency
+	to   Currency
+}
+
+// StaticRates is a fixed table of exchange rates that ignores time. A rate
+// registered for A/B is also used, inverted, for B/A.
+type StaticRates struct {
+	mu    sync.RWMutex
+	rates map[pair]decimal.Decimal
+}
+
+// NewStaticRates returns an empty StaticRates table
+func NewStaticRates() *StaticRates {
+	return &StaticRates{rates: make(map[pair]decimal.Decimal)}
+}
+
+// Set registers the rate for one unit of from expressed in to.
+// Non-positive rates are ignored.
+func (s *StaticRates) Set(from, to Currency, rate decimal.Decimal) *StaticRates {
+	if !rate.IsPositive() {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[pair{from, to}] = rate
+	return s
+}
+
+// Rate implements RateProvider
+func (s *StaticRates) Rate(from, to Currency, _ time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.ONE, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.rates[pair{from, to}]; ok {
+		return r, nil
+	}
+	if r, ok := s.rates[pair{to, from}]; ok {
+		return decimal.ONE.Div(r), nil
+	}
+	return decimal.ZERO, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+// SeriesRates looks rates up from time series of FX candles. The rate at time
+// at is the close of the latest candle that has finished by at, so a backtest
+// never converts with a rate from the future. A series registered for A/B is
+// also used, inverted, for B/A.
+type SeriesRates struct {
+	mu     sync.RWMutex
+	series map[pair]*series.TimeSeries
+}
+
+// NewSeriesRates returns an empty SeriesRates provider
+func NewSeriesRates() *SeriesRates {
+	return &SeriesRates{series: make(map[pair]*series.TimeSeries)}
+}
+
+// Add registers a series whose close prices quote one unit of from in to
+func (s *SeriesRates) Add(from, to Currency, ts *series.TimeSeries) *SeriesRates {
+	if ts == nil {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series[pair{from, to}] = ts
+	return s
+}
+
+// Rate implements RateProvider
+func (s *SeriesRates) Rate(from, to Currency, at time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.ONE, nil
+	}
+	s.mu.RLock()
+	direct, hasDirect := s.series[pair{from, to}]
+	inverse, hasInverse := s.series[pair{to, from}]
+	s.mu.RUnlock()
+
+	if hasDirect {
+		if r, ok := closeAt(direct, at); ok {
+			return r, nil
+		}
+	}
+	if hasInverse {
+		if r, ok := closeAt(inverse, at); ok {
+			return decimal.ONE.Div(r), nil
+		}
+	}
+	return decimal.ZERO, fmt.Errorf("%w: %s/%s at %s", ErrRateNotFound, from, to, at.Format(time.RFC3339))
+}
+
+// closeAt returns the close of the last candle whose period ended at or before at.
+func closeAt(ts *series.TimeSeries, at time.Time) (decimal.Decimal, bool) {
+	candles := ts.CandlesSnapshot()
+	i := sort.Search(len(candles), func(i int) bool {
+		return candles[i] != nil && candles[i].Period.End.After(at)
+	})
+	for i--; i >= 0; i-- {
+		if c := candles[i]; c != nil && c.ClosePrice.IsPositive() {
+			return c.ClosePrice, true
+		}
+	}
+	return decimal.ZERO, false
+}
@@ -0,0 +1,57 @@
+package money
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+func TestStaticRates(t *testing.T) {
+	rates := NewStaticRates().Set(EUR, USD, decimal.New(1.25)).Set(USD, JPY, decimal.ZERO)
+
+	r, err := rates.Rate(EUR, USD, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 1.25, r.Float())
+
+	r, err = rates.Rate(USD, EUR, time.Time{})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, r.Float(), 1e-12)
+
+	r, err = rates.Rate(BTC, BTC, time.Time{})
+	require.NoError(t, err)
+	assert.True(t, r.EQ(decimal.ONE))
+
+	_, err = rates.Rate(USD, JPY, time.Time{})
+	assert.ErrorIs(t, err, ErrRateNotFound, "non-positive rates are ignored")
+}
+
+func TestSeriesRates_NoLookAhead(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := series.NewTimeSeries()
+	for i, rate := range []float64{1.10, 1.20, 1.30} {
+		c := series.NewCandle(series.NewTimePeriod(start.Add(time.Duration(i)*time.Hour), time.Hour))
+		c.ClosePrice = decimal.New(rate)
+		ts.AddCandle(c)
+	}
+	rates := NewSeriesRates().Add(EUR, USD, ts)
+
+	_, err := rates.Rate(EUR, USD, start.Add(30*time.Minute))
+	assert.ErrorIs(t, err, ErrRateNotFound, "first candle has not closed yet")
+
+	r, err := rates.Rate(EUR, USD, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1.10, r.Float())
+
+	r, err = rates.Rate(EUR, USD, start.Add(150*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1.20, r.Float())
+
+	r, err = rates.Rate(USD, EUR, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.InDelta(t, 1/1.30, r.Float(), 1e-12)
+}
@@ -0,0 +1,188 @@
+package reftest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+)
+
+type compatCase struct {
+	name string
+	want []float64
+	got  indicators.Indicator
+}
+
+// assertMatches checks that got has a value exactly where want does and that
+// the values agree to within floating point noise
+func assertMatches(t *testing.T, name string, want []float64, got indicators.Indicator) {
+	t.Helper()
+	for i, w := range want {
+		g := got.Calculate(i)
+		if math.IsNaN(w) {
+			if !g.IsNaN() {
+				t.Fatalf("%s[%d] = %s, want no value", name, i, g)
+			}
+			continue
+		}
+		if g.IsNaN() || math.Abs(g.Float()-w) > 1e-7*math.Max(1, math.Abs(w)) {
+			t.Fatalf("%s[%d] = %s, want %v", name, i, g, w)
+		}
+	}
+}
+
+// talibCases pairs the TA-Lib ports over bars with the goflux indicators of
+// the CompatTALib profile, named after the TA-Lib functions and outputs
+func talibCases(bars []ohlcvBar) []compatCase {
+	ts := newSeries(bars)
+	_, high, low, closes, volume := columns(bars)
+	closePrice := indicators.NewClosePriceIndicator(ts)
+	profile := indicators.CompatTALib
+
+	macd := indicators.NewMACDLinesIndicatorCompat(closePrice, 12, 26, 9, profile)
+	wantMACD, wantSignal, wantHist := talibMACD(closes, 12, 26, 9)
+	bands := indicators.NewBollingerBandsIndicatorCompat(closePrice, 20, 2, profile)
+	wantUpper, wantMiddle, wantLower := talibBBands(closes, 20, 2)
+	stoch := indicators.NewStochasticIndicatorCompat(ts, 14, 3, 3, profile)
+	wantK, wantD := talibStoch(high, low, closes, 14, 3, 3)
+
+	return []compatCase{
+		{"EMA(10)", talibEMA(closes, 10, 9), indicators.NewEMAIndicatorCompat(closePrice, 10, profile)},
+		{"RSI(14)", talibRSI(closes, 14), indicators.NewRelativeStrengthIndexIndicatorCompat(closePrice, 14, profile)},
+		{"MACD.macd", wantMACD, macd.Output("macd")},
+		{"MACD.signal", wantSignal, macd.Output("signal")},
+		{"MACD.histogram", wantHist, macd.Output("histogram")},
+		{"BBANDS.upper", wantUpper, bands.Output("upper")},
+		{"BBANDS.middle", wantMiddle, bands.Output("middle")},
+		{"BBANDS.lower", wantLower, bands.Output("lower")},
+		{"STOCH.k", wantK, stoch.Output("k")},
+		{"STOCH.d", wantD, stoch.Output("d")},
+		{"ATR(14)", talibATR(high, low, closes, 14), indicators.NewAverageTrueRangeIndicatorCompat(ts, 14, profile)},
+		{"ADX(14)", talibADX(high, low, closes, 14), indicators.NewADXIndicatorCompat(ts, 14, profile)},
+		{"CCI(20)", talibCCI(high, low, closes, 20), indicators.NewCCIIndicatorCompat(ts, 20, profile)},
+		{"OBV", talibOBV(closes, volume), indicators.NewOBVIndicatorCompat(ts, profile)},
+		{"SAR(0.02, 0.2)", talibSAR(high, low, 0.02, 0.2), indicators.NewParabolicSARIndicatorCompat(ts, 0.02, 0.02, 0.2, profile)},
+	}
+}
+
+func TestTALibCompatibility(t *testing.T) {
+	for _, tc := range talibCases(dailyOHLCV) {
+		t.Run(tc.name, func(t *testing.T) {
+			assertMatches(t, tc.name, tc.want, tc.got)
+		})
+	}
+}
+
+// talibHilbertCases pairs the port of the TA-Lib Hilbert transform functions
+// over bars with the goflux HT indicators
+func talibHilbertCases(bars []ohlcvBar) []compatCase {
+	_, _, _, closes, _ := columns(bars)
+	closePrice := indicators.NewClosePriceIndicator(newSeries(bars))
+	want := talibHilbert(closes)
+	phasor := indicators.NewHTPhasorIndicator(closePrice)
+	sine := indicators.NewHTSineIndicator(closePrice)
+
+	return []compatCase{
+		{"HT_DCPERIOD", want.dcPeriod, indicators.NewHTDCPeriodIndicator(closePrice)},
+		{"HT_DCPHASE", want.dcPhase, indicators.NewHTDCPhaseIndicator(closePrice)},
+		{"HT_PHASOR.inphase", want.inPhase, phasor.Output("inphase")},
+		{"HT_PHASOR.quadrature", want.quadrature, phasor.Output("quadrature")},
+		{"HT_SINE.sine", want.sine, sine.Output("sine")},
+		{"HT_SINE.leadsine", want.leadSine, sine.Output("leadsine")},
+		{"HT_TRENDLINE", want.trendline, indicators.NewHTTrendlineIndicator(closePrice)},
+		{"HT_TRENDMODE", want.trendMode, indicators.NewHTTrendModeIndicator(closePrice)},
+	}
+}
+
+func TestTALibHilbertTransform(t *testing.T) {
+	for _, tc := range talibHilbertCases(dailyOHLCV) {
+		t.Run(tc.name, func(t *testing.T) {
+			assertMatches(t, tc.name, tc.want, tc.got)
+		})
+	}
+}
+
+// pineCases pairs the Pine Script ports over bars with the goflux indicators
+// of the CompatTradingView profile, named after the Pine Script built-ins
+func pineCases(bars []ohlcvBar) []compatCase {
+	ts := newSeries(bars)
+	_, high, low, closes, volume := columns(bars)
+	closePrice := indicators.NewClosePriceIndicator(ts)
+	profile := indicators.CompatTradingView
+
+	macd := indicators.NewMACDLinesIndicatorCompat(closePrice, 12, 26, 9, profile)
+	wantMACD, wantSignal, wantHist := pineMACD(closes, 12, 26, 9)
+	bands := indicators.NewBollingerBandsIndicatorCompat(closePrice, 20, 2, profile)
+	middle, dev := pineSMA(closes, 20), pineStdev(closes, 20)
+	wantUpper, wantLower := make([]float64, len(closes)), make([]float64, len(closes))
+	for i := range closes {
+		wantUpper[i], wantLower[i] = middle[i]+2*dev[i], middle[i]-2*dev[i]
+	}
+	stoch := indicators.NewStochasticIndicatorCompat(ts, 14, 3, 3, profile)
+	wantK, wantD := pineStoch(high, low, closes, 14, 3, 3)
+
+	return []compatCase{
+		{"ta.ema(10)", pineEMA(closes, 10), indicators.NewEMAIndicatorCompat(closePrice, 10, profile)},
+		{"ta.rsi(14)", pineRSI(closes, 14), indicators.NewRelativeStrengthIndexIndicatorCompat(closePrice, 14, profile)},
+		{"ta.macd.macd", wantMACD, macd.Output("macd")},
+		{"ta.macd.signal", wantSignal, macd.Output("signal")},
+		{"ta.macd.histogram", wantHist, macd.Output("histogram")},
+		{"ta.bb.upper", wantUpper, bands.Output("upper")},
+		{"ta.bb.middle", middle, bands.Output("middle")},
+		{"ta.bb.lower", wantLower, bands.Output("lower")},
+		{"stoch.k", wantK, stoch.Output("k")},
+		{"stoch.d", wantD, stoch.Output("d")},
+		{"ta.atr(14)", pineATR(high, low, closes, 14), indicators.NewAverageTrueRangeIndicatorCompat(ts, 14, profile)},
+		{"ta.dmi.adx(14)", pineADX(high, low, closes, 14), indicators.NewADXIndicatorCompat(ts, 14, profile)},
+		{"ta.cci(20)", pineCCI(high, low, closes, 20), indicators.NewCCIIndicatorCompat(ts, 20, profile)},
+		{"ta.obv", pineOBV(closes, volume), indicators.NewOBVIndicatorCompat(ts, profile)},
+		{"ta.sar(0.02, 0.02, 0.2)", pineSAR(high, low, closes, 0.02, 0.02, 0.2), indicators.NewParabolicSARIndicatorCompat(ts, 0.02, 0.02, 0.2, profile)},
+	}
+}
+
+func TestTradingViewCompatibility(t *testing.T) {
+	for _, tc := range pineCases(dailyOHLCV) {
+		t.Run(tc.name, func(t *testing.T) {
+			assertMatches(t, tc.name, tc.want, tc.got)
+		})
+	}
+}
+
+func TestStockChartsRSI(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(closeSeries(stockChartsRSI.Close))
+	for _, profile := range []indicators.Compatibility{indicators.CompatTALib, indicators.CompatTradingView} {
+		rsi := indicators.NewRelativeStrengthIndexIndicatorCompat(closePrice, 14, profile)
+		if got := rsi.Calculate(13); !got.IsNaN() {
+			t.Errorf("%s RSI[13] = %s, want no value", profile, got)
+		}
+		for i, want := range stockChartsRSI.RSI {
+			if got := rsi.Calculate(14 + i).Float(); math.Abs(got-want) > 0.005 {
+				t.Errorf("%s RSI[%d] = %.4f, want %.2f", profile, 14+i, got, want)
+			}
+		}
+	}
+}
+
+func TestTALibAndTradingViewDiffer(t *testing.T) {
+	ts := newSeries(dailyOHLCV)
+	last := ts.LastIndex()
+	differ := map[string][2]indicators.Indicator{
+		"ATR": {
+			indicators.NewAverageTrueRangeIndicatorCompat(ts, 14, indicators.CompatTALib),
+			indicators.NewAverageTrueRangeIndicatorCompat(ts, 14, indicators.CompatTradingView),
+		},
+		"ADX": {
+			indicators.NewADXIndicatorCompat(ts, 14, indicators.CompatTALib),
+			indicators.NewADXIndicatorCompat(ts, 14, indicators.CompatTradingView),
+		},
+		"OBV": {
+			indicators.NewOBVIndicatorCompat(ts, indicators.CompatTALib),
+			indicators.NewOBVIndicatorCompat(ts, indicators.CompatTradingView),
+		},
+	}
+	for name, pair := range differ {
+		if pair[0].Calculate(last).EQ(pair[1].Calculate(last)) {
+			t.Errorf("%s: TA-Lib and TradingView profiles agree at %d, want them to differ", name, last)
+		}
+	}
+}
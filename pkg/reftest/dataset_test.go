@@ -0,0 +1,155 @@
+package reftest
+
+import (
+	"time"
+
+	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// ohlcvBar is one daily bar of a reference dataset
+type ohlcvBar struct {
+	Open, High, Low, Close float64
+	Volume                 float64
+}
+
+// dailyOHLCV is an 80-bar daily dataset with an up trend, a reversal and a
+// second up trend, so every indicator crosses its warm-up and SAR flips
+// direction. Bars 17 and 46 repeat the previous close.
+var dailyOHLCV = []ohlcvBar{
+	{100.06, 101.68, 98.70, 100.40, 39870},
+	{100.13, 102.75, 99.60, 102.06, 15740},
+	{102.23, 104.02, 101.64, 103.78, 8120},
+	{104.33, 105.95, 104.14, 105.73, 28900},
+	{106.21, 107.73, 105.57, 107.55, 21660},
+	{107.73, 109.42, 107.19, 108.91, 24870},
+	{108.82, 109.10, 107.17, 108.28, 42720},
+	{107.75, 109.59, 106.71, 109.33, 14130},
+	{109.95, 110.27, 109.45, 110.01, 24200},
+	{110.59, 112.41, 110.01, 111.22, 15390},
+	{111.26, 114.41, 109.95, 113.65, 37700},
+	{112.91, 113.57, 112.40, 113.14, 13610},
+	{113.97, 116.12, 113.45, 115.20, 26630},
+	{115.02, 118.77, 113.63, 117.60, 37010},
+	{118.53, 121.49, 118.34, 120.17, 12010},
+	{119.84, 121.50, 118.70, 120.32, 36000},
+	{120.28, 122.20, 119.18, 122.10, 29290},
+	{122.12, 122.45, 121.83, 122.10, 29530},
+	{123.05, 126.44, 121.88, 125.57, 28630},
+	{125.86, 129.66, 125.10, 128.47, 22720},
+	{129.44, 131.11, 127.96, 129.87, 20100},
+	{129.09, 129.60, 128.81, 129.26, 44970},
+	{129.67, 133.00, 128.36, 131.87, 45820},
+	{130.92, 133.84, 129.94, 132.87, 21850},
+	{132.16, 133.72, 131.63, 132.30, 21530},
+	{132.96, 133.61, 131.63, 133.20, 33450},
+	{133.09, 133.97, 131.30, 132.45, 27310},
+	{131.75, 132.48, 131.50, 131.95, 8410},
+	{132.71, 134.63, 131.41, 133.85, 32740},
+	{133.34, 135.53, 133.09, 134.93, 36000},
+	{134.61, 136.02, 133.27, 135.50, 36920},
+	{135.86, 136.39, 133.30, 133.64, 49320},
+	{133.57, 134.70, 130.21, 131.12, 40360},
+	{131.85, 133.92, 130.56, 133.02, 43610},
+	{133.71, 134.88, 130.49, 131.14, 45830},
+	{131.42, 132.56, 131.06, 132.04, 27600},
+	{133.02, 134.07, 131.57, 132.24, 25730},
+	{131.87, 132.61, 128.96, 129.32, 26370},
+	{129.74, 130.62, 125.77, 126.97, 12290},
+	{127.34, 128.76, 123.91, 124.34, 49910},
+	{123.95, 125.99, 122.74, 124.69, 32810},
+	{124.85, 125.95, 121.38, 122.73, 33800},
+	{122.44, 122.62, 118.82, 120.18, 27060},
+	{119.89, 120.58, 117.55, 118.60, 25180},
+	{118.72, 119.95, 115.18, 116.22, 13200},
+	{116.96, 118.27, 113.53, 114.45, 19550},
+	{115.30, 115.91, 113.19, 114.45, 30470},
+	{114.48, 115.14, 114.06, 114.46, 17200},
+	{115.33, 116.71, 111.62, 113.06, 14000},
+	{112.77, 113.77, 111.68, 113.66, 10420},
+	{114.66, 115.93, 112.62, 113.07, 24730},
+	{113.03, 115.11, 112.06, 114.26, 19530},
+	{114.06, 114.58, 111.26, 112.53, 34530},
+	{111.68, 113.03, 110.26, 110.78, 16630},
+	{110.01, 111.97, 109.17, 111.66, 9120},
+	{112.43, 112.92, 109.25, 110.36, 9280},
+	{110.96, 112.14, 110.83, 111.88, 44860},
+	{112.77, 113.99, 112.20, 112.46, 14610},
+	{112.74, 114.40, 111.67, 112.91, 26970},
+	{113.63, 115.03, 111.61, 112.31, 49460},
+	{111.68, 112.66, 110.99, 112.25, 24470},
+	{111.52, 112.85, 109.41, 110.28, 18150},
+	{111.27, 114.61, 109.94, 113.78, 25120},
+	{114.33, 115.74, 113.04, 114.11, 18010},
+	{114.07, 114.27, 112.70, 113.36, 42090},
+	{113.11, 115.54, 112.95, 115.09, 42850},
+	{115.67, 117.31, 115.02, 116.85, 46970},
+	{117.34, 118.89, 117.17, 118.50, 37090},
+	{119.39, 121.02, 117.94, 119.77, 39780},
+	{119.81, 122.39, 119.02, 121.83, 25510},
+	{122.78, 123.92, 121.65, 123.59, 26220},
+	{122.90, 126.79, 122.49, 125.73, 22650},
+	{126.47, 127.12, 125.56, 126.81, 39500},
+	{125.85, 127.82, 124.47, 126.84, 30560},
+	{126.51, 129.12, 125.29, 128.61, 41150},
+	{128.71, 130.48, 127.39, 130.24, 46670},
+	{130.62, 132.20, 129.98, 131.73, 29630},
+	{132.30, 135.73, 130.82, 135.12, 22970},
+	{135.74, 138.90, 134.80, 137.43, 8130},
+	{137.29, 140.77, 136.33, 139.64, 28470},
+}
+
+// stockChartsRSI is the 14-period RSI worked example published by
+// StockCharts, whose values TA-Lib and TradingView both reproduce. RSI
+// starts at index 14 and is rounded to two decimals.
+var stockChartsRSI = struct {
+	Close []float64
+	RSI   []float64
+}{
+	Close: []float64{
+		44.3389, 44.0902, 44.1497, 43.6124, 44.3278, 44.8264, 45.0955, 45.4245, 45.8433, 46.0826, 45.8931,
+		46.0328, 45.6140, 46.2820, 46.2820, 46.0028, 46.0328, 46.4116, 46.2222, 45.6439, 46.2122, 46.2521,
+		45.7137, 46.4515, 45.7835, 45.3547, 44.0288, 44.1783, 44.2181, 44.5672, 43.4205, 42.6628, 43.1314,
+	},
+	RSI: []float64{
+		70.53, 66.32, 66.55, 69.41, 66.36, 57.97, 62.93, 63.26, 56.06, 62.38,
+		54.71, 50.42, 39.99, 41.46, 41.87, 45.46, 37.30, 33.08, 37.77,
+	},
+}
+
+// newSeries builds a daily series from bars
+func newSeries(bars []ohlcvBar) *series.TimeSeries {
+	ts := series.NewTimeSeries()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, b := range bars {
+		candle := series.NewCandle(series.NewTimePeriod(start.AddDate(0, 0, i), 24*time.Hour))
+		candle.OpenPrice = decimal.New(b.Open)
+		candle.MaxPrice = decimal.New(b.High)
+		candle.MinPrice = decimal.New(b.Low)
+		candle.ClosePrice = decimal.New(b.Close)
+		candle.Volume = decimal.New(b.Volume)
+		ts.AddCandle(candle)
+	}
+	return ts
+}
+
+// closeSeries builds a series whose bars all sit at the given closes
+func closeSeries(closes []float64) *series.TimeSeries {
+	bars := make([]ohlcvBar, len(closes))
+	for i, c := range closes {
+		bars[i] = ohlcvBar{c, c, c, c, 0}
+	}
+	return newSeries(bars)
+}
+
+// columns splits bars into open, high, low, close and volume columns
+func columns(bars []ohlcvBar) (open, high, low, closes, volume []float64) {
+	for _, b := range bars {
+		open = append(open, b.Open)
+		high = append(high, b.High)
+		low = append(low, b.Low)
+		closes = append(closes, b.Close)
+		volume = append(volume, b.Volume)
+	}
+	return
+}
@@ -0,0 +1,185 @@
+package reftest
+
+import (
+	"encoding/csv"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Values exported from TA-Lib and TradingView are read from CSV files in
+// testdata with a header row: open, high, low, close and volume columns, which
+// the indicators are recalculated from, and a column per output named as in
+// talibCases, talibHilbertCases or pineCases. An empty or NaN cell means no
+// value. A time column is ignored, and a bar_index column must count the rows
+// from 0, so an export that does not start at the first bar of its symbol is
+// refused.
+const (
+	talibExport       = "testdata/talib.csv"
+	tradingViewExport = "testdata/tradingview.csv"
+)
+
+// export is a CSV of exported values: its bars and its output columns
+type export struct {
+	bars    []ohlcvBar
+	outputs map[string][]float64
+}
+
+// readExport reads the export at path; a missing file is fs.ErrNotExist
+func readExport(path string) (*export, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New(path + ": no bars")
+	}
+
+	columns := make(map[string][]float64)
+	for i, name := range rows[0] {
+		name = strings.TrimSpace(name)
+		switch strings.ToLower(name) {
+		case "time":
+			continue
+		case "open", "high", "low", "close", "volume", "bar_index":
+			name = strings.ToLower(name)
+		}
+		if _, ok := columns[name]; ok {
+			continue
+		}
+		values := make([]float64, len(rows)-1)
+		for r, row := range rows[1:] {
+			cell := strings.TrimSpace(row[i])
+			if cell == "" || strings.EqualFold(cell, "nan") {
+				values[r] = math.NaN()
+				continue
+			}
+			if values[r], err = strconv.ParseFloat(cell, 64); err != nil {
+				return nil, err
+			}
+		}
+		columns[name] = values
+	}
+
+	e := &export{bars: make([]ohlcvBar, len(rows)-1), outputs: columns}
+	for _, name := range []string{"open", "high", "low", "close", "volume"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.New(path + ": no " + name + " column")
+		}
+	}
+	for i := range e.bars {
+		e.bars[i] = ohlcvBar{columns["open"][i], columns["high"][i], columns["low"][i], columns["close"][i], columns["volume"][i]}
+	}
+	if index, ok := columns["bar_index"]; ok {
+		for i, v := range index {
+			if v != float64(i) {
+				return nil, errors.New(path + ": does not start at the first bar")
+			}
+		}
+	}
+	for _, name := range []string{"open", "high", "low", "close", "volume", "bar_index"} {
+		delete(e.outputs, name)
+	}
+	return e, nil
+}
+
+// checkExport compares the indicators of cases over the bars of the export at
+// path with its exported values, skipping the test when nothing was exported
+func checkExport(t *testing.T, path string, cases func([]ohlcvBar) []compatCase) {
+	t.Helper()
+	e, err := readExport(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s has not been exported, see README.md", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.outputs) == 0 {
+		t.Fatalf("%s: no output columns", path)
+	}
+
+	known := make(map[string]compatCase)
+	for _, tc := range cases(e.bars) {
+		known[tc.name] = tc
+	}
+	for name, want := range e.outputs {
+		tc, ok := known[name]
+		if !ok {
+			t.Errorf("%s: unknown output column %q", path, name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			assertMatches(t, name, want, tc.got)
+		})
+	}
+}
+
+func TestTALibExported(t *testing.T) {
+	checkExport(t, talibExport, func(bars []ohlcvBar) []compatCase {
+		return append(talibCases(bars), talibHilbertCases(bars)...)
+	})
+}
+
+func TestTradingViewExported(t *testing.T) {
+	checkExport(t, tradingViewExport, pineCases)
+}
+
+func TestReadExport(t *testing.T) {
+	path := t.TempDir() + "/export.csv"
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("time,open,high,low,close,Volume,bar_index,OBV\n" +
+		"1,100,101,99,100.5,10,0,\n" +
+		"2,100.5,102,100,101.5,20,1,20\n")
+	e, err := readExport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.bars) != 2 || e.bars[1] != (ohlcvBar{100.5, 102, 100, 101.5, 20}) {
+		t.Errorf("bars = %v", e.bars)
+	}
+	if obv := e.outputs["OBV"]; len(e.outputs) != 1 || !math.IsNaN(obv[0]) || obv[1] != 20 {
+		t.Errorf("outputs = %v", e.outputs)
+	}
+
+	write("open,high,low,close,volume,bar_index\n1,1,1,1,1,5\n")
+	if _, err := readExport(path); err == nil {
+		t.Error("an export not starting at the first bar was read")
+	}
+	write("open,high,low,close\n1,1,1,1\n")
+	if _, err := readExport(path); err == nil {
+		t.Error("an export without volume was read")
+	}
+	if _, err := readExport(path + ".missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing export: %v", err)
+	}
+}
+
+// TestDailyOHLCVCSV keeps testdata/daily_ohlcv.csv, the input of the TA-Lib
+// export script, equal to dailyOHLCV
+func TestDailyOHLCVCSV(t *testing.T) {
+	e, err := readExport("testdata/daily_ohlcv.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.bars) != len(dailyOHLCV) {
+		t.Fatalf("%d bars, want %d", len(e.bars), len(dailyOHLCV))
+	}
+	for i, b := range dailyOHLCV {
+		if e.bars[i] != b {
+			t.Errorf("bar %d = %v, want %v", i, e.bars[i], b)
+		}
+	}
+}
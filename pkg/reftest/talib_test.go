@@ -0,0 +1,537 @@
+package reftest
+
+import "math"
+
+// Float64 ports of the TA-Lib C functions with default settings: no unstable
+// period and the default (non-Metastock) compatibility. Each returns a slice
+// as long as its input, NaN where TA-Lib produces no output. They are written
+// from the C source, not exported from TA-Lib, so they check goflux against
+// that reading of it rather than against TA-Lib itself.
+
+func nanSlice(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// talibEMA is TA_INT_EMA starting at startIdx: seeded with the SMA of the
+// period values ending at startIdx.
+func talibEMA(in []float64, period, startIdx int) []float64 {
+	out := nanSlice(len(in))
+	k := 2 / float64(period+1)
+	today := startIdx - (period - 1)
+	sum := 0.0
+	for i := 0; i < period; i++ {
+		sum += in[today]
+		today++
+	}
+	prev := sum / float64(period)
+	out[startIdx] = prev
+	for ; today < len(in); today++ {
+		prev = (in[today]-prev)*k + prev
+		out[today] = prev
+	}
+	return out
+}
+
+func talibRSI(in []float64, period int) []float64 {
+	out := nanSlice(len(in))
+	prevGain, prevLoss := 0.0, 0.0
+	prevValue := in[0]
+	today := 1
+	for i := period; i > 0; i-- {
+		diff := in[today] - prevValue
+		prevValue = in[today]
+		today++
+		if diff < 0 {
+			prevLoss -= diff
+		} else {
+			prevGain += diff
+		}
+	}
+	prevGain /= float64(period)
+	prevLoss /= float64(period)
+	rsi := func() float64 {
+		if sum := prevGain + prevLoss; sum != 0 {
+			return 100 * prevGain / sum
+		}
+		return 0
+	}
+	out[today-1] = rsi()
+	for ; today < len(in); today++ {
+		diff := in[today] - prevValue
+		prevValue = in[today]
+		prevGain *= float64(period - 1)
+		prevLoss *= float64(period - 1)
+		if diff < 0 {
+			prevLoss -= diff
+		} else {
+			prevGain += diff
+		}
+		prevGain /= float64(period)
+		prevLoss /= float64(period)
+		out[today] = rsi()
+	}
+	return out
+}
+
+func talibMACD(in []float64, fast, slow, signal int) (macd, sig, hist []float64) {
+	lookbackSignal := signal - 1
+	startIdx := slow - 1 + lookbackSignal
+	slowEMA := talibEMA(in, slow, startIdx-lookbackSignal)
+	fastEMA := talibEMA(in, fast, startIdx-lookbackSignal)
+
+	line := nanSlice(len(in))
+	for i := startIdx - lookbackSignal; i < len(in); i++ {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+	signalLine := talibEMA(line[startIdx-lookbackSignal:], signal, lookbackSignal)
+
+	macd, sig, hist = nanSlice(len(in)), nanSlice(len(in)), nanSlice(len(in))
+	for i := startIdx; i < len(in); i++ {
+		macd[i] = line[i]
+		sig[i] = signalLine[i-(startIdx-lookbackSignal)]
+		hist[i] = macd[i] - sig[i]
+	}
+	return
+}
+
+func talibBBands(in []float64, period int, nbDev float64) (upper, middle, lower []float64) {
+	upper, middle, lower = nanSlice(len(in)), nanSlice(len(in)), nanSlice(len(in))
+	for i := period - 1; i < len(in); i++ {
+		sum, sumSq := 0.0, 0.0
+		for _, v := range in[i-period+1 : i+1] {
+			sum += v
+			sumSq += v * v
+		}
+		mean := sum / float64(period)
+		dev := math.Sqrt(sumSq/float64(period) - mean*mean)
+		middle[i] = mean
+		upper[i] = mean + nbDev*dev
+		lower[i] = mean - nbDev*dev
+	}
+	return
+}
+
+func talibSMA(in []float64, period, begin int) []float64 {
+	out := nanSlice(len(in))
+	for i := begin + period - 1; i < len(in); i++ {
+		sum := 0.0
+		for _, v := range in[i-period+1 : i+1] {
+			sum += v
+		}
+		out[i] = sum / float64(period)
+	}
+	return out
+}
+
+func talibStoch(high, low, closes []float64, fastK, slowK, slowD int) (k, d []float64) {
+	raw := nanSlice(len(closes))
+	for today := fastK - 1; today < len(closes); today++ {
+		lowest, highest := low[today], high[today]
+		for i := today - fastK + 1; i <= today; i++ {
+			lowest = math.Min(lowest, low[i])
+			highest = math.Max(highest, high[i])
+		}
+		diff := (highest - lowest) / 100
+		if diff != 0 {
+			raw[today] = (closes[today] - lowest) / diff
+		} else {
+			raw[today] = 0
+		}
+	}
+	smoothK := talibSMA(raw, slowK, fastK-1)
+	slowDLine := talibSMA(smoothK, slowD, fastK+slowK-2)
+
+	k, d = nanSlice(len(closes)), nanSlice(len(closes))
+	for i := fastK + slowK + slowD - 3; i < len(closes); i++ {
+		k[i], d[i] = smoothK[i], slowDLine[i]
+	}
+	return
+}
+
+func trueRange(high, low, closes []float64, today int) float64 {
+	prevClose := closes[today-1]
+	tr := high[today] - low[today]
+	tr = math.Max(tr, math.Abs(high[today]-prevClose))
+	return math.Max(tr, math.Abs(low[today]-prevClose))
+}
+
+func talibATR(high, low, closes []float64, period int) []float64 {
+	out := nanSlice(len(closes))
+	tr := nanSlice(len(closes))
+	for today := 1; today < len(closes); today++ {
+		tr[today] = trueRange(high, low, closes, today)
+	}
+	prev := talibSMA(tr, period, 1)[period]
+	out[period] = prev
+	for today := period + 1; today < len(closes); today++ {
+		prev = (prev*float64(period-1) + tr[today]) / float64(period)
+		out[today] = prev
+	}
+	return out
+}
+
+func talibADX(high, low, closes []float64, period int) []float64 {
+	out := nanSlice(len(closes))
+	n := float64(period)
+	today := 0
+	prevMinusDM, prevPlusDM, prevTR := 0.0, 0.0, 0.0
+	prevHigh, prevLow := high[today], low[today]
+
+	step := func() (diffP, diffM, tr float64) {
+		today++
+		diffP, prevHigh = high[today]-prevHigh, high[today]
+		diffM, prevLow = prevLow-low[today], low[today]
+		tr = trueRange(high, low, closes, today)
+		return
+	}
+	addDM := func(diffP, diffM float64) {
+		if diffM > 0 && diffP < diffM {
+			prevMinusDM += diffM
+		} else if diffP > 0 && diffP > diffM {
+			prevPlusDM += diffP
+		}
+	}
+	smooth := func() (dx float64, ok bool) {
+		diffP, diffM, tr := step()
+		prevMinusDM -= prevMinusDM / n
+		prevPlusDM -= prevPlusDM / n
+		addDM(diffP, diffM)
+		prevTR = prevTR - prevTR/n + tr
+		if prevTR == 0 {
+			return 0, false
+		}
+		minusDI := 100 * (prevMinusDM / prevTR)
+		plusDI := 100 * (prevPlusDM / prevTR)
+		if sum := minusDI + plusDI; sum != 0 {
+			return 100 * (math.Abs(minusDI-plusDI) / sum), true
+		}
+		return 0, false
+	}
+
+	for i := period - 1; i > 0; i-- {
+		diffP, diffM, tr := step()
+		addDM(diffP, diffM)
+		prevTR += tr
+	}
+	sumDX := 0.0
+	for i := period; i > 0; i-- {
+		if dx, ok := smooth(); ok {
+			sumDX += dx
+		}
+	}
+	prevADX := sumDX / n
+	out[today] = prevADX
+	for today < len(closes)-1 {
+		if dx, ok := smooth(); ok {
+			prevADX = (prevADX*(n-1) + dx) / n
+		}
+		out[today] = prevADX
+	}
+	return out
+}
+
+func talibCCI(high, low, closes []float64, period int) []float64 {
+	out := nanSlice(len(closes))
+	tp := make([]float64, len(closes))
+	for i := range closes {
+		tp[i] = (high[i] + low[i] + closes[i]) / 3
+	}
+	for i := period - 1; i < len(closes); i++ {
+		window := tp[i-period+1 : i+1]
+		avg := 0.0
+		for _, v := range window {
+			avg += v
+		}
+		avg /= float64(period)
+		dev := 0.0
+		for _, v := range window {
+			dev += math.Abs(v - avg)
+		}
+		if last := tp[i] - avg; last != 0 && dev != 0 {
+			out[i] = last / (0.015 * (dev / float64(period)))
+		} else {
+			out[i] = 0
+		}
+	}
+	return out
+}
+
+func talibOBV(closes, volume []float64) []float64 {
+	out := make([]float64, len(closes))
+	prevOBV, prevReal := volume[0], closes[0]
+	for i := range closes {
+		if closes[i] > prevReal {
+			prevOBV += volume[i]
+		} else if closes[i] < prevReal {
+			prevOBV -= volume[i]
+		}
+		out[i] = prevOBV
+		prevReal = closes[i]
+	}
+	return out
+}
+
+func talibSAR(high, low []float64, acceleration, maximum float64) []float64 {
+	out := nanSlice(len(high))
+	af := acceleration
+	todayIdx := 1
+
+	isLong := true
+	if diffM, diffP := low[0]-low[1], high[1]-high[0]; diffM > 0 && diffP < diffM {
+		isLong = false
+	}
+
+	var ep, sar float64
+	if isLong {
+		ep, sar = high[todayIdx], low[todayIdx-1]
+	} else {
+		ep, sar = low[todayIdx], high[todayIdx-1]
+	}
+	newLow, newHigh := low[todayIdx], high[todayIdx]
+
+	for todayIdx < len(high) {
+		prevLow, prevHigh := newLow, newHigh
+		newLow, newHigh = low[todayIdx], high[todayIdx]
+		outIdx := todayIdx
+		todayIdx++
+
+		if isLong {
+			if newLow <= sar {
+				isLong = false
+				sar = math.Max(math.Max(ep, prevHigh), newHigh)
+				out[outIdx] = sar
+				af = acceleration
+				ep = newLow
+				sar = sar + af*(ep-sar)
+				sar = math.Max(math.Max(sar, prevHigh), newHigh)
+			} else {
+				out[outIdx] = sar
+				if newHigh > ep {
+					ep = newHigh
+					af = math.Min(af+acceleration, maximum)
+				}
+				sar = sar + af*(ep-sar)
+				sar = math.Min(math.Min(sar, prevLow), newLow)
+			}
+		} else {
+			if newHigh >= sar {
+				isLong = true
+				sar = math.Min(math.Min(ep, prevLow), newLow)
+				out[outIdx] = sar
+				af = acceleration
+				ep = newHigh
+				sar = sar + af*(ep-sar)
+				sar = math.Min(math.Min(sar, prevLow), newLow)
+			} else {
+				out[outIdx] = sar
+				if newLow < ep {
+					ep = newLow
+					af = math.Min(af+acceleration, maximum)
+				}
+				sar = sar + af*(ep-sar)
+				sar = math.Max(math.Max(sar, prevHigh), newHigh)
+			}
+		}
+	}
+	return out
+}
+
+// talibHT holds the outputs of the port of the TA-Lib Hilbert transform
+// functions
+type talibHT struct {
+	dcPeriod, dcPhase, inPhase, quadrature, sine, leadSine, trendline, trendMode []float64
+}
+
+// hilbertBuffers is TA-Lib's HILBERT_VARIABLES: the transform of a series
+// kept separately for odd and even bars in 3-slot circular buffers
+type hilbertBuffers struct {
+	odd, even                 [3]float64
+	prevOdd, prevEven         float64
+	prevInputOdd, prevInputEv float64
+}
+
+func (h *hilbertBuffers) do(input float64, idx int, evenBar bool, adjusted float64) float64 {
+	const a, b = 0.0962, 0.5769
+	buf, prev, prevInput := &h.odd, &h.prevOdd, &h.prevInputOdd
+	if evenBar {
+		buf, prev, prevInput = &h.even, &h.prevEven, &h.prevInputEv
+	}
+	temp := a * input
+	v := -buf[idx]
+	buf[idx] = temp
+	v += temp
+	v -= *prev
+	*prev = b * *prevInput
+	v += *prev
+	*prevInput = input
+	return v * adjusted
+}
+
+// talibHilbert ports the shared body of TA_HT_DCPERIOD, TA_HT_DCPHASE,
+// TA_HT_PHASOR, TA_HT_SINE, TA_HT_TRENDLINE and TA_HT_TRENDMODE computed over
+// the whole input. Period and phasor start at 32, the rest at 63.
+func talibHilbert(in []float64) talibHT {
+	n := len(in)
+	out := talibHT{nanSlice(n), nanSlice(n), nanSlice(n), nanSlice(n), nanSlice(n), nanSlice(n), nanSlice(n), nanSlice(n)}
+	rad2Deg := 180 / math.Pi
+
+	trailingWMAIdx, today := 0, 0
+	periodWMASub, periodWMASum := 0.0, 0.0
+	for w := 1.0; w <= 3; w++ {
+		periodWMASub += in[today]
+		periodWMASum += in[today] * w
+		today++
+	}
+	trailingWMAValue := 0.0
+	priceWMA := func(price float64) float64 {
+		periodWMASub += price
+		periodWMASub -= trailingWMAValue
+		periodWMASum += price * 4
+		trailingWMAValue = in[trailingWMAIdx]
+		trailingWMAIdx++
+		smoothed := periodWMASum * 0.1
+		periodWMASum -= periodWMASub
+		return smoothed
+	}
+	for i := 0; i < 9; i++ {
+		priceWMA(in[today])
+		today++
+	}
+
+	var detrender, q1, jI, jQ hilbertBuffers
+	hilbertIdx := 0
+	period, smoothPeriod := 0.0, 0.0
+	prevI2, prevQ2, re, im := 0.0, 0.0, 0.0, 0.0
+	i1OddPrev2, i1OddPrev3, i1EvenPrev2, i1EvenPrev3 := 0.0, 0.0, 0.0, 0.0
+	var smoothPrice [50]float64
+	smoothPriceIdx := 0
+	dcPhase, prevDCPhase := 0.0, 0.0
+	sine, leadSine, prevSine, prevLeadSine := 0.0, 0.0, 0.0, 0.0
+	iTrend1, iTrend2, iTrend3 := 0.0, 0.0, 0.0
+	daysInTrend := 0
+
+	for ; today < n; today++ {
+		adjusted := 0.075*period + 0.54
+		smoothed := priceWMA(in[today])
+		var det, q, i1, q2, i2 float64
+		if today%2 == 0 {
+			det = detrender.do(smoothed, hilbertIdx, true, adjusted)
+			q = q1.do(det, hilbertIdx, true, adjusted)
+			ji := jI.do(i1EvenPrev3, hilbertIdx, true, adjusted)
+			jq := jQ.do(q, hilbertIdx, true, adjusted)
+			if hilbertIdx++; hilbertIdx == 3 {
+				hilbertIdx = 0
+			}
+			i1 = i1EvenPrev3
+			q2 = 0.2*(q+ji) + 0.8*prevQ2
+			i2 = 0.2*(i1EvenPrev3-jq) + 0.8*prevI2
+			i1OddPrev3, i1OddPrev2 = i1OddPrev2, det
+		} else {
+			det = detrender.do(smoothed, hilbertIdx, false, adjusted)
+			q = q1.do(det, hilbertIdx, false, adjusted)
+			ji := jI.do(i1OddPrev3, hilbertIdx, false, adjusted)
+			jq := jQ.do(q, hilbertIdx, false, adjusted)
+			i1 = i1OddPrev3
+			q2 = 0.2*(q+ji) + 0.8*prevQ2
+			i2 = 0.2*(i1OddPrev3-jq) + 0.8*prevI2
+			i1EvenPrev3, i1EvenPrev2 = i1EvenPrev2, det
+		}
+		re = 0.2*(i2*prevI2+q2*prevQ2) + 0.8*re
+		im = 0.2*(i2*prevQ2-q2*prevI2) + 0.8*im
+		prevQ2, prevI2 = q2, i2
+		prevPeriod := period
+		if im != 0 && re != 0 {
+			period = 360 / (math.Atan(im/re) * rad2Deg)
+		}
+		if period > 1.5*prevPeriod {
+			period = 1.5 * prevPeriod
+		}
+		if period < 0.67*prevPeriod {
+			period = 0.67 * prevPeriod
+		}
+		if period < 6 {
+			period = 6
+		} else if period > 50 {
+			period = 50
+		}
+		period = 0.2*period + 0.8*prevPeriod
+		smoothPeriod = 0.33*period + 0.67*smoothPeriod
+
+		prevDCPhase = dcPhase
+		smoothPrice[smoothPriceIdx] = smoothed
+		dcPeriodInt := int(smoothPeriod + 0.5)
+		realPart, imagPart := 0.0, 0.0
+		idx := smoothPriceIdx
+		for i := 0; i < dcPeriodInt; i++ {
+			angle := float64(i) * 2 * math.Pi / float64(dcPeriodInt)
+			realPart += math.Sin(angle) * smoothPrice[idx]
+			imagPart += math.Cos(angle) * smoothPrice[idx]
+			if idx == 0 {
+				idx = len(smoothPrice) - 1
+			} else {
+				idx--
+			}
+		}
+		if math.Abs(imagPart) > 0 {
+			dcPhase = math.Atan(realPart/imagPart) * rad2Deg
+		} else if math.Abs(imagPart) <= 0.01 {
+			if realPart < 0 {
+				dcPhase -= 90
+			} else if realPart > 0 {
+				dcPhase += 90
+			}
+		}
+		dcPhase += 90
+		dcPhase += 360 / smoothPeriod
+		if imagPart < 0 {
+			dcPhase += 180
+		}
+		if dcPhase > 315 {
+			dcPhase -= 360
+		}
+		prevSine, prevLeadSine = sine, leadSine
+		sine = math.Sin(dcPhase / rad2Deg)
+		leadSine = math.Sin((dcPhase + 45) / rad2Deg)
+
+		mean := 0.0
+		for i, idx := 0, today; i < dcPeriodInt; i, idx = i+1, idx-1 {
+			mean += in[idx]
+		}
+		if dcPeriodInt > 0 {
+			mean /= float64(dcPeriodInt)
+		}
+		trendline := (4*mean + 3*iTrend1 + 2*iTrend2 + iTrend3) / 10
+		iTrend3, iTrend2, iTrend1 = iTrend2, iTrend1, mean
+
+		trend := 1.0
+		if sine > leadSine && prevSine <= prevLeadSine || sine < leadSine && prevSine >= prevLeadSine {
+			daysInTrend, trend = 0, 0
+		}
+		daysInTrend++
+		if float64(daysInTrend) < 0.5*smoothPeriod {
+			trend = 0
+		}
+		if delta := dcPhase - prevDCPhase; smoothPeriod != 0 && delta > 0.67*360/smoothPeriod && delta < 1.5*360/smoothPeriod {
+			trend = 0
+		}
+		if trendline != 0 && math.Abs((smoothPrice[smoothPriceIdx]-trendline)/trendline) >= 0.015 {
+			trend = 1
+		}
+		if smoothPriceIdx++; smoothPriceIdx == len(smoothPrice) {
+			smoothPriceIdx = 0
+		}
+
+		if today >= 32 {
+			out.dcPeriod[today], out.inPhase[today], out.quadrature[today] = smoothPeriod, i1, q
+		}
+		if today >= 63 {
+			out.dcPhase[today], out.sine[today], out.leadSine[today] = dcPhase, sine, leadSine
+			out.trendline[today], out.trendMode[today] = trendline, trend
+		}
+	}
+	return out
+}
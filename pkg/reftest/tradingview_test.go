@@ -0,0 +1,268 @@
+package reftest
+
+import "math"
+
+// Float64 ports of the Pine Script ta.* built-ins, evaluated bar by bar with
+// na represented as NaN. Each returns a slice as long as its input. They are
+// written from the Pine Script reference manual, not exported from
+// TradingView.
+
+// pineSMA is ta.sma: na while the window holds any na
+func pineSMA(src []float64, length int) []float64 {
+	out := nanSlice(len(src))
+	for i := length - 1; i < len(src); i++ {
+		sum := 0.0
+		for _, v := range src[i-length+1 : i+1] {
+			sum += v
+		}
+		out[i] = sum / float64(length)
+	}
+	return out
+}
+
+// pineMA is the shared body of ta.ema and ta.rma: seeded with ta.sma the
+// first time the previous value is na, then moving alpha towards src.
+func pineMA(src []float64, length int, alpha float64) []float64 {
+	out := nanSlice(len(src))
+	sma := pineSMA(src, length)
+	for i := range src {
+		if i == 0 || math.IsNaN(out[i-1]) {
+			out[i] = sma[i]
+			continue
+		}
+		out[i] = alpha*src[i] + (1-alpha)*out[i-1]
+	}
+	return out
+}
+
+func pineEMA(src []float64, length int) []float64 {
+	return pineMA(src, length, 2/float64(length+1))
+}
+
+func pineRMA(src []float64, length int) []float64 {
+	return pineMA(src, length, 1/float64(length))
+}
+
+// pineChange is ta.change: na on the first bar
+func pineChange(src []float64) []float64 {
+	out := nanSlice(len(src))
+	for i := 1; i < len(src); i++ {
+		out[i] = src[i] - src[i-1]
+	}
+	return out
+}
+
+func pineRSI(src []float64, length int) []float64 {
+	change := pineChange(src)
+	up, down := nanSlice(len(src)), nanSlice(len(src))
+	for i, c := range change {
+		if !math.IsNaN(c) {
+			up[i], down[i] = math.Max(c, 0), -math.Min(c, 0)
+		}
+	}
+	up, down = pineRMA(up, length), pineRMA(down, length)
+	out := nanSlice(len(src))
+	for i := range src {
+		switch {
+		case math.IsNaN(up[i]) || math.IsNaN(down[i]):
+		case down[i] == 0:
+			out[i] = 100
+		case up[i] == 0:
+			out[i] = 0
+		default:
+			out[i] = 100 - 100/(1+up[i]/down[i])
+		}
+	}
+	return out
+}
+
+func pineMACD(src []float64, fast, slow, signal int) (macd, sig, hist []float64) {
+	fastMA, slowMA := pineEMA(src, fast), pineEMA(src, slow)
+	macd = make([]float64, len(src))
+	for i := range src {
+		macd[i] = fastMA[i] - slowMA[i]
+	}
+	sig = pineEMA(macd, signal)
+	hist = make([]float64, len(src))
+	for i := range src {
+		hist[i] = macd[i] - sig[i]
+	}
+	return
+}
+
+// pineStdev is ta.stdev with biased = true
+func pineStdev(src []float64, length int) []float64 {
+	out := nanSlice(len(src))
+	mean := pineSMA(src, length)
+	for i := length - 1; i < len(src); i++ {
+		sum := 0.0
+		for _, v := range src[i-length+1 : i+1] {
+			sum += (v - mean[i]) * (v - mean[i])
+		}
+		out[i] = math.Sqrt(sum / float64(length))
+	}
+	return out
+}
+
+func pineStoch(high, low, closes []float64, periodK, smoothK, periodD int) (k, d []float64) {
+	raw := nanSlice(len(closes))
+	for i := periodK - 1; i < len(closes); i++ {
+		lowest, highest := math.Inf(1), math.Inf(-1)
+		for j := i - periodK + 1; j <= i; j++ {
+			lowest, highest = math.Min(lowest, low[j]), math.Max(highest, high[j])
+		}
+		if highest != lowest {
+			raw[i] = 100 * (closes[i] - lowest) / (highest - lowest)
+		}
+	}
+	k = pineSMA(raw, smoothK)
+	return k, pineSMA(k, periodD)
+}
+
+// pineTR is ta.tr(handleNA): the first bar is high-low when handleNA is set
+// and na otherwise
+func pineTR(high, low, closes []float64, handleNA bool) []float64 {
+	out := nanSlice(len(closes))
+	if handleNA {
+		out[0] = high[0] - low[0]
+	}
+	for i := 1; i < len(closes); i++ {
+		out[i] = trueRange(high, low, closes, i)
+	}
+	return out
+}
+
+func pineATR(high, low, closes []float64, length int) []float64 {
+	return pineRMA(pineTR(high, low, closes, true), length)
+}
+
+// pineADX is the adx output of ta.dmi(length, length)
+func pineADX(high, low, closes []float64, length int) []float64 {
+	up, down := pineChange(high), pineChange(low)
+	plusDM, minusDM := nanSlice(len(closes)), nanSlice(len(closes))
+	for i := 1; i < len(closes); i++ {
+		down[i] = -down[i]
+		plusDM[i], minusDM[i] = 0, 0
+		if up[i] > down[i] && up[i] > 0 {
+			plusDM[i] = up[i]
+		}
+		if down[i] > up[i] && down[i] > 0 {
+			minusDM[i] = down[i]
+		}
+	}
+	trur := pineRMA(pineTR(high, low, closes, false), length)
+	plusRMA, minusRMA := pineRMA(plusDM, length), pineRMA(minusDM, length)
+
+	dx := nanSlice(len(closes))
+	for i := range closes {
+		plus, minus := 100*plusRMA[i]/trur[i], 100*minusRMA[i]/trur[i]
+		sum := plus + minus
+		if sum == 0 {
+			sum = 1
+		}
+		dx[i] = math.Abs(plus-minus) / sum
+	}
+	adx := pineRMA(dx, length)
+	for i := range adx {
+		adx[i] *= 100
+	}
+	return adx
+}
+
+func pineCCI(high, low, closes []float64, length int) []float64 {
+	out := nanSlice(len(closes))
+	tp := make([]float64, len(closes))
+	for i := range closes {
+		tp[i] = (high[i] + low[i] + closes[i]) / 3
+	}
+	mean := pineSMA(tp, length)
+	for i := length - 1; i < len(closes); i++ {
+		dev := 0.0
+		for _, v := range tp[i-length+1 : i+1] {
+			dev += math.Abs(v - mean[i])
+		}
+		dev /= float64(length)
+		if dev != 0 {
+			out[i] = (tp[i] - mean[i]) / (0.015 * dev)
+		}
+	}
+	return out
+}
+
+// pineOBV is ta.obv, ta.cum(math.sign(ta.change(close)) * volume)
+func pineOBV(closes, volume []float64) []float64 {
+	out := make([]float64, len(closes))
+	for i := 1; i < len(closes); i++ {
+		sign := 0.0
+		if closes[i] > closes[i-1] {
+			sign = 1
+		} else if closes[i] < closes[i-1] {
+			sign = -1
+		}
+		out[i] = out[i-1] + sign*volume[i]
+	}
+	return out
+}
+
+// pineSAR is the Pine Script reference implementation of ta.sar
+func pineSAR(high, low, closes []float64, start, inc, maximum float64) []float64 {
+	out := nanSlice(len(closes))
+	result, maxMin, acceleration := math.NaN(), math.NaN(), math.NaN()
+	isBelow := false
+	for bar := 1; bar < len(closes); bar++ {
+		isFirstTrendBar := false
+		if bar == 1 {
+			if closes[1] > closes[0] {
+				isBelow, maxMin, result = true, high[1], low[0]
+			} else {
+				isBelow, maxMin, result = false, low[1], high[0]
+			}
+			isFirstTrendBar = true
+			acceleration = start
+		}
+
+		result += acceleration * (maxMin - result)
+
+		if isBelow {
+			if result > low[bar] {
+				isFirstTrendBar, isBelow = true, false
+				result = math.Max(high[bar], maxMin)
+				maxMin, acceleration = low[bar], start
+			}
+		} else {
+			if result < high[bar] {
+				isFirstTrendBar, isBelow = true, true
+				result = math.Min(low[bar], maxMin)
+				maxMin, acceleration = high[bar], start
+			}
+		}
+
+		if !isFirstTrendBar {
+			if isBelow {
+				if high[bar] > maxMin {
+					maxMin = high[bar]
+					acceleration = math.Min(acceleration+inc, maximum)
+				}
+			} else {
+				if low[bar] < maxMin {
+					maxMin = low[bar]
+					acceleration = math.Min(acceleration+inc, maximum)
+				}
+			}
+		}
+
+		if isBelow {
+			result = math.Min(result, low[bar-1])
+			if bar > 1 {
+				result = math.Min(result, low[bar-2])
+			}
+		} else {
+			result = math.Max(result, high[bar-1])
+			if bar > 1 {
+				result = math.Max(result, high[bar-2])
+			}
+		}
+		out[bar] = result
+	}
+	return out
+}
@@ -2,11 +2,31 @@ package series
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/irfndi/goflux/pkg/decimal"
 )
 
+// Side is the aggressor of a trade, the side that crossed the spread: a buyer
+// lifting the offer or a seller hitting the bid
+type Side int
+
+// Aggressor sides. Trades of UnknownSide count towards Volume only.
+const (
+	UnknownSide Side = iota
+	BuySide
+	SellSide
+)
+
+// FootprintLevel is the volume traded at one price of a candle by buying and
+// selling aggressors
+type FootprintLevel struct {
+	Price      decimal.Decimal
+	BuyVolume  decimal.Decimal
+	SellVolume decimal.Decimal
+}
+
 // Candle represents basic market information for a security over a given time period
 type Candle struct {
 	Period     TimePeriod
@@ -16,6 +36,12 @@ type Candle struct {
 	MinPrice   decimal.Decimal
 	Volume     decimal.Decimal
 	TradeCount uint
+	// BuyVolume and SellVolume split Volume by the aggressor of each trade
+	// added with AddSideTrade; volume of unknown side is in neither
+	BuyVolume  decimal.Decimal
+	SellVolume decimal.Decimal
+	// Footprint is the volume at each price by side, in ascending price order
+	Footprint []FootprintLevel
 }
 
 // NewCandle returns a new *Candle for a given time period
@@ -27,6 +53,8 @@ func NewCandle(period TimePeriod) (c *Candle) {
 		MaxPrice:   decimal.ZERO,
 		MinPrice:   decimal.ZERO,
 		Volume:     decimal.ZERO,
+		BuyVolume:  decimal.ZERO,
+		SellVolume: decimal.ZERO,
 	}
 }
 
@@ -59,6 +87,38 @@ func (c *Candle) AddTrade(tradeAmount, tradePrice decimal.Decimal) {
 	c.TradeCount++
 }
 
+// AddSideTrade adds a trade like AddTrade, also adding its amount to the
+// volume of its aggressor side and to the footprint at its price
+func (c *Candle) AddSideTrade(side Side, tradeAmount, tradePrice decimal.Decimal) {
+	c.AddTrade(tradeAmount, tradePrice)
+	switch side {
+	case BuySide:
+		c.BuyVolume = c.BuyVolume.Add(tradeAmount)
+		c.addFootprint(FootprintLevel{Price: tradePrice, BuyVolume: tradeAmount, SellVolume: decimal.ZERO})
+	case SellSide:
+		c.SellVolume = c.SellVolume.Add(tradeAmount)
+		c.addFootprint(FootprintLevel{Price: tradePrice, BuyVolume: decimal.ZERO, SellVolume: tradeAmount})
+	}
+}
+
+// Delta returns the buying less the selling aggressor volume
+func (c *Candle) Delta() decimal.Decimal {
+	return c.BuyVolume.Sub(c.SellVolume)
+}
+
+// addFootprint adds the volumes of level to the footprint level at its price
+func (c *Candle) addFootprint(level FootprintLevel) {
+	k := sort.Search(len(c.Footprint), func(k int) bool { return c.Footprint[k].Price.GTE(level.Price) })
+	if k < len(c.Footprint) && c.Footprint[k].Price.EQ(level.Price) {
+		c.Footprint[k].BuyVolume = c.Footprint[k].BuyVolume.Add(level.BuyVolume)
+		c.Footprint[k].SellVolume = c.Footprint[k].SellVolume.Add(level.SellVolume)
+		return
+	}
+	c.Footprint = append(c.Footprint, FootprintLevel{})
+	copy(c.Footprint[k+1:], c.Footprint[k:])
+	c.Footprint[k] = level
+}
+
 func (c *Candle) String() string {
 	return strings.TrimSpace(fmt.Sprintf(
 		`
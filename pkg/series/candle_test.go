@@ -68,3 +68,25 @@ func TestCandle_AddTradeWithZeroPrice(t *testing.T) {
 	assert.True(t, candle.MaxPrice.EQ(decimal.New(10)))
 	assert.True(t, candle.ClosePrice.EQ(decimal.New(10)))
 }
+
+func TestCandle_AddSideTrade(t *testing.T) {
+	candle := series.NewCandle(series.TimePeriod{})
+	candle.AddSideTrade(series.BuySide, decimal.New(2), decimal.New(10))
+	candle.AddSideTrade(series.SellSide, decimal.New(3), decimal.New(9))
+	candle.AddSideTrade(series.BuySide, decimal.New(1), decimal.New(11))
+	candle.AddSideTrade(series.SellSide, decimal.New(4), decimal.New(10))
+	candle.AddSideTrade(series.UnknownSide, decimal.New(5), decimal.New(10))
+
+	assert.EqualValues(t, 15, candle.Volume.Float())
+	assert.EqualValues(t, 5, candle.TradeCount)
+	assert.EqualValues(t, 3, candle.BuyVolume.Float())
+	assert.EqualValues(t, 7, candle.SellVolume.Float())
+	assert.EqualValues(t, -4, candle.Delta().Float())
+
+	if assert.Len(t, candle.Footprint, 3) {
+		for i, want := range [][3]float64{{9, 0, 3}, {10, 2, 4}, {11, 1, 0}} {
+			level := candle.Footprint[i]
+			assert.Equal(t, want, [3]float64{level.Price.Float(), level.BuyVolume.Float(), level.SellVolume.Float()})
+		}
+	}
+}
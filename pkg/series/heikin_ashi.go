@@ -42,6 +42,11 @@ func NewHeikinAshiseries(s *TimeSeries) *TimeSeries {
 
 		haCandle.Volume = candle.Volume
 		haCandle.TradeCount = candle.TradeCount
+		haCandle.BuyVolume = candle.BuyVolume
+		haCandle.SellVolume = candle.SellVolume
+		// The traded prices lie within the HA high and low, which extend the
+		// candle's own
+		haCandle.Footprint = append([]FootprintLevel(nil), candle.Footprint...)
 
 		haSeries.AddCandle(haCandle)
 
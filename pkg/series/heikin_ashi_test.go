@@ -83,4 +83,20 @@ func TestNewHeikinAshiseries(t *testing.T) {
 		expectedOpen2 := decimal.New(105)
 		assert.Equal(t, expectedOpen2.String(), haSeries.Candles[1].OpenPrice.String())
 	})
+	t.Run("order flow is kept", func(t *testing.T) {
+		ts := series.NewTimeSeries()
+		candle := series.NewCandle(series.TimePeriod{Start: time.Now(), End: time.Now().Add(time.Minute)})
+		candle.AddSideTrade(series.BuySide, decimal.New(2), decimal.New(100))
+		candle.AddSideTrade(series.SellSide, decimal.New(3), decimal.New(101))
+		ts.AddCandle(candle)
+
+		ha := series.NewHeikinAshiseries(ts).Candles[0]
+
+		assert.Equal(t, candle.BuyVolume.String(), ha.BuyVolume.String())
+		assert.Equal(t, candle.SellVolume.String(), ha.SellVolume.String())
+		assert.Equal(t, candle.Footprint, ha.Footprint)
+
+		ha.Footprint[0].BuyVolume = decimal.ZERO
+		assert.Equal(t, "2", candle.Footprint[0].BuyVolume.String(), "the footprint is copied")
+	})
 }
@@ -35,6 +35,11 @@ func Resample(s *TimeSeries, newDuration time.Duration) *TimeSeries {
 			currentHA.ClosePrice = candle.ClosePrice
 			currentHA.Volume = candle.Volume
 			currentHA.TradeCount = candle.TradeCount
+			currentHA.BuyVolume = candle.BuyVolume
+			currentHA.SellVolume = candle.SellVolume
+			for _, level := range candle.Footprint {
+				currentHA.addFootprint(level)
+			}
 		} else {
 			// Update current
 			if candle.MaxPrice.GT(currentHA.MaxPrice) {
@@ -46,6 +51,11 @@ func Resample(s *TimeSeries, newDuration time.Duration) *TimeSeries {
 			currentHA.ClosePrice = candle.ClosePrice
 			currentHA.Volume = currentHA.Volume.Add(candle.Volume)
 			currentHA.TradeCount += candle.TradeCount
+			currentHA.BuyVolume = currentHA.BuyVolume.Add(candle.BuyVolume)
+			currentHA.SellVolume = currentHA.SellVolume.Add(candle.SellVolume)
+			for _, level := range candle.Footprint {
+				currentHA.addFootprint(level)
+			}
 		}
 	}
 
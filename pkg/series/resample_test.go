@@ -36,3 +36,23 @@ func TestResample(t *testing.T) {
 	assert.Equal(t, 100.0, c0.OpenPrice.Float())
 	assert.Equal(t, 105.0, c0.ClosePrice.Float())
 }
+
+func TestResampleSideVolume(t *testing.T) {
+	s := NewTimeSeries()
+	base := time.Now().Truncate(time.Hour)
+	for i := 0; i < 2; i++ {
+		c := NewCandle(NewTimePeriod(base.Add(time.Duration(i)*time.Minute), time.Minute))
+		c.AddSideTrade(BuySide, decimal.New(1), decimal.New(float64(100+i)))
+		c.AddSideTrade(SellSide, decimal.New(2), decimal.New(100))
+		s.AddCandle(c)
+	}
+
+	c := Resample(s, 5*time.Minute).GetCandle(0)
+	assert.Equal(t, 2.0, c.BuyVolume.Float())
+	assert.Equal(t, 4.0, c.SellVolume.Float())
+	if assert.Len(t, c.Footprint, 2) {
+		assert.Equal(t, 1.0, c.Footprint[0].BuyVolume.Float())
+		assert.Equal(t, 4.0, c.Footprint[0].SellVolume.Float())
+		assert.Equal(t, 101.0, c.Footprint[1].Price.Float())
+	}
+}
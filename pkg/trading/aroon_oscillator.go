@@ -26,7 +26,11 @@ func NewAroonOscillatorOverLevelRule(aroonOsc indicators.Indicator, level float6
 }
 
 func (ar aroonOscillatorOverLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return ar.aroonOsc.Calculate(index).GT(ar.level)
+	return indicators.IsReady(index, ar.aroonOsc) && ar.aroonOsc.Calculate(index).GT(ar.level)
+}
+
+func (ar aroonOscillatorOverLevelRule) Lookback() int {
+	return indicators.Lookback(ar.aroonOsc)
 }
 
 // aroonOscillatorUnderLevelRule is satisfied when the Aroon Oscillator
@@ -49,7 +53,11 @@ func NewAroonOscillatorUnderLevelRule(aroonOsc indicators.Indicator, level float
 }
 
 func (ar aroonOscillatorUnderLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return ar.aroonOsc.Calculate(index).LT(ar.level)
+	return indicators.IsReady(index, ar.aroonOsc) && ar.aroonOsc.Calculate(index).LT(ar.level)
+}
+
+func (ar aroonOscillatorUnderLevelRule) Lookback() int {
+	return indicators.Lookback(ar.aroonOsc)
 }
 
 // NewAroonOscillatorBullishRule returns a convenience rule using an Aroon Oscillator
@@ -26,7 +26,11 @@ func NewChaikinMoneyFlowOverLevelRule(cmf indicators.Indicator, level float64) R
 }
 
 func (r chaikinMoneyFlowOverLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return r.cmf.Calculate(index).GT(r.level)
+	return indicators.IsReady(index, r.cmf) && r.cmf.Calculate(index).GT(r.level)
+}
+
+func (r chaikinMoneyFlowOverLevelRule) Lookback() int {
+	return indicators.Lookback(r.cmf)
 }
 
 // chaikinMoneyFlowUnderLevelRule is satisfied when the Chaikin Money Flow
@@ -49,7 +53,11 @@ func NewChaikinMoneyFlowUnderLevelRule(cmf indicators.Indicator, level float64)
 }
 
 func (r chaikinMoneyFlowUnderLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return r.cmf.Calculate(index).LT(r.level)
+	return indicators.IsReady(index, r.cmf) && r.cmf.Calculate(index).LT(r.level)
+}
+
+func (r chaikinMoneyFlowUnderLevelRule) Lookback() int {
+	return indicators.Lookback(r.cmf)
 }
 
 // NewChaikinMoneyFlowBullishRule returns a convenience rule using a CMF
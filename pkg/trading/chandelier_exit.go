@@ -36,6 +36,10 @@ func (ce chandelierExitLongRule) IsSatisfied(index int, record *TradingRecord) b
 		return false
 	}
 
+	if !indicators.IsReady(index, ce.exitLevel) {
+		return false
+	}
+
 	exitLevel := ce.exitLevel.Calculate(index)
 	if exitLevel.IsZero() {
 		return false
@@ -75,6 +79,10 @@ func (ce chandelierExitShortRule) IsSatisfied(index int, record *TradingRecord)
 		return false
 	}
 
+	if !indicators.IsReady(index, ce.exitLevel) {
+		return false
+	}
+
 	exitLevel := ce.exitLevel.Calculate(index)
 	if exitLevel.IsZero() {
 		return false
@@ -82,3 +90,11 @@ func (ce chandelierExitShortRule) IsSatisfied(index int, record *TradingRecord)
 
 	return ce.closePrice.Calculate(index).GTE(exitLevel)
 }
+
+func (ce chandelierExitLongRule) Lookback() int {
+	return indicators.Lookback(ce.exitLevel)
+}
+
+func (ce chandelierExitShortRule) Lookback() int {
+	return indicators.Lookback(ce.exitLevel)
+}
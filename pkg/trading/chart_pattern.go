@@ -0,0 +1,102 @@
+package trading
+
+import (
+	"github.com/irfndi/goflux/pkg/chartpatterns"
+	"github.com/irfndi/goflux/pkg/series"
+)
+
+// patternBreakoutRule is satisfied on the candle that confirms a chart
+// pattern by closing beyond its breakout line in the direction of bias
+type patternBreakoutRule struct {
+	scanner *chartpatterns.BreakoutScanner
+	cfg     chartpatterns.Config
+	kinds   map[chartpatterns.Kind]bool
+	bias    int
+}
+
+// NewBullishPatternBreakoutRule returns a rule that triggers when a pattern of
+// one of kinds (any kind if none are given) breaks out upwards at index. Only
+// the candles up to index are used. Panics if s is nil.
+func NewBullishPatternBreakoutRule(s *series.TimeSeries, cfg chartpatterns.Config, kinds ...chartpatterns.Kind) Rule {
+	return newPatternBreakoutRule(s, cfg, 1, kinds)
+}
+
+// NewBearishPatternBreakoutRule returns a rule that triggers when a pattern of
+// one of kinds (any kind if none are given) breaks down at index. Only the
+// candles up to index are used. Panics if s is nil.
+func NewBearishPatternBreakoutRule(s *series.TimeSeries, cfg chartpatterns.Config, kinds ...chartpatterns.Kind) Rule {
+	return newPatternBreakoutRule(s, cfg, -1, kinds)
+}
+
+func newPatternBreakoutRule(s *series.TimeSeries, cfg chartpatterns.Config, bias int, kinds []chartpatterns.Kind) Rule {
+	if s == nil {
+		panic("goflux: PatternBreakoutRule series cannot be nil")
+	}
+	r := patternBreakoutRule{scanner: chartpatterns.NewBreakoutScanner(s, cfg), cfg: cfg, bias: bias}
+	if len(kinds) > 0 {
+		r.kinds = make(map[chartpatterns.Kind]bool, len(kinds))
+		for _, k := range kinds {
+			r.kinds[k] = true
+		}
+	}
+	return r
+}
+
+func (r patternBreakoutRule) IsSatisfied(index int, record *TradingRecord) bool {
+	if index < r.Lookback() {
+		return false
+	}
+	for _, p := range r.scanner.Breakouts(index) {
+		if p.Bias == r.bias && (r.kinds == nil || r.kinds[p.Kind]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookback is the first index at which a swing can be confirmed
+func (r patternBreakoutRule) Lookback() int { return r.cfg.Lookback() }
+
+// harmonicRule is satisfied on the candle that confirms D of a harmonic
+// pattern inside its PRZ, for patterns of the given bias
+type harmonicRule struct {
+	scanner *chartpatterns.HarmonicScanner
+	cfg     chartpatterns.Config
+	bias    int
+}
+
+// NewBullishHarmonicRule returns a rule that triggers when a bullish harmonic
+// pattern of one of kinds (any kind if none are given) completes at index.
+// Only the candles up to index are used. Panics if s is nil.
+func NewBullishHarmonicRule(s *series.TimeSeries, cfg chartpatterns.Config, kinds ...chartpatterns.HarmonicKind) Rule {
+	return newHarmonicRule(s, cfg, 1, kinds)
+}
+
+// NewBearishHarmonicRule returns a rule that triggers when a bearish harmonic
+// pattern of one of kinds (any kind if none are given) completes at index.
+// Only the candles up to index are used. Panics if s is nil.
+func NewBearishHarmonicRule(s *series.TimeSeries, cfg chartpatterns.Config, kinds ...chartpatterns.HarmonicKind) Rule {
+	return newHarmonicRule(s, cfg, -1, kinds)
+}
+
+func newHarmonicRule(s *series.TimeSeries, cfg chartpatterns.Config, bias int, kinds []chartpatterns.HarmonicKind) Rule {
+	if s == nil {
+		panic("goflux: HarmonicRule series cannot be nil")
+	}
+	return harmonicRule{scanner: chartpatterns.NewHarmonicScanner(s, cfg, kinds...), cfg: cfg, bias: bias}
+}
+
+func (r harmonicRule) IsSatisfied(index int, record *TradingRecord) bool {
+	if index < r.Lookback() {
+		return false
+	}
+	for _, p := range r.scanner.Completed(index) {
+		if p.Bias == r.bias {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookback is the first index at which a swing can be confirmed
+func (r harmonicRule) Lookback() int { return r.cfg.Lookback() }
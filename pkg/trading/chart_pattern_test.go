@@ -0,0 +1,61 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/irfndi/goflux/pkg/chartpatterns"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+func TestPatternBreakoutRules(t *testing.T) {
+	// Double top at 120/120.5 with a neckline at 105, breaking down at 19
+	s := testutils.MockTimeSeriesFl(
+		100, 104, 108, 112, 116, 120, 117, 114, 111, 108,
+		105, 108.1, 111.2, 114.3, 117.4, 120.5, 115.4, 110.3, 105.2, 100.1,
+		95, 92, 89, 86, 83, 80,
+	)
+	record := NewTradingRecord()
+
+	bearish := NewBearishPatternBreakoutRule(s, chartpatterns.Config{})
+	var fired []int
+	for i := range s.Candles {
+		if bearish.IsSatisfied(i, record) {
+			fired = append(fired, i)
+		}
+	}
+	assert.Equal(t, []int{19}, fired)
+
+	assert.True(t, NewBearishPatternBreakoutRule(s, chartpatterns.Config{}, chartpatterns.DoubleTop).IsSatisfied(19, record))
+	assert.False(t, NewBearishPatternBreakoutRule(s, chartpatterns.Config{}, chartpatterns.HeadAndShoulders).IsSatisfied(19, record))
+	assert.False(t, NewBullishPatternBreakoutRule(s, chartpatterns.Config{}).IsSatisfied(19, record))
+	assert.Equal(t, 6, RuleLookback(bearish))
+}
+
+func TestHarmonicRules(t *testing.T) {
+	// Bullish Gartley with X at 5 (100), A at 10 (200), B at 15 (138.2),
+	// C at 20 (176.4) and D at 25 (121.4), confirmed at 28
+	values := []float64{120}
+	waypoints := []float64{120, 100, 200, 138.2, 176.4, 121.4, 160}
+	for i := 1; i < len(waypoints); i++ {
+		for j := 1; j <= 5; j++ {
+			values = append(values, waypoints[i-1]+(waypoints[i]-waypoints[i-1])*float64(j)/5)
+		}
+	}
+	s := testutils.MockTimeSeriesFl(values...)
+	record := NewTradingRecord()
+
+	bullish := NewBullishHarmonicRule(s, chartpatterns.Config{})
+	var fired []int
+	for i := range s.Candles {
+		if bullish.IsSatisfied(i, record) {
+			fired = append(fired, i)
+		}
+	}
+	assert.Equal(t, []int{28}, fired)
+
+	assert.True(t, NewBullishHarmonicRule(s, chartpatterns.Config{}, chartpatterns.Gartley).IsSatisfied(28, record))
+	assert.False(t, NewBullishHarmonicRule(s, chartpatterns.Config{}, chartpatterns.Crab).IsSatisfied(28, record))
+	assert.False(t, NewBearishHarmonicRule(s, chartpatterns.Config{}).IsSatisfied(28, record))
+}
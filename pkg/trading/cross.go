@@ -28,16 +28,21 @@ type crossRule struct {
 	cmp   int
 }
 
+// IsSatisfied only looks back as far as the first index at which both
+// indicators are ready, so warm-up placeholders cannot count as the side the
+// lower indicator crossed from. Bars where either indicator is NaN are skipped:
+// an undefined value is on neither side and never touches.
 func (cr crossRule) IsSatisfied(index int, record *TradingRecord) bool {
 	i := index
+	first := indicators.MaxLookback(cr.upper, cr.lower)
 
-	if i == 0 {
+	if i <= first {
 		return false
 	}
 
-	if cmp := cr.lower.Calculate(i).Cmp(cr.upper.Calculate(i)); cmp == 0 || cmp == cr.cmp {
-		for ; i >= 0; i-- {
-			if cmp = cr.lower.Calculate(i).Cmp(cr.upper.Calculate(i)); cmp == 0 || cmp == -cr.cmp {
+	if cmp, ok := cr.compare(i); ok && (cmp == 0 || cmp == cr.cmp) {
+		for ; i >= first; i-- {
+			if cmp, ok = cr.compare(i); ok && (cmp == 0 || cmp == -cr.cmp) {
 				return true
 			}
 		}
@@ -45,3 +50,19 @@ func (cr crossRule) IsSatisfied(index int, record *TradingRecord) bool {
 
 	return false
 }
+
+// compare returns the side of the lower indicator against the upper one at
+// index, and false when either is NaN
+func (cr crossRule) compare(index int) (int, bool) {
+	lower, upper := cr.lower.Calculate(index), cr.upper.Calculate(index)
+	if lower.IsNaN() || upper.IsNaN() {
+		return 0, false
+	}
+	return lower.Cmp(upper), true
+}
+
+// Lookback is one past the warm-up of both indicators: a cross needs a ready
+// value before the current one
+func (cr crossRule) Lookback() int {
+	return indicators.MaxLookback(cr.upper, cr.lower) + 1
+}
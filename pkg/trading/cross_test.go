@@ -1,11 +1,14 @@
 package trading_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
 	"github.com/irfndi/goflux/pkg/trading"
 )
 
@@ -42,3 +45,35 @@ func TestCrossDownIndicatorRule(t *testing.T) {
 		assert.True(t, rule.IsSatisfied(3, nil))
 	})
 }
+
+func TestCrossUpIndicatorRule_IgnoresWarmUp(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(10, 11, 12, 13, 14, 15))
+	sma := indicators.NewSimpleMovingAverage(closePrice, 3)
+
+	// The SMA is zero until index 2 and above 5 from then on, so it never crosses 5
+	rule := trading.NewCrossUpIndicatorRule(indicators.NewConstantIndicator(5), sma)
+
+	assert.Equal(t, 3, trading.RuleLookback(rule))
+	for i := 0; i < 6; i++ {
+		assert.False(t, rule.IsSatisfied(i, nil), "index %d", i)
+	}
+}
+
+func TestCrossUpIndicatorRule_IgnoresNaN(t *testing.T) {
+	// Williams %R is NaN over the flat candles, then 0 at the new high
+	flat, high := []float64{10, 10, 10, 10}, []float64{11, 11, 11, 11}
+	wr := indicators.NewWilliamsRIndicator(testutils.MockTimeSeriesOCHL(flat, flat, flat, flat, high), 3)
+	require.True(t, wr.Calculate(3).IsNaN())
+
+	rule := trading.NewCrossUpIndicatorRule(indicators.NewConstantIndicator(-80), wr)
+	assert.False(t, rule.IsSatisfied(4, nil), "NaN is not below -80")
+
+	// A NaN indicator does not cross or touch itself
+	self := trading.NewCrossUpIndicatorRule(wr, wr)
+	assert.False(t, self.IsSatisfied(3, nil))
+
+	// NaN bars are skipped back to the last defined side
+	gap := trading.NewCrossUpIndicatorRule(indicators.NewConstantIndicator(-80), indicators.NewFixedIndicator(-90, math.NaN(), -70))
+	assert.False(t, gap.IsSatisfied(1, nil))
+	assert.True(t, gap.IsSatisfied(2, nil))
+}
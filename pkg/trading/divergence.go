@@ -33,7 +33,7 @@ func NewBullishDivergenceRule(price, osc indicators.Indicator, lookback int) Rul
 }
 
 func (r bullishDivergenceRule) IsSatisfied(index int, record *TradingRecord) bool {
-	if index < r.lookback {
+	if !indicators.IsReady(index-r.lookback, r.price, r.osc) {
 		return false
 	}
 
@@ -46,6 +46,12 @@ func (r bullishDivergenceRule) IsSatisfied(index int, record *TradingRecord) boo
 	return endPrice.LT(startPrice) && endOsc.GT(startOsc)
 }
 
+// Lookback is the window past the warm-up of price and the oscillator, so
+// both ends of the comparison are ready
+func (r bullishDivergenceRule) Lookback() int {
+	return indicators.MaxLookback(r.price, r.osc) + r.lookback
+}
+
 // --- Bearish Divergence ---
 
 // bearishDivergenceRule is satisfied when price has made a higher high
@@ -71,7 +77,7 @@ func NewBearishDivergenceRule(price, osc indicators.Indicator, lookback int) Rul
 }
 
 func (r bearishDivergenceRule) IsSatisfied(index int, record *TradingRecord) bool {
-	if index < r.lookback {
+	if !indicators.IsReady(index-r.lookback, r.price, r.osc) {
 		return false
 	}
 
@@ -84,6 +90,12 @@ func (r bearishDivergenceRule) IsSatisfied(index int, record *TradingRecord) boo
 	return endPrice.GT(startPrice) && endOsc.LT(startOsc)
 }
 
+// Lookback is the window past the warm-up of price and the oscillator, so
+// both ends of the comparison are ready
+func (r bearishDivergenceRule) Lookback() int {
+	return indicators.MaxLookback(r.price, r.osc) + r.lookback
+}
+
 // --- Convenience constructors for RSI ---
 
 // NewRSIBullishDivergenceRule returns a bullish divergence rule using close price
@@ -31,6 +31,10 @@ func (r donchianBreakoutUpperRule) IsSatisfied(index int, record *TradingRecord)
 	return r.closePrice.Calculate(index).GT(r.upperBand.Calculate(index - 1))
 }
 
+func (r donchianBreakoutUpperRule) Lookback() int {
+	return r.window
+}
+
 // donchianBreakoutLowerRule is satisfied when the close price breaks
 // below the previous period's Donchian Channel lower band.
 type donchianBreakoutLowerRule struct {
@@ -56,6 +60,10 @@ func (r donchianBreakoutLowerRule) IsSatisfied(index int, record *TradingRecord)
 	return r.closePrice.Calculate(index).LT(r.lowerBand.Calculate(index - 1))
 }
 
+func (r donchianBreakoutLowerRule) Lookback() int {
+	return r.window
+}
+
 // donchianChannelWidthRule is satisfied when the channel width
 // (upper - lower) exceeds a given threshold, indicating volatility.
 type donchianChannelWidthRule struct {
@@ -75,6 +83,13 @@ func NewDonchianChannelWidthRule(s *series.TimeSeries, window int, threshold flo
 }
 
 func (r donchianChannelWidthRule) IsSatisfied(index int, record *TradingRecord) bool {
+	if !indicators.IsReady(index, r.upper, r.lower) {
+		return false
+	}
 	width := r.upper.Calculate(index).Sub(r.lower.Calculate(index))
 	return width.GT(r.threshold)
 }
+
+func (r donchianChannelWidthRule) Lookback() int {
+	return indicators.MaxLookback(r.upper, r.lower)
+}
@@ -11,7 +11,7 @@ type IncreaseRule struct {
 // IsSatisfied returns true when the given indicators.Indicator at the given index is greater than the value at the previous
 // index.
 func (ir IncreaseRule) IsSatisfied(index int, record *TradingRecord) bool {
-	if index == 0 {
+	if index <= indicators.Lookback(ir.Indicator) {
 		return false
 	}
 
@@ -27,9 +27,19 @@ type DecreaseRule struct {
 // IsSatisfied returns true when the given indicators.Indicator at the given index is less than the value at the previous
 // index.
 func (dr DecreaseRule) IsSatisfied(index int, record *TradingRecord) bool {
-	if index == 0 {
+	if index <= indicators.Lookback(dr.Indicator) {
 		return false
 	}
 
 	return dr.Calculate(index).LT(dr.Calculate(index - 1))
 }
+
+// Lookback is one past the warm-up of the indicator
+func (ir IncreaseRule) Lookback() int {
+	return indicators.Lookback(ir.Indicator) + 1
+}
+
+// Lookback is one past the warm-up of the indicator
+func (dr DecreaseRule) Lookback() int {
+	return indicators.Lookback(dr.Indicator) + 1
+}
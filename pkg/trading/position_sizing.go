@@ -83,6 +83,11 @@ func (kcs *kellyCriterionSizer) CalculateSize(config PositionSizingConfig) decim
 	return kellyFraction.Mul(config.Capital).Div(config.CurrentPrice)
 }
 
+// NewVolatilityBasedSizer returns a sizer risking 1% of capital with a stop
+// multiplier times the volatility below the price, unless a stop loss is set.
+// The volatility is the ATR as a fraction of the price, or when no ATR is set,
+// the Volatility of the config, a standard deviation of returns such as a
+// GARCH forecast.
 func NewVolatilityBasedSizer(multiplier float64) PositionSizer {
 	return &volatilityBasedSizer{multiplier: decimal.New(multiplier)}
 }
@@ -97,6 +102,9 @@ func (vbs *volatilityBasedSizer) CalculateSize(config PositionSizingConfig) deci
 	}
 
 	atrPercent := config.ATR.Div(config.CurrentPrice)
+	if config.ATR.IsZero() {
+		atrPercent = config.Volatility
+	}
 	atrStopLoss := config.CurrentPrice.Sub(config.CurrentPrice.Mul(atrPercent.Mul(vbs.multiplier)))
 
 	stopLoss := config.StopLoss
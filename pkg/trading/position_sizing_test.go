@@ -114,6 +114,18 @@ func TestVolatilityBasedSizerCalculateSize(t *testing.T) {
 	assert.Equal(t, decimal.ZERO, size)
 }
 
+func TestVolatilityBasedSizerWithoutATR(t *testing.T) {
+	config := trading.PositionSizingConfig{
+		Capital:      decimal.New(10000),
+		CurrentPrice: decimal.New(100),
+		Volatility:   decimal.New(0.05),
+	}
+
+	// The stop is 2 * 5% below the price, risking 1% of capital
+	size := trading.NewVolatilityBasedSizer(2.0).CalculateSize(config)
+	assert.InDelta(t, 10, size.Float(), 1e-9)
+}
+
 func TestNewRiskBasedSizer(t *testing.T) {
 	sizer := trading.NewRiskBasedSizer()
 	assert.NotNil(t, sizer)
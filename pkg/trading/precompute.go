@@ -0,0 +1,56 @@
+package trading
+
+import "github.com/irfndi/goflux/pkg/indicators"
+
+// Precomputable is implemented by strategies that can evaluate their indicators
+// for a whole series before it is walked bar by bar. Backtesters call
+// Precompute with the series length and run the returned strategy.
+type Precomputable interface {
+	Precompute(length int) Strategy
+}
+
+// Precompute returns a copy of the strategy whose rules read single-pass
+// precomputed indicator values. Rules it does not know are used unchanged.
+func (rs RuleStrategy) Precompute(length int) Strategy {
+	rs.EntryRule = PrecomputeRule(rs.EntryRule, length)
+	rs.ExitRule = PrecomputeRule(rs.ExitRule, length)
+	return rs
+}
+
+// PrecomputeRule returns r with every indicators.VectorIndicator it compares
+// replaced by one precomputed for indices [0, length). Composite rules are
+// rebuilt recursively; other rules are returned as is.
+func PrecomputeRule(r Rule, length int) Rule {
+	switch rule := r.(type) {
+	case andRule:
+		return andRule{PrecomputeRule(rule.r1, length), PrecomputeRule(rule.r2, length)}
+	case orRule:
+		return orRule{PrecomputeRule(rule.r1, length), PrecomputeRule(rule.r2, length)}
+	case notRule:
+		return notRule{PrecomputeRule(rule.r, length)}
+	case voteRule:
+		rules := make([]Rule, len(rule.rules))
+		for i, sub := range rule.rules {
+			rules[i] = PrecomputeRule(sub, length)
+		}
+		return voteRule{rule.threshold, rules}
+	case OverIndicatorRule:
+		return OverIndicatorRule{precompute(rule.First, length), precompute(rule.Second, length)}
+	case UnderIndicatorRule:
+		return UnderIndicatorRule{precompute(rule.First, length), precompute(rule.Second, length)}
+	case crossRule:
+		return crossRule{upper: precompute(rule.upper, length), lower: precompute(rule.lower, length), cmp: rule.cmp}
+	case IncreaseRule:
+		return IncreaseRule{precompute(rule.Indicator, length)}
+	case DecreaseRule:
+		return DecreaseRule{precompute(rule.Indicator, length)}
+	}
+	return r
+}
+
+func precompute(ind indicators.Indicator, length int) indicators.Indicator {
+	if _, ok := ind.(indicators.VectorIndicator); ok {
+		return indicators.NewPrecomputedIndicator(ind, length)
+	}
+	return ind
+}
@@ -0,0 +1,39 @@
+package trading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
+)
+
+func TestRuleStrategy_Precompute(t *testing.T) {
+	ts := testutils.RandomTimeSeries(120)
+	closes := indicators.NewClosePriceIndicator(ts)
+	fast := indicators.NewEMAIndicator(closes, 5)
+	slow := indicators.NewSimpleMovingAverage(closes, 20)
+
+	strategy := RuleStrategy{
+		EntryRule:      And(NewCrossUpIndicatorRule(slow, fast), Not(IncreaseRule{slow})),
+		ExitRule:       Or(NewCrossDownIndicatorRule(slow, fast), OverIndicatorRule{closes, slow}),
+		UnstablePeriod: 20,
+	}
+	precomputed := strategy.Precompute(ts.Length())
+
+	entry := precomputed.(RuleStrategy).EntryRule.(andRule)
+	_, ok := entry.r1.(crossRule).upper.(indicators.VectorIndicator)
+	assert.True(t, ok)
+
+	record := NewTradingRecord()
+	for i := 0; i < ts.Length(); i++ {
+		assert.Equal(t, strategy.EntryRule.IsSatisfied(i, record), precomputed.(RuleStrategy).EntryRule.IsSatisfied(i, record), "entry %d", i)
+		assert.Equal(t, strategy.ExitRule.IsSatisfied(i, record), precomputed.(RuleStrategy).ExitRule.IsSatisfied(i, record), "exit %d", i)
+	}
+}
+
+func TestPrecomputeRule_LeavesUnknownRules(t *testing.T) {
+	rule := truthRule{}
+	assert.Equal(t, Rule(rule), PrecomputeRule(rule, 10))
+}
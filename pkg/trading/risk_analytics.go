@@ -106,8 +106,13 @@ func (vc *VaRCalculator) parametricVaR(returns []decimal.Decimal) *VaRResult {
 
 	mean := vc.calculateMean(returns)
 	variance := vc.calculateVariance(returns, mean)
-	stdDev := variance.Sqrt()
+	return vc.CalculateParametric(mean, variance.Sqrt())
+}
 
+// CalculateParametric returns the parametric VaR and CVaR of normally
+// distributed returns with the given mean and standard deviation, such as a
+// GARCH volatility forecast in place of the historical deviation
+func (vc *VaRCalculator) CalculateParametric(mean, stdDev decimal.Decimal) *VaRResult {
 	zScore := vc.getZScore(vc.confidence.Float())
 	varLoss := mean.Sub(stdDev.Mul(zScore))
 
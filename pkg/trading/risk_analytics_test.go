@@ -52,6 +52,24 @@ func TestParametricVaR(t *testing.T) {
 	}
 }
 
+func TestCalculateParametricVaR(t *testing.T) {
+	returns := []decimal.Decimal{decimal.New(0.01), decimal.New(-0.02), decimal.New(0.03), decimal.New(-0.01)}
+	calc := NewVaRCalculator(ParametricVaR, 0.95, 1)
+	fromReturns := calc.Calculate(returns)
+
+	mean := calc.calculateMean(returns)
+	stdDev := calc.calculateVariance(returns, mean).Sqrt()
+	fromForecast := calc.CalculateParametric(mean, stdDev)
+	if !fromForecast.VaR.EQ(fromReturns.VaR) || !fromForecast.CVaR.EQ(fromReturns.CVaR) {
+		t.Errorf("Expected VaR %v and CVaR %v, got %v and %v", fromReturns.VaR, fromReturns.CVaR, fromForecast.VaR, fromForecast.CVaR)
+	}
+
+	// A higher volatility forecast raises the VaR
+	if !calc.CalculateParametric(mean, stdDev.Mul(decimal.New(2))).VaR.GT(fromReturns.VaR) {
+		t.Error("VaR should grow with the standard deviation")
+	}
+}
+
 func TestMonteCarloVaR(t *testing.T) {
 	returns := []decimal.Decimal{
 		decimal.New(0.01),
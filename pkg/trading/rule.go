@@ -1,6 +1,7 @@
 package trading
 
 import (
+	"sort"
 	"time"
 
 	"github.com/irfndi/goflux/pkg/decimal"
@@ -13,6 +14,27 @@ type Rule interface {
 	IsSatisfied(index int, record *TradingRecord) bool
 }
 
+// LookbackRule is a Rule that reports its warm-up, the first index at which
+// every indicator it reads has a real value. Rules built on indicators are
+// never satisfied before it.
+type LookbackRule interface {
+	Rule
+	Lookback() int
+}
+
+// RuleLookback returns the warm-up of r, or 0 if it does not report one
+func RuleLookback(r Rule) int {
+	if l, ok := r.(LookbackRule); ok {
+		return max(l.Lookback(), 0)
+	}
+	return 0
+}
+
+// ready reports whether r is warmed up at index, and so may be consulted
+func ready(r Rule, index int) bool {
+	return index >= RuleLookback(r)
+}
+
 // And returns a new rule whereby BOTH of the passed-in rules must be satisfied for the rule to be satisfied
 func And(r1, r2 Rule) Rule {
 	return andRule{r1, r2}
@@ -39,7 +61,11 @@ type andRule struct {
 }
 
 func (ar andRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return ar.r1.IsSatisfied(index, record) && ar.r2.IsSatisfied(index, record)
+	return ready(ar, index) && ar.r1.IsSatisfied(index, record) && ar.r2.IsSatisfied(index, record)
+}
+
+func (ar andRule) Lookback() int {
+	return max(RuleLookback(ar.r1), RuleLookback(ar.r2))
 }
 
 type orRule struct {
@@ -48,7 +74,14 @@ type orRule struct {
 }
 
 func (or orRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return or.r1.IsSatisfied(index, record) || or.r2.IsSatisfied(index, record)
+	return ready(or.r1, index) && or.r1.IsSatisfied(index, record) ||
+		ready(or.r2, index) && or.r2.IsSatisfied(index, record)
+}
+
+// Lookback is the earlier warm-up of the two: either rule may be satisfied as
+// soon as it is ready, and each is consulted only from its own warm-up on
+func (or orRule) Lookback() int {
+	return min(RuleLookback(or.r1), RuleLookback(or.r2))
 }
 
 type notRule struct {
@@ -56,7 +89,13 @@ type notRule struct {
 }
 
 func (nr notRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return !nr.r.IsSatisfied(index, record)
+	return ready(nr.r, index) && !nr.r.IsSatisfied(index, record)
+}
+
+// Lookback is the warm-up of the negated rule, which is false, and so would
+// make Not true, until then; Not is false before it
+func (nr notRule) Lookback() int {
+	return RuleLookback(nr.r)
 }
 
 type voteRule struct {
@@ -67,13 +106,27 @@ type voteRule struct {
 func (vr voteRule) IsSatisfied(index int, record *TradingRecord) bool {
 	count := 0
 	for _, rule := range vr.rules {
-		if rule.IsSatisfied(index, record) {
+		if ready(rule, index) && rule.IsSatisfied(index, record) {
 			count++
 		}
 	}
 	return count >= vr.threshold
 }
 
+// Lookback is the first index at which threshold of the rules are warmed up,
+// the earliest the vote can pass; only warmed up rules are counted
+func (vr voteRule) Lookback() int {
+	if vr.threshold <= 0 || len(vr.rules) == 0 {
+		return 0
+	}
+	lookbacks := make([]int, len(vr.rules))
+	for i, rule := range vr.rules {
+		lookbacks[i] = RuleLookback(rule)
+	}
+	sort.Ints(lookbacks)
+	return lookbacks[min(vr.threshold, len(lookbacks))-1]
+}
+
 // SignalRule is a rule that is satisfied when the underlying SignalIndicator returns SignalBuy
 type SignalRule struct {
 	Signal indicators.SignalIndicator
@@ -113,9 +166,14 @@ func NewOverIndicatorRule(first, second indicators.Indicator) Rule {
 	return OverIndicatorRule{first, second}
 }
 
-// IsSatisfied returns true when the First indicators.Indicator is greater than the Second indicators.Indicator
+// IsSatisfied returns true when both indicators are ready and the First indicators.Indicator is greater than the
+// Second indicators.Indicator
 func (oir OverIndicatorRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return oir.First.Calculate(index).GT(oir.Second.Calculate(index))
+	return indicators.IsReady(index, oir.First, oir.Second) && oir.First.Calculate(index).GT(oir.Second.Calculate(index))
+}
+
+func (oir OverIndicatorRule) Lookback() int {
+	return indicators.MaxLookback(oir.First, oir.Second)
 }
 
 // UnderIndicatorRule is a rule where the First indicators.Indicator must be less than the Second indicators.Indicator to be Satisfied
@@ -129,9 +187,14 @@ func NewUnderIndicatorRule(first, second indicators.Indicator) Rule {
 	return UnderIndicatorRule{first, second}
 }
 
-// IsSatisfied returns true when the First indicators.Indicator is less than the Second indicators.Indicator
+// IsSatisfied returns true when both indicators are ready and the First indicators.Indicator is less than the
+// Second indicators.Indicator
 func (uir UnderIndicatorRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return uir.First.Calculate(index).LT(uir.Second.Calculate(index))
+	return indicators.IsReady(index, uir.First, uir.Second) && uir.First.Calculate(index).LT(uir.Second.Calculate(index))
+}
+
+func (uir UnderIndicatorRule) Lookback() int {
+	return indicators.MaxLookback(uir.First, uir.Second)
 }
 
 type percentChangeRule struct {
@@ -140,7 +203,11 @@ type percentChangeRule struct {
 }
 
 func (pgr percentChangeRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return pgr.indicator.Calculate(index).Abs().GT(pgr.percent.Abs())
+	return indicators.IsReady(index, pgr.indicator) && pgr.indicator.Calculate(index).Abs().GT(pgr.percent.Abs())
+}
+
+func (pgr percentChangeRule) Lookback() int {
+	return indicators.Lookback(pgr.indicator)
 }
 
 // NewPercentChangeRule returns a rule whereby the given indicators.Indicator must have changed by a given percentage to be satisfied.
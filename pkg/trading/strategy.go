@@ -12,7 +12,9 @@ type Strategy interface {
 var ErrNilRule = errors.New("rule cannot be nil")
 
 // RuleStrategy is a strategy based on rules and an unstable period. The two rules determine whether a position should
-// be created or closed, and unstable period is an index before no positions should be created or exited
+// be created or closed, and unstable period is an index before no positions should be created or exited. Independently
+// of the unstable period, a rule is not consulted before its RuleLookback, when the indicators it reads are still
+// warming up.
 type RuleStrategy struct {
 	EntryRule      Rule
 	ExitRule       Rule
@@ -32,26 +34,28 @@ func NewRuleStrategy(entryRule, exitRule Rule, unstablePeriod int) (RuleStrategy
 	}, nil
 }
 
-// ShouldEnter will return true when index is greater than unstable period and entry rule is satisfied
+// ShouldEnter will return true when index is greater than unstable period, the entry rule is warmed up and the entry
+// rule is satisfied
 func (rs RuleStrategy) ShouldEnter(index int, record *TradingRecord) bool {
 	if rs.EntryRule == nil || record == nil {
 		return false
 	}
 
-	if index > rs.UnstablePeriod && record.CurrentPosition().IsNew() {
+	if index > rs.UnstablePeriod && index >= RuleLookback(rs.EntryRule) && record.CurrentPosition().IsNew() {
 		return rs.EntryRule.IsSatisfied(index, record)
 	}
 
 	return false
 }
 
-// ShouldExit will return true when index is greater than unstable period and exit rule is satisfied
+// ShouldExit will return true when index is greater than unstable period, the exit rule is warmed up and the exit
+// rule is satisfied
 func (rs RuleStrategy) ShouldExit(index int, record *TradingRecord) bool {
 	if rs.ExitRule == nil || record == nil {
 		return false
 	}
 
-	if index > rs.UnstablePeriod && record.CurrentPosition().IsOpen() {
+	if index > rs.UnstablePeriod && index >= RuleLookback(rs.ExitRule) && record.CurrentPosition().IsOpen() {
 		return rs.ExitRule.IsSatisfied(index, record)
 	}
 
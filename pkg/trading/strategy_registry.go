@@ -3,6 +3,7 @@ package trading
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/irfndi/goflux/pkg/indicators"
 	"github.com/irfndi/goflux/pkg/series"
@@ -10,9 +11,14 @@ import (
 
 type StrategyFactory func(ts *series.TimeSeries, params map[string]interface{}) Strategy
 
+// StrategyBuilder is a StrategyFactory that reports why params cannot build a
+// strategy
+type StrategyBuilder func(ts *series.TimeSeries, params map[string]interface{}) (Strategy, error)
+
 type NamedStrategy struct {
 	Name    string
 	Factory StrategyFactory
+	Builder StrategyBuilder
 }
 
 type StrategyRegistry struct {
@@ -40,6 +46,21 @@ func (sr *StrategyRegistry) Register(name string, factory StrategyFactory) {
 	}
 }
 
+// RegisterBuilder registers a strategy whose builder errors are returned by
+// Instantiate
+func (sr *StrategyRegistry) RegisterBuilder(name string, builder StrategyBuilder) {
+	if sr == nil {
+		return
+	}
+	if sr.strategies == nil {
+		sr.strategies = make(map[string]NamedStrategy)
+	}
+	sr.strategies[name] = NamedStrategy{
+		Name:    name,
+		Builder: builder,
+	}
+}
+
 func (sr *StrategyRegistry) Lookup(name string) (*NamedStrategy, bool) {
 	strategy, ok := sr.strategies[name]
 	return &strategy, ok
@@ -50,10 +71,21 @@ func (sr *StrategyRegistry) Instantiate(name string, ts *series.TimeSeries, para
 	if !ok {
 		return nil, fmt.Errorf("strategy %s not found", name)
 	}
+	if strategy.Builder != nil {
+		built, err := strategy.Builder(ts, params)
+		if err != nil {
+			return nil, fmt.Errorf("strategy %s: %w", name, err)
+		}
+		return built, nil
+	}
 	if strategy.Factory == nil {
 		return nil, fmt.Errorf("strategy %s has no factory", name)
 	}
-	return strategy.Factory(ts, params), nil
+	built := strategy.Factory(ts, params)
+	if built == nil {
+		return nil, fmt.Errorf("strategy %s could not be built from params %v", name, params)
+	}
+	return built, nil
 }
 
 func (sr *StrategyRegistry) List() []string {
@@ -72,6 +104,7 @@ func (sr *StrategyRegistry) registerDefaults() {
 	sr.Register("bollinger_bounce", createBollingerStrategy)
 	sr.Register("supertrend", createSuperTrendStrategy)
 	sr.Register("adx_trending", createADXStrategy)
+	sr.RegisterBuilder("indicator_cross", createIndicatorCrossStrategy)
 }
 
 func createSMACrossFastStrategy(ts *series.TimeSeries, params map[string]interface{}) Strategy {
@@ -210,6 +243,55 @@ func createADXStrategy(ts *series.TimeSeries, params map[string]interface{}) Str
 	}
 }
 
+// createIndicatorCrossStrategy enters when the fast indicator crosses above the
+// slow one and exits on the cross back down. fast_indicator and slow_indicator
+// name entries of the indicator registry, such as "ema" or "bbands.middle";
+// parameters prefixed with fast_ and slow_ are passed to them, so
+// {"fast_indicator": "ema", "fast_window": 12} builds a 12 bar EMA. It returns
+// the error of an indicator that cannot be built.
+func createIndicatorCrossStrategy(ts *series.TimeSeries, params map[string]interface{}) (Strategy, error) {
+	fast, fastPeriod, err := registryIndicator(ts, params, "fast", "ema")
+	if err != nil {
+		return nil, fmt.Errorf("fast indicator: %w", err)
+	}
+	slow, slowPeriod, err := registryIndicator(ts, params, "slow", "sma")
+	if err != nil {
+		return nil, fmt.Errorf("slow indicator: %w", err)
+	}
+
+	return RuleStrategy{
+		EntryRule:      NewCrossUpIndicatorRule(slow, fast),
+		ExitRule:       NewCrossDownIndicatorRule(fast, slow),
+		UnstablePeriod: max(fastPeriod, slowPeriod),
+	}, nil
+}
+
+// registryIndicator builds the registry indicator named by params[prefix+"_indicator"]
+// from the numeric params starting with prefix+"_". It also returns the largest
+// whole-number parameter as an estimate of the indicator's unstable period.
+func registryIndicator(ts *series.TimeSeries, params map[string]interface{}, prefix, defaultName string) (indicators.Indicator, int, error) {
+	name := defaultName
+	if v, ok := params[prefix+"_indicator"].(string); ok {
+		name = v
+	}
+	spec, ok := indicators.LookupIndicator(strings.SplitN(name, ".", 2)[0])
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %q", indicators.ErrUnknownIndicator, name)
+	}
+
+	indParams := make(map[string]float64)
+	period := 0
+	for _, ps := range spec.Params {
+		v := getParam(params, prefix+"_"+ps.Name, ps.Default)
+		indParams[ps.Name] = v
+		if ps.Type == indicators.ParamInt && int(v) > period {
+			period = int(v)
+		}
+	}
+	ind, err := indicators.NewIndicatorByName(name, ts, indParams)
+	return ind, period, err
+}
+
 func getParam(params map[string]interface{}, key string, defaultVal float64) float64 {
 	if params == nil {
 		return defaultVal
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/irfndi/goflux/pkg/indicators"
 	"github.com/irfndi/goflux/pkg/testutils"
 	"github.com/irfndi/goflux/pkg/trading"
 )
@@ -164,3 +165,37 @@ func TestVoteRule(t *testing.T) {
 	vote3 := trading.Vote(3, r1, r2, r3)
 	assert.False(t, vote3.IsSatisfied(0, record))
 }
+
+func TestIndicatorCrossStrategy(t *testing.T) {
+	ts := testutils.MockTimeSeriesFl(100, 101, 102, 103, 104, 105)
+	registry := trading.NewStrategyRegistry()
+
+	strategy, err := registry.Instantiate("indicator_cross", ts, map[string]interface{}{
+		"fast_indicator": "wma",
+		"fast_window":    2,
+		"slow_indicator": "bbands.middle",
+		"slow_window":    4,
+		"slow_sigma":     1.5,
+	})
+	assert.NoError(t, err)
+	rs, ok := strategy.(trading.RuleStrategy)
+	assert.True(t, ok)
+	assert.Equal(t, 4, rs.UnstablePeriod)
+
+	strategy, err = registry.Instantiate("indicator_cross", ts, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, strategy.(trading.RuleStrategy).UnstablePeriod)
+
+	for _, tc := range []struct {
+		params map[string]interface{}
+		err    error
+	}{
+		{map[string]interface{}{"fast_indicator": "nope"}, indicators.ErrUnknownIndicator},
+		{map[string]interface{}{"slow_window": 0}, indicators.ErrInvalidParam},
+		{map[string]interface{}{"slow_indicator": "bbands.nope"}, indicators.ErrUnknownIndicator},
+	} {
+		strategy, err := registry.Instantiate("indicator_cross", ts, tc.params)
+		assert.ErrorIs(t, err, tc.err, "%v", tc.params)
+		assert.Nil(t, strategy)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/irfndi/goflux/pkg/decimal"
+	"github.com/irfndi/goflux/pkg/indicators"
+	"github.com/irfndi/goflux/pkg/testutils"
 	"github.com/irfndi/goflux/pkg/trading"
 )
 
@@ -148,3 +150,84 @@ func TestRuleStrategy_ShouldExit(t *testing.T) {
 		assert.False(t, s.ShouldExit(0, nil))
 	})
 }
+
+func TestRuleStrategy_WaitsForRuleLookback(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(10, 11, 12, 13, 14, 15, 16, 17))
+	sma := indicators.NewSimpleMovingAverage(closePrice, 4)
+	entry := trading.NewOverIndicatorRule(sma, indicators.NewConstantIndicator(5))
+	exit := trading.Not(entry)
+
+	assert.Equal(t, 3, trading.RuleLookback(entry))
+	assert.Equal(t, 3, trading.RuleLookback(exit))
+	assert.Equal(t, 3, trading.RuleLookback(trading.And(alwaysSatisfiedRule{}, entry)))
+	assert.Equal(t, 0, trading.RuleLookback(alwaysSatisfiedRule{}))
+
+	s := trading.RuleStrategy{EntryRule: entry, ExitRule: exit}
+
+	record := trading.NewTradingRecord()
+	assert.False(t, s.ShouldEnter(2, record))
+	assert.True(t, s.ShouldEnter(3, record))
+
+	record.Operate(trading.Order{Side: trading.BUY, Amount: decimal.ONE, Price: decimal.ONE})
+	assert.False(t, s.ShouldExit(2, record), "Not of a rule that is still warming up must not exit")
+}
+
+func TestRuleStrategy_OrDoesNotWaitForSlowerRule(t *testing.T) {
+	prices := make([]float64, 30)
+	for i := range prices {
+		prices[i] = 100
+	}
+	for i := 20; i < len(prices); i++ {
+		prices[i] = 90
+	}
+	ts := testutils.MockTimeSeriesFl(prices...)
+	closePrice := indicators.NewClosePriceIndicator(ts)
+	slow := trading.NewCrossDownIndicatorRule(closePrice, indicators.NewSimpleMovingAverage(closePrice, 200))
+	exit := trading.Or(trading.NewStopLossRule(ts, -0.05), slow)
+
+	assert.Equal(t, 0, trading.RuleLookback(exit))
+	assert.Equal(t, 200, trading.RuleLookback(trading.Or(slow, slow)))
+
+	s := trading.RuleStrategy{EntryRule: alwaysSatisfiedRule{}, ExitRule: exit}
+	record := trading.NewTradingRecord()
+	record.Operate(trading.Order{Side: trading.BUY, Amount: decimal.ONE, Price: decimal.New(100)})
+
+	assert.False(t, s.ShouldExit(19, record))
+	assert.True(t, s.ShouldExit(20, record), "the stop loss is ready before the moving average")
+}
+
+func TestRuleStrategy_NotOfWarmingRuleInsideOr(t *testing.T) {
+	prices := make([]float64, 20)
+	for i := range prices {
+		prices[i] = float64(100 - i)
+	}
+	closePrice := indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(prices...))
+	sma := indicators.NewSimpleMovingAverage(closePrice, 10)
+	never := trading.NewOverIndicatorRule(closePrice, indicators.NewConstantIndicator(1000))
+	notAbove := trading.Not(trading.NewOverIndicatorRule(closePrice, sma))
+	entry := trading.Or(never, notAbove)
+
+	assert.Equal(t, 0, trading.RuleLookback(entry))
+	s := trading.RuleStrategy{EntryRule: entry, ExitRule: alwaysSatisfiedRule{}}
+	record := trading.NewTradingRecord()
+	for i := 1; i < 9; i++ {
+		assert.False(t, s.ShouldEnter(i, record), "the negated SMA rule is warming up at %d", i)
+		assert.False(t, notAbove.IsSatisfied(i, record))
+	}
+	assert.True(t, s.ShouldEnter(9, record))
+	assert.False(t, trading.Vote(1, never, notAbove).IsSatisfied(8, record))
+	assert.True(t, trading.Vote(1, never, notAbove).IsSatisfied(9, record))
+}
+
+func TestVoteLookback(t *testing.T) {
+	closePrice := indicators.NewClosePriceIndicator(testutils.MockTimeSeriesFl(1, 2, 3))
+	over := func(window int) trading.Rule {
+		return trading.NewOverIndicatorRule(indicators.NewSimpleMovingAverage(closePrice, window), indicators.NewConstantIndicator(0))
+	}
+	rules := []trading.Rule{over(10), alwaysSatisfiedRule{}, over(5)}
+
+	assert.Equal(t, 0, trading.RuleLookback(trading.Vote(1, rules...)))
+	assert.Equal(t, 4, trading.RuleLookback(trading.Vote(2, rules...)))
+	assert.Equal(t, 9, trading.RuleLookback(trading.Vote(3, rules...)))
+	assert.Equal(t, 9, trading.RuleLookback(trading.Vote(4, rules...)))
+}
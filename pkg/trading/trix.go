@@ -24,7 +24,11 @@ func NewTRIXOverLevelRule(trix indicators.Indicator, level float64) Rule {
 }
 
 func (r trixOverLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return r.trix.Calculate(index).GT(r.level)
+	return indicators.IsReady(index, r.trix) && r.trix.Calculate(index).GT(r.level)
+}
+
+func (r trixOverLevelRule) Lookback() int {
+	return indicators.Lookback(r.trix)
 }
 
 // trixUnderLevelRule is satisfied when TRIX falls below a given level.
@@ -45,7 +49,11 @@ func NewTRIXUnderLevelRule(trix indicators.Indicator, level float64) Rule {
 }
 
 func (r trixUnderLevelRule) IsSatisfied(index int, record *TradingRecord) bool {
-	return r.trix.Calculate(index).LT(r.level)
+	return indicators.IsReady(index, r.trix) && r.trix.Calculate(index).LT(r.level)
+}
+
+func (r trixUnderLevelRule) Lookback() int {
+	return indicators.Lookback(r.trix)
 }
 
 // NewTRIXBullishRule returns a convenience rule using a TRIX
@@ -0,0 +1,88 @@
+#!/usr/bin/env python3
+"""Export TA-Lib values over the reftest dataset for pkg/reftest
+
+Reads pkg/reftest/testdata/daily_ohlcv.csv and writes
+pkg/reftest/testdata/talib.csv with a column per TA-Lib output, named as in
+talibCases and talibHilbertCases, which TestTALibExported compares the
+CompatTALib and HT indicators with.
+Needs the TA-Lib C library and its Python wrapper (pip install TA-Lib).
+"""
+
+import csv
+import math
+import os
+import sys
+
+import numpy as np
+import talib
+
+ROOT = os.path.join(os.path.dirname(os.path.abspath(__file__)), "..")
+TESTDATA = os.path.join(ROOT, "pkg", "reftest", "testdata")
+
+
+def read_bars(path):
+    with open(path, newline="") as f:
+        rows = list(csv.DictReader(f))
+    return {
+        name: np.array([float(row[name]) for row in rows])
+        for name in ("open", "high", "low", "close", "volume")
+    }
+
+
+def outputs(bars):
+    high, low, close, volume = bars["high"], bars["low"], bars["close"], bars["volume"]
+    macd, signal, hist = talib.MACD(close, fastperiod=12, slowperiod=26, signalperiod=9)
+    upper, middle, lower = talib.BBANDS(close, timeperiod=20, nbdevup=2, nbdevdn=2, matype=0)
+    k, d = talib.STOCH(
+        high, low, close, fastk_period=14, slowk_period=3, slowk_matype=0, slowd_period=3, slowd_matype=0
+    )
+    inphase, quadrature = talib.HT_PHASOR(close)
+    sine, leadsine = talib.HT_SINE(close)
+    return {
+        "EMA(10)": talib.EMA(close, timeperiod=10),
+        "RSI(14)": talib.RSI(close, timeperiod=14),
+        "MACD.macd": macd,
+        "MACD.signal": signal,
+        "MACD.histogram": hist,
+        "BBANDS.upper": upper,
+        "BBANDS.middle": middle,
+        "BBANDS.lower": lower,
+        "STOCH.k": k,
+        "STOCH.d": d,
+        "ATR(14)": talib.ATR(high, low, close, timeperiod=14),
+        "ADX(14)": talib.ADX(high, low, close, timeperiod=14),
+        "CCI(20)": talib.CCI(high, low, close, timeperiod=20),
+        "OBV": talib.OBV(close, volume),
+        "SAR(0.02, 0.2)": talib.SAR(high, low, acceleration=0.02, maximum=0.2),
+        "HT_DCPERIOD": talib.HT_DCPERIOD(close),
+        "HT_DCPHASE": talib.HT_DCPHASE(close),
+        "HT_PHASOR.inphase": inphase,
+        "HT_PHASOR.quadrature": quadrature,
+        "HT_SINE.sine": sine,
+        "HT_SINE.leadsine": leadsine,
+        "HT_TRENDLINE": talib.HT_TRENDLINE(close),
+        "HT_TRENDMODE": talib.HT_TRENDMODE(close),
+    }
+
+
+def cell(v):
+    return "" if math.isnan(v) else repr(float(v))
+
+
+def main():
+    source = sys.argv[1] if len(sys.argv) > 1 else os.path.join(TESTDATA, "daily_ohlcv.csv")
+    target = sys.argv[2] if len(sys.argv) > 2 else os.path.join(TESTDATA, "talib.csv")
+    bars = read_bars(source)
+    columns = outputs(bars)
+
+    with open(target, "w", newline="") as f:
+        writer = csv.writer(f)
+        ohlcv = ["open", "high", "low", "close", "volume"]
+        writer.writerow(ohlcv + list(columns))
+        for i in range(len(bars["close"])):
+            writer.writerow([cell(bars[name][i]) for name in ohlcv] + [cell(values[i]) for values in columns.values()])
+    print(f"wrote {target}")
+
+
+if __name__ == "__main__":
+    main()